@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/reporter"
+)
+
+var (
+	apidiffFormat string
+	apidiffOutput string
+)
+
+var apidiffCmd = &cobra.Command{
+	Use:   "apidiff OLD [NEW]",
+	Short: "对比两个版本的导出 API，找出不兼容的变化",
+	Long: `比较两个版本中导出的结构体、字段、方法、函数签名和接口方法集，
+按 Go 的兼容性规则把每处变化分为不兼容（breaking）和兼容。
+main 包和 internal 下的包不属于公开 API，不参与比较。
+
+OLD 和 NEW 可以是:
+  - 源码目录
+  - 项目所在 git 仓库中的修订（分支、标签或提交，用 git archive 导出到临时目录）
+  - 保存的分析结果 JSON 文件（--format json 生成，只能比较其中的结构体；两边需要都是 JSON）
+省略 NEW 时与项目当前的工作目录比较。同名的目录优先于 git 修订。
+
+存在不兼容的变化时退出码为 1，可直接用于 CI。
+
+示例:
+  go-struct-analyzer apidiff -p ./mylib v1.2.0
+  go-struct-analyzer apidiff -p ./mylib v1.2.0 main --format sarif -o apidiff.sarif
+  go-struct-analyzer apidiff ./old-checkout ./new-checkout
+  go-struct-analyzer apidiff old_report.json new_report.json --format json`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  runAPIDiff,
+}
+
+func init() {
+	apidiffCmd.Flags().StringVar(&apidiffFormat, "format", "markdown", "输出格式: markdown, json, sarif")
+	apidiffCmd.Flags().StringVarP(&apidiffOutput, "output", "o", "", "输出文件路径（默认输出到终端）")
+	apidiffCmd.Flags().StringVarP(&projectPath, "project", "p", ".", "项目路径（git 修订所在的仓库，以及省略 NEW 时的新版本）")
+	apidiffCmd.Flags().StringVar(&configPath, "config", "", "配置文件路径（默认自动查找）")
+	apidiffCmd.Flags().StringVar(&profile, "profile", "", "使用配置文件中的命名 profile")
+	rootCmd.AddCommand(apidiffCmd)
+}
+
+func runAPIDiff(cmd *cobra.Command, args []string) {
+	switch apidiffFormat {
+	case "markdown", "json", "sarif":
+	default:
+		fmt.Fprintf(os.Stderr, "错误: 不支持的输出格式 %q（可选 markdown、json、sarif）\n", apidiffFormat)
+		os.Exit(1)
+	}
+	cfg := mustLoadConfig(cmd)
+
+	absProjectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法解析项目路径: %v\n", err)
+		os.Exit(1)
+	}
+
+	oldArg, newArg := args[0], ""
+	if len(args) > 1 {
+		newArg = args[1]
+	}
+	if isJSONSource(oldArg) != isJSONSource(newArg) {
+		fmt.Fprintln(os.Stderr, "错误: 分析结果 JSON 只能与分析结果 JSON 比较")
+		os.Exit(1)
+	}
+
+	// 1. 收集两个版本的导出 API
+	oldAPI, err := loadAPISnapshot(oldArg, absProjectPath, cfg.Verbose)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 加载 %s 失败: %v\n", oldArg, err)
+		os.Exit(1)
+	}
+	newAPI, err := loadAPISnapshot(newArg, absProjectPath, cfg.Verbose)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 加载 %s 失败: %v\n", apiSourceName(newArg, absProjectPath), err)
+		os.Exit(1)
+	}
+
+	// 2. 比较
+	result := analyzer.DiffAPI(oldAPI, newAPI)
+	result.Old = oldArg
+	result.New = apiSourceName(newArg, absProjectPath)
+
+	// 3. 输出
+	var content string
+	switch apidiffFormat {
+	case "json":
+		content, err = reporter.NewJSONReporter().GenerateAPIDiff(&result)
+		content += "\n"
+	case "sarif":
+		content, err = reporter.NewSARIFReporter().GenerateAPIDiff(&result)
+		content += "\n"
+	default:
+		content = reporter.NewMarkdownReporter().GenerateAPIDiff(&result)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 生成 %s 失败: %v\n", apidiffFormat, err)
+		os.Exit(1)
+	}
+
+	if apidiffOutput == "" {
+		fmt.Print(content)
+	} else {
+		if err := os.WriteFile(apidiffOutput, []byte(content), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 写入输出文件失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("API 差异已生成: %s（不兼容 %d，兼容 %d）\n", apidiffOutput, result.Breaking, result.Compatible)
+	}
+
+	if result.Breaking > 0 {
+		os.Exit(1)
+	}
+}
+
+// isJSONSource 判断输入是否为保存的分析结果 JSON 文件
+func isJSONSource(arg string) bool {
+	if !strings.HasSuffix(arg, ".json") {
+		return false
+	}
+	info, err := os.Stat(arg)
+	return err == nil && !info.IsDir()
+}
+
+// apiSourceName 返回输入的展示名称，省略时为项目路径
+func apiSourceName(arg, projectDir string) string {
+	if arg == "" {
+		return projectDir
+	}
+	return arg
+}
+
+// loadAPISnapshot 从分析结果 JSON、源码目录或 git 修订中收集导出 API（arg 为空表示项目目录）
+func loadAPISnapshot(arg, projectDir string, verbose bool) (*analyzer.APISnapshot, error) {
+	if isJSONSource(arg) {
+		result, err := reporter.NewJSONReporter().LoadFromFile(arg)
+		if err != nil {
+			return nil, err
+		}
+		return analyzer.APISnapshotFromResult(result), nil
+	}
+
+	dir := projectDir
+	if arg != "" {
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			dir = arg
+		} else {
+			treeDir, cleanup, err := analyzer.ExtractRevision(projectDir, arg)
+			if err != nil {
+				return nil, err
+			}
+			defer cleanup()
+			dir = treeDir
+		}
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := parser.NewParser(verbose)
+	if err := p.ParseProject(absDir); err != nil {
+		return nil, err
+	}
+	return analyzer.NewAPISnapshot(p, absDir), nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+var (
+	baselinePath  string
+	writeBaseline bool
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "按配置文件中的架构分层、死代码和成员使用规则检查项目（用于 CI）",
+	Long: `分析项目中全部结构体的依赖边，按配置文件 architecture 节中的分层和允许/禁止规则检查，
+列出违规依赖及其依据。reachability.check 为 true 时，从根不可达的结构体、接口和未使用的
+构造函数同样视为违规；members.check 为 true 时，没有被其他包使用的导出成员和没有被使用的
+未导出成员同样视为违规。存在未被基线忽略的违规时以非零状态码退出。
+
+示例:
+  go-struct-analyzer check -p ./myapp
+  go-struct-analyzer check -p ./myapp --write-baseline   # 记录当前违规为基线
+  go-struct-analyzer check -p ./myapp --baseline ./arch-baseline.json`,
+	Run: runCheck,
+}
+
+func init() {
+	checkCmd.Flags().StringVarP(&projectPath, "project", "p", ".", "项目路径")
+	checkCmd.Flags().StringVarP(&blacklistPath, "blacklist", "b", "", "黑名单文件路径")
+	checkCmd.Flags().StringVar(&baselinePath, "baseline", "", "基线文件路径（覆盖配置文件中的 architecture.baseline）")
+	checkCmd.Flags().BoolVar(&writeBaseline, "write-baseline", false, "将当前全部违规写入基线文件后退出")
+	checkCmd.Flags().StringVar(&configPath, "config", "", "配置文件路径（默认自动查找）")
+	checkCmd.Flags().StringVar(&profile, "profile", "", "使用配置文件中的命名 profile")
+	rootCmd.AddCommand(checkCmd)
+}
+
+func runCheck(cmd *cobra.Command, args []string) {
+	cfg := mustLoadConfig(cmd)
+	if len(cfg.Architecture.Layers) == 0 && !cfg.Reachability.Check && !cfg.Members.Check {
+		fmt.Fprintln(os.Stderr, "错误: 配置文件中没有 architecture.layers 分层定义，也没有启用 reachability.check 或 members.check")
+		os.Exit(1)
+	}
+	if cmd.Flags().Changed("baseline") {
+		cfg.Architecture.Baseline = baselinePath
+	}
+
+	absProjectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法解析项目路径: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := parser.NewParser(cfg.Verbose)
+	if err := p.ParseProject(absProjectPath); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 解析项目失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	blacklist, err := cfg.LoadBlacklist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 加载黑名单失败: %v\n", err)
+	}
+	checker, err := analyzer.NewLayerChecker(p, cfg.Architecture)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 分层规则无效: %v\n", err)
+		os.Exit(1)
+	}
+	roles, err := analyzer.NewRoleClassifier(p, cfg.Roles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 角色规则无效: %v\n", err)
+		os.Exit(1)
+	}
+	checker.SetRoleClassifier(roles)
+
+	filter := analyzer.NewScopeFilter(p, blacklist)
+	violations := analyzer.CheckArchitecture(p, filter, checker)
+	if cfg.Reachability.Check {
+		report, err := analyzer.FindDeadCode(p, filter, cfg.Reachability.Roots)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 死代码规则无效: %v\n", err)
+			os.Exit(1)
+		}
+		violations = append(violations, analyzer.DeadCodeViolations(report, absProjectPath)...)
+	}
+	if cfg.Members.Check {
+		violations = append(violations, analyzer.UnusedMemberViolations(analyzer.FindUnusedMembers(p, filter), absProjectPath)...)
+	}
+
+	if writeBaseline {
+		if cfg.Architecture.Baseline == "" {
+			fmt.Fprintln(os.Stderr, "错误: 未指定基线文件（--baseline 或 architecture.baseline）")
+			os.Exit(1)
+		}
+		if err := analyzer.SaveBaseline(cfg.Architecture.Baseline, violations); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 保存基线失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已将 %d 处违规写入基线: %s\n", len(violations), cfg.Architecture.Baseline)
+		return
+	}
+
+	remaining := violations
+	var suppressed, stale []types.LayerViolation
+	if cfg.Architecture.Baseline != "" {
+		baseline, err := analyzer.LoadBaseline(cfg.Architecture.Baseline)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 读取基线失败: %v\n", err)
+			os.Exit(1)
+		}
+		remaining, suppressed, stale = baseline.Apply(violations)
+	}
+
+	for _, v := range remaining {
+		if v.To == "" {
+			fmt.Printf("[%s] %s (包 %s)\n", v.Rule, v.From, v.Context)
+			fmt.Printf("    %s\n", v.Reason)
+		} else {
+			fmt.Printf("[%s -> %s] %s -> %s (%s: %s)\n", v.FromLayer, v.ToLayer, v.From, v.To, v.DepType, v.Context)
+			fmt.Printf("    %s: %s\n", v.Rule, v.Reason)
+		}
+		if v.FilePath != "" {
+			fmt.Printf("    %s\n", v.FilePath)
+		}
+	}
+
+	if len(suppressed) > 0 {
+		fmt.Printf("\n基线已忽略 %d 处已知违规\n", len(suppressed))
+	}
+	if len(stale) > 0 {
+		fmt.Printf("基线中有 %d 处违规已修复，可以重新生成基线（--write-baseline）\n", len(stale))
+	}
+
+	if len(remaining) > 0 {
+		fmt.Printf("\n发现 %d 处违规\n", len(remaining))
+		os.Exit(1)
+	}
+	fmt.Println("\n检查通过")
+}
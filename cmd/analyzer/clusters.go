@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/reporter"
+)
+
+var (
+	clustersFormat     string
+	clustersOutput     string
+	clustersDepTypes   []string
+	clustersMermaid    string
+	clustersVisualizer string
+)
+
+var clustersCmd = &cobra.Command{
+	Use:   "clusters",
+	Short: "对结构体依赖图做社区划分，给出模块边界建议",
+	Long: `在项目全部结构体的依赖图上运行 Louvain 社区划分（两个结构体之间的权重为依赖数），
+并与实际的包布局比较:
+  - 位置不当的结构体：位于一个包，却与另一个包的结构体聚在一起
+  - 建议拆分的包：包中的结构体分散在多个社区
+同时给出检测到的划分与包布局的模块度，可作为拆分单体的数据参考。
+
+示例:
+  go-struct-analyzer clusters -p ./myapp
+  go-struct-analyzer clusters -p ./myapp --dep-types field,embed,constructor -o clusters.md
+  go-struct-analyzer clusters -p ./myapp --mermaid clusters.mmd --visualizer clusters.json
+  go-struct-analyzer clusters -p ./myapp --format json`,
+	Run: runClusters,
+}
+
+func init() {
+	clustersCmd.Flags().StringVar(&clustersFormat, "format", "markdown", "输出格式: markdown, json")
+	clustersCmd.Flags().StringVarP(&clustersOutput, "output", "o", "", "输出文件路径（默认输出到终端）")
+	clustersCmd.Flags().StringSliceVar(&clustersDepTypes, "dep-types", nil, "只使用这些依赖类型的边（逗号分隔，如 field,constructor）")
+	clustersCmd.Flags().StringVar(&clustersMermaid, "mermaid", "", "将按社区着色的 Mermaid 图写入文件")
+	clustersCmd.Flags().StringVar(&clustersVisualizer, "visualizer", "", "将按社区着色的可视化 JSON 写入文件")
+	clustersCmd.Flags().StringVarP(&projectPath, "project", "p", ".", "项目路径")
+	clustersCmd.Flags().StringVarP(&blacklistPath, "blacklist", "b", "", "黑名单文件路径")
+	clustersCmd.Flags().StringVar(&configPath, "config", "", "配置文件路径（默认自动查找）")
+	clustersCmd.Flags().StringVar(&profile, "profile", "", "使用配置文件中的命名 profile")
+	rootCmd.AddCommand(clustersCmd)
+}
+
+func runClusters(cmd *cobra.Command, args []string) {
+	if clustersFormat != "markdown" && clustersFormat != "json" {
+		fmt.Fprintf(os.Stderr, "错误: 不支持的输出格式 %q（可选 markdown、json）\n", clustersFormat)
+		os.Exit(1)
+	}
+	cfg := mustLoadConfig(cmd)
+
+	absProjectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法解析项目路径: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := parser.NewParser(cfg.Verbose)
+	if err := p.ParseProject(absProjectPath); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 解析项目失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	blacklist, err := cfg.LoadBlacklist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 加载黑名单失败: %v\n", err)
+	}
+	filter := analyzer.NewScopeFilter(p, blacklist)
+
+	result := analyzer.DetectClusters(p, filter, clustersDepTypes)
+
+	// 1. 图
+	if clustersMermaid != "" {
+		mermaid := reporter.NewMermaidGenerator().GenerateClusters(&result)
+		if err := os.WriteFile(clustersMermaid, []byte(mermaid), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 写入 Mermaid 图失败: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if clustersVisualizer != "" {
+		vizReporter := reporter.NewVisualizerReporter()
+		vizOutput := vizReporter.GenerateClusters(&result)
+		vizOutput.ProjectPath = absProjectPath
+		if err := vizReporter.SaveToFile(vizOutput, clustersVisualizer); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 保存可视化 JSON 失败: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// 2. 报告
+	var content string
+	if clustersFormat == "json" {
+		content, err = reporter.NewJSONReporter().GenerateClusters(&result)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 生成 JSON 失败: %v\n", err)
+			os.Exit(1)
+		}
+		content += "\n"
+	} else {
+		content = reporter.NewMarkdownReporter().GenerateClusters(&result)
+	}
+
+	if clustersOutput == "" {
+		fmt.Print(content)
+		return
+	}
+	if err := os.WriteFile(clustersOutput, []byte(content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 写入输出文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("模块边界建议已生成: %s（社区 %d，位置不当 %d，建议拆分 %d）\n",
+		clustersOutput, len(result.Clusters), len(result.Misplaced), len(result.SplitPackages))
+}
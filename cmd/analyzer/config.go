@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/user/go-struct-analyzer/internal/config"
+)
+
+var (
+	configPath string
+	profile    string
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "查看和校验项目配置文件 " + config.FileName,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "校验配置文件",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := mustLoadConfig(cmd)
+		if cfg.Source == "" {
+			fmt.Printf("未找到配置文件 %s，使用默认配置\n", config.FileName)
+			return
+		}
+		fmt.Printf("配置有效: %s\n", cfg.Source)
+		if names := cfg.ProfileNames(); len(names) > 0 {
+			fmt.Printf("可用 profile: %v\n", names)
+		}
+	},
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "输出生效的配置（应用 profile 后）",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := mustLoadConfig(cmd)
+		content, err := cfg.YAML()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			os.Exit(1)
+		}
+		if cfg.Source != "" {
+			fmt.Printf("# 来源: %s\n", cfg.Source)
+		}
+		if cfg.Profile != "" {
+			fmt.Printf("# profile: %s\n", cfg.Profile)
+		}
+		fmt.Print(content)
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{configValidateCmd, configPrintCmd} {
+		cmd.Flags().StringVarP(&projectPath, "project", "p", ".", "项目路径，从该目录向上查找配置文件")
+		cmd.Flags().StringVar(&configPath, "config", "", "配置文件路径（默认自动查找 "+config.FileName+"）")
+		cmd.Flags().StringVar(&profile, "profile", "", "使用配置文件中的命名 profile")
+		configCmd.AddCommand(cmd)
+	}
+	rootCmd.AddCommand(configCmd)
+}
+
+// loadConfig 加载生效的配置：默认值 < 配置文件 < profile < 命令行参数
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	absProjectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析项目路径: %w", err)
+	}
+
+	path := configPath
+	if path == "" {
+		path = config.Find(absProjectPath)
+	}
+
+	cfg := config.Default()
+	if path != "" {
+		if cfg, err = config.Load(path, profile); err != nil {
+			return nil, err
+		}
+	} else if profile != "" {
+		return nil, fmt.Errorf("未找到配置文件 %s，无法使用 profile %q", config.FileName, profile)
+	}
+
+	applyFlags(cmd, cfg)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// mustLoadConfig 加载配置，失败时退出
+func mustLoadConfig(cmd *cobra.Command) *config.Config {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 加载配置失败: %v\n", err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// applyFlags 用显式指定的命令行参数覆盖配置
+func applyFlags(cmd *cobra.Command, cfg *config.Config) {
+	flags := cmd.Flags()
+	if flags.Changed("start") {
+		cfg.Start = startStruct
+	}
+	if flags.Changed("depth") {
+		cfg.Depth = depth
+	}
+	if flags.Changed("output") {
+		cfg.Output = outputPath
+	}
+	if flags.Changed("format") {
+		cfg.Format = format
+	}
+	if flags.Changed("blacklist") {
+		cfg.Blacklist = blacklistPath
+	}
+	if flags.Changed("llm") {
+		cfg.LLM.Provider = llmProvider
+	}
+	if flags.Changed("model") {
+		cfg.LLM.Model = llmModel
+	}
+	if flags.Changed("mermaid") {
+		cfg.Mermaid = mermaidPath
+	}
+	if flags.Changed("visualizer") {
+		cfg.Visualizer = visualizerPath
+	}
+	if flags.Changed("package-mermaid") {
+		cfg.PackageMermaid = pkgMermaid
+	}
+	if flags.Changed("package-visualizer") {
+		cfg.PackageVisualizer = pkgVisualizer
+	}
+	if flags.Changed("no-cache") {
+		cfg.LLM.Cache = !noCache
+	}
+	if flags.Changed("verbose") {
+		cfg.Verbose = verbose
+	}
+	if flags.Changed("cycle-types") {
+		cfg.Cycles.DepTypes = cycleTypes
+	}
+	if flags.Changed("cycle-limit") {
+		cfg.Cycles.Limit = cycleLimit
+	}
+	if flags.Changed("metrics-sort") {
+		cfg.MetricsSort = metricsSort
+	}
+	if flags.Changed("rank-by") {
+		cfg.RankBy = rankBy
+	}
+	if flags.Changed("project-centrality") {
+		cfg.ProjectCentrality = projectCentral
+	}
+	if flags.Changed("deadcode") {
+		cfg.Reachability.Report = deadCode
+	}
+	if flags.Changed("members") {
+		cfg.Members.Report = members
+	}
+	if flags.Changed("layout") {
+		cfg.Layout = layout
+	}
+	if flags.Changed("arch") {
+		cfg.Arch = arch
+	}
+	if flags.Changed("explain") {
+		cfg.Explain = explain
+	}
+	if flags.Changed("external") {
+		cfg.External.Enabled = external
+	}
+	if flags.Changed("external-vendor") {
+		cfg.External.Vendor = externalVendor
+	}
+	if flags.Changed("gomodcache") {
+		cfg.External.GoModCache = goModCache
+	}
+}
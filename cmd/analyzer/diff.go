@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/reporter"
+)
+
+var (
+	diffFormat  string
+	diffOutput  string
+	diffMermaid string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff OLD.json NEW.json",
+	Short: "对比两次分析结果（跟踪架构漂移）",
+	Long: `比较两次 --format json 保存的分析结果，列出新增和删除的结构体、字段、方法和依赖边，
+深度变化，新出现和已消除的循环依赖，以及结构体和包的度量变化。
+
+Markdown 输出适合作为 PR 评论，依赖边有变化时附带 Mermaid 图（新增为绿色，删除为红色）。
+配合定时运行的分析，可以跟踪架构随时间的漂移。
+
+示例:
+  go-struct-analyzer diff last_week.json today.json
+  go-struct-analyzer diff base.json head.json -o drift.md --mermaid drift.mmd
+  go-struct-analyzer diff base.json head.json --format json`,
+	Args: cobra.ExactArgs(2),
+	Run:  runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFormat, "format", "markdown", "输出格式: markdown, json")
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "输出文件路径（默认输出到终端）")
+	diffCmd.Flags().StringVar(&diffMermaid, "mermaid", "", "将依赖变化的 Mermaid 图写入文件")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) {
+	if diffFormat != "markdown" && diffFormat != "json" {
+		fmt.Fprintf(os.Stderr, "错误: 不支持的输出格式 %q（可选 markdown、json）\n", diffFormat)
+		os.Exit(1)
+	}
+
+	jsonReporter := reporter.NewJSONReporter()
+	oldResult, err := jsonReporter.LoadFromFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 读取分析结果失败: %v\n", err)
+		os.Exit(1)
+	}
+	newResult, err := jsonReporter.LoadFromFile(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 读取分析结果失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	diff := analyzer.DiffResults(oldResult, newResult)
+	diff.Old, diff.New = args[0], args[1]
+
+	var content string
+	if diffFormat == "json" {
+		content, err = jsonReporter.GenerateDiff(&diff)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 生成 JSON 失败: %v\n", err)
+			os.Exit(1)
+		}
+		content += "\n"
+	} else {
+		content = reporter.NewMarkdownReporter().GenerateDiff(&diff)
+	}
+
+	if diffMermaid != "" {
+		if err := os.WriteFile(diffMermaid, []byte(reporter.NewMermaidGenerator().GenerateDiff(&diff)), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 写入 Mermaid 图失败: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if diffOutput == "" {
+		fmt.Print(content)
+		return
+	}
+	if err := os.WriteFile(diffOutput, []byte(content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 写入输出文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("对比结果已生成: %s\n", diffOutput)
+}
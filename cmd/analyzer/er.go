@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/reporter"
+)
+
+var (
+	erFormat string
+	erOutput string
+)
+
+var erCmd = &cobra.Command{
+	Use:   "er",
+	Short: "从任一实体出发生成持久化模型的 ER 图",
+	Long: `从起点实体出发，沿类型为项目结构体的字段收集持久化模型，按 gorm 约定推断关联:
+  - 带 many2many 标签的字段: 多对多（通过连接表）
+  - 切片字段: has-many（外键默认为 实体名+ID，可用 foreignKey 指定）
+  - 单个字段: 本实体有外键字段（字段名+ID 或 foreignKey）时为 belongs-to，否则为 has-one
+嵌入字段（包括 gorm.Model）和带 embedded 标签的字段展开为列，列名取 gorm column 设置或字段名的 snake_case。
+
+示例:
+  go-struct-analyzer er -p ./myapp -s User
+  go-struct-analyzer er -p ./myapp -s Order -o order-er.mmd
+  go-struct-analyzer er -p ./myapp -s User --format markdown -o er.md`,
+	Run: runER,
+}
+
+func init() {
+	erCmd.Flags().StringVarP(&startStruct, "start", "s", "", "起点实体名称（默认使用配置文件中的 start）")
+	erCmd.Flags().StringVar(&erFormat, "format", "mermaid", "输出格式: mermaid, markdown, json")
+	erCmd.Flags().StringVarP(&erOutput, "output", "o", "", "输出文件路径（默认输出到终端）")
+	erCmd.Flags().StringVarP(&projectPath, "project", "p", ".", "项目路径")
+	erCmd.Flags().StringVarP(&blacklistPath, "blacklist", "b", "", "黑名单文件路径")
+	erCmd.Flags().StringVar(&configPath, "config", "", "配置文件路径（默认自动查找）")
+	erCmd.Flags().StringVar(&profile, "profile", "", "使用配置文件中的命名 profile")
+	rootCmd.AddCommand(erCmd)
+}
+
+func runER(cmd *cobra.Command, args []string) {
+	if erFormat != "mermaid" && erFormat != "markdown" && erFormat != "json" {
+		fmt.Fprintf(os.Stderr, "错误: 不支持的输出格式 %q（可选 mermaid、markdown、json）\n", erFormat)
+		os.Exit(1)
+	}
+	cfg := mustLoadConfig(cmd)
+	if cfg.Start == "" {
+		fmt.Fprintln(os.Stderr, "错误: 未指定起点实体（--start 或配置文件中的 start）")
+		os.Exit(1)
+	}
+
+	absProjectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法解析项目路径: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := parser.NewParser(cfg.Verbose)
+	if err := p.ParseProject(absProjectPath); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 解析项目失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	blacklist, err := cfg.LoadBlacklist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 加载黑名单失败: %v\n", err)
+	}
+	filter := analyzer.NewScopeFilter(p, blacklist)
+
+	model, err := analyzer.BuildERModel(p, filter, cfg.Start)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		os.Exit(1)
+	}
+
+	var content string
+	switch erFormat {
+	case "json":
+		content, err = reporter.NewJSONReporter().GenerateER(&model)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 生成 JSON 失败: %v\n", err)
+			os.Exit(1)
+		}
+		content += "\n"
+	case "markdown":
+		content = reporter.NewMarkdownReporter().GenerateER(&model)
+	default:
+		content = reporter.NewMermaidGenerator().GenerateER(&model)
+	}
+
+	if erOutput == "" {
+		fmt.Print(content)
+		return
+	}
+	if err := os.WriteFile(erOutput, []byte(content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 写入输出文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("ER 图已生成: %s（实体 %d，关联 %d）\n", erOutput, len(model.Entities), len(model.Relations))
+}
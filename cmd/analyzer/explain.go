@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/parser"
+)
+
+var explainTypeCmd = &cobra.Command{
+	Use:   "explain-type <name>",
+	Short: "解释某个类型为什么被保留或忽略",
+	Long: `扫描项目中全部结构体，列出指定类型作为候选依赖出现的每个位置，
+以及范围过滤器对它的判定规则（内置类型、标准库、黑名单、小写、未找到等）。
+
+示例:
+  go-struct-analyzer explain-type -p ./myapp Config
+  go-struct-analyzer explain-type -p ./myapp gorm.DB --external`,
+	Args: cobra.ExactArgs(1),
+	Run:  runExplainType,
+}
+
+func init() {
+	explainTypeCmd.Flags().StringVarP(&projectPath, "project", "p", ".", "项目路径")
+	explainTypeCmd.Flags().StringVarP(&blacklistPath, "blacklist", "b", "", "黑名单文件路径")
+	explainTypeCmd.Flags().BoolVar(&external, "external", false, "保留第三方模块类型作为叶子节点")
+	explainTypeCmd.Flags().StringVar(&configPath, "config", "", "配置文件路径（默认自动查找）")
+	explainTypeCmd.Flags().StringVar(&profile, "profile", "", "使用配置文件中的命名 profile")
+	rootCmd.AddCommand(explainTypeCmd)
+}
+
+func runExplainType(cmd *cobra.Command, args []string) {
+	typeName := args[0]
+	cfg := mustLoadConfig(cmd)
+
+	absProjectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法解析项目路径: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := parser.NewParser(cfg.Verbose)
+	if err := p.ParseProject(absProjectPath); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 解析项目失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	blacklist, err := cfg.LoadBlacklist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 加载黑名单失败: %v\n", err)
+	}
+	filter := analyzer.NewScopeFilter(p, blacklist)
+	filter.SetExternalOptions(analyzer.ExternalOptions{
+		Enabled:   cfg.External.Enabled,
+		UseVendor: cfg.External.Vendor,
+		ModCache:  cfg.External.GoModCache,
+	})
+
+	// 1. 类型定义
+	fmt.Printf("类型: %s\n", typeName)
+	if info := p.GetStruct(typeName); info != nil {
+		fmt.Printf("定义: 结构体，包 %s，%s\n", info.Package, relPath(absProjectPath, info.FilePath))
+	} else if iface := p.GetInterface(typeName); iface != nil {
+		fmt.Printf("定义: 接口，包 %s，%s\n", iface.Package, relPath(absProjectPath, iface.FilePath))
+	} else {
+		fmt.Println("定义: 项目中未找到（GetStruct/GetInterface 均未命中）")
+	}
+
+	// 2. 不带文件上下文的直接判定
+	accepted, rule, detail := filter.Classify(typeName, "")
+	fmt.Printf("直接判定: %s [%s] %s\n", verdict(accepted), rule, detail)
+	if filter.ShouldStop(typeName) {
+		fmt.Println("遍历: 命中 stop 规则，节点保留但不继续展开")
+	}
+
+	// 3. 作为候选依赖出现的位置
+	decisions := analyzer.ExplainType(p, filter, typeName)
+	fmt.Printf("\n出现位置 (%d):\n", len(decisions))
+	for _, d := range decisions {
+		location := d.From + " " + d.Context
+		if d.FilePath != "" {
+			location += " (" + relPath(absProjectPath, d.FilePath) + ")"
+		}
+		fmt.Printf("  %s [%s] %s\n      %s, 依赖类型 %s, 写法 %s\n",
+			verdict(d.Accepted), d.Rule, d.Detail, location, d.DepType, d.Type)
+	}
+}
+
+// verdict 返回判定结果的文字描述
+func verdict(accepted bool) string {
+	if accepted {
+		return "保留"
+	}
+	return "忽略"
+}
+
+// relPath 返回相对项目根目录的路径，失败时返回原路径
+func relPath(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return rel
+	}
+	return path
+}
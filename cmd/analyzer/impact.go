@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/reporter"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+var (
+	impactBase     string
+	impactDepth    int
+	impactDepTypes []string
+	impactFormat   string
+	impactOutput   string
+)
+
+var impactCmd = &cobra.Command{
+	Use:   "impact [file...]",
+	Short: "分析改动影响了哪些结构体（用于确定评审和集成测试范围）",
+	Long: `将改动的行映射到所在的结构体、接口、方法和构造函数声明，再沿反向依赖
+列出所有受影响的类型。
+
+不指定文件时，在项目目录下运行 git diff，对比 --base 与 HEAD 的合并基点（包括未提交的改动，未跟踪的新 Go 文件整个视为改动）；
+指定文件时（相对项目路径），将这些文件中的全部声明视为改动。
+
+示例:
+  go-struct-analyzer impact -p ./myapp --base main
+  go-struct-analyzer impact -p ./myapp --base origin/main --depth 2 --format json
+  go-struct-analyzer impact -p ./myapp service/user.go repository/user.go`,
+	Run: runImpact,
+}
+
+func init() {
+	impactCmd.Flags().StringVar(&impactBase, "base", "main", "对比的 git 基准分支或提交")
+	impactCmd.Flags().IntVar(&impactDepth, "depth", analyzer.DefaultImpactDepth, "反向依赖的最大深度（0 表示不限制）")
+	impactCmd.Flags().StringSliceVar(&impactDepTypes, "dep-types", nil, "只沿这些依赖类型的边传播（逗号分隔，如 field,constructor）")
+	impactCmd.Flags().StringVar(&impactFormat, "format", "markdown", "输出格式: markdown, json")
+	impactCmd.Flags().StringVarP(&impactOutput, "output", "o", "", "输出文件路径（默认输出到终端）")
+	impactCmd.Flags().StringVarP(&projectPath, "project", "p", ".", "项目路径")
+	impactCmd.Flags().StringVarP(&blacklistPath, "blacklist", "b", "", "黑名单文件路径")
+	impactCmd.Flags().BoolVar(&external, "external", false, "保留第三方模块类型作为叶子节点")
+	impactCmd.Flags().StringVar(&configPath, "config", "", "配置文件路径（默认自动查找）")
+	impactCmd.Flags().StringVar(&profile, "profile", "", "使用配置文件中的命名 profile")
+	rootCmd.AddCommand(impactCmd)
+}
+
+func runImpact(cmd *cobra.Command, args []string) {
+	if impactFormat != "markdown" && impactFormat != "json" {
+		fmt.Fprintf(os.Stderr, "错误: 不支持的输出格式 %q（可选 markdown、json）\n", impactFormat)
+		os.Exit(1)
+	}
+	cfg := mustLoadConfig(cmd)
+
+	absProjectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法解析项目路径: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 1. 收集改动
+	var changes []types.FileChange
+	base := ""
+	if len(args) > 0 {
+		for _, f := range args {
+			if !filepath.IsAbs(f) {
+				f = filepath.Join(absProjectPath, f)
+			}
+			changes = append(changes, types.FileChange{Path: filepath.Clean(f)})
+		}
+	} else {
+		base = impactBase
+		changes, err = analyzer.GitChanges(absProjectPath, base)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 获取 git 改动失败: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// 2. 解析项目
+	p := parser.NewParser(cfg.Verbose)
+	if err := p.ParseProject(absProjectPath); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 解析项目失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	blacklist, err := cfg.LoadBlacklist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 加载黑名单失败: %v\n", err)
+	}
+	filter := analyzer.NewScopeFilter(p, blacklist)
+	filter.SetExternalOptions(analyzer.ExternalOptions{
+		Enabled:   cfg.External.Enabled,
+		UseVendor: cfg.External.Vendor,
+		ModCache:  cfg.External.GoModCache,
+	})
+
+	// 3. 分析影响，输出中的路径相对项目根目录
+	result := analyzer.AnalyzeImpact(p, filter, analyzer.ImpactQuery{
+		Changes:  changes,
+		MaxDepth: impactDepth,
+		DepTypes: impactDepTypes,
+	})
+	result.Base = base
+	for i := range result.Files {
+		result.Files[i] = relPath(absProjectPath, result.Files[i])
+	}
+	for i := range result.Changed {
+		result.Changed[i].FilePath = relPath(absProjectPath, result.Changed[i].FilePath)
+	}
+	for i := range result.Impacted {
+		result.Impacted[i].FilePath = relPath(absProjectPath, result.Impacted[i].FilePath)
+	}
+
+	// 4. 输出
+	var content string
+	if impactFormat == "json" {
+		content, err = reporter.NewJSONReporter().GenerateImpact(&result)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 生成 JSON 失败: %v\n", err)
+			os.Exit(1)
+		}
+		content += "\n"
+	} else {
+		content = reporter.NewMarkdownReporter().GenerateImpact(&result)
+	}
+
+	if impactOutput == "" {
+		fmt.Print(content)
+		return
+	}
+	if err := os.WriteFile(impactOutput, []byte(content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 写入输出文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("影响分析已生成: %s\n", impactOutput)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/reporter"
+)
+
+var (
+	wirePath    string
+	wirePackage string
+)
+
+var initOrderCmd = &cobra.Command{
+	Use:   "init-order",
+	Short: "计算构造起点结构体所需的结构体构造顺序",
+	Long: `沿字段、嵌入和构造函数参数依赖，从叶子开始计算结构体的构造顺序，
+依赖总是先于使用者构造。接口依赖在只有一个实现时使用该实现。
+依赖环上的结构体无法构造，会列出对应的环。
+
+指定 --wire 时生成类似 wire.go 的初始化函数：按顺序调用检测到的 New* 构造函数，
+无法解析的参数生成带 TODO 的占位变量。
+
+示例:
+  go-struct-analyzer init-order -p ./myapp -s App
+  go-struct-analyzer init-order -p ./myapp -s App --wire ./cmd/app/wire_gen.go --wire-package main`,
+	Run: runInitOrder,
+}
+
+func init() {
+	initOrderCmd.Flags().StringVarP(&startStruct, "start", "s", "", "根结构体名称（默认使用配置文件中的 start）")
+	initOrderCmd.Flags().StringVar(&wirePath, "wire", "", "生成初始化函数的输出文件路径")
+	initOrderCmd.Flags().StringVar(&wirePackage, "wire-package", "main", "生成的初始化函数所在的包名")
+	initOrderCmd.Flags().StringVarP(&projectPath, "project", "p", ".", "项目路径")
+	initOrderCmd.Flags().StringVarP(&blacklistPath, "blacklist", "b", "", "黑名单文件路径")
+	initOrderCmd.Flags().StringVar(&configPath, "config", "", "配置文件路径（默认自动查找）")
+	initOrderCmd.Flags().StringVar(&profile, "profile", "", "使用配置文件中的命名 profile")
+	rootCmd.AddCommand(initOrderCmd)
+}
+
+func runInitOrder(cmd *cobra.Command, args []string) {
+	cfg := mustLoadConfig(cmd)
+	if cfg.Start == "" {
+		fmt.Fprintln(os.Stderr, "错误: 未指定根结构体（--start 或配置文件中的 start）")
+		os.Exit(1)
+	}
+
+	absProjectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法解析项目路径: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := parser.NewParser(cfg.Verbose)
+	if err := p.ParseProject(absProjectPath); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 解析项目失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	blacklist, err := cfg.LoadBlacklist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 加载黑名单失败: %v\n", err)
+	}
+	filter := analyzer.NewScopeFilter(p, blacklist)
+
+	order, err := analyzer.ComputeInitOrder(p, filter, cfg.Start)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 1. 构造顺序
+	fmt.Printf("构造顺序（根 %s，共 %d 步）:\n", order.Root, len(order.Steps))
+	for i, step := range order.Steps {
+		ctor := step.Constructor
+		if ctor == "" {
+			ctor = "无构造函数"
+		}
+		fmt.Printf("  %d. %s.%s  [%s]", i+1, step.Package, step.Struct, ctor)
+		if len(step.Deps) > 0 {
+			fmt.Printf("  依赖: %s", strings.Join(step.Deps, ", "))
+		}
+		fmt.Println()
+		for _, param := range step.Params {
+			if param.Source == "" && !param.Variadic {
+				fmt.Printf("       未解析参数: %s %s\n", param.Name, param.Type)
+			}
+		}
+	}
+
+	// 2. 接口绑定
+	if len(order.Interfaces) > 0 {
+		fmt.Println("\n接口:")
+		for _, b := range order.Interfaces {
+			switch {
+			case b.Implementer != "":
+				fmt.Printf("  %s -> %s\n", b.Interface, b.Implementer)
+			case len(b.Candidates) == 0:
+				fmt.Printf("  %s: 没有实现\n", b.Interface)
+			default:
+				fmt.Printf("  %s: 多个实现（%s），需要手动选择\n", b.Interface, strings.Join(b.Candidates, ", "))
+			}
+		}
+	}
+
+	// 3. 无法构造的部分
+	if len(order.Cycles) > 0 {
+		fmt.Printf("\n发现 %d 个使构造无法进行的依赖环:\n", len(order.Cycles))
+		for _, cycle := range order.Cycles {
+			fmt.Printf("  %s -> %s\n", strings.Join(cycle.Nodes, " -> "), cycle.Nodes[0])
+			for _, edge := range cycle.Edges {
+				fmt.Printf("     %s -> %s [%s] %s\n", edge.From, edge.To, strings.Join(edge.Types, ", "), strings.Join(edge.Evidence, ", "))
+			}
+		}
+	}
+	if len(order.Blocked) > 0 {
+		fmt.Printf("\n无法构造的结构体: %s\n", strings.Join(order.Blocked, ", "))
+	}
+
+	// 4. 生成初始化函数
+	if wirePath != "" {
+		src, err := reporter.NewWireGenerator().Generate(&order, wirePackage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 生成初始化函数失败: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(wirePath, []byte(src), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 写入初始化函数失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n初始化函数已生成: %s\n", wirePath)
+	}
+
+	for _, name := range order.Blocked {
+		if name == order.Root {
+			os.Exit(1)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/user/go-struct-analyzer/internal/analyzer"
@@ -24,8 +25,23 @@ var (
 	llmModel       string
 	mermaidPath    string
 	visualizerPath string
+	pkgMermaid     string
+	pkgVisualizer  string
 	noCache        bool
 	verbose        bool
+	external       bool
+	externalVendor bool
+	goModCache     string
+	explain        bool
+	cycleTypes     []string
+	cycleLimit     int
+	metricsSort    string
+	rankBy         string
+	deadCode       bool
+	members        bool
+	layout         bool
+	projectCentral bool
+	arch           string
 )
 
 var rootCmd = &cobra.Command{
@@ -43,7 +59,11 @@ var rootCmd = &cobra.Command{
   go-struct-analyzer -p ./myapp -s UserService --llm glm -k $GLM_API_KEY
   go-struct-analyzer -p ./myapp -s UserService --llm claude -k $CLAUDE_API_KEY
   go-struct-analyzer -p ./myapp -s UserService -b ./blacklist.yaml -v
-  go-struct-analyzer -p ./myapp -s UserService --visualizer ./output.json`,
+  go-struct-analyzer -p ./myapp -s UserService --visualizer ./output.json
+  go-struct-analyzer -p ./myapp --profile ci
+
+项目根目录（或其上级目录）中的 .struct-analyzer.yaml 会被自动加载，
+命令行参数优先于配置文件。`,
 	Run: runAnalyzer,
 }
 
@@ -59,11 +79,27 @@ func init() {
 	rootCmd.Flags().StringVarP(&llmModel, "model", "m", "", "LLM 模型（可选，默认: glm-4-flash / claude-sonnet-4-20250514）")
 	rootCmd.Flags().StringVar(&mermaidPath, "mermaid", "", "Mermaid 图输出路径（可选）")
 	rootCmd.Flags().StringVar(&visualizerPath, "visualizer", "", "可视化工具 JSON 输出路径（可选）")
+	rootCmd.Flags().StringVar(&pkgMermaid, "package-mermaid", "", "包级依赖 Mermaid 图输出路径（可选）")
+	rootCmd.Flags().StringVar(&pkgVisualizer, "package-visualizer", "", "包级依赖可视化 JSON 输出路径（可选）")
 	rootCmd.Flags().BoolVar(&noCache, "no-cache", false, "禁用 LLM 分析结果缓存")
 	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "详细输出模式")
+	rootCmd.Flags().BoolVar(&external, "external", false, "保留第三方模块类型作为叶子节点")
+	rootCmd.Flags().BoolVar(&externalVendor, "external-vendor", false, "从 vendor/ 读取第三方结构体定义（需配合 --external）")
+	rootCmd.Flags().StringVar(&goModCache, "gomodcache", "", "从本地 GOMODCACHE 目录读取第三方结构体定义（需配合 --external）")
+	rootCmd.Flags().BoolVar(&explain, "explain", false, "在报告中附带每个候选类型的过滤判定")
+	rootCmd.Flags().StringSliceVar(&cycleTypes, "cycle-types", nil, "只检测由这些依赖类型构成的循环（如 field,embed）")
+	rootCmd.Flags().IntVar(&cycleLimit, "cycle-limit", analyzer.DefaultCycleLimit, "最多枚举的循环数量")
+	rootCmd.Flags().StringVar(&metricsSort, "metrics-sort", reporter.SortByFanIn, "度量表格排序键："+strings.Join(reporter.MetricsSortKeys(), ", "))
+	rootCmd.Flags().StringVar(&rankBy, "rank-by", reporter.SortByReach, "关键结构体排行和可视化节点大小依据的度量："+strings.Join(reporter.RankKeys(), ", "))
+	rootCmd.Flags().BoolVar(&projectCentral, "project-centrality", false, "在全项目依赖图上计算中心性度量（包括起点 BFS 之外的结构体，较慢）")
+	rootCmd.Flags().BoolVar(&deadCode, "deadcode", false, "在报告中附带死代码检测（配置了 reachability.roots 时总是附带）")
+	rootCmd.Flags().BoolVar(&members, "members", false, "在报告中附带被分析结构体的未使用成员")
+	rootCmd.Flags().BoolVar(&layout, "layout", false, "计算结构体内存布局")
+	rootCmd.Flags().StringVar(&arch, "arch", analyzer.DefaultArch, "计算结构体内存布局使用的 GOARCH（如 amd64、arm64、386，需配合 --layout）")
+	rootCmd.Flags().StringVar(&configPath, "config", "", "配置文件路径（默认从项目路径向上查找 .struct-analyzer.yaml）")
+	rootCmd.Flags().StringVar(&profile, "profile", "", "使用配置文件中的命名 profile")
 
 	rootCmd.MarkFlagRequired("project")
-	rootCmd.MarkFlagRequired("start")
 }
 
 func main() {
@@ -86,11 +122,25 @@ func runAnalyzer(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// 加载配置文件并用命令行参数覆盖
+	cfg := mustLoadConfig(cmd)
+	if cfg.Start == "" {
+		fmt.Fprintln(os.Stderr, "错误: 未指定起点结构体（--start 或配置文件中的 start）")
+		os.Exit(1)
+	}
+	verbose = cfg.Verbose
+
 	if verbose {
 		fmt.Println("=== Go 结构体依赖分析器 ===")
 		fmt.Printf("项目路径: %s\n", absProjectPath)
-		fmt.Printf("起点结构体: %s\n", startStruct)
-		fmt.Printf("分析深度: %d\n", depth)
+		if cfg.Source != "" {
+			fmt.Printf("配置文件: %s\n", cfg.Source)
+		}
+		if cfg.Profile != "" {
+			fmt.Printf("Profile: %s\n", cfg.Profile)
+		}
+		fmt.Printf("起点结构体: %s\n", cfg.Start)
+		fmt.Printf("分析深度: %d\n", cfg.Depth)
 		fmt.Println()
 	}
 
@@ -106,8 +156,8 @@ func runAnalyzer(cmd *cobra.Command, args []string) {
 	}
 
 	// 验证起点结构体存在
-	if p.GetStruct(startStruct) == nil {
-		fmt.Fprintf(os.Stderr, "错误: 未找到起点结构体 '%s'\n", startStruct)
+	if p.GetStruct(cfg.Start) == nil {
+		fmt.Fprintf(os.Stderr, "错误: 未找到起点结构体 '%s'\n", cfg.Start)
 		fmt.Fprintln(os.Stderr, "可用的结构体:")
 		for name := range p.GetAllStructs() {
 			fmt.Fprintf(os.Stderr, "  - %s\n", name)
@@ -120,33 +170,24 @@ func runAnalyzer(cmd *cobra.Command, args []string) {
 	}
 
 	// 2. 加载黑名单
-	blacklist := analyzer.NewBlacklist()
-	if blacklistPath != "" {
-		if err := blacklist.LoadFromFile(blacklistPath); err != nil {
-			fmt.Fprintf(os.Stderr, "警告: 加载黑名单失败: %v\n", err)
-		} else if verbose {
-			fmt.Printf("已加载黑名单: %v\n", blacklist.GetBlockedTypes())
-		}
+	blacklist, err := cfg.LoadBlacklist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 加载黑名单失败: %v\n", err)
+	} else if verbose && (cfg.Blacklist != "" || len(blacklist.GetBlockedTypes()) > 0) {
+		fmt.Printf("已加载黑名单: %v\n", blacklist.GetBlockedTypes())
 	}
 
 	// 3. 创建 LLM 客户端（可选）
 	var llmClient llm.LLMClient
 	effectiveAPIKey := apiKey
 
-	// 根据 LLM 后端选择环境变量
+	// 根据 LLM 后端（或配置的 api_key_env）选择环境变量
 	if effectiveAPIKey == "" {
-		switch llmProvider {
-		case "glm", "zhipu":
-			effectiveAPIKey = os.Getenv("GLM_API_KEY")
-		case "claude", "anthropic":
-			effectiveAPIKey = os.Getenv("CLAUDE_API_KEY")
-		default:
-			effectiveAPIKey = os.Getenv("GLM_API_KEY")
-		}
+		effectiveAPIKey = cfg.APIKey()
 	}
 
 	if effectiveAPIKey != "" {
-		llmClient = llm.NewLLMClientWithModel(llmProvider, effectiveAPIKey, llmModel)
+		llmClient = llm.NewLLMClientWithModel(cfg.LLM.Provider, effectiveAPIKey, cfg.LLM.Model)
 		if verbose {
 			fmt.Printf("已启用 LLM 分析功能 (后端: %s, 模型: %s)\n", llmClient.Name(), llmClient.Model())
 		}
@@ -156,10 +197,29 @@ func runAnalyzer(cmd *cobra.Command, args []string) {
 
 	// 4. 创建过滤器和遍历器
 	filter := analyzer.NewScopeFilter(p, blacklist)
+	filter.SetExternalOptions(analyzer.ExternalOptions{
+		Enabled:   cfg.External.Enabled,
+		UseVendor: cfg.External.Vendor,
+		ModCache:  cfg.External.GoModCache,
+	})
 	traverser := analyzer.NewTraverser(p, filter, llmClient, verbose)
+	traverser.SetExplain(cfg.Explain)
+	traverser.SetCycleOptions(cfg.Cycles.DepTypes, cfg.Cycles.Limit)
+	traverser.SetDeadCode(cfg.Reachability.Report)
+	traverser.SetRoots(cfg.Reachability.Roots)
+	traverser.SetMembers(cfg.Members.Report)
+	traverser.SetLayout(cfg.Layout)
+	traverser.SetProjectCentrality(cfg.ProjectCentrality)
+	traverser.SetArch(cfg.Arch)
+	roles, err := analyzer.NewRoleClassifier(p, cfg.Roles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 角色规则无效: %v\n", err)
+		os.Exit(1)
+	}
+	traverser.SetRoles(roles, cfg.Roles.LLM)
 
 	// 5. 创建缓存（如果未禁用且有 LLM 客户端）
-	if !noCache && llmClient != nil && llmClient.IsConfigured() {
+	if cfg.LLM.Cache && llmClient != nil && llmClient.IsConfigured() {
 		cache := analyzer.NewAnalysisCache(absProjectPath)
 		traverser.SetCache(cache)
 		if verbose {
@@ -172,7 +232,7 @@ func runAnalyzer(cmd *cobra.Command, args []string) {
 		fmt.Println("\n正在分析依赖关系...")
 	}
 
-	result := traverser.Analyze(startStruct, depth, absProjectPath)
+	result := traverser.Analyze(cfg.Start, cfg.Depth, absProjectPath)
 
 	// 保存缓存
 	if err := traverser.SaveCache(); err != nil && verbose {
@@ -188,50 +248,82 @@ func runAnalyzer(cmd *cobra.Command, args []string) {
 		fmt.Println("正在生成报告...")
 	}
 
-	switch format {
+	switch cfg.Format {
 	case "json":
 		jsonReporter := reporter.NewJSONReporter()
-		if err := jsonReporter.SaveToFile(result, outputPath); err != nil {
+		if err := jsonReporter.SaveToFile(result, cfg.Output); err != nil {
 			fmt.Fprintf(os.Stderr, "错误: 保存 JSON 报告失败: %v\n", err)
 			os.Exit(1)
 		}
 	default: // markdown
 		mdReporter := reporter.NewMarkdownReporter()
-		content := mdReporter.Generate(result, blacklist.GetBlockedTypes())
-		if err := mdReporter.SaveToFile(content, outputPath); err != nil {
+		mdReporter.SetMetricsSort(cfg.MetricsSort)
+		mdReporter.SetRankBy(cfg.RankBy)
+		content := mdReporter.Generate(result, append(blacklist.GetBlockedTypes(), blacklist.GetBlockedPatterns()...))
+		if err := mdReporter.SaveToFile(content, cfg.Output); err != nil {
 			fmt.Fprintf(os.Stderr, "错误: 保存 Markdown 报告失败: %v\n", err)
 			os.Exit(1)
 		}
 	}
 
-	fmt.Printf("报告已保存至: %s\n", outputPath)
+	fmt.Printf("报告已保存至: %s\n", cfg.Output)
 
 	// 7. 生成 Mermaid 图（可选）
-	if mermaidPath != "" {
+	if cfg.Mermaid != "" {
 		mermaidGen := reporter.NewMermaidGenerator()
-		if err := mermaidGen.GenerateToFile(result, mermaidPath); err != nil {
+		if err := mermaidGen.GenerateToFile(result, cfg.Mermaid); err != nil {
 			fmt.Fprintf(os.Stderr, "错误: 保存 Mermaid 图失败: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("Mermaid 图已保存至: %s\n", mermaidPath)
+		fmt.Printf("Mermaid 图已保存至: %s\n", cfg.Mermaid)
 	}
 
 	// 8. 生成可视化工具 JSON（可选）
-	if visualizerPath != "" {
+	if cfg.Visualizer != "" {
 		vizReporter := reporter.NewVisualizerReporter()
+		vizReporter.SizeBy = cfg.RankBy
 		vizOutput := vizReporter.Generate(result)
-		if err := vizReporter.SaveToFile(vizOutput, visualizerPath); err != nil {
+		if err := vizReporter.SaveToFile(vizOutput, cfg.Visualizer); err != nil {
 			fmt.Fprintf(os.Stderr, "错误: 保存可视化 JSON 失败: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("可视化 JSON 已保存至: %s\n", visualizerPath)
+		fmt.Printf("可视化 JSON 已保存至: %s\n", cfg.Visualizer)
 	}
 
-	// 9. 输出摘要
+	// 9. 生成包级依赖图（可选）
+	if cfg.PackageMermaid != "" {
+		mermaidGen := reporter.NewMermaidGenerator()
+		if err := mermaidGen.GeneratePackagesToFile(result, cfg.PackageMermaid); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 保存包级 Mermaid 图失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("包级 Mermaid 图已保存至: %s\n", cfg.PackageMermaid)
+	}
+	if cfg.PackageVisualizer != "" {
+		vizReporter := reporter.NewVisualizerReporter()
+		vizOutput := vizReporter.GeneratePackages(result)
+		if err := vizReporter.SaveToFile(vizOutput, cfg.PackageVisualizer); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 保存包级可视化 JSON 失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("包级可视化 JSON 已保存至: %s\n", cfg.PackageVisualizer)
+	}
+
+	// 10. 输出摘要
 	if len(result.Cycles) > 0 {
 		fmt.Printf("\n警告: 发现 %d 个循环依赖\n", len(result.Cycles))
-		for _, cycle := range result.Cycles {
-			fmt.Printf("  %v\n", cycle)
+		for _, cycle := range result.CycleDetails {
+			fmt.Printf("  %s -> %s %v\n", strings.Join(cycle.Nodes, " -> "), cycle.Nodes[0], cycle.EdgeTypes)
+		}
+		if result.CyclesTruncated {
+			fmt.Printf("  （超过上限 %d，仅列出部分）\n", cfg.Cycles.Limit)
+		}
+	}
+
+	if len(result.PackageGraph.Cycles) > 0 {
+		fmt.Printf("\n警告: 发现 %d 个包级循环依赖\n", len(result.PackageGraph.Cycles))
+		for _, cycle := range result.PackageGraph.Cycles {
+			fmt.Printf("  %s -> %s\n", strings.Join(cycle, " -> "), cycle[0])
 		}
 	}
 
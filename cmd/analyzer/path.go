@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/reporter"
+)
+
+var (
+	pathFrom     string
+	pathTo       string
+	pathAll      bool
+	pathMaxHops  int
+	pathLimit    int
+	pathDepTypes []string
+	pathMermaid  string
+)
+
+var pathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "查询两个类型之间的依赖路径",
+	Long: `在项目全部结构体的依赖图上查找从 --from 到 --to 的依赖路径，
+默认只给出最短路径，--all 列出全部简单路径。每一跳都会列出依赖类型和依据。
+
+示例:
+  go-struct-analyzer path -p ./myapp --from UserHandler --to DB
+  go-struct-analyzer path -p ./myapp --from UserHandler --to DB --all --max-hops 5
+  go-struct-analyzer path -p ./myapp --from UserHandler --to DB --dep-types field,constructor`,
+	Run: runPath,
+}
+
+func init() {
+	pathCmd.Flags().StringVar(&pathFrom, "from", "", "起点结构体（必填）")
+	pathCmd.Flags().StringVar(&pathTo, "to", "", "终点类型（必填）")
+	pathCmd.Flags().BoolVar(&pathAll, "all", false, "列出全部简单路径（默认只给出最短路径）")
+	pathCmd.Flags().IntVar(&pathMaxHops, "max-hops", 0, "列出全部路径时的最大跳数（0 表示不限制）")
+	pathCmd.Flags().IntVar(&pathLimit, "limit", analyzer.DefaultPathLimit, "列出全部路径时的最大数量")
+	pathCmd.Flags().StringSliceVar(&pathDepTypes, "dep-types", nil, "只沿这些依赖类型的边查找（逗号分隔，如 field,constructor）")
+	pathCmd.Flags().StringVar(&pathMermaid, "mermaid", "", "将高亮路径的 Mermaid 图写入文件（默认输出到终端）")
+	pathCmd.Flags().StringVarP(&projectPath, "project", "p", ".", "项目路径")
+	pathCmd.Flags().StringVarP(&blacklistPath, "blacklist", "b", "", "黑名单文件路径")
+	pathCmd.Flags().BoolVar(&external, "external", false, "保留第三方模块类型作为叶子节点")
+	pathCmd.Flags().StringVar(&configPath, "config", "", "配置文件路径（默认自动查找）")
+	pathCmd.Flags().StringVar(&profile, "profile", "", "使用配置文件中的命名 profile")
+	_ = pathCmd.MarkFlagRequired("from")
+	_ = pathCmd.MarkFlagRequired("to")
+	rootCmd.AddCommand(pathCmd)
+}
+
+func runPath(cmd *cobra.Command, args []string) {
+	cfg := mustLoadConfig(cmd)
+
+	absProjectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 无法解析项目路径: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := parser.NewParser(cfg.Verbose)
+	if err := p.ParseProject(absProjectPath); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: 解析项目失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	blacklist, err := cfg.LoadBlacklist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 加载黑名单失败: %v\n", err)
+	}
+	filter := analyzer.NewScopeFilter(p, blacklist)
+	filter.SetExternalOptions(analyzer.ExternalOptions{
+		Enabled:   cfg.External.Enabled,
+		UseVendor: cfg.External.Vendor,
+		ModCache:  cfg.External.GoModCache,
+	})
+
+	result, err := analyzer.FindPaths(p, filter, analyzer.PathQuery{
+		From:     pathFrom,
+		To:       pathTo,
+		DepTypes: pathDepTypes,
+		All:      pathAll,
+		MaxHops:  pathMaxHops,
+		Limit:    pathLimit,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		os.Exit(1)
+	}
+	if len(result.Paths) == 0 {
+		fmt.Printf("%s 与 %s 之间没有依赖路径\n", pathFrom, pathTo)
+		os.Exit(1)
+	}
+
+	for i, path := range result.Paths {
+		fmt.Printf("路径 %d (%d 跳): %s\n", i+1, len(path.Hops), strings.Join(path.Nodes, " -> "))
+		for _, hop := range path.Hops {
+			fmt.Printf("  %s -> %s [%s]\n", hop.From, hop.To, strings.Join(hop.Types, ", "))
+			for _, e := range hop.Evidence {
+				fmt.Printf("      %s\n", e)
+			}
+		}
+		fmt.Println()
+	}
+	if result.Truncated {
+		fmt.Printf("路径数量超过上限 %d，结果已截断\n\n", pathLimit)
+	}
+
+	mermaid := reporter.NewMermaidGenerator().GeneratePath(result)
+	if pathMermaid != "" {
+		if err := os.WriteFile(pathMermaid, []byte(mermaid), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 写入 Mermaid 文件失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Mermaid 图已生成: %s\n", pathMermaid)
+		return
+	}
+	fmt.Println("```mermaid")
+	fmt.Print(mermaid)
+	fmt.Println("```")
+}
@@ -23,9 +23,12 @@ func main() {
 		}
 	}
 
-	fmt.Println("\n=== Functions (New*) ===")
+	fmt.Println("\n=== Functions (constructors) ===")
 	for key, fn := range p.GetAllFunctions() {
-		fmt.Printf("Function: %s -> %s\n", key, fn.ReturnType)
+		fmt.Printf("Function: %s%s -> %s\n", key, fn.TypeParams, fn.ReturnType)
+		for _, param := range fn.Params {
+			fmt.Printf("  param %s %s\n", param.Name, param.Type)
+		}
 	}
 
 	fmt.Println("\n=== Testing Interface Implementation ===")
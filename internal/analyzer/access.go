@@ -0,0 +1,245 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// MultiWriterThreshold 字段被至少这么多个方法写入时标记为多写入字段
+const MultiWriterThreshold = 3
+
+// accessRecorder 记录单个结构体的方法对字段的访问
+type accessRecorder struct {
+	fields   []string                                 // 字段（声明顺序，不含 ignore 字段）
+	fieldSet map[string]bool                          // 字段选择器名集合
+	methods  map[string]bool                          // 已分析的方法
+	access   map[string]map[string]*types.FieldAccess // 方法 -> 字段 -> 访问
+
+	fieldCalls map[string]map[string]map[string]bool // 字段 -> 在字段值上调用的方法 -> 调用方方法
+}
+
+// newAccessRecorder 为结构体创建访问记录器
+func newAccessRecorder(structInfo *types.StructInfo) *accessRecorder {
+	r := &accessRecorder{
+		fieldSet: make(map[string]bool),
+		methods:  make(map[string]bool),
+		access:   make(map[string]map[string]*types.FieldAccess),
+
+		fieldCalls: make(map[string]map[string]map[string]bool),
+	}
+	for _, f := range structInfo.Fields {
+		if f.Annotations.Ignore {
+			continue
+		}
+		name := fieldSelectorName(f)
+		if !r.fieldSet[name] {
+			r.fieldSet[name] = true
+			r.fields = append(r.fields, name)
+		}
+	}
+	return r
+}
+
+// get 返回方法对字段的访问记录，不存在时创建
+func (r *accessRecorder) get(method, field string) *types.FieldAccess {
+	if r.access[method] == nil {
+		r.access[method] = make(map[string]*types.FieldAccess)
+	}
+	acc := r.access[method][field]
+	if acc == nil {
+		acc = &types.FieldAccess{Method: method, Field: field}
+		r.access[method][field] = acc
+	}
+	return acc
+}
+
+// recordFieldAccess 记录方法体中通过接收者访问字段的读、写和调用
+// 写入包括直接赋值、复合赋值、自增自减、取地址，以及经由字段的间接写入（recv.f.x = v、recv.f[i] = v、*recv.f = v）；
+// 调用指在字段值上调用方法（recv.f.Do()）或调用函数类型的字段（recv.f()）
+func (a *DependencyAnalyzer) recordFieldAccess(structInfo *types.StructInfo, funcDecl *ast.FuncDecl) {
+	rec := a.access[structInfo.Name]
+	if rec == nil {
+		return
+	}
+	method := funcDecl.Name.Name
+	rec.methods[method] = true
+
+	recv := receiverName(funcDecl)
+	if recv == "" {
+		return
+	}
+
+	// fieldOf 沿选择器、索引、解引用向内查找，返回表达式所基于的 recv.field
+	var fieldOf func(expr ast.Expr) *ast.SelectorExpr
+	fieldOf = func(expr ast.Expr) *ast.SelectorExpr {
+		switch e := expr.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := e.X.(*ast.Ident); ok && ident.Name == recv && rec.fieldSet[e.Sel.Name] {
+				return e
+			}
+			return fieldOf(e.X)
+		case *ast.IndexExpr:
+			return fieldOf(e.X)
+		case *ast.StarExpr:
+			return fieldOf(e.X)
+		case *ast.ParenExpr:
+			return fieldOf(e.X)
+		}
+		return nil
+	}
+
+	// 第一遍：找出处于写入和调用位置的字段选择器
+	writes := make(map[*ast.SelectorExpr]bool)
+	readWrites := make(map[*ast.SelectorExpr]bool) // 复合赋值、自增自减：既读又写
+	calls := make(map[*ast.SelectorExpr]bool)
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if sel := fieldOf(lhs); sel != nil {
+					if node.Tok == token.ASSIGN || node.Tok == token.DEFINE {
+						writes[sel] = true
+					} else {
+						readWrites[sel] = true
+					}
+				}
+			}
+		case *ast.IncDecStmt:
+			if sel := fieldOf(node.X); sel != nil {
+				readWrites[sel] = true
+			}
+		case *ast.UnaryExpr:
+			if node.Op == token.AND {
+				if sel := fieldOf(node.X); sel != nil {
+					writes[sel] = true
+				}
+			}
+		case *ast.CallExpr:
+			fun := node.Fun
+			if sel, ok := fun.(*ast.SelectorExpr); ok && fieldOf(sel) != sel {
+				fun = sel.X
+			}
+			if sel := fieldOf(fun); sel != nil {
+				calls[sel] = true
+			}
+		}
+		return true
+	})
+
+	// 第二遍：记录每一处 recv.field
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || fieldOf(sel) != sel {
+			return true
+		}
+		acc := rec.get(method, sel.Sel.Name)
+		switch {
+		case readWrites[sel]:
+			acc.Read = true
+			acc.Write = true
+		case writes[sel]:
+			acc.Write = true
+		case calls[sel]:
+			acc.Call = true
+		default:
+			acc.Read = true
+		}
+		return true
+	})
+}
+
+// recordFieldCall 记录方法体中形如 recv.field.Method() 的调用：在字段值上调用了哪个方法
+func (a *DependencyAnalyzer) recordFieldCall(structInfo *types.StructInfo, funcDecl *ast.FuncDecl, call *ast.SelectorExpr) {
+	rec := a.access[structInfo.Name]
+	recv := receiverName(funcDecl)
+	if rec == nil || recv == "" {
+		return
+	}
+	fieldSel, ok := call.X.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	if ident, ok := fieldSel.X.(*ast.Ident); !ok || ident.Name != recv || !rec.fieldSet[fieldSel.Sel.Name] {
+		return
+	}
+
+	field, method := fieldSel.Sel.Name, call.Sel.Name
+	if rec.fieldCalls[field] == nil {
+		rec.fieldCalls[field] = make(map[string]map[string]bool)
+	}
+	if rec.fieldCalls[field][method] == nil {
+		rec.fieldCalls[field][method] = make(map[string]bool)
+	}
+	rec.fieldCalls[field][method][funcDecl.Name.Name] = true
+}
+
+// FieldAccess 返回结构体的方法 × 字段访问矩阵（需先调用 AnalyzeStruct）
+func (a *DependencyAnalyzer) FieldAccess(structName string) types.FieldAccessMatrix {
+	rec := a.access[structName]
+	if rec == nil {
+		return types.FieldAccessMatrix{}
+	}
+
+	matrix := types.FieldAccessMatrix{Fields: rec.fields}
+	for m := range rec.methods {
+		matrix.Methods = append(matrix.Methods, m)
+	}
+	sort.Strings(matrix.Methods)
+
+	read := make(map[string]bool)
+	writers := make(map[string]int)
+	for _, m := range matrix.Methods {
+		touched := false
+		for _, f := range rec.fields {
+			acc := rec.access[m][f]
+			if acc == nil {
+				continue
+			}
+			touched = true
+			matrix.Accesses = append(matrix.Accesses, *acc)
+			if acc.Read || acc.Call {
+				read[f] = true
+			}
+			if acc.Write {
+				writers[f]++
+			}
+		}
+		if !touched {
+			matrix.FieldlessMethods = append(matrix.FieldlessMethods, m)
+		}
+	}
+
+	for _, f := range rec.fields {
+		if !read[f] {
+			matrix.UnreadFields = append(matrix.UnreadFields, f)
+		}
+		if writers[f] >= MultiWriterThreshold {
+			matrix.MultiWriterFields = append(matrix.MultiWriterFields, f)
+		}
+	}
+	return matrix
+}
+
+// fieldSelectorName 返回通过选择器访问字段时使用的名称（嵌入字段为类型名）
+func fieldSelectorName(field types.FieldInfo) string {
+	if field.IsEmbedded {
+		return shortTypeName(parser.ExtractBaseType(field.Type))
+	}
+	return field.Name
+}
+
+// receiverName 返回方法接收者的变量名，匿名接收者返回空字符串
+func receiverName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 || len(funcDecl.Recv.List[0].Names) == 0 {
+		return ""
+	}
+	name := funcDecl.Recv.List[0].Names[0].Name
+	if name == "_" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+func TestDependencyAnalyzer_FieldAccess(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"app/app.go": `package app
+
+import "sync"
+
+type Stats struct{ Hits int }
+
+type Counter struct {
+	mu     sync.Mutex
+	count  int
+	items  []string
+	stats  *Stats
+	onDone func()
+	unused string
+}
+
+func (c *Counter) Inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count++
+	c.stats.Hits = c.count
+}
+
+func (c *Counter) Add(s string) {
+	c.items[0] = s
+	c.count = len(c.items)
+}
+
+func (c *Counter) Reset() {
+	c.count = 0
+	c.items = nil
+	c.onDone()
+}
+
+func (c *Counter) Name() string { return "counter" }
+`,
+	})
+
+	a := NewDependencyAnalyzer(p, NewScopeFilter(p, NewBlacklist()), false)
+	a.AnalyzeStruct(p.GetStruct("Counter"))
+	matrix := a.FieldAccess("Counter")
+
+	if want := []string{"Add", "Inc", "Name", "Reset"}; !reflect.DeepEqual(matrix.Methods, want) {
+		t.Errorf("Methods = %v, want %v", matrix.Methods, want)
+	}
+
+	cells := make(map[string]types.FieldAccess)
+	for _, acc := range matrix.Accesses {
+		cells[acc.Method+"."+acc.Field] = acc
+	}
+	tests := []struct {
+		cell              string
+		read, write, call bool
+	}{
+		{"Inc.mu", false, false, true},
+		{"Inc.count", true, true, false},
+		{"Inc.stats", false, true, false},
+		{"Add.items", true, true, false},
+		{"Add.count", false, true, false},
+		{"Reset.onDone", false, false, true},
+	}
+	for _, tt := range tests {
+		acc, ok := cells[tt.cell]
+		if !ok {
+			t.Errorf("missing access %s", tt.cell)
+			continue
+		}
+		if acc.Read != tt.read || acc.Write != tt.write || acc.Call != tt.call {
+			t.Errorf("%s = R:%v W:%v C:%v, want R:%v W:%v C:%v", tt.cell, acc.Read, acc.Write, acc.Call, tt.read, tt.write, tt.call)
+		}
+	}
+
+	if want := []string{"stats", "unused"}; !reflect.DeepEqual(matrix.UnreadFields, want) {
+		t.Errorf("UnreadFields = %v, want %v", matrix.UnreadFields, want)
+	}
+	if want := []string{"count"}; !reflect.DeepEqual(matrix.MultiWriterFields, want) {
+		t.Errorf("MultiWriterFields = %v, want %v", matrix.MultiWriterFields, want)
+	}
+	if want := []string{"Name"}; !reflect.DeepEqual(matrix.FieldlessMethods, want) {
+		t.Errorf("FieldlessMethods = %v, want %v", matrix.FieldlessMethods, want)
+	}
+}
@@ -0,0 +1,619 @@
+package analyzer
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	gotypes "go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// APISnapshot 表示一个版本中可被其他模块导入的包（非 main、不在 internal 下）的导出 API
+type APISnapshot struct {
+	decls map[string]*apiDecl // 导入路径.名称 -> 声明
+}
+
+// apiDecl 表示一个导出的类型或函数
+type apiDecl struct {
+	importPath string
+	name       string
+	kind       string               // types.APIKind*
+	decl       string               // 函数签名、非结构体类型的定义，或结构体和接口的类型参数
+	key        string               // decl 的比较形式：包限定符替换为导入路径（从分析结果 JSON 收集时为空）
+	sealed     bool                 // 接口包含未导出方法（包外无法实现）
+	members    map[string]apiMember // 导出字段、嵌入类型和导出方法（接口为导出方法和嵌入接口）
+	filePath   string
+	line       int
+}
+
+// apiMember 表示类型的一个成员
+type apiMember struct {
+	kind     string // types.APIKindField、APIKindEmbedded、APIKindMethod
+	decl     string // 字段类型或方法签名
+	key      string // decl 的比较形式（见 apiDecl.key）
+	pointer  bool   // 方法的接收者为指针
+	filePath string
+	line     int
+}
+
+// apiKindLabels API 声明种类的中文名称
+var apiKindLabels = map[string]string{
+	types.APIKindStruct:    "结构体",
+	types.APIKindInterface: "接口",
+	types.APIKindType:      "类型",
+	types.APIKindFunction:  "函数",
+	types.APIKindField:     "字段",
+	types.APIKindEmbedded:  "嵌入类型",
+	types.APIKindMethod:    "方法",
+}
+
+// NewAPISnapshot 从解析后的源码树中收集导出 API，文件路径相对 root
+func NewAPISnapshot(p *parser.Parser, root string) *APISnapshot {
+	s := &APISnapshot{decls: make(map[string]*apiDecl)}
+	fset := p.GetFileSet()
+
+	type pendingMethod struct {
+		key    string
+		name   string
+		member apiMember
+	}
+	var methods []pendingMethod
+
+	for filePath, file := range p.GetAllFiles() {
+		importPath := p.ImportPathOf(filePath)
+		if file.Name.Name == "main" || isInternalPackage(importPath) {
+			continue
+		}
+		rel := filePath
+		if r, err := filepath.Rel(root, filePath); err == nil {
+			rel = r
+		}
+		line := func(n ast.Node) int { return fset.Position(n.Pos()).Line }
+		qualify := importPathQualifier(p.GetImports(filePath))
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range d.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if !ts.Name.IsExported() {
+						continue
+					}
+					ad := newAPIType(importPath, rel, ts, line, qualify)
+					s.decls[importPath+"."+ad.name] = ad
+				}
+			case *ast.FuncDecl:
+				if !d.Name.IsExported() {
+					continue
+				}
+				if d.Recv == nil || len(d.Recv.List) == 0 {
+					s.decls[importPath+"."+d.Name.Name] = &apiDecl{
+						importPath: importPath,
+						name:       d.Name.Name,
+						kind:       types.APIKindFunction,
+						decl:       funcTypeString(d.Type, gotypes.ExprString),
+						key:        funcTypeString(d.Type, qualify),
+						filePath:   rel,
+						line:       line(d),
+					}
+					continue
+				}
+				recv := d.Recv.List[0].Type
+				if index, ok := recv.(*ast.IndexExpr); ok {
+					recv = index.X
+				}
+				_, pointer := recv.(*ast.StarExpr)
+				methods = append(methods, pendingMethod{
+					key:  importPath + "." + baseTypeName(recv),
+					name: d.Name.Name,
+					member: apiMember{
+						kind:     types.APIKindMethod,
+						decl:     funcTypeString(d.Type, gotypes.ExprString),
+						key:      funcTypeString(d.Type, qualify),
+						pointer:  pointer,
+						filePath: rel,
+						line:     line(d),
+					},
+				})
+			}
+		}
+	}
+
+	// 方法可能先于类型声明出现，类型收集完后再挂到类型上
+	for _, m := range methods {
+		if d := s.decls[m.key]; d != nil && d.kind != types.APIKindInterface {
+			d.members[m.name] = m.member
+		}
+	}
+	return s
+}
+
+// newAPIType 从类型声明构造 API 声明，qualify 返回类型表达式的比较形式
+func newAPIType(importPath, filePath string, ts *ast.TypeSpec, line func(ast.Node) int, qualify func(ast.Expr) string) *apiDecl {
+	d := &apiDecl{
+		importPath: importPath,
+		name:       ts.Name.Name,
+		members:    make(map[string]apiMember),
+		filePath:   filePath,
+		line:       line(ts),
+	}
+	typeParams, typeParamsKey := typeParamsString(ts.TypeParams, gotypes.ExprString), typeParamsString(ts.TypeParams, qualify)
+
+	switch t := ts.Type.(type) {
+	case *ast.StructType:
+		d.kind = types.APIKindStruct
+		d.decl, d.key = typeParams, typeParamsKey
+		for _, field := range t.Fields.List {
+			typeStr, typeKey := gotypes.ExprString(field.Type), qualify(field.Type)
+			if len(field.Names) == 0 {
+				d.members[baseTypeName(field.Type)] = apiMember{kind: types.APIKindEmbedded, decl: typeStr, key: typeKey, filePath: filePath, line: line(field)}
+				continue
+			}
+			for _, name := range field.Names {
+				if name.IsExported() {
+					d.members[name.Name] = apiMember{kind: types.APIKindField, decl: typeStr, key: typeKey, filePath: filePath, line: line(name)}
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		d.kind = types.APIKindInterface
+		d.decl, d.key = typeParams, typeParamsKey
+		for _, field := range t.Methods.List {
+			ft, ok := field.Type.(*ast.FuncType)
+			if !ok || len(field.Names) == 0 {
+				typeStr, typeKey := gotypes.ExprString(field.Type), qualify(field.Type)
+				d.members[typeKey] = apiMember{kind: types.APIKindEmbedded, decl: typeStr, key: typeKey, filePath: filePath, line: line(field)}
+				continue
+			}
+			for _, name := range field.Names {
+				if !name.IsExported() {
+					d.sealed = true
+					continue
+				}
+				d.members[name.Name] = apiMember{kind: types.APIKindMethod, decl: funcTypeString(ft, gotypes.ExprString), key: funcTypeString(ft, qualify), filePath: filePath, line: line(name)}
+			}
+		}
+	default:
+		d.kind = types.APIKindType
+		d.decl, d.key = typeParams+gotypes.ExprString(ts.Type), typeParamsKey+qualify(ts.Type)
+		if ts.Assign.IsValid() {
+			d.decl, d.key = "= "+d.decl, "= "+d.key
+		}
+	}
+	return d
+}
+
+// importPathQualifier 返回把类型表达式中的包限定符（导入名或别名）替换为导入路径的渲染函数，
+// 导入别名改名不会让类型的比较形式变化
+func importPathQualifier(imports map[string]string) func(ast.Expr) string {
+	return func(expr ast.Expr) string {
+		s := gotypes.ExprString(expr)
+		qualifiers := make(map[string]bool)
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok {
+					if _, ok := imports[ident.Name]; ok {
+						qualifiers[ident.Name] = true
+					}
+				}
+			}
+			return true
+		})
+		if len(qualifiers) == 0 {
+			return s
+		}
+		var b strings.Builder
+		for i := 0; i < len(s); {
+			j := i
+			for j < len(s) && isIdentByte(s[j]) {
+				j++
+			}
+			if j == i {
+				b.WriteByte(s[i])
+				i++
+				continue
+			}
+			word := s[i:j]
+			// 只替换作为选择器前缀出现的标识符（前面不是 "."，后面紧跟 "."）
+			if qualifiers[word] && j < len(s) && s[j] == '.' && (i == 0 || s[i-1] != '.') {
+				word = imports[word]
+			}
+			b.WriteString(word)
+			i = j
+		}
+		return b.String()
+	}
+}
+
+// isIdentByte 判断字节是否可以出现在标识符中（非 ASCII 字节按标识符处理）
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// APISnapshotFromResult 从保存的分析结果 JSON 中收集导出 API
+// 分析结果只包含遍历到的结构体，因此只能比较结构体、字段和方法
+func APISnapshotFromResult(result *types.AnalysisResult) *APISnapshot {
+	s := &APISnapshot{decls: make(map[string]*apiDecl)}
+	for _, sa := range result.Structs {
+		if sa.Package == "main" || isInternalPackage(sa.ImportPath) || !token.IsExported(sa.Name) {
+			continue
+		}
+		d := &apiDecl{
+			importPath: sa.ImportPath,
+			name:       sa.Name,
+			kind:       types.APIKindStruct,
+			members:    make(map[string]apiMember),
+		}
+		for _, f := range sa.Fields {
+			switch {
+			case f.IsEmbedded:
+				d.members[f.Name] = apiMember{kind: types.APIKindEmbedded, decl: f.Type}
+			case f.IsExported:
+				d.members[f.Name] = apiMember{kind: types.APIKindField, decl: f.Type}
+			}
+		}
+		for _, m := range sa.Methods {
+			if m.IsExported {
+				d.members[m.Name] = apiMember{
+					kind:    types.APIKindMethod,
+					decl:    normalizeSignature(m.Signature),
+					pointer: strings.HasPrefix(m.Receiver, "*"),
+				}
+			}
+		}
+		s.decls[sa.ImportPath+"."+sa.Name] = d
+	}
+	return s
+}
+
+// DiffAPI 比较两个版本的导出 API，按 Go 的兼容性规则把每处变化分为不兼容和兼容
+func DiffAPI(oldAPI, newAPI *APISnapshot) types.APIDiffResult {
+	var result types.APIDiffResult
+	add := func(d *apiDecl, member string, m apiMember, change string, breaking bool, oldDecl, newDecl, reason string) {
+		c := types.APIChange{
+			Package:  d.importPath,
+			Name:     d.name,
+			Kind:     d.kind,
+			Change:   change,
+			Breaking: breaking,
+			Old:      oldDecl,
+			New:      newDecl,
+			Reason:   reason,
+			FilePath: d.filePath,
+			Line:     d.line,
+		}
+		if member != "" {
+			c.Name += "." + member
+			c.Kind = m.kind
+			c.FilePath, c.Line = m.filePath, m.line
+		}
+		result.Changes = append(result.Changes, c)
+	}
+
+	for _, key := range sortedDeclKeys(oldAPI.decls) {
+		o, n := oldAPI.decls[key], newAPI.decls[key]
+		switch {
+		case n == nil:
+			add(o, "", apiMember{}, types.APIChangeRemoved, true, o.decl, "", "删除了导出的"+apiKindLabels[o.kind])
+			continue
+		case o.kind != n.kind:
+			add(n, "", apiMember{}, types.APIChangeChanged, true, apiKindLabels[o.kind], apiKindLabels[n.kind],
+				fmt.Sprintf("类型种类由%s变为%s", apiKindLabels[o.kind], apiKindLabels[n.kind]))
+			continue
+		case !sameDecl(o.decl, o.key, n.decl, n.key):
+			reason := "类型参数改变"
+			switch o.kind {
+			case types.APIKindFunction:
+				reason = "函数签名改变"
+			case types.APIKindType:
+				reason = "类型定义改变"
+			}
+			add(n, "", apiMember{}, types.APIChangeChanged, true, o.decl, n.decl, reason)
+		}
+
+		if o.kind == types.APIKindInterface {
+			diffInterfaceMembers(o, n, add)
+		} else {
+			diffTypeMembers(o, n, add)
+		}
+	}
+
+	for _, key := range sortedDeclKeys(newAPI.decls) {
+		if _, ok := oldAPI.decls[key]; !ok {
+			n := newAPI.decls[key]
+			add(n, "", apiMember{}, types.APIChangeAdded, false, "", n.decl, "新增导出的"+apiKindLabels[n.kind])
+		}
+	}
+
+	sort.SliceStable(result.Changes, func(i, j int) bool {
+		a, b := result.Changes[i], result.Changes[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Name < b.Name
+	})
+	for _, c := range result.Changes {
+		if c.Breaking {
+			result.Breaking++
+		} else {
+			result.Compatible++
+		}
+	}
+	return result
+}
+
+// apiChangeFunc 记录一处变化，member 为空表示声明本身的变化
+type apiChangeFunc func(d *apiDecl, member string, m apiMember, change string, breaking bool, oldDecl, newDecl, reason string)
+
+// diffTypeMembers 比较结构体和其他具名类型的字段、嵌入类型和方法
+func diffTypeMembers(o, n *apiDecl, add apiChangeFunc) {
+	for _, name := range sortedMemberKeys(o.members) {
+		om := o.members[name]
+		nm, ok := n.members[name]
+		switch {
+		case !ok:
+			reason := "删除了导出的" + apiKindLabels[om.kind]
+			if om.kind == types.APIKindEmbedded {
+				reason = "删除了嵌入类型，提升的字段和方法不再可用"
+			}
+			add(o, name, om, types.APIChangeRemoved, true, memberString(o, name, om), "", reason)
+		case om.kind == types.APIKindMethod || nm.kind == types.APIKindMethod:
+			if om.kind != nm.kind {
+				add(n, name, nm, types.APIChangeChanged, true, memberString(o, name, om), memberString(n, name, nm),
+					fmt.Sprintf("成员由%s变为%s", apiKindLabels[om.kind], apiKindLabels[nm.kind]))
+				continue
+			}
+			switch {
+			case !sameDecl(om.decl, om.key, nm.decl, nm.key):
+				add(n, name, nm, types.APIChangeChanged, true, memberString(o, name, om), memberString(n, name, nm), "方法签名改变")
+			case !om.pointer && nm.pointer:
+				add(n, name, nm, types.APIChangeChanged, true, memberString(o, name, om), memberString(n, name, nm),
+					"接收者由值改为指针，值类型的方法集不再包含该方法")
+			case om.pointer && !nm.pointer:
+				add(n, name, nm, types.APIChangeChanged, false, memberString(o, name, om), memberString(n, name, nm), "接收者由指针改为值")
+			}
+		case !sameDecl(om.decl, om.key, nm.decl, nm.key):
+			add(n, name, nm, types.APIChangeChanged, true, om.decl, nm.decl, apiKindLabels[om.kind]+"类型改变")
+		case om.kind == types.APIKindEmbedded && nm.kind == types.APIKindField:
+			add(n, name, nm, types.APIChangeChanged, true, om.decl, nm.decl, "不再嵌入，提升的字段和方法不再可用")
+		case om.kind == types.APIKindField && nm.kind == types.APIKindEmbedded:
+			add(n, name, nm, types.APIChangeChanged, false, om.decl, nm.decl, "改为嵌入")
+		}
+	}
+	for _, name := range sortedMemberKeys(n.members) {
+		if _, ok := o.members[name]; !ok {
+			nm := n.members[name]
+			add(n, name, nm, types.APIChangeAdded, false, "", memberString(n, name, nm), "新增"+apiKindLabels[nm.kind])
+		}
+	}
+}
+
+// diffInterfaceMembers 比较接口的方法集：删除或修改方法破坏调用方，
+// 新增方法破坏包外的实现（接口已含未导出方法、包外无法实现时除外）
+func diffInterfaceMembers(o, n *apiDecl, add apiChangeFunc) {
+	if n.sealed && !o.sealed {
+		add(n, "", apiMember{}, types.APIChangeChanged, true, "", "", "接口新增未导出方法，包外无法再实现")
+	}
+	for _, name := range sortedMemberKeys(o.members) {
+		om := o.members[name]
+		nm, ok := n.members[name]
+		switch {
+		case !ok && om.kind == types.APIKindEmbedded:
+			add(o, name, om, types.APIChangeRemoved, true, om.decl, "", "删除了嵌入接口，其方法可能不再属于该接口")
+		case !ok:
+			add(o, name, om, types.APIChangeRemoved, true, memberString(o, name, om), "", "删除了接口方法")
+		case !sameDecl(om.decl, om.key, nm.decl, nm.key):
+			add(n, name, nm, types.APIChangeChanged, true, memberString(o, name, om), memberString(n, name, nm), "接口方法签名改变")
+		}
+	}
+	for _, name := range sortedMemberKeys(n.members) {
+		if _, ok := o.members[name]; ok {
+			continue
+		}
+		nm := n.members[name]
+		if o.sealed {
+			add(n, name, nm, types.APIChangeAdded, false, "", memberString(n, name, nm), "接口新增"+apiKindLabels[nm.kind]+"（接口含未导出方法，包外无法实现）")
+		} else {
+			add(n, name, nm, types.APIChangeAdded, true, "", memberString(n, name, nm), "接口新增"+apiKindLabels[nm.kind]+"，包外的实现不再满足该接口")
+		}
+	}
+}
+
+// sameDecl 比较新旧两个版本的声明：两边都有比较形式（从源码收集）时按比较形式，否则按展示形式
+func sameDecl(oldDecl, oldKey, newDecl, newKey string) bool {
+	if oldKey != "" && newKey != "" {
+		return oldKey == newKey
+	}
+	return oldDecl == newDecl
+}
+
+// memberString 返回成员的展示形式，方法带上方法名和接收者
+func memberString(d *apiDecl, name string, m apiMember) string {
+	if m.kind != types.APIKindMethod {
+		return m.decl
+	}
+	method := name + strings.TrimPrefix(m.decl, "func")
+	if d.kind == types.APIKindInterface {
+		return method
+	}
+	recv := d.name
+	if m.pointer {
+		recv = "*" + recv
+	}
+	return fmt.Sprintf("(%s) %s", recv, method)
+}
+
+// funcTypeString 返回去掉参数名的函数签名，参数改名不视为变化；exprString 渲染每个类型表达式
+func funcTypeString(ft *ast.FuncType, exprString func(ast.Expr) string) string {
+	var b strings.Builder
+	b.WriteString("func")
+	b.WriteString(typeParamsString(ft.TypeParams, exprString))
+	b.WriteString("(" + strings.Join(fieldTypes(ft.Params, exprString), ", ") + ")")
+	results := fieldTypes(ft.Results, exprString)
+	switch len(results) {
+	case 0:
+	case 1:
+		b.WriteString(" " + results[0])
+	default:
+		b.WriteString(" (" + strings.Join(results, ", ") + ")")
+	}
+	return b.String()
+}
+
+// fieldTypes 返回参数列表中每个参数的类型
+func fieldTypes(list *ast.FieldList, exprString func(ast.Expr) string) []string {
+	if list == nil {
+		return nil
+	}
+	var result []string
+	for _, field := range list.List {
+		typeStr := exprString(field.Type)
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			result = append(result, typeStr)
+		}
+	}
+	return result
+}
+
+// typeParamsString 返回类型参数列表（如 "[K comparable, V any]"），没有时为空
+func typeParamsString(list *ast.FieldList, exprString func(ast.Expr) string) string {
+	if list == nil || len(list.List) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(list.List))
+	for _, field := range list.List {
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		parts = append(parts, strings.Join(names, ", ")+" "+exprString(field.Type))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// normalizeSignature 将分析结果中带参数名的签名转换为 funcTypeString 的形式，无法解析时只规整空白
+func normalizeSignature(sig string) string {
+	if expr, err := goparser.ParseExpr("func" + sig); err == nil {
+		if ft, ok := expr.(*ast.FuncType); ok {
+			return funcTypeString(ft, gotypes.ExprString)
+		}
+	}
+	return strings.Join(strings.Fields(sig), " ")
+}
+
+// sortedDeclKeys 返回已排序的声明键
+func sortedDeclKeys(decls map[string]*apiDecl) []string {
+	keys := make([]string, 0, len(decls))
+	for k := range decls {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedMemberKeys 返回已排序的成员名
+func sortedMemberKeys(members map[string]apiMember) []string {
+	keys := make([]string, 0, len(members))
+	for k := range members {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ExtractRevision 用 git archive 将 dir 所在仓库中 rev 版本的 dir 目录导出到临时目录（rev 先解析为树对象），
+// 返回导出的目录（与 dir 同名，没有 go.mod 时推导出的包路径保持一致）和删除临时目录的函数
+func ExtractRevision(dir, rev string) (string, func(), error) {
+	top, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, err
+	}
+	prefix, err := runGit(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", nil, err
+	}
+	// 先解析为树对象 ID，避免以 - 开头的 rev 被 git archive 当作选项
+	tree, err := runGit(dir, "rev-parse", "--verify", "--end-of-options", rev+"^{tree}")
+	if err != nil {
+		return "", nil, err
+	}
+	archive, err := runGit(strings.TrimSpace(top), "archive", "--format=tar", strings.TrimSpace(tree)+":"+strings.TrimSpace(prefix))
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "struct-analyzer-apidiff-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	treeDir := filepath.Join(tmpDir, filepath.Base(absDir))
+	if err := os.Mkdir(treeDir, 0755); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if err := extractTar(strings.NewReader(archive), treeDir); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("extract %s: %w", rev, err)
+	}
+	return treeDir, cleanup, nil
+}
+
+// extractTar 将 tar 中的目录和普通文件解压到 dir（忽略链接和越界路径）
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(target, dir+string(filepath.Separator)) {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.Create(target)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
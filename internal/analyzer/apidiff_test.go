@@ -0,0 +1,254 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+func TestDiffAPI(t *testing.T) {
+	oldP, oldDir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/lib\n",
+		"store/store.go": `package store
+
+type Store interface {
+	Get(key string) (string, error)
+	Delete(key string) error
+}
+
+type Sealed interface {
+	Get() int
+	seal()
+}
+
+type Item struct {
+	Key   string
+	Value []byte
+	Base
+	hidden int
+}
+
+type Base struct{ ID int64 }
+
+func (i Item) Size() int      { return len(i.Value) }
+func (i *Item) Reset()        {}
+func (i Item) Legacy()        {}
+func (i Item) unexported()    {}
+
+func NewItem(key string, value []byte) *Item { return nil }
+
+func Open(path string) error { return nil }
+
+type Status int
+
+type Removed struct{}
+`,
+		"internal/x/x.go": "package x\n\ntype Gone struct{}\n",
+		"main.go":         "package main\n\ntype Gone struct{}\n\nfunc main() {}\n",
+	})
+	newP, newDir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/lib\n",
+		"store/store.go": `package store
+
+type Store interface {
+	Get(k string) (string, error)
+	Put(key, value string) error
+}
+
+type Sealed interface {
+	Get() int
+	Keys() []string
+	seal()
+}
+
+type Item struct {
+	Key   int
+	Value []byte
+	Base  Base
+	TTL   int
+}
+
+type Base struct{ ID int64 }
+
+func (i *Item) Size() int { return len(i.Value) }
+func (i Item) Reset()     {}
+
+func NewItem(k string, v []byte) *Item { return nil }
+
+func Open(path string, mode int) error { return nil }
+
+type Status string
+
+type Added struct{}
+`,
+	})
+
+	result := DiffAPI(NewAPISnapshot(oldP, oldDir), NewAPISnapshot(newP, newDir))
+
+	type want struct {
+		kind, change string
+		breaking     bool
+	}
+	expected := map[string]want{
+		"Store.Delete": {types.APIKindMethod, types.APIChangeRemoved, true},
+		"Store.Put":    {types.APIKindMethod, types.APIChangeAdded, true},
+		"Sealed.Keys":  {types.APIKindMethod, types.APIChangeAdded, false},
+		"Item.Key":     {types.APIKindField, types.APIChangeChanged, true},
+		"Item.Base":    {types.APIKindField, types.APIChangeChanged, true},
+		"Item.TTL":     {types.APIKindField, types.APIChangeAdded, false},
+		"Item.Size":    {types.APIKindMethod, types.APIChangeChanged, true},
+		"Item.Reset":   {types.APIKindMethod, types.APIChangeChanged, false},
+		"Item.Legacy":  {types.APIKindMethod, types.APIChangeRemoved, true},
+		"Open":         {types.APIKindFunction, types.APIChangeChanged, true},
+		"Status":       {types.APIKindType, types.APIChangeChanged, true},
+		"Removed":      {types.APIKindStruct, types.APIChangeRemoved, true},
+		"Added":        {types.APIKindStruct, types.APIChangeAdded, false},
+	}
+
+	got := make(map[string]types.APIChange)
+	for _, c := range result.Changes {
+		if c.Package != "example.com/lib/store" {
+			t.Errorf("main and internal packages should be ignored, got %+v", c)
+		}
+		got[c.Name] = c
+	}
+	for name, w := range expected {
+		c, ok := got[name]
+		if !ok {
+			t.Errorf("missing change for %s", name)
+			continue
+		}
+		if c.Kind != w.kind || c.Change != w.change || c.Breaking != w.breaking {
+			t.Errorf("%s: got kind=%s change=%s breaking=%v, want %+v", name, c.Kind, c.Change, c.Breaking, w)
+		}
+	}
+	// 参数改名不是变化
+	if _, ok := got["NewItem"]; ok {
+		t.Error("renaming parameters should not be reported")
+	}
+	if _, ok := got["Store.Get"]; ok {
+		t.Error("renaming interface method parameters should not be reported")
+	}
+	if len(got) != len(expected) {
+		t.Errorf("got %d changes, want %d: %+v", len(got), len(expected), result.Changes)
+	}
+	if result.Breaking != 9 || result.Compatible != 4 {
+		t.Errorf("Breaking=%d Compatible=%d, want 9 and 4", result.Breaking, result.Compatible)
+	}
+
+	size := got["Item.Size"]
+	if size.Old != "(Item) Size() int" || size.New != "(*Item) Size() int" || size.FilePath != "store/store.go" || size.Line == 0 {
+		t.Errorf("unexpected Item.Size change: %+v", size)
+	}
+	if removed := got["Item.Legacy"]; removed.Line == 0 || removed.FilePath != "store/store.go" {
+		t.Errorf("removed members should point to the old location: %+v", removed)
+	}
+}
+
+func TestDiffAPI_ImportAlias(t *testing.T) {
+	files := func(api string) map[string]string {
+		return map[string]string{
+			"go.mod":           "module example.com/lib\n",
+			"model/model.go":   "package model\n\ntype User struct{}\n",
+			"model/v2/user.go": "package model\n\ntype User struct{}\n",
+			"api/api.go":       api,
+		}
+	}
+	oldP, oldDir := writeTestProject(t, files(`package api
+
+import "example.com/lib/model"
+
+type Service struct {
+	Owner *model.User
+}
+
+func Find(id int) (*model.User, error) { return nil, nil }
+
+func Load() model.User { return model.User{} }
+`))
+	newP, newDir := writeTestProject(t, files(`package api
+
+import (
+	m "example.com/lib/model"
+	model "example.com/lib/model/v2"
+)
+
+type Service struct {
+	Owner *m.User
+}
+
+func Find(id int) (*m.User, error) { return nil, nil }
+
+func Load() model.User { return model.User{} }
+`))
+
+	result := DiffAPI(NewAPISnapshot(oldP, oldDir), NewAPISnapshot(newP, newDir))
+	got := make(map[string]types.APIChange)
+	for _, c := range result.Changes {
+		got[c.Name] = c
+	}
+	// 只改了导入别名，类型不变
+	for _, name := range []string{"Service.Owner", "Find"} {
+		if c, ok := got[name]; ok {
+			t.Errorf("renaming an import alias should not be reported: %+v", c)
+		}
+	}
+	// 限定符相同但指向另一个包
+	if c, ok := got["Load"]; !ok || !c.Breaking {
+		t.Errorf("Load now returns a type from another package and should be breaking, got %+v", result.Changes)
+	}
+}
+
+func TestAPISnapshotFromResult(t *testing.T) {
+	oldResult := &types.AnalysisResult{Structs: []types.StructAnalysis{{
+		Name: "UserService", Package: "service", ImportPath: "example.com/app/service",
+		Fields: []types.FieldAnalysis{
+			{Name: "Repo", Type: "*Repository", IsExported: true},
+			{Name: "cache", Type: "*Cache"},
+		},
+		Methods: []types.MethodAnalysis{
+			{Name: "Get", Signature: "(id int64) (*User, error)", IsExported: true, Receiver: "*UserService"},
+		},
+	}}}
+	newResult := &types.AnalysisResult{Structs: []types.StructAnalysis{{
+		Name: "UserService", Package: "service", ImportPath: "example.com/app/service",
+		Fields: []types.FieldAnalysis{
+			{Name: "cache", Type: "*Cache"},
+		},
+		Methods: []types.MethodAnalysis{
+			{Name: "Get", Signature: "(userID int64) (*User, error)", IsExported: true, Receiver: "*UserService"},
+		},
+	}}}
+
+	result := DiffAPI(APISnapshotFromResult(oldResult), APISnapshotFromResult(newResult))
+	if len(result.Changes) != 1 || result.Changes[0].Name != "UserService.Repo" || !result.Changes[0].Breaking {
+		t.Errorf("expected only the removed field, got %+v", result.Changes)
+	}
+}
+
+func TestExtractRevision(t *testing.T) {
+	dir := newTestGitRepo(t, map[string]string{"api/item.go": "package api\n\ntype Item struct{}\n"})
+
+	treeDir, cleanup, err := ExtractRevision(filepath.Join(dir, "api"), "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if filepath.Base(treeDir) != "api" {
+		t.Errorf("extracted dir = %s, want base name api", treeDir)
+	}
+	if _, err := os.Stat(filepath.Join(treeDir, "item.go")); err != nil {
+		t.Errorf("item.go should be extracted: %v", err)
+	}
+
+	// 以 - 开头的 rev 不能被当作 git 选项
+	outDir := t.TempDir()
+	if _, _, err := ExtractRevision(dir, "--output="+filepath.Join(outDir, "out.tar")); err == nil {
+		t.Error("option-like revision should be rejected")
+	}
+	if entries, _ := os.ReadDir(outDir); len(entries) > 0 {
+		t.Error("option-like revision must not be passed to git archive")
+	}
+}
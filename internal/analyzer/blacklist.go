@@ -1,7 +1,9 @@
 package analyzer
 
 import (
+	"fmt"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/user/go-struct-analyzer/internal/types"
@@ -12,13 +14,37 @@ import (
 type Blacklist struct {
 	types    map[string]bool
 	packages map[string]bool
+
+	patterns []*pattern // 类型名模式
+	paths    []*pattern // 导入路径模式
+
+	allowTypes    map[string]bool
+	allowPatterns []*pattern
+	allowPaths    []*pattern
+
+	rules []*edgeRule // 按依赖类型生效的规则
+}
+
+// pattern 表示一个已编译的 glob 或正则模式
+type pattern struct {
+	raw string
+	re  *regexp.Regexp
+}
+
+// edgeRule 表示一条已编译的依赖边规则
+type edgeRule struct {
+	typePattern *pattern
+	pathPattern *pattern
+	depTypes    map[string]bool
+	action      string
 }
 
 // NewBlacklist 创建黑名单
 func NewBlacklist() *Blacklist {
 	return &Blacklist{
-		types:    make(map[string]bool),
-		packages: make(map[string]bool),
+		types:      make(map[string]bool),
+		packages:   make(map[string]bool),
+		allowTypes: make(map[string]bool),
 	}
 }
 
@@ -38,6 +64,11 @@ func (b *Blacklist) LoadFromFile(filePath string) error {
 		return err
 	}
 
+	return b.ApplyConfig(config)
+}
+
+// ApplyConfig 应用黑名单配置
+func (b *Blacklist) ApplyConfig(config types.BlacklistConfig) error {
 	for _, t := range config.Types {
 		b.types[t] = true
 	}
@@ -46,6 +77,42 @@ func (b *Blacklist) LoadFromFile(filePath string) error {
 		b.packages[p] = true
 	}
 
+	for _, p := range config.Patterns {
+		if err := b.AddPattern(p); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range config.Paths {
+		if err := b.AddPathPattern(p); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range config.Allow.Types {
+		b.allowTypes[t] = true
+	}
+	for _, p := range config.Allow.Patterns {
+		compiled, err := compilePattern(p)
+		if err != nil {
+			return err
+		}
+		b.allowPatterns = append(b.allowPatterns, compiled)
+	}
+	for _, p := range config.Allow.Paths {
+		compiled, err := compilePattern(p)
+		if err != nil {
+			return err
+		}
+		b.allowPaths = append(b.allowPaths, compiled)
+	}
+
+	for _, rule := range config.Rules {
+		if err := b.AddRule(rule); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -59,12 +126,122 @@ func (b *Blacklist) AddPackage(pkgName string) {
 	b.packages[pkgName] = true
 }
 
+// AddPattern 添加类型名模式到黑名单
+func (b *Blacklist) AddPattern(p string) error {
+	compiled, err := compilePattern(p)
+	if err != nil {
+		return err
+	}
+	b.patterns = append(b.patterns, compiled)
+	return nil
+}
+
+// AddPathPattern 添加导入路径模式到黑名单
+func (b *Blacklist) AddPathPattern(p string) error {
+	compiled, err := compilePattern(p)
+	if err != nil {
+		return err
+	}
+	b.paths = append(b.paths, compiled)
+	return nil
+}
+
+// AddAllowType 添加类型到白名单
+func (b *Blacklist) AddAllowType(typeName string) {
+	b.allowTypes[typeName] = true
+}
+
+// AddRule 添加依赖边规则
+func (b *Blacklist) AddRule(rule types.BlacklistRule) error {
+	compiled := &edgeRule{
+		depTypes: make(map[string]bool),
+		action:   rule.Action,
+	}
+	if compiled.action == "" {
+		compiled.action = types.BlacklistActionBlock
+	}
+	if compiled.action != types.BlacklistActionBlock && compiled.action != types.BlacklistActionStop {
+		return fmt.Errorf("invalid blacklist rule action %q", rule.Action)
+	}
+	if rule.Type == "" && rule.Path == "" {
+		return fmt.Errorf("blacklist rule requires type or path")
+	}
+
+	var err error
+	if rule.Type != "" {
+		if compiled.typePattern, err = compilePattern(rule.Type); err != nil {
+			return err
+		}
+	}
+	if rule.Path != "" {
+		if compiled.pathPattern, err = compilePattern(rule.Path); err != nil {
+			return err
+		}
+	}
+	for _, depType := range rule.DepTypes {
+		compiled.depTypes[depType] = true
+	}
+
+	b.rules = append(b.rules, compiled)
+	return nil
+}
+
 // IsBlocked 检查类型是否在黑名单中
 func (b *Blacklist) IsBlocked(typeName string) bool {
+	return b.IsBlockedPath(typeName, "")
+}
+
+// IsBlockedPath 检查类型是否在黑名单中，importPath 为类型所属包的完整导入路径（可为空）
+func (b *Blacklist) IsBlockedPath(typeName, importPath string) bool {
 	// 清理类型名
 	typeName = strings.TrimPrefix(typeName, "*")
 	typeName = strings.TrimPrefix(typeName, "[]")
 
+	if b.isAllowed(typeName, importPath) {
+		return false
+	}
+
+	return b.matchesBlock(typeName, importPath)
+}
+
+// IsEdgeBlocked 检查指向该类型的某种依赖边是否被忽略
+func (b *Blacklist) IsEdgeBlocked(typeName, importPath, depType string) bool {
+	if b.IsBlockedPath(typeName, importPath) {
+		return true
+	}
+
+	typeName = strings.TrimPrefix(strings.TrimPrefix(typeName, "*"), "[]")
+	if b.isAllowed(typeName, importPath) {
+		return false
+	}
+
+	for _, rule := range b.rules {
+		if rule.action != types.BlacklistActionBlock {
+			continue
+		}
+		if len(rule.depTypes) > 0 && !rule.depTypes[depType] {
+			continue
+		}
+		if rule.matches(typeName, importPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStopped 检查类型是否命中 stop 规则（保留节点但不继续遍历其依赖）
+func (b *Blacklist) IsStopped(typeName, importPath string) bool {
+	typeName = strings.TrimPrefix(strings.TrimPrefix(typeName, "*"), "[]")
+	for _, rule := range b.rules {
+		if rule.action == types.BlacklistActionStop && rule.matches(typeName, importPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesBlock 检查类型是否命中阻止规则
+func (b *Blacklist) matchesBlock(typeName, importPath string) bool {
 	// 检查类型是否在黑名单
 	if b.types[typeName] {
 		return true
@@ -91,6 +268,42 @@ func (b *Blacklist) IsBlocked(typeName string) bool {
 		}
 	}
 
+	// 检查类型名模式
+	for _, p := range b.patterns {
+		if p.matchType(typeName) {
+			return true
+		}
+	}
+
+	// 检查导入路径模式
+	if importPath != "" {
+		for _, p := range b.paths {
+			if p.match(importPath) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isAllowed 检查类型是否在白名单中
+func (b *Blacklist) isAllowed(typeName, importPath string) bool {
+	if b.allowTypes[typeName] || b.allowTypes[shortTypeName(typeName)] {
+		return true
+	}
+	for _, p := range b.allowPatterns {
+		if p.matchType(typeName) {
+			return true
+		}
+	}
+	if importPath != "" {
+		for _, p := range b.allowPaths {
+			if p.match(importPath) {
+				return true
+			}
+		}
+	}
 	return false
 }
 
@@ -111,3 +324,101 @@ func (b *Blacklist) GetBlockedPackages() []string {
 	}
 	return result
 }
+
+// GetBlockedPatterns 获取所有类型名和导入路径模式
+func (b *Blacklist) GetBlockedPatterns() []string {
+	var result []string
+	for _, p := range b.patterns {
+		result = append(result, p.raw)
+	}
+	for _, p := range b.paths {
+		result = append(result, p.raw)
+	}
+	return result
+}
+
+// matches 检查规则是否匹配类型（类型模式和路径模式需同时满足）
+func (r *edgeRule) matches(typeName, importPath string) bool {
+	if r.typePattern != nil && !r.typePattern.matchType(typeName) {
+		return false
+	}
+	if r.pathPattern != nil && (importPath == "" || !r.pathPattern.match(importPath)) {
+		return false
+	}
+	return true
+}
+
+// compilePattern 编译模式：以 "re:" 开头为正则表达式，否则为 glob
+func compilePattern(raw string) (*pattern, error) {
+	if strings.HasPrefix(raw, "re:") {
+		re, err := regexp.Compile(strings.TrimPrefix(raw, "re:"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex pattern %q: %w", raw, err)
+		}
+		return &pattern{raw: raw, re: re}, nil
+	}
+
+	re, err := regexp.Compile(globToRegex(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %q: %w", raw, err)
+	}
+	return &pattern{raw: raw, re: re}, nil
+}
+
+// globToRegex 将 glob 转换为正则表达式
+// * 匹配除 "/" 外的任意字符，? 匹配单个非 "/" 字符，"..." 匹配任意字符（含 "/"）
+func globToRegex(glob string) string {
+	var sb strings.Builder
+	literal := 0 // 当前字面量片段的起始位置
+	flush := func(end int) {
+		sb.WriteString(regexp.QuoteMeta(glob[literal:end]))
+	}
+
+	sb.WriteString("^")
+	for i := 0; i < len(glob); {
+		var token string
+		var width int
+		switch {
+		case strings.HasPrefix(glob[i:], "/..."):
+			token, width = "(/.*)?", 4
+		case strings.HasPrefix(glob[i:], "..."):
+			token, width = ".*", 3
+		case glob[i] == '*':
+			token, width = "[^/]*", 1
+		case glob[i] == '?':
+			token, width = "[^/]", 1
+		default:
+			i++
+			continue
+		}
+		flush(i)
+		sb.WriteString(token)
+		i += width
+		literal = i
+	}
+	flush(len(glob))
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// match 检查字符串是否匹配模式
+func (p *pattern) match(s string) bool {
+	return p.re.MatchString(s)
+}
+
+// matchType 检查类型名是否匹配模式（同时尝试完整名称和不带包前缀的短名）
+func (p *pattern) matchType(typeName string) bool {
+	if p.match(typeName) {
+		return true
+	}
+	short := shortTypeName(typeName)
+	return short != typeName && p.match(short)
+}
+
+// shortTypeName 去掉类型名的包前缀
+func shortTypeName(typeName string) string {
+	if idx := strings.LastIndex(typeName, "."); idx != -1 {
+		return typeName[idx+1:]
+	}
+	return typeName
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
 )
 
 func TestNewBlacklist(t *testing.T) {
@@ -258,3 +260,173 @@ func TestBlacklist_LoadFromFile_InvalidYAML(t *testing.T) {
 		t.Error("LoadFromFile() should return error for invalid YAML")
 	}
 }
+
+func TestBlacklist_Patterns(t *testing.T) {
+	bl := NewBlacklist()
+	if err := bl.AddPattern("*Mock"); err != nil {
+		t.Fatalf("AddPattern failed: %v", err)
+	}
+	if err := bl.AddPattern("re:^Test[A-Z]"); err != nil {
+		t.Fatalf("AddPattern failed: %v", err)
+	}
+
+	tests := []struct {
+		typeName string
+		expected bool
+	}{
+		{"UserMock", true},
+		{"mocks.RepoMock", true},
+		{"TestHelper", true},
+		{"Testament", false},
+		{"UserService", false},
+	}
+	for _, tt := range tests {
+		if got := bl.IsBlocked(tt.typeName); got != tt.expected {
+			t.Errorf("IsBlocked(%q) = %v, want %v", tt.typeName, got, tt.expected)
+		}
+	}
+
+	if err := bl.AddPattern("re:[invalid"); err == nil {
+		t.Error("AddPattern should fail for invalid regex")
+	}
+}
+
+func TestBlacklist_PathPatterns(t *testing.T) {
+	bl := NewBlacklist()
+	bl.AddPathPattern("example.com/app/internal/gen/...")
+	bl.AddPathPattern("github.com/*/mocks")
+
+	tests := []struct {
+		typeName   string
+		importPath string
+		expected   bool
+	}{
+		{"Model", "example.com/app/internal/gen", true},
+		{"Model", "example.com/app/internal/gen/v1/types", true},
+		{"Model", "example.com/app/internal/generated", false},
+		{"Repo", "github.com/acme/mocks", true},
+		{"Repo", "github.com/acme/sub/mocks", false},
+		{"Repo", "", false},
+	}
+	for _, tt := range tests {
+		if got := bl.IsBlockedPath(tt.typeName, tt.importPath); got != tt.expected {
+			t.Errorf("IsBlockedPath(%q, %q) = %v, want %v", tt.typeName, tt.importPath, got, tt.expected)
+		}
+	}
+}
+
+func TestBlacklist_AllowOverridesBlock(t *testing.T) {
+	bl := NewBlacklist()
+	err := bl.ApplyConfig(types.BlacklistConfig{
+		Packages: []string{"log"},
+		Patterns: []string{"*Config"},
+		Allow: types.BlacklistAllow{
+			Types:    []string{"AppConfig"},
+			Patterns: []string{"log.Audit*"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ApplyConfig failed: %v", err)
+	}
+
+	if bl.IsBlocked("AppConfig") {
+		t.Error("AppConfig is allowed and must not be blocked")
+	}
+	if !bl.IsBlocked("DBConfig") {
+		t.Error("DBConfig should be blocked by pattern")
+	}
+	if bl.IsBlocked("log.AuditLogger") {
+		t.Error("log.AuditLogger is allowed and must not be blocked")
+	}
+	if !bl.IsBlocked("log.Logger") {
+		t.Error("log.Logger should be blocked by package")
+	}
+}
+
+func TestBlacklist_EdgeRules(t *testing.T) {
+	bl := NewBlacklist()
+	err := bl.ApplyConfig(types.BlacklistConfig{
+		Rules: []types.BlacklistRule{
+			{Type: "*Logger", DepTypes: []string{types.DepTypeMethodCall}},
+			{Path: "example.com/app/infra/...", Action: types.BlacklistActionStop},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ApplyConfig failed: %v", err)
+	}
+
+	if !bl.IsEdgeBlocked("Logger", "", types.DepTypeMethodCall) {
+		t.Error("method_call edge into Logger should be blocked")
+	}
+	if bl.IsEdgeBlocked("Logger", "", types.DepTypeField) {
+		t.Error("field edge into Logger should be kept")
+	}
+	if bl.IsBlocked("Logger") {
+		t.Error("edge-scoped rule must not block the type itself")
+	}
+
+	if !bl.IsStopped("Client", "example.com/app/infra/redis") {
+		t.Error("types under infra should be stopped")
+	}
+	if bl.IsStopped("Client", "example.com/app/service") {
+		t.Error("types outside infra should not be stopped")
+	}
+
+	if err := bl.AddRule(types.BlacklistRule{Type: "X", Action: "skip"}); err == nil {
+		t.Error("AddRule should reject unknown action")
+	}
+	if err := bl.AddRule(types.BlacklistRule{}); err == nil {
+		t.Error("AddRule should reject rule without type or path")
+	}
+}
+
+func TestTraverser_StopRule(t *testing.T) {
+	p, tmpDir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"app/app.go": `package app
+
+type Logger struct{}
+
+func (l *Logger) Info(msg string) {}
+
+type Gateway struct{ client *Client }
+type Client struct{ conn *Conn }
+type Conn struct{}
+
+type Service struct {
+	gw  *Gateway
+	log *Logger
+}
+
+func (s *Service) Run() {
+	s.log.Info("run")
+}
+`,
+	})
+
+	bl := NewBlacklist()
+	bl.AddRule(types.BlacklistRule{Type: "Gateway", Action: types.BlacklistActionStop})
+	bl.AddRule(types.BlacklistRule{Type: "Logger", DepTypes: []string{types.DepTypeMethodCall}})
+
+	filter := NewScopeFilter(p, bl)
+	result := NewTraverser(p, filter, nil, false).Analyze("Service", 5, tmpDir)
+
+	names := make(map[string]types.StructAnalysis)
+	for _, s := range result.Structs {
+		names[s.Name] = s
+	}
+	if gw, ok := names["Gateway"]; !ok || !gw.Stopped {
+		t.Errorf("Gateway should be kept and marked stopped, got %+v", names)
+	}
+	if _, ok := names["Client"]; ok {
+		t.Error("traversal must not continue past a stopped node")
+	}
+
+	svc := names["Service"]
+	if findDep(svc.Dependencies, "Logger", types.DepTypeField) == nil {
+		t.Error("field edge into Logger should be kept")
+	}
+	if findDep(svc.Dependencies, "Logger", types.DepTypeMethodCall) != nil {
+		t.Error("method_call edge into Logger should be removed by edge rule")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,11 +22,21 @@ type CacheEntry struct {
 	LLMProvider string                   `json:"llm_provider"`
 }
 
+// RoleCacheEntry LLM 角色判定缓存条目
+type RoleCacheEntry struct {
+	StructName  string    `json:"struct_name"`
+	SourceHash  string    `json:"source_hash"` // 源码和可选角色的哈希
+	Role        string    `json:"role"`        // 判定的角色（为空表示 LLM 无法判定）
+	CachedAt    time.Time `json:"cached_at"`
+	LLMProvider string    `json:"llm_provider"`
+}
+
 // AnalysisCache 分析结果缓存
 type AnalysisCache struct {
-	Entries   map[string]*CacheEntry `json:"entries"` // key: structName
-	Version   string                 `json:"version"`
-	UpdatedAt time.Time              `json:"updated_at"`
+	Entries   map[string]*CacheEntry     `json:"entries"`         // key: structName
+	Roles     map[string]*RoleCacheEntry `json:"roles,omitempty"` // key: structName
+	Version   string                     `json:"version"`
+	UpdatedAt time.Time                  `json:"updated_at"`
 	mu        sync.RWMutex
 	filePath  string
 	dirty     bool // 是否有未保存的更改
@@ -40,6 +51,7 @@ const (
 func NewAnalysisCache(projectPath string) *AnalysisCache {
 	cache := &AnalysisCache{
 		Entries:   make(map[string]*CacheEntry),
+		Roles:     make(map[string]*RoleCacheEntry),
 		Version:   CacheVersion,
 		UpdatedAt: time.Now(),
 		filePath:  filepath.Join(projectPath, CacheFileName),
@@ -73,10 +85,15 @@ func (c *AnalysisCache) Load() error {
 	if loaded.Version != CacheVersion {
 		// 版本不匹配，清空缓存
 		c.Entries = make(map[string]*CacheEntry)
+		c.Roles = make(map[string]*RoleCacheEntry)
 		return nil
 	}
 
 	c.Entries = loaded.Entries
+	c.Roles = loaded.Roles
+	if c.Roles == nil {
+		c.Roles = make(map[string]*RoleCacheEntry)
+	}
 	c.UpdatedAt = loaded.UpdatedAt
 	return nil
 }
@@ -144,12 +161,40 @@ func (c *AnalysisCache) Set(structName, sourceCode, llmProvider string, result *
 	c.dirty = true
 }
 
+// GetRole 获取缓存的 LLM 角色判定，源码、可选角色或 LLM 提供商变化时缓存失效
+func (c *AnalysisCache) GetRole(structName, sourceCode, llmProvider string, roles []string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.Roles[structName]
+	if !ok || entry.SourceHash != hashRoleSource(sourceCode, roles) || entry.LLMProvider != llmProvider {
+		return "", false
+	}
+	return entry.Role, true
+}
+
+// SetRole 设置 LLM 角色判定缓存
+func (c *AnalysisCache) SetRole(structName, sourceCode, llmProvider string, roles []string, role string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Roles[structName] = &RoleCacheEntry{
+		StructName:  structName,
+		SourceHash:  hashRoleSource(sourceCode, roles),
+		Role:        role,
+		CachedAt:    time.Now(),
+		LLMProvider: llmProvider,
+	}
+	c.dirty = true
+}
+
 // Clear 清空缓存
 func (c *AnalysisCache) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.Entries = make(map[string]*CacheEntry)
+	c.Roles = make(map[string]*RoleCacheEntry)
 	c.dirty = true
 }
 
@@ -165,3 +210,8 @@ func hashSource(source string) string {
 	hash := sha256.Sum256([]byte(source))
 	return hex.EncodeToString(hash[:8]) // 使用前8字节（16字符）
 }
+
+// hashRoleSource 计算角色判定缓存的哈希（可选角色变化时缓存失效）
+func hashRoleSource(source string, roles []string) string {
+	return hashSource(source + "\n" + strings.Join(roles, ","))
+}
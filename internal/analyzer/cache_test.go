@@ -182,3 +182,46 @@ func TestHashSource(t *testing.T) {
 		t.Errorf("Hash length: got %d, want 16", len(hash1))
 	}
 }
+
+func TestAnalysisCache_Roles(t *testing.T) {
+	tmpDir := t.TempDir()
+	roles := []string{"handler", "service"}
+
+	cache := NewAnalysisCache(tmpDir)
+	if _, ok := cache.GetRole("Widget", "source", "glm", roles); ok {
+		t.Error("Expected cache miss on empty cache")
+	}
+	cache.SetRole("Widget", "source", "glm", roles, "service")
+	cache.SetRole("Gadget", "source", "glm", roles, "") // LLM 无法判定的结果也缓存
+
+	if role, ok := cache.GetRole("Widget", "source", "glm", roles); !ok || role != "service" {
+		t.Errorf("GetRole = %q, %v, want service, true", role, ok)
+	}
+	if role, ok := cache.GetRole("Gadget", "source", "glm", roles); !ok || role != "" {
+		t.Errorf("GetRole = %q, %v, want empty role cached", role, ok)
+	}
+
+	// 源码、可选角色或 LLM 提供商变化时缓存失效
+	if _, ok := cache.GetRole("Widget", "changed", "glm", roles); ok {
+		t.Error("Expected cache miss after source change")
+	}
+	if _, ok := cache.GetRole("Widget", "source", "glm", []string{"handler", "service", "gateway"}); ok {
+		t.Error("Expected cache miss after roles change")
+	}
+	if _, ok := cache.GetRole("Widget", "source", "claude", roles); ok {
+		t.Error("Expected cache miss with different provider")
+	}
+
+	// 角色缓存与描述缓存一起保存和加载
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if role, ok := NewAnalysisCache(tmpDir).GetRole("Widget", "source", "glm", roles); !ok || role != "service" {
+		t.Errorf("loaded GetRole = %q, %v, want service, true", role, ok)
+	}
+
+	cache.Clear()
+	if _, ok := cache.GetRole("Widget", "source", "glm", roles); ok {
+		t.Error("Expected cache miss after clear")
+	}
+}
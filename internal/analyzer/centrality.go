@@ -0,0 +1,143 @@
+package analyzer
+
+import "math"
+
+// PageRank 迭代参数
+const (
+	pageRankDamping    = 0.85
+	pageRankIterations = 100
+	pageRankTolerance  = 1e-10
+)
+
+// PageRank 计算依赖图上每个节点的 PageRank：边从依赖方指向被依赖方，
+// 被越多（且越重要）的结构体依赖，值越大。没有出边的节点把权重平均分给所有节点，全部节点之和为 1
+func (g *Graph) PageRank() map[string]float64 {
+	nodes := g.Nodes()
+	n := float64(len(nodes))
+	rank := make(map[string]float64, len(nodes))
+	if len(nodes) == 0 {
+		return rank
+	}
+	for _, node := range nodes {
+		rank[node] = 1 / n
+	}
+
+	for i := 0; i < pageRankIterations; i++ {
+		dangling := 0.0
+		for _, node := range nodes {
+			if len(g.edges[node]) == 0 {
+				dangling += rank[node]
+			}
+		}
+
+		next := make(map[string]float64, len(nodes))
+		base := (1-pageRankDamping)/n + pageRankDamping*dangling/n
+		for _, node := range nodes {
+			next[node] = base
+		}
+		for _, node := range nodes {
+			succ := g.Successors(node)
+			for _, to := range succ {
+				next[to] += pageRankDamping * rank[node] / float64(len(succ))
+			}
+		}
+
+		delta := 0.0
+		for _, node := range nodes {
+			delta += math.Abs(next[node] - rank[node])
+		}
+		rank = next
+		if delta < pageRankTolerance {
+			break
+		}
+	}
+	return rank
+}
+
+// Betweenness 计算每个节点的介数中心性（Brandes 算法，有向、无权）：
+// 经过该节点的最短路径所占比例之和，按 (n-1)(n-2) 归一化到 [0, 1]。
+// 值越大，越多的依赖链要经过该结构体
+func (g *Graph) Betweenness() map[string]float64 {
+	nodes := g.Nodes()
+	centrality := make(map[string]float64, len(nodes))
+	for _, node := range nodes {
+		centrality[node] = 0
+	}
+
+	for _, s := range nodes {
+		var stack []string
+		preds := make(map[string][]string)
+		sigma := map[string]float64{s: 1}
+		dist := map[string]int{s: 0}
+
+		queue := []string{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, w := range g.Successors(v) {
+				if _, seen := dist[w]; !seen {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+
+		delta := make(map[string]float64)
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				centrality[w] += delta[w]
+			}
+		}
+	}
+
+	if n := float64(len(nodes)); n > 2 {
+		for node := range centrality {
+			centrality[node] /= (n - 1) * (n - 2)
+		}
+	}
+	return centrality
+}
+
+// ReachCount 计算每个节点的传递闭包大小：能够（直接或间接）到达该节点的其他节点数，
+// 即该结构体改动时可能受影响的结构体数量
+func (g *Graph) ReachCount() map[string]int {
+	nodes := g.Nodes()
+	preds := make(map[string][]string, len(nodes))
+	for _, node := range nodes {
+		for _, to := range g.Successors(node) {
+			preds[to] = append(preds[to], node)
+		}
+	}
+
+	counts := make(map[string]int, len(nodes))
+	for _, node := range nodes {
+		visited := map[string]bool{node: true}
+		queue := []string{node}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			for _, pred := range preds[v] {
+				if !visited[pred] {
+					visited[pred] = true
+					queue = append(queue, pred)
+				}
+			}
+		}
+		counts[node] = len(visited) - 1
+	}
+	return counts
+}
+
+// round4 保留四位小数
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+func TestGraph_Centrality(t *testing.T) {
+	// A -> B -> C，D -> B：C 被所有结构体间接依赖，B 位于所有到 C 的路径上
+	g := buildTestGraph(
+		[3]string{"A", "B", types.DepTypeField},
+		[3]string{"B", "C", types.DepTypeField},
+		[3]string{"D", "B", types.DepTypeInit},
+	)
+
+	wantReach := map[string]int{"A": 0, "B": 2, "C": 3, "D": 0}
+	if got := g.ReachCount(); !reflect.DeepEqual(got, wantReach) {
+		t.Errorf("ReachCount() = %v, want %v", got, wantReach)
+	}
+
+	// B 位于 A->C 和 D->C 两条最短路径上，归一化系数 (4-1)(4-2) = 6
+	betweenness := g.Betweenness()
+	if math.Abs(betweenness["B"]-2.0/6) > 1e-9 || betweenness["A"] != 0 || betweenness["C"] != 0 {
+		t.Errorf("Betweenness() = %v", betweenness)
+	}
+
+	rank := g.PageRank()
+	sum := 0.0
+	for _, v := range rank {
+		sum += v
+	}
+	if math.Abs(sum-1) > 1e-6 {
+		t.Errorf("PageRank should sum to 1, got %v", sum)
+	}
+	if !(rank["C"] > rank["B"] && rank["B"] > rank["A"]) || math.Abs(rank["A"]-rank["D"]) > 1e-9 {
+		t.Errorf("unexpected PageRank order: %v", rank)
+	}
+}
+
+func TestGraph_CentralityCycle(t *testing.T) {
+	// 环上的节点互相可达，自环不计入影响范围
+	g := buildTestGraph(
+		[3]string{"A", "B", types.DepTypeField},
+		[3]string{"B", "A", types.DepTypeField},
+		[3]string{"B", "B", types.DepTypeMethodCall},
+	)
+
+	if got := g.ReachCount(); got["A"] != 1 || got["B"] != 1 {
+		t.Errorf("ReachCount() = %v, want 1 for both nodes", got)
+	}
+	if got := g.Betweenness(); got["A"] != 0 || got["B"] != 0 {
+		t.Errorf("Betweenness() = %v, want 0 for both nodes", got)
+	}
+	if got := NewGraph().PageRank(); len(got) != 0 {
+		t.Errorf("PageRank() of empty graph = %v", got)
+	}
+}
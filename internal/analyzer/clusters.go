@@ -0,0 +1,356 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// DetectClusters 在项目全部结构体的依赖图上做社区划分（Louvain），并与实际包布局比较：
+// 两个结构体之间的权重为两个方向上的依赖数，依赖方向不影响划分。
+// depTypes 为空时包含全部依赖类型
+func DetectClusters(p *parser.Parser, filter *ScopeFilter, depTypes []string) types.ClusterResult {
+	result := types.ClusterResult{DepTypes: depTypes}
+
+	structs := AnalyzeProject(p, filter)
+	pkgOf := make(map[string]string, len(structs))
+	for _, s := range structs {
+		pkgOf[s.Name] = s.ImportPath
+	}
+
+	// 1. 无向加权图（只包含项目内结构体，忽略自环）
+	g := BuildGraph(structs, depTypes)
+	weights := make(map[string]map[string]int)
+	addWeight := func(a, b string, w int) {
+		if weights[a] == nil {
+			weights[a] = make(map[string]int)
+		}
+		weights[a][b] += w
+	}
+	for _, from := range g.Nodes() {
+		if _, ok := pkgOf[from]; !ok {
+			continue
+		}
+		for _, to := range g.Successors(from) {
+			if _, ok := pkgOf[to]; !ok || to == from {
+				continue
+			}
+			w := len(g.EdgeDeps(from, to))
+			addWeight(from, to, w)
+			addWeight(to, from, w)
+		}
+	}
+
+	var nodes []string
+	for _, s := range structs {
+		if len(weights[s.Name]) == 0 {
+			result.Isolated = append(result.Isolated, s.Name)
+			continue
+		}
+		nodes = append(nodes, s.Name)
+	}
+	for _, a := range nodes {
+		for b, w := range weights[a] {
+			if a < b {
+				result.Edges = append(result.Edges, types.ClusterEdge{From: a, To: b, Weight: w})
+			}
+		}
+	}
+	sort.Slice(result.Edges, func(i, j int) bool {
+		if result.Edges[i].From != result.Edges[j].From {
+			return result.Edges[i].From < result.Edges[j].From
+		}
+		return result.Edges[i].To < result.Edges[j].To
+	})
+	if len(nodes) == 0 {
+		return result
+	}
+
+	// 2. 社区划分
+	index := make(map[string]int, len(nodes))
+	for i, name := range nodes {
+		index[name] = i
+	}
+	lg := newLouvainGraph(len(nodes))
+	for _, e := range result.Edges {
+		lg.addEdge(index[e.From], index[e.To], float64(e.Weight))
+	}
+	community := louvain(lg)
+
+	pkgCommunity := make([]int, len(nodes))
+	pkgIndex := make(map[string]int)
+	for i, name := range nodes {
+		if _, ok := pkgIndex[pkgOf[name]]; !ok {
+			pkgIndex[pkgOf[name]] = len(pkgIndex)
+		}
+		pkgCommunity[i] = pkgIndex[pkgOf[name]]
+	}
+	result.Modularity = round4(lg.modularity(community))
+	result.PackageModularity = round4(lg.modularity(pkgCommunity))
+
+	// 3. 社区按大小降序编号
+	members := make(map[int][]string)
+	for i, name := range nodes {
+		members[community[i]] = append(members[community[i]], name)
+	}
+	var groups [][]string
+	for _, names := range members {
+		sort.Strings(names)
+		groups = append(groups, names)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i]) != len(groups[j]) {
+			return len(groups[i]) > len(groups[j])
+		}
+		return groups[i][0] < groups[j][0]
+	})
+	clusterOf := make(map[string]int, len(nodes))
+	for i, names := range groups {
+		for _, name := range names {
+			clusterOf[name] = i + 1
+		}
+	}
+
+	for i, names := range groups {
+		c := types.Cluster{ID: i + 1, Structs: names}
+		byPkg := make(map[string][]string)
+		for _, name := range names {
+			byPkg[pkgOf[name]] = append(byPkg[pkgOf[name]], name)
+			for other, w := range weights[name] {
+				if clusterOf[other] == c.ID {
+					c.Internal += w
+				} else {
+					c.External += w
+				}
+			}
+		}
+		c.Internal /= 2
+		for path, pkgStructs := range byPkg {
+			c.Packages = append(c.Packages, types.ClusterPackage{ImportPath: path, Structs: pkgStructs})
+		}
+		sort.Slice(c.Packages, func(a, b int) bool {
+			if len(c.Packages[a].Structs) != len(c.Packages[b].Structs) {
+				return len(c.Packages[a].Structs) > len(c.Packages[b].Structs)
+			}
+			return c.Packages[a].ImportPath < c.Packages[b].ImportPath
+		})
+		c.Package = c.Packages[0].ImportPath
+		result.Clusters = append(result.Clusters, c)
+	}
+
+	// 4. 与包布局比较
+	result.Misplaced = misplacedStructs(result.Clusters, weights, pkgOf, clusterOf)
+	result.SplitPackages = splitPackages(result.Clusters)
+	return result
+}
+
+// misplacedStructs 找出所在包在社区中不是多数的结构体（并列时不算），建议移入社区中成员最多的包
+func misplacedStructs(clusters []types.Cluster, weights map[string]map[string]int, pkgOf map[string]string, clusterOf map[string]int) []types.MisplacedStruct {
+	var misplaced []types.MisplacedStruct
+	for _, c := range clusters {
+		majority := len(c.Packages[0].Structs)
+		for _, group := range c.Packages {
+			if len(group.Structs) == majority {
+				continue
+			}
+			for _, name := range group.Structs {
+				m := types.MisplacedStruct{Struct: name, Package: group.ImportPath, Cluster: c.ID, Suggested: c.Package}
+				for other, w := range weights[name] {
+					if pkgOf[other] == group.ImportPath {
+						m.PackageLinks += w
+					}
+					if clusterOf[other] == c.ID {
+						m.ClusterLinks += w
+					}
+				}
+				misplaced = append(misplaced, m)
+			}
+		}
+	}
+	sort.Slice(misplaced, func(i, j int) bool { return misplaced[i].Struct < misplaced[j].Struct })
+	return misplaced
+}
+
+// splitPackages 找出在至少两个社区中各有两个以上结构体的包
+func splitPackages(clusters []types.Cluster) []types.PackageSplit {
+	parts := make(map[string][]types.PackagePart)
+	for _, c := range clusters {
+		for _, group := range c.Packages {
+			parts[group.ImportPath] = append(parts[group.ImportPath], types.PackagePart{Cluster: c.ID, Structs: group.Structs})
+		}
+	}
+
+	var splits []types.PackageSplit
+	for path, pkgParts := range parts {
+		large := 0
+		for _, part := range pkgParts {
+			if len(part.Structs) >= 2 {
+				large++
+			}
+		}
+		if large < 2 {
+			continue
+		}
+		// 社区按大小降序编号，同一个包的成员也就按数量降序排列
+		splits = append(splits, types.PackageSplit{Package: path, Parts: pkgParts})
+	}
+	sort.Slice(splits, func(i, j int) bool { return splits[i].Package < splits[j].Package })
+	return splits
+}
+
+// louvainGraph 表示 Louvain 算法使用的无向加权图
+// 自环 adj[i][i] 记录两倍的内部权重，使节点度数等于邻接权重之和
+type louvainGraph struct {
+	adj []map[int]float64
+}
+
+// newLouvainGraph 创建 n 个节点的空图
+func newLouvainGraph(n int) *louvainGraph {
+	g := &louvainGraph{adj: make([]map[int]float64, n)}
+	for i := range g.adj {
+		g.adj[i] = make(map[int]float64)
+	}
+	return g
+}
+
+// addEdge 添加一条无向边
+func (g *louvainGraph) addEdge(a, b int, w float64) {
+	g.adj[a][b] += w
+	g.adj[b][a] += w
+}
+
+// degree 返回节点的加权度数
+func (g *louvainGraph) degree(i int) float64 {
+	d := 0.0
+	for _, w := range g.adj[i] {
+		d += w
+	}
+	return d
+}
+
+// neighbors 返回节点的邻居（已排序，保证结果确定）
+func (g *louvainGraph) neighbors(i int) []int {
+	var ns []int
+	for j := range g.adj[i] {
+		ns = append(ns, j)
+	}
+	sort.Ints(ns)
+	return ns
+}
+
+// modularity 计算划分的模块度 Q = Σ_c [in_c / 2m - (tot_c / 2m)²]
+func (g *louvainGraph) modularity(community []int) float64 {
+	m2 := 0.0
+	in := make(map[int]float64)
+	tot := make(map[int]float64)
+	for i := range g.adj {
+		for j, w := range g.adj[i] {
+			m2 += w
+			tot[community[i]] += w
+			if community[i] == community[j] {
+				in[community[i]] += w
+			}
+		}
+	}
+	if m2 == 0 {
+		return 0
+	}
+	q := 0.0
+	for c, t := range tot {
+		q += in[c]/m2 - (t/m2)*(t/m2)
+	}
+	return q
+}
+
+// louvain 执行 Louvain 社区划分，返回每个节点所属的社区编号
+// 每一轮先逐个移动节点到模块度增益最大的相邻社区，再把社区合并为节点，直到不再变化
+func louvain(g *louvainGraph) []int {
+	n := len(g.adj)
+	community := make([]int, n)
+	for i := range community {
+		community[i] = i
+	}
+
+	for {
+		level, moved := louvainLevel(g)
+		if !moved {
+			return community
+		}
+
+		// 社区重新编号并合并为节点
+		renumber := make(map[int]int)
+		for _, c := range level {
+			if _, ok := renumber[c]; !ok {
+				renumber[c] = len(renumber)
+			}
+		}
+		next := newLouvainGraph(len(renumber))
+		for i := range g.adj {
+			for j, w := range g.adj[i] {
+				next.adj[renumber[level[i]]][renumber[level[j]]] += w
+			}
+		}
+		for i := range community {
+			community[i] = renumber[level[community[i]]]
+		}
+		g = next
+	}
+}
+
+// louvainLevel 执行一轮局部移动，返回每个节点的社区以及是否有节点移动过
+func louvainLevel(g *louvainGraph) ([]int, bool) {
+	n := len(g.adj)
+	community := make([]int, n)
+	degree := make([]float64, n)
+	tot := make([]float64, n)
+	m2 := 0.0
+	for i := 0; i < n; i++ {
+		community[i] = i
+		degree[i] = g.degree(i)
+		tot[i] = degree[i]
+		m2 += degree[i]
+	}
+	if m2 == 0 {
+		return community, false
+	}
+
+	movedAny := false
+	for {
+		moved := false
+		for i := 0; i < n; i++ {
+			current := community[i]
+			tot[current] -= degree[i]
+
+			// 与每个相邻社区之间的权重
+			links := make(map[int]float64)
+			for _, j := range g.neighbors(i) {
+				if j != i {
+					links[community[j]] += g.adj[i][j]
+				}
+			}
+
+			best := current
+			bestGain := links[current] - tot[current]*degree[i]/m2
+			var candidates []int
+			for c := range links {
+				candidates = append(candidates, c)
+			}
+			sort.Ints(candidates)
+			for _, c := range candidates {
+				if gain := links[c] - tot[c]*degree[i]/m2; gain > bestGain+1e-12 {
+					best, bestGain = c, gain
+				}
+			}
+
+			community[i] = best
+			tot[best] += degree[i]
+			if best != current {
+				moved, movedAny = true, true
+			}
+		}
+		if !moved {
+			return community, movedAny
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+func TestLouvain(t *testing.T) {
+	// 两个四元完全图之间只有一条桥
+	g := newLouvainGraph(8)
+	for _, group := range [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}} {
+		for i, a := range group {
+			for _, b := range group[i+1:] {
+				g.addEdge(a, b, 1)
+			}
+		}
+	}
+	g.addEdge(3, 4, 1)
+
+	community := louvain(g)
+	for i := 1; i < 8; i++ {
+		same := community[i] == community[0]
+		if same != (i < 4) {
+			t.Fatalf("louvain() = %v, want the two cliques as communities", community)
+		}
+	}
+	// 每个社区 in = 12，tot = 13，2m = 26：Q = 2 × (12/26 - 0.25)
+	if q := g.modularity(community); q < 0.4230 || q > 0.4231 {
+		t.Errorf("modularity = %v, want ≈0.4231", q)
+	}
+}
+
+func TestDetectClusters(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/shop\n",
+		"order/order.go": `package order
+
+import "example.com/shop/shared"
+
+type Order struct {
+	Items []OrderItem
+	Repo  *OrderRepo
+	Cart  *shared.Cart
+}
+
+type OrderItem struct {
+	Order *Order
+	Line  *shared.CartLine
+}
+
+type OrderRepo struct {
+	Last *Order
+	Item *OrderItem
+	Cart *shared.Cart
+	Line *shared.CartLine
+}
+`,
+		"billing/billing.go": `package billing
+
+import (
+	"example.com/shop/order"
+	"example.com/shop/shared"
+)
+
+type Invoice struct {
+	Payment *Payment
+	Ledger  *Ledger
+	Account *shared.Account
+}
+
+type Payment struct {
+	Invoice *Invoice
+	Ledger  *Ledger
+}
+
+type Ledger struct {
+	Last  *Payment
+	Limit *shared.AccountLimit
+}
+
+// Refund 只和订单打交道
+type Refund struct {
+	Order *order.Order
+	Item  *order.OrderItem
+	Repo  *order.OrderRepo
+}
+`,
+		"shared/shared.go": `package shared
+
+type Cart struct{ Lines []CartLine }
+
+type CartLine struct{ Cart *Cart }
+
+type Account struct{ Limit *AccountLimit }
+
+type AccountLimit struct{ Account *Account }
+
+type Unused struct{ n int }
+`,
+	})
+
+	result := DetectClusters(p, NewScopeFilter(p, NewBlacklist()), nil)
+
+	if len(result.Clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %+v", result.Clusters)
+	}
+	clusterOf := make(map[string]int)
+	for _, c := range result.Clusters {
+		for _, name := range c.Structs {
+			clusterOf[name] = c.ID
+		}
+	}
+	for _, group := range [][]string{
+		{"Order", "OrderItem", "OrderRepo", "Refund", "Cart", "CartLine"},
+		{"Invoice", "Payment", "Ledger", "Account", "AccountLimit"},
+	} {
+		for _, name := range group[1:] {
+			if clusterOf[name] != clusterOf[group[0]] {
+				t.Errorf("%s should cluster with %s, got %v", name, group[0], clusterOf)
+			}
+		}
+	}
+	if clusterOf["Order"] == clusterOf["Invoice"] {
+		t.Error("order and billing should be separate clusters")
+	}
+
+	if !reflect.DeepEqual(result.Isolated, []string{"Unused"}) {
+		t.Errorf("Isolated = %v, want [Unused]", result.Isolated)
+	}
+	if result.Modularity <= result.PackageModularity {
+		t.Errorf("detected modularity %v should beat package modularity %v", result.Modularity, result.PackageModularity)
+	}
+
+	misplaced := make(map[string]types.MisplacedStruct)
+	for _, m := range result.Misplaced {
+		misplaced[m.Struct] = m
+	}
+	refund, ok := misplaced["Refund"]
+	if !ok || refund.Package != "example.com/shop/billing" || refund.Suggested != "example.com/shop/order" ||
+		refund.PackageLinks != 0 || refund.ClusterLinks != 3 {
+		t.Errorf("Refund should be misplaced into order, got %+v", refund)
+	}
+	if _, ok := misplaced["Invoice"]; ok {
+		t.Error("Invoice is in the majority package of its cluster")
+	}
+
+	if len(result.SplitPackages) != 1 || result.SplitPackages[0].Package != "example.com/shop/shared" ||
+		len(result.SplitPackages[0].Parts) != 2 {
+		t.Fatalf("shared should be split into 2 parts, got %+v", result.SplitPackages)
+	}
+}
@@ -0,0 +1,332 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// DefaultRoots 未配置根时使用的根
+var DefaultRoots = []string{types.RootMain, types.RootExported}
+
+// 可达性图中的节点前缀
+const (
+	typeNode = "type:"
+	funcNode = "func:"
+)
+
+// ValidateRoots 检查根配置：内置根或合法的类型名模式
+func ValidateRoots(roots []string) error {
+	for _, root := range roots {
+		if root == types.RootMain || root == types.RootExported {
+			continue
+		}
+		if _, err := compilePattern(root); err != nil {
+			return fmt.Errorf("root %q: %w", root, err)
+		}
+	}
+	return nil
+}
+
+// reachability 记录代码单元（函数、结构体的方法）引用的类型和函数
+type reachability struct {
+	parser *parser.Parser
+	refs   map[string]map[string]bool // 节点 -> 引用的节点
+	roots  map[string]bool
+	funcs  map[string]bool // 项目中的包级函数（导入路径.函数名）
+}
+
+// FindDeadCode 在全项目依赖图上从根出发标记可达的类型和构造函数，报告不可达的声明，
+// 以及没有实现或只有一个实现的接口。被黑名单或注释忽略的类型不参与报告
+func FindDeadCode(p *parser.Parser, filter *ScopeFilter, roots []string) (types.DeadCodeReport, error) {
+	return findDeadCode(p, filter, BuildGraph(AnalyzeProject(p, filter), nil), roots)
+}
+
+// findDeadCode 在已构建的全项目依赖图 g 上检测死代码（见 FindDeadCode）
+func findDeadCode(p *parser.Parser, filter *ScopeFilter, g *Graph, roots []string) (types.DeadCodeReport, error) {
+	if len(roots) == 0 {
+		roots = DefaultRoots
+	}
+	report := types.DeadCodeReport{Roots: roots}
+
+	var patterns []*pattern
+	useMain, useExported := false, false
+	for _, root := range roots {
+		switch root {
+		case types.RootMain:
+			useMain = true
+		case types.RootExported:
+			useExported = true
+		default:
+			pat, err := compilePattern(root)
+			if err != nil {
+				return report, fmt.Errorf("root %q: %w", root, err)
+			}
+			patterns = append(patterns, pat)
+		}
+	}
+
+	r := &reachability{
+		parser: p,
+		refs:   make(map[string]map[string]bool),
+		roots:  make(map[string]bool),
+		funcs:  make(map[string]bool),
+	}
+	files := p.GetAllFiles()
+	for filePath, file := range files {
+		importPath := p.ImportPathOf(filePath)
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil {
+				r.funcs[funcKey(importPath, funcDecl.Name.Name)] = true
+			}
+		}
+	}
+
+	// 1. 结构体之间的依赖边
+	for _, from := range g.Nodes() {
+		for _, to := range g.Successors(from) {
+			r.addRef(typeNode+from, typeNode+to)
+		}
+	}
+
+	// 2. 函数和方法中引用的类型与函数
+	for filePath, file := range files {
+		isMain := file.Name.Name == "main"
+		isLibrary := !isMain && !isInternalPackage(p.ImportPathOf(filePath))
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			var node string
+			if funcDecl.Recv != nil {
+				node = typeNode + receiverTypeName(funcDecl)
+			} else {
+				node = funcNode + funcKey(p.ImportPathOf(filePath), funcDecl.Name.Name)
+				if isMain && useMain || isLibrary && useExported && funcDecl.Name.IsExported() {
+					r.roots[node] = true
+				}
+			}
+			r.collectRefs(node, funcDecl, filePath)
+		}
+	}
+
+	// 3. 构造函数可达时其返回的结构体可达
+	for _, fn := range p.GetAllFunctions() {
+		r.addRef(funcNode+constructorKey(p, fn), typeNode+parser.ExtractBaseType(fn.ReturnType))
+	}
+
+	// 4. 根类型
+	isRootType := func(name, pkgName, importPath string) bool {
+		if useMain && pkgName == "main" {
+			return true
+		}
+		if useExported && pkgName != "main" && ast.IsExported(name) && !isInternalPackage(importPath) {
+			return true
+		}
+		for _, pat := range patterns {
+			if pat.matchType(pkgName+"."+name) || importPath != "" && pat.match(importPath+"."+name) {
+				return true
+			}
+		}
+		return false
+	}
+	for name, info := range p.GetAllStructs() {
+		if isRootType(name, info.Package, info.ImportPath) {
+			r.roots[typeNode+name] = true
+		}
+	}
+	for name, iface := range p.GetAllInterfaces() {
+		if isRootType(name, iface.Package, iface.ImportPath) {
+			r.roots[typeNode+name] = true
+		}
+	}
+
+	// 根类型的构造函数也是根
+	for _, fn := range p.GetAllFunctions() {
+		if r.roots[typeNode+parser.ExtractBaseType(fn.ReturnType)] {
+			r.roots[funcNode+constructorKey(p, fn)] = true
+		}
+	}
+
+	reached := r.walk()
+
+	// 5. 汇总
+	for name, info := range p.GetAllStructs() {
+		if !reached[typeNode+name] && filter.ShouldAnalyze(name) {
+			report.UnreachableStructs = append(report.UnreachableStructs, types.DeadDecl{Name: name, Package: info.Package, FilePath: info.FilePath})
+		}
+	}
+	for _, fn := range p.GetAllFunctions() {
+		ret := parser.ExtractBaseType(fn.ReturnType)
+		if !reached[funcNode+constructorKey(p, fn)] && filter.ShouldAnalyze(ret) {
+			report.UnusedConstructors = append(report.UnusedConstructors, types.DeadDecl{Name: fn.Name, Package: fn.Package, FilePath: fn.FilePath})
+		}
+	}
+
+	implementers := make(map[string][]string)
+	for _, from := range g.Nodes() {
+		for _, to := range g.Successors(from) {
+			for _, dep := range g.EdgeDeps(from, to) {
+				if dep.Type == types.DepTypeInterface {
+					implementers[to] = append(implementers[to], from)
+					break
+				}
+			}
+		}
+	}
+	for name, iface := range p.GetAllInterfaces() {
+		if !filter.ShouldAnalyze(name) {
+			continue
+		}
+		decl := types.DeadDecl{Name: name, Package: iface.Package, FilePath: iface.FilePath}
+		if !reached[typeNode+name] {
+			report.UnreachableInterfaces = append(report.UnreachableInterfaces, decl)
+		}
+		switch impls := implementers[name]; len(impls) {
+		case 0:
+			report.UnimplementedInterfaces = append(report.UnimplementedInterfaces, decl)
+		case 1:
+			report.SingleImplInterfaces = append(report.SingleImplInterfaces, types.InterfaceImpl{
+				Interface:   name,
+				Package:     iface.Package,
+				Implementer: impls[0],
+				FilePath:    iface.FilePath,
+			})
+		}
+	}
+
+	for _, list := range [][]types.DeadDecl{report.UnreachableStructs, report.UnreachableInterfaces, report.UnusedConstructors, report.UnimplementedInterfaces} {
+		sortDeadDecls(list)
+	}
+	sort.Slice(report.SingleImplInterfaces, func(i, j int) bool {
+		return report.SingleImplInterfaces[i].Interface < report.SingleImplInterfaces[j].Interface
+	})
+	return report, nil
+}
+
+// addRef 记录 from 引用了 to
+func (r *reachability) addRef(from, to string) {
+	if r.refs[from] == nil {
+		r.refs[from] = make(map[string]bool)
+	}
+	r.refs[from][to] = true
+}
+
+// funcKey 返回包级函数在可达性图中的键（导入路径.函数名），避免不同目录下同名包的函数相互混淆
+func funcKey(importPath, name string) string {
+	return importPath + "." + name
+}
+
+// constructorKey 返回构造函数在可达性图中的键
+func constructorKey(p *parser.Parser, fn *types.FunctionInfo) string {
+	return funcKey(p.ImportPathOf(fn.FilePath), fn.Name)
+}
+
+// collectRefs 记录函数签名和函数体中引用的项目类型和包级函数（包括普通辅助函数）
+func (r *reachability) collectRefs(node string, funcDecl *ast.FuncDecl, filePath string) {
+	ownImportPath := r.parser.ImportPathOf(filePath)
+	dir := filepath.Dir(filePath)
+	imports := r.parser.GetImports(filePath)
+
+	ast.Inspect(funcDecl, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.SelectorExpr:
+			ident, ok := e.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			importPath, ok := imports[ident.Name]
+			if !ok {
+				return true
+			}
+			name := e.Sel.Name
+			if info := r.parser.GetStruct(name); info != nil && info.ImportPath == importPath {
+				r.addRef(node, typeNode+name)
+			} else if iface := r.parser.GetInterface(name); iface != nil && iface.ImportPath == importPath {
+				r.addRef(node, typeNode+name)
+			} else if key := funcKey(importPath, name); r.funcs[key] {
+				r.addRef(node, funcNode+key)
+			}
+			return false
+
+		case *ast.Ident:
+			name := e.Name
+			if info := r.parser.GetStruct(name); info != nil && filepath.Dir(info.FilePath) == dir {
+				r.addRef(node, typeNode+name)
+			} else if iface := r.parser.GetInterface(name); iface != nil && filepath.Dir(iface.FilePath) == dir {
+				r.addRef(node, typeNode+name)
+			} else if key := funcKey(ownImportPath, name); r.funcs[key] {
+				r.addRef(node, funcNode+key)
+			}
+		}
+		return true
+	})
+}
+
+// walk 从根出发标记全部可达节点
+func (r *reachability) walk() map[string]bool {
+	reached := make(map[string]bool)
+	var stack []string
+	for root := range r.roots {
+		reached[root] = true
+		stack = append(stack, root)
+	}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for next := range r.refs[node] {
+			if !reached[next] {
+				reached[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return reached
+}
+
+// isInternalPackage 判断包是否位于 internal 目录下（不属于库的公开 API）
+func isInternalPackage(importPath string) bool {
+	for _, part := range strings.Split(importPath, "/") {
+		if part == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+// sortDeadDecls 按包名、名称排序
+func sortDeadDecls(decls []types.DeadDecl) {
+	sort.Slice(decls, func(i, j int) bool {
+		if decls[i].Package != decls[j].Package {
+			return decls[i].Package < decls[j].Package
+		}
+		return decls[i].Name < decls[j].Name
+	})
+}
+
+// DeadCodeViolations 将不可达的结构体、接口和未使用的构造函数转换为 check 子命令的违规（文件路径相对项目根目录 root）
+func DeadCodeViolations(report types.DeadCodeReport, root string) []types.LayerViolation {
+	var violations []types.LayerViolation
+	add := func(decls []types.DeadDecl, rule, reason string) {
+		for _, d := range decls {
+			violations = append(violations, types.LayerViolation{
+				From:     d.Name,
+				DepType:  rule,
+				Context:  d.Package,
+				Rule:     rule,
+				Reason:   reason,
+				FilePath: projectRelPath(root, d.FilePath),
+			})
+		}
+	}
+	add(report.UnreachableStructs, types.DeadCodeRuleStruct, "从根不可达的结构体")
+	add(report.UnreachableInterfaces, types.DeadCodeRuleInterface, "从根不可达的接口")
+	add(report.UnusedConstructors, types.DeadCodeRuleConstructor, "没有被可达代码调用的构造函数")
+	return violations
+}
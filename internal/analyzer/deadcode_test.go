@@ -0,0 +1,207 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// deadCodeProject 创建包含 main 包、internal 包和库包的测试项目
+func deadCodeProject() map[string]string {
+	return map[string]string{
+		"go.mod": "module example.com/app\n",
+		"cmd/app/main.go": `package main
+
+import "example.com/app/internal/service"
+
+func main() {
+	svc := service.NewUserService()
+	svc.Run()
+}
+`,
+		"internal/service/service.go": `package service
+
+type Store interface {
+	Load()
+}
+
+type Notifier interface {
+	Notify()
+}
+
+type memStore struct{}
+
+func (m *memStore) Load() {}
+
+type UserService struct {
+	store Store
+}
+
+func NewUserService() *UserService {
+	return &UserService{store: newMemStore()}
+}
+
+func newMemStore() *memStore {
+	return &memStore{}
+}
+
+func (s *UserService) Run() {}
+
+// LegacyService 不再被使用
+type LegacyService struct {
+	helper *Helper
+}
+
+type Helper struct{}
+
+func NewLegacyService() *LegacyService {
+	return &LegacyService{}
+}
+`,
+		"pkg/client/client.go": `package client
+
+type Client struct{}
+`,
+	}
+}
+
+func deadNames(decls []types.DeadDecl) map[string]bool {
+	names := make(map[string]bool)
+	for _, d := range decls {
+		names[d.Name] = true
+	}
+	return names
+}
+
+func TestFindDeadCode(t *testing.T) {
+	p, dir := writeTestProject(t, deadCodeProject())
+	filter := NewScopeFilter(p, NewBlacklist())
+
+	report, err := FindDeadCode(p, filter, nil)
+	if err != nil {
+		t.Fatalf("FindDeadCode failed: %v", err)
+	}
+
+	structs := deadNames(report.UnreachableStructs)
+	for _, name := range []string{"LegacyService", "Helper"} {
+		if !structs[name] {
+			t.Errorf("%s should be unreachable, got %+v", name, report.UnreachableStructs)
+		}
+	}
+	for _, name := range []string{"UserService", "memStore", "Client"} {
+		if structs[name] {
+			t.Errorf("%s should be reachable", name)
+		}
+	}
+
+	ctors := deadNames(report.UnusedConstructors)
+	if !ctors["NewLegacyService"] || ctors["NewUserService"] || ctors["newMemStore"] {
+		t.Errorf("unexpected unused constructors: %+v", report.UnusedConstructors)
+	}
+
+	if ifaces := deadNames(report.UnreachableInterfaces); !ifaces["Notifier"] || ifaces["Store"] {
+		t.Errorf("unexpected unreachable interfaces: %+v", report.UnreachableInterfaces)
+	}
+	if unimpl := deadNames(report.UnimplementedInterfaces); len(unimpl) != 1 || !unimpl["Notifier"] {
+		t.Errorf("unexpected unimplemented interfaces: %+v", report.UnimplementedInterfaces)
+	}
+	if len(report.SingleImplInterfaces) != 1 || report.SingleImplInterfaces[0].Implementer != "memStore" {
+		t.Errorf("Store should have exactly one implementer, got %+v", report.SingleImplInterfaces)
+	}
+
+	// 自定义根：只从 LegacyService 出发
+	report, _ = FindDeadCode(p, filter, []string{"service.LegacyService"})
+	structs = deadNames(report.UnreachableStructs)
+	if structs["LegacyService"] || structs["Helper"] || !structs["UserService"] {
+		t.Errorf("unexpected unreachable structs with custom root: %+v", report.UnreachableStructs)
+	}
+	if ctors := deadNames(report.UnusedConstructors); ctors["NewLegacyService"] {
+		t.Error("constructors of root types should be roots")
+	}
+
+	// 黑名单中的类型不参与报告
+	blacklist := NewBlacklist()
+	blacklist.AddType("Helper")
+	report, _ = FindDeadCode(p, NewScopeFilter(p, blacklist), nil)
+	if deadNames(report.UnreachableStructs)["Helper"] {
+		t.Error("blacklisted types should not be reported")
+	}
+
+	violations := DeadCodeViolations(report, dir)
+	if len(violations) == 0 || violations[0].Rule != types.DeadCodeRuleStruct || violations[0].To != "" {
+		t.Errorf("unexpected violations: %+v", violations)
+	}
+	if path := violations[0].FilePath; path != filepath.Join("internal", "service", "service.go") {
+		t.Errorf("violation path should be relative to the project root, got %s", path)
+	}
+
+	if err := ValidateRoots([]string{"main", "re:["}); err == nil {
+		t.Error("expected error for invalid root pattern")
+	}
+}
+
+func TestFindDeadCode_ReachableThroughHelper(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"cmd/app/main.go": `package main
+
+import "example.com/app/internal/service"
+
+func main() {
+	svc := service.NewService()
+	svc.Run()
+}
+`,
+		"internal/service/service.go": `package service
+
+type Service struct{}
+
+type Worker struct{}
+
+type Orphan struct{}
+
+func NewService() *Service { return &Service{} }
+
+func (s *Service) Run() { setup() }
+
+func setup() { _ = &Worker{} }
+
+func unused() { _ = &Orphan{} }
+`,
+	})
+
+	report, err := FindDeadCode(p, NewScopeFilter(p, NewBlacklist()), nil)
+	if err != nil {
+		t.Fatalf("FindDeadCode failed: %v", err)
+	}
+	structs := deadNames(report.UnreachableStructs)
+	if structs["Worker"] {
+		t.Error("Worker is reachable through main -> svc.Run() -> setup()")
+	}
+	if !structs["Orphan"] {
+		t.Errorf("Orphan is only referenced by an uncalled helper, got %+v", report.UnreachableStructs)
+	}
+}
+
+func TestTraverser_DeadCodeOptIn(t *testing.T) {
+	p, dir := writeTestProject(t, deadCodeProject())
+	filter := NewScopeFilter(p, NewBlacklist())
+
+	traverser := NewTraverser(p, filter, nil, false)
+	if dead := traverser.Analyze("UserService", 1, dir).DeadCode; len(dead.Roots) != 0 || len(dead.UnreachableStructs) != 0 {
+		t.Errorf("dead code should not be detected by default, got %+v", dead)
+	}
+
+	traverser.SetDeadCode(true)
+	if structs := deadNames(traverser.Analyze("UserService", 1, dir).DeadCode.UnreachableStructs); !structs["LegacyService"] {
+		t.Error("LegacyService should be unreachable when dead code detection is enabled")
+	}
+
+	// 设置了根时总是检测
+	traverser.SetDeadCode(false)
+	traverser.SetRoots([]string{"LegacyService"})
+	if structs := deadNames(traverser.Analyze("UserService", 1, dir).DeadCode.UnreachableStructs); structs["LegacyService"] || !structs["UserService"] {
+		t.Errorf("roots should enable dead code detection, got %+v", structs)
+	}
+}
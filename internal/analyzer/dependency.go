@@ -14,6 +14,12 @@ type DependencyAnalyzer struct {
 	typeResolver *parser.TypeResolver
 	filter       *ScopeFilter
 	verbose      bool
+
+	explain   bool                 // 是否记录候选类型的判定（explain 模式）
+	decisions []types.TypeDecision // 已记录的判定
+	seen      map[string]int       // 判定去重：位置 -> decisions 下标
+
+	access map[string]*accessRecorder // 结构体 -> 字段访问记录
 }
 
 // NewDependencyAnalyzer 创建依赖分析器
@@ -23,12 +29,14 @@ func NewDependencyAnalyzer(p *parser.Parser, filter *ScopeFilter, verbose bool)
 		typeResolver: parser.NewTypeResolver(p),
 		filter:       filter,
 		verbose:      verbose,
+		access:       make(map[string]*accessRecorder),
 	}
 }
 
 // AnalyzeStruct 分析单个结构体的依赖关系
 func (a *DependencyAnalyzer) AnalyzeStruct(structInfo *types.StructInfo) []types.Dependency {
 	var deps []types.Dependency
+	a.access[structInfo.Name] = newAccessRecorder(structInfo)
 
 	// 1. 分析字段依赖
 	deps = append(deps, a.analyzeFieldDeps(structInfo)...)
@@ -39,6 +47,12 @@ func (a *DependencyAnalyzer) AnalyzeStruct(structInfo *types.StructInfo) []types
 	// 3. 分析接口实现关系
 	deps = append(deps, a.analyzeInterfaceImpl(structInfo)...)
 
+	// 4. 分析构造函数参数（依赖注入关系）
+	deps = append(deps, a.analyzeConstructorParams(structInfo)...)
+
+	// 应用按依赖类型生效的黑名单规则
+	deps = a.filterBlockedEdges(deps)
+
 	// 去重
 	deps = a.deduplicateDeps(deps)
 
@@ -50,23 +64,47 @@ func (a *DependencyAnalyzer) analyzeFieldDeps(structInfo *types.StructInfo) []ty
 	var deps []types.Dependency
 
 	for _, field := range structInfo.Fields {
-		baseType := parser.ExtractBaseType(field.Type)
-
-		if !a.filter.ShouldAnalyze(baseType) {
+		// 标注了 //structanalyzer:ignore 的字段不产生依赖
+		if field.Annotations.Ignore {
 			continue
 		}
 
+		baseType := parser.ExtractBaseType(field.Type)
+
 		depType := types.DepTypeField
 		if field.IsEmbedded {
 			depType = types.DepTypeEmbed
 		}
 
+		// 第三方模块类型作为叶子节点保留
+		if dep := a.externalDep(structInfo.Name, field.Type, structInfo.FilePath, depType, field.Name+" 字段"); dep != nil {
+			deps = append(deps, *dep)
+			continue
+		}
+
+		if !a.accept(parser.ExtractQualifiedType(field.Type), structInfo.FilePath, structInfo.Name, depType, field.Name+" 字段") {
+			continue
+		}
+
 		deps = append(deps, types.Dependency{
 			From:    structInfo.Name,
 			To:      baseType,
 			Type:    depType,
 			Context: field.Name + " 字段",
 		})
+
+		// gorm 关联标签（foreignKey、many2many）额外产生一条关联边
+		if setting := gormRelationTag(field); setting != "" {
+			context := field.Name + " 字段（gorm " + setting + "）"
+			if a.accept(parser.ExtractQualifiedType(field.Type), structInfo.FilePath, structInfo.Name, types.DepTypeRelation, context) {
+				deps = append(deps, types.Dependency{
+					From:    structInfo.Name,
+					To:      baseType,
+					Type:    types.DepTypeRelation,
+					Context: context,
+				})
+			}
+		}
 	}
 
 	return deps
@@ -76,8 +114,17 @@ func (a *DependencyAnalyzer) analyzeFieldDeps(structInfo *types.StructInfo) []ty
 func (a *DependencyAnalyzer) analyzeMethodDeps(structInfo *types.StructInfo) []types.Dependency {
 	var deps []types.Dependency
 
-	// 遍历项目中的所有文件，找到该结构体的方法
-	for _, file := range a.getFilesForStruct(structInfo) {
+	a.forEachMethod(structInfo, func(funcDecl *ast.FuncDecl, filePath string) {
+		deps = append(deps, a.analyzeMethodBody(structInfo, funcDecl, filePath)...)
+	})
+
+	return deps
+}
+
+// forEachMethod 遍历结构体的所有带方法体的方法（方法可能分布在同包的多个文件中）
+func (a *DependencyAnalyzer) forEachMethod(structInfo *types.StructInfo, fn func(funcDecl *ast.FuncDecl, filePath string)) {
+	for _, filePath := range a.getFilesForStruct(structInfo) {
+		file := a.parser.GetFile(filePath)
 		ast.Inspect(file, func(n ast.Node) bool {
 			funcDecl, ok := n.(*ast.FuncDecl)
 			if !ok || funcDecl.Recv == nil || funcDecl.Body == nil {
@@ -90,24 +137,19 @@ func (a *DependencyAnalyzer) analyzeMethodDeps(structInfo *types.StructInfo) []t
 				return true
 			}
 
-			// 分析方法体
-			methodDeps := a.analyzeMethodBody(structInfo.Name, funcDecl)
-			deps = append(deps, methodDeps...)
-
+			fn(funcDecl, filePath)
 			return true
 		})
 	}
-
-	return deps
 }
 
-// getFilesForStruct 获取包含该结构体的文件
-func (a *DependencyAnalyzer) getFilesForStruct(structInfo *types.StructInfo) []*ast.File {
-	var files []*ast.File
+// getFilesForStruct 获取包含该结构体的文件路径
+func (a *DependencyAnalyzer) getFilesForStruct(structInfo *types.StructInfo) []string {
+	var files []string
 
 	// 首先添加结构体定义所在的文件
 	if file := a.parser.GetFile(structInfo.FilePath); file != nil {
-		files = append(files, file)
+		files = append(files, structInfo.FilePath)
 	}
 
 	// 遍历所有结构体，找到同包的文件（方法可能在不同文件中）
@@ -117,13 +159,13 @@ func (a *DependencyAnalyzer) getFilesForStruct(structInfo *types.StructInfo) []*
 				// 避免重复添加
 				found := false
 				for _, existing := range files {
-					if existing == f {
+					if existing == s.FilePath {
 						found = true
 						break
 					}
 				}
 				if !found {
-					files = append(files, f)
+					files = append(files, s.FilePath)
 				}
 			}
 		}
@@ -133,21 +175,29 @@ func (a *DependencyAnalyzer) getFilesForStruct(structInfo *types.StructInfo) []*
 }
 
 // analyzeMethodBody 分析方法体内的依赖
-func (a *DependencyAnalyzer) analyzeMethodBody(structName string, funcDecl *ast.FuncDecl) []types.Dependency {
+func (a *DependencyAnalyzer) analyzeMethodBody(structInfo *types.StructInfo, funcDecl *ast.FuncDecl, filePath string) []types.Dependency {
 	var deps []types.Dependency
+	structName := structInfo.Name
 	methodName := funcDecl.Name.Name
 
-	// 构建类型上下文
-	ctx := a.typeResolver.BuildTypeContext(funcDecl.Body)
+	// 记录字段读写
+	a.recordFieldAccess(structInfo, funcDecl)
+
+	// 构建类型上下文（绑定到所在文件，以便按导入路径解析 pkg.Open() 等构造函数调用）
+	resolver := a.typeResolver.ForFile(filePath)
+	ctx := resolver.BuildTypeContext(funcDecl.Body)
+	imports := a.parser.GetImports(filePath)
 
 	// 遍历方法体
 	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
 		// 复合字面量: B{}
 		case *ast.CompositeLit:
-			typeName := a.typeResolver.InferTypeFromExpr(node)
+			typeName := resolver.InferTypeFromExpr(node)
 			baseType := parser.ExtractBaseType(typeName)
-			if a.filter.ShouldAnalyze(baseType) {
+			if dep := a.externalDep(structName, typeName, filePath, types.DepTypeInit, methodName+" 方法"); dep != nil {
+				deps = append(deps, *dep)
+			} else if a.accept(parser.ExtractQualifiedType(typeName), filePath, structName, types.DepTypeInit, methodName+" 方法") {
 				deps = append(deps, types.Dependency{
 					From:    structName,
 					To:      baseType,
@@ -163,7 +213,7 @@ func (a *DependencyAnalyzer) analyzeMethodBody(structName string, funcDecl *ast.
 				if ident.Name == "new" {
 					if len(node.Args) > 0 {
 						typeName := a.parser.GetStruct(a.getTypeName(node.Args[0]))
-						if typeName != nil && a.filter.ShouldAnalyze(typeName.Name) {
+						if typeName != nil && a.accept(typeName.Name, filePath, structName, types.DepTypeInit, methodName+" 方法") {
 							deps = append(deps, types.Dependency{
 								From:    structName,
 								To:      typeName.Name,
@@ -172,9 +222,9 @@ func (a *DependencyAnalyzer) analyzeMethodBody(structName string, funcDecl *ast.
 							})
 						}
 					}
-				} else if strings.HasPrefix(ident.Name, "New") {
-					// 检查构造函数调用: NewXxx()
-					dep := a.analyzeConstructorCall(structName, methodName, ident.Name, "")
+				} else if ident.Name != "make" && ctx.GetType(ident.Name) == "" {
+					// 检查同包构造函数调用: NewXxx()、Open()、MustXxx()
+					dep := a.analyzeConstructorCall(structName, methodName, ident.Name, structInfo.ImportPath)
 					if dep != nil {
 						deps = append(deps, *dep)
 					}
@@ -185,25 +235,26 @@ func (a *DependencyAnalyzer) analyzeMethodBody(structName string, funcDecl *ast.
 			if selExpr, ok := node.Fun.(*ast.SelectorExpr); ok {
 				methodOrFuncName := selExpr.Sel.Name
 
-				// 检查是否是构造函数调用: pkg.NewXxx()
-				if strings.HasPrefix(methodOrFuncName, "New") {
-					if pkgIdent, ok := selExpr.X.(*ast.Ident); ok {
-						dep := a.analyzeConstructorCall(structName, methodName, methodOrFuncName, pkgIdent.Name)
-						if dep != nil {
-							deps = append(deps, *dep)
-							return true
-						}
+				// 检查是否是跨包构造函数调用: pkg.NewXxx()、pkg.Open()
+				if pkgIdent, ok := selExpr.X.(*ast.Ident); ok && ctx.GetType(pkgIdent.Name) == "" {
+					dep := a.analyzeConstructorCall(structName, methodName, methodOrFuncName, imports[pkgIdent.Name])
+					if dep != nil {
+						deps = append(deps, *dep)
+						return true
 					}
 				}
 
 				// 检查方法调用: b.Method()
-				receiverType := a.typeResolver.InferType(selExpr.X, ctx)
+				a.recordFieldCall(structInfo, funcDecl, selExpr)
+				receiverType := resolver.InferType(selExpr.X, ctx)
 				// 跳过无法推断类型的情况（避免将变量名误识别为类型名）
 				if receiverType == "" {
 					return true
 				}
 				baseType := parser.ExtractBaseType(receiverType)
-				if a.filter.ShouldAnalyze(baseType) {
+				if dep := a.externalDep(structName, receiverType, filePath, types.DepTypeMethodCall, methodName+" -> "+methodOrFuncName); dep != nil {
+					deps = append(deps, *dep)
+				} else if a.accept(parser.ExtractQualifiedType(receiverType), filePath, structName, types.DepTypeMethodCall, methodName+" -> "+methodOrFuncName) {
 					deps = append(deps, types.Dependency{
 						From:    structName,
 						To:      baseType,
@@ -242,6 +293,19 @@ func (a *DependencyAnalyzer) getTypeName(expr ast.Expr) string {
 	}
 }
 
+// filterBlockedEdges 去除被黑名单边规则忽略的依赖
+func (a *DependencyAnalyzer) filterBlockedEdges(deps []types.Dependency) []types.Dependency {
+	result := deps[:0]
+	for _, dep := range deps {
+		if a.filter.AllowDependency(dep) {
+			result = append(result, dep)
+		} else {
+			a.record(dep.To, "", dep.From, dep.Type, dep.Context, false, types.DecisionEdgeRule, "依赖边被按依赖类型生效的黑名单规则忽略")
+		}
+	}
+	return result
+}
+
 // deduplicateDeps 去除重复的依赖
 func (a *DependencyAnalyzer) deduplicateDeps(deps []types.Dependency) []types.Dependency {
 	seen := make(map[string]bool)
@@ -270,13 +334,8 @@ func (a *DependencyAnalyzer) analyzeInterfaceImpl(structInfo *types.StructInfo)
 
 	// 检查所有接口
 	for _, iface := range a.parser.GetAllInterfaces() {
-		// 跳过不在分析范围内的接口
-		if !a.filter.ShouldAnalyze(iface.Name) {
-			continue
-		}
-
-		// 检查结构体是否实现了该接口的所有方法
-		if a.implementsInterface(structMethods, iface) {
+		// 检查结构体是否实现了该接口的所有方法，并跳过不在分析范围内的接口
+		if a.implementsInterface(structMethods, iface) && a.accept(iface.Name, iface.FilePath, structInfo.Name, types.DepTypeInterface, "实现接口") {
 			deps = append(deps, types.Dependency{
 				From:    structInfo.Name,
 				To:      iface.Name,
@@ -310,46 +369,115 @@ func (a *DependencyAnalyzer) implementsInterface(structMethods map[string]string
 	return true
 }
 
+// lookupConstructor 按导入路径查找构造函数；导入路径未知时不做按名查找，避免误配其他包的同名函数
+func (a *DependencyAnalyzer) lookupConstructor(importPath, funcName string) *types.FunctionInfo {
+	if importPath == "" {
+		return nil
+	}
+	return a.parser.LookupConstructor(importPath, funcName)
+}
+
 // analyzeConstructorCall 分析构造函数调用
-func (a *DependencyAnalyzer) analyzeConstructorCall(structName, methodName, funcName, pkgAlias string) *types.Dependency {
+// importPath 为被调用函数所在包的导入路径（未知时为空）；找不到已解析的构造函数时回退到 NewXxx -> Xxx 的命名推断
+func (a *DependencyAnalyzer) analyzeConstructorCall(structName, methodName, funcName, importPath string) *types.Dependency {
+	if fn := a.lookupConstructor(importPath, funcName); fn != nil {
+		baseType := parser.ExtractBaseType(fn.ReturnType)
+		if a.accept(baseType, fn.FilePath, structName, types.DepTypeConstructor, methodName+" -> "+funcName) {
+			return &types.Dependency{
+				From:    structName,
+				To:      baseType,
+				Type:    types.DepTypeConstructor,
+				Context: methodName + " -> " + funcName,
+			}
+		}
+		return nil
+	}
+
 	// 尝试从函数名推断返回类型
 	// NewUserService -> UserService
 	// NewCache -> Cache
+	if !strings.HasPrefix(funcName, "New") {
+		return nil
+	}
 	inferredType := strings.TrimPrefix(funcName, "New")
 	if inferredType == "" {
 		return nil
 	}
 
-	// 如果有包别名，尝试查找完整的函数信息
-	if pkgAlias != "" {
-		// 在已解析的函数中查找
-		for key, fn := range a.parser.GetAllFunctions() {
-			if strings.HasSuffix(key, "."+funcName) {
-				baseType := parser.ExtractBaseType(fn.ReturnType)
-				if a.filter.ShouldAnalyze(baseType) {
-					return &types.Dependency{
-						From:    structName,
-						To:      baseType,
-						Type:    types.DepTypeConstructor,
-						Context: methodName + " -> " + funcName,
-					}
-				}
-			}
-		}
+	// 验证推断的类型确实存在
+	context := methodName + " -> " + funcName
+	if a.parser.GetStruct(inferredType) == nil {
+		a.record(inferredType, "", structName, types.DepTypeConstructor, context, false, types.DecisionNotFound, "按 New 前缀从 "+funcName+" 推断的结构体不存在")
+		return nil
 	}
 
 	// 直接使用推断的类型名
-	if a.filter.ShouldAnalyze(inferredType) {
-		// 验证推断的类型确实存在
-		if a.parser.GetStruct(inferredType) != nil {
-			return &types.Dependency{
-				From:    structName,
-				To:      inferredType,
-				Type:    types.DepTypeConstructor,
-				Context: methodName + " -> " + funcName,
-			}
+	if a.accept(inferredType, "", structName, types.DepTypeConstructor, context) {
+		return &types.Dependency{
+			From:    structName,
+			To:      inferredType,
+			Type:    types.DepTypeConstructor,
+			Context: context,
 		}
 	}
 
 	return nil
 }
+
+// analyzeConstructorParams 分析结构体构造函数的参数类型，生成依赖注入边
+func (a *DependencyAnalyzer) analyzeConstructorParams(structInfo *types.StructInfo) []types.Dependency {
+	var deps []types.Dependency
+
+	for _, fn := range a.parser.GetFunctionsByReturnType(structInfo.Name) {
+		if fn.Package != structInfo.Package {
+			continue
+		}
+
+		for _, param := range fn.Params {
+			context := fn.Name + " 参数"
+			if param.Name != "" {
+				context += " " + param.Name
+			}
+
+			if dep := a.externalDep(structInfo.Name, param.Type, fn.FilePath, types.DepTypeConstructorParam, context); dep != nil {
+				deps = append(deps, *dep)
+				continue
+			}
+
+			baseType := parser.ExtractBaseType(param.Type)
+			if baseType == structInfo.Name || !a.accept(parser.ExtractQualifiedType(param.Type), fn.FilePath, structInfo.Name, types.DepTypeConstructorParam, context) {
+				continue
+			}
+
+			deps = append(deps, types.Dependency{
+				From:    structInfo.Name,
+				To:      baseType,
+				Type:    types.DepTypeConstructorParam,
+				Context: context,
+			})
+		}
+	}
+
+	return deps
+}
+
+// externalDep 在启用外部类型模式时，为第三方模块类型生成叶子依赖
+func (a *DependencyAnalyzer) externalDep(from, typeName, filePath, depType, context string) *types.Dependency {
+	if !a.filter.IncludeExternal() {
+		return nil
+	}
+
+	ext := a.filter.ResolveExternal(typeName, filePath)
+	if ext == nil {
+		return nil
+	}
+	a.record(typeName, filePath, from, depType, context, true, types.DecisionExternal, "第三方模块 "+ext.Module+" 的类型，作为叶子节点保留")
+
+	return &types.Dependency{
+		From:     from,
+		To:       ext.Name,
+		Type:     depType,
+		Context:  context,
+		External: true,
+	}
+}
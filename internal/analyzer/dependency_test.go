@@ -0,0 +1,148 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// writeTestProject 在临时目录中创建测试项目并解析
+func writeTestProject(t *testing.T, files map[string]string) (*parser.Parser, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	p := parser.NewParser(false)
+	if err := p.ParseProject(tmpDir); err != nil {
+		t.Fatalf("ParseProject failed: %v", err)
+	}
+	return p, tmpDir
+}
+
+// findDep 查找指定目标和类型的依赖
+func findDep(deps []types.Dependency, to, depType string) *types.Dependency {
+	for i := range deps {
+		if deps[i].To == to && deps[i].Type == depType {
+			return &deps[i]
+		}
+	}
+	return nil
+}
+
+func TestDependencyAnalyzer_Constructors(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"store/store.go": `package store
+
+type DB struct{}
+type Store struct{ db *DB }
+
+func Open(db *DB) (*Store, error) { return &Store{db: db}, nil }
+`,
+		"service/service.go": `package service
+
+import "example.com/app/store"
+
+type Logger struct{}
+type Service struct{}
+
+func MustLogger() *Logger { return &Logger{} }
+
+func (s *Service) Run() {
+	st, _ := store.Open(nil)
+	_ = st
+	_ = MustLogger()
+}
+`,
+	})
+
+	filter := NewScopeFilter(p, NewBlacklist())
+	da := NewDependencyAnalyzer(p, filter, false)
+
+	deps := da.AnalyzeStruct(p.GetStruct("Service"))
+	if findDep(deps, "Store", types.DepTypeConstructor) == nil {
+		t.Errorf("expected constructor edge Service -> Store via store.Open, got %+v", deps)
+	}
+	if findDep(deps, "Logger", types.DepTypeConstructor) == nil {
+		t.Errorf("expected constructor edge Service -> Logger via MustLogger, got %+v", deps)
+	}
+
+	storeDeps := da.AnalyzeStruct(p.GetStruct("Store"))
+	dep := findDep(storeDeps, "DB", types.DepTypeConstructorParam)
+	if dep == nil {
+		t.Fatalf("expected constructor_param edge Store -> DB, got %+v", storeDeps)
+	}
+	if dep.Context != "Open 参数 db" {
+		t.Errorf("constructor_param context = %q", dep.Context)
+	}
+}
+
+func TestTraverser_Annotations(t *testing.T) {
+	p, tmpDir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"app/app.go": `package app
+
+type Metrics struct{}
+type Store struct{}
+
+//structanalyzer:stop
+type Cache struct{ store *Store }
+
+//structanalyzer:ignore
+type Tracer struct{}
+
+//structanalyzer:layer=service
+//structanalyzer:desc "订单服务"
+type Service struct {
+	//structanalyzer:ignore
+	metrics *Metrics
+	cache   *Cache // structanalyzer:desc "本地缓存"
+	tracer  *Tracer
+}
+`,
+	})
+
+	filter := NewScopeFilter(p, NewBlacklist())
+	result := NewTraverser(p, filter, nil, false).Analyze("Service", 5, tmpDir)
+
+	names := make(map[string]types.StructAnalysis)
+	for _, s := range result.Structs {
+		names[s.Name] = s
+	}
+
+	svc := names["Service"]
+	if svc.Layer != "service" || svc.Description != "订单服务" {
+		t.Errorf("Service layer=%q desc=%q", svc.Layer, svc.Description)
+	}
+	for _, f := range svc.Fields {
+		if f.Name == "metrics" {
+			t.Error("ignored field should be omitted from analysis")
+		}
+		if f.Name == "cache" && f.Description != "本地缓存" {
+			t.Errorf("cache field desc = %q", f.Description)
+		}
+	}
+	if findDep(svc.Dependencies, "Metrics", types.DepTypeField) != nil {
+		t.Error("ignored field must not produce a dependency")
+	}
+	if findDep(svc.Dependencies, "Tracer", types.DepTypeField) != nil {
+		t.Error("ignored type must not produce a dependency")
+	}
+	if c, ok := names["Cache"]; !ok || !c.Stopped {
+		t.Errorf("Cache should be kept and marked stopped, got %+v", names)
+	}
+	if _, ok := names["Store"]; ok {
+		t.Error("traversal must not continue past an annotated stop node")
+	}
+}
@@ -0,0 +1,253 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// gormModelColumns gorm.Model 展开后的列
+var gormModelColumns = []types.ERAttribute{
+	{Field: "ID", Column: "id", Type: "uint", Keys: []string{"PK"}},
+	{Field: "CreatedAt", Column: "created_at", Type: "time.Time"},
+	{Field: "UpdatedAt", Column: "updated_at", Type: "time.Time"},
+	{Field: "DeletedAt", Column: "deleted_at", Type: "gorm.DeletedAt"},
+}
+
+// BuildERModel 从 root 实体出发，沿类型为项目结构体的字段收集持久化模型，按 gorm 约定推断关联：
+// 切片字段为 has-many，带 many2many 标签的为 many2many；单个字段在本实体有外键字段（foreignKey 或 字段名+ID）时为 belongs-to，否则为 has-one。
+// 嵌入的项目结构体、gorm.Model 和带 embedded 标签的字段展开为本实体的列，gorm:"-" 和未导出的字段被忽略
+func BuildERModel(p *parser.Parser, filter *ScopeFilter, root string) (types.ERModel, error) {
+	model := types.ERModel{Root: root}
+	if p.GetStruct(root) == nil {
+		return model, fmt.Errorf("struct %q not found", root)
+	}
+
+	relationIndex := make(map[string]int) // 关联的标识 -> 在 Relations 中的位置
+	visited := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		info := p.GetStruct(queue[0])
+		queue = queue[1:]
+
+		entity := types.EREntity{Name: info.Name, ImportPath: info.ImportPath}
+		for _, field := range info.Fields {
+			// 嵌入字段的字段名为类型名（如 gorm.Model），未导出的嵌入结构体中的导出字段同样会被提升
+			if !field.IsExported && !field.IsEmbedded || field.Annotations.Ignore {
+				continue
+			}
+			if _, skip := field.Tags.Gorm["-"]; skip || field.Tags.Values["gorm"] == "-" {
+				continue
+			}
+
+			target := entityStruct(p, filter, info, field)
+			_, embedded := field.Tags.Gorm["embedded"]
+			switch {
+			case field.IsEmbedded && strings.TrimPrefix(field.Type, "*") == "gorm.Model":
+				entity.Attributes = append(entity.Attributes, gormModelColumns...)
+			case (field.IsEmbedded || embedded) && target != nil:
+				entity.Attributes = append(entity.Attributes, embeddedColumns(p, filter, target, field.Tags.Gorm["embeddedprefix"], map[string]bool{info.Name: true})...)
+			case target != nil:
+				rel := gormRelation(info, field, target.Name)
+				key := relationKey(rel)
+				if i, ok := relationIndex[key]; ok {
+					// 同一关联从两端声明时保留信息更多的一端（has-one/has-many 优先于 belongs-to）
+					if model.Relations[i].Kind == types.RelationBelongsTo && rel.Kind != types.RelationBelongsTo {
+						model.Relations[i] = rel
+					}
+				} else {
+					relationIndex[key] = len(model.Relations)
+					model.Relations = append(model.Relations, rel)
+				}
+				if !visited[target.Name] {
+					visited[target.Name] = true
+					queue = append(queue, target.Name)
+				}
+			case field.IsEmbedded:
+				// 嵌入的第三方类型（如 sync.Mutex）不对应列
+			default:
+				entity.Attributes = append(entity.Attributes, erAttribute(field, ""))
+			}
+		}
+		model.Entities = append(model.Entities, entity)
+	}
+
+	markKeys(&model)
+	return model, nil
+}
+
+// entityStruct 返回字段类型对应的项目结构体（在分析范围内），不是时返回 nil
+func entityStruct(p *parser.Parser, filter *ScopeFilter, owner *types.StructInfo, field types.FieldInfo) *types.StructInfo {
+	if !filter.ShouldAnalyzeInFile(parser.ExtractQualifiedType(field.Type), owner.FilePath) {
+		return nil
+	}
+	return p.GetStruct(parser.ExtractBaseType(field.Type))
+}
+
+// embeddedColumns 展开嵌入结构体的列（列名加上 embeddedPrefix），嵌套的嵌入结构体同样展开
+func embeddedColumns(p *parser.Parser, filter *ScopeFilter, info *types.StructInfo, prefix string, seen map[string]bool) []types.ERAttribute {
+	if seen[info.Name] {
+		return nil
+	}
+	seen[info.Name] = true
+
+	var attrs []types.ERAttribute
+	for _, field := range info.Fields {
+		if !field.IsExported && !field.IsEmbedded || field.Annotations.Ignore || field.Tags.Values["gorm"] == "-" {
+			continue
+		}
+		_, embedded := field.Tags.Gorm["embedded"]
+		target := entityStruct(p, filter, info, field)
+		if target != nil && (field.IsEmbedded || embedded) {
+			attrs = append(attrs, embeddedColumns(p, filter, target, prefix+field.Tags.Gorm["embeddedprefix"], seen)...)
+			continue
+		}
+		if field.IsEmbedded {
+			continue
+		}
+		attrs = append(attrs, erAttribute(field, prefix))
+	}
+	return attrs
+}
+
+// erAttribute 根据字段和 gorm 设置生成列
+func erAttribute(field types.FieldInfo, prefix string) types.ERAttribute {
+	attr := types.ERAttribute{
+		Field:  field.Name,
+		Column: field.Tags.Gorm["column"],
+		Type:   field.Type,
+	}
+	if attr.Column == "" {
+		attr.Column = snakeCase(field.Name)
+	}
+	attr.Column = prefix + attr.Column
+	if name, ok := field.Tags.Names["json"]; ok && name != "-" {
+		attr.JSON = name
+	}
+
+	_, pk := field.Tags.Gorm["primarykey"]
+	_, pkLegacy := field.Tags.Gorm["primary_key"]
+	if pk || pkLegacy || field.Name == "ID" {
+		attr.Keys = append(attr.Keys, "PK")
+	}
+	_, unique := field.Tags.Gorm["unique"]
+	_, uniqueIndex := field.Tags.Gorm["uniqueindex"]
+	if unique || uniqueIndex {
+		attr.Keys = append(attr.Keys, "UK")
+	}
+	return attr
+}
+
+// gormRelation 按 gorm 约定推断关联字段的关联类型和外键
+func gormRelation(owner *types.StructInfo, field types.FieldInfo, target string) types.ERRelation {
+	rel := types.ERRelation{From: owner.Name, To: target, Field: field.Name}
+	foreignKey := field.Tags.Gorm["foreignkey"]
+
+	if joinTable, ok := field.Tags.Gorm["many2many"]; ok {
+		rel.Kind = types.RelationManyToMany
+		rel.JoinTable = joinTable
+		return rel
+	}
+	if strings.HasPrefix(strings.TrimPrefix(field.Type, "*"), "[]") {
+		rel.Kind = types.RelationHasMany
+		rel.ForeignKey = foreignKey
+		if rel.ForeignKey == "" {
+			rel.ForeignKey = owner.Name + "ID"
+		}
+		return rel
+	}
+
+	belongsKey := foreignKey
+	if belongsKey == "" {
+		belongsKey = field.Name + "ID"
+	}
+	for _, f := range owner.Fields {
+		if f.Name == belongsKey {
+			rel.Kind = types.RelationBelongsTo
+			rel.ForeignKey = belongsKey
+			return rel
+		}
+	}
+	rel.Kind = types.RelationHasOne
+	rel.ForeignKey = foreignKey
+	if rel.ForeignKey == "" {
+		rel.ForeignKey = owner.Name + "ID"
+	}
+	return rel
+}
+
+// relationKey 返回关联的标识：同一个外键（或连接表）从两端声明时标识相同
+func relationKey(rel types.ERRelation) string {
+	switch rel.Kind {
+	case types.RelationManyToMany:
+		if rel.JoinTable != "" {
+			return "many2many:" + rel.JoinTable
+		}
+		return "many2many:" + rel.From + "." + rel.Field
+	case types.RelationBelongsTo:
+		// 外键在 From 上，被关联的是 To
+		return rel.To + "|" + rel.From + "|" + rel.ForeignKey
+	default:
+		return rel.From + "|" + rel.To + "|" + rel.ForeignKey
+	}
+}
+
+// markKeys 将关联中的外键字段标记为 FK
+func markKeys(model *types.ERModel) {
+	entities := make(map[string]*types.EREntity, len(model.Entities))
+	for i := range model.Entities {
+		entities[model.Entities[i].Name] = &model.Entities[i]
+	}
+
+	for _, rel := range model.Relations {
+		holder := rel.To
+		switch rel.Kind {
+		case types.RelationManyToMany:
+			continue
+		case types.RelationBelongsTo:
+			holder = rel.From
+		}
+		entity := entities[holder]
+		if entity == nil {
+			continue
+		}
+		for i := range entity.Attributes {
+			attr := &entity.Attributes[i]
+			if attr.Field == rel.ForeignKey && !containsString(attr.Keys, "FK") {
+				attr.Keys = append(attr.Keys, "FK")
+			}
+		}
+	}
+}
+
+// gormRelationTag 返回字段上声明 gorm 关联的标签设置（many2many、foreignKey 或 references），没有时返回空字符串
+func gormRelationTag(field types.FieldInfo) string {
+	for _, key := range []string{"many2many", "foreignkey", "references"} {
+		if value, ok := field.Tags.Gorm[key]; ok {
+			return key + ":" + value
+		}
+	}
+	return ""
+}
+
+// snakeCase 将字段名转换为 gorm 默认的列名：UserID -> user_id，CreatedAt -> created_at
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// 在小写字母之后、或一串大写字母的最后一个（后面跟小写字母）之前加下划线
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
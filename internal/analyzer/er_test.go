@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// gormProject 创建使用 gorm 关联标签的测试项目
+func gormProject() map[string]string {
+	return map[string]string{
+		"go.mod": "module example.com/shop\n",
+		"model/user.go": `package model
+
+import "gorm.io/gorm"
+
+type User struct {
+	gorm.Model
+	Name      string      ` + "`json:\"name\" gorm:\"uniqueIndex\"`" + `
+	CompanyID uint        ` + "`json:\"company_id\"`" + `
+	Company   Company     ` + "`json:\"company\"`" + `
+	Orders    []Order     ` + "`gorm:\"foreignKey:OwnerID\" json:\"orders\"`" + `
+	Languages []*Language ` + "`gorm:\"many2many:user_languages;\"`" + `
+	Profile   *Profile
+	Audit     Audit       ` + "`gorm:\"embedded;embeddedPrefix:audit_\"`" + `
+	Temp      string      ` + "`gorm:\"-\"`" + `
+	secret    string
+}
+
+type Company struct {
+	ID    uint
+	Name  string
+	Users []User
+}
+
+type Order struct {
+	ID      uint  ` + "`gorm:\"primaryKey\"`" + `
+	OwnerID uint
+	Owner   *User ` + "`gorm:\"foreignKey:OwnerID\"`" + `
+}
+
+type Language struct {
+	ID    uint
+	Users []*User ` + "`gorm:\"many2many:user_languages;\"`" + `
+}
+
+type Profile struct {
+	ID     uint
+	UserID uint
+}
+
+type Audit struct {
+	CreatedBy string
+}
+`,
+	}
+}
+
+func TestBuildERModel(t *testing.T) {
+	p, _ := writeTestProject(t, gormProject())
+	model, err := BuildERModel(p, NewScopeFilter(p, NewBlacklist()), "User")
+	if err != nil {
+		t.Fatalf("BuildERModel failed: %v", err)
+	}
+
+	var names []string
+	entities := make(map[string]types.EREntity)
+	for _, e := range model.Entities {
+		names = append(names, e.Name)
+		entities[e.Name] = e
+	}
+	if got := strings.Join(names, ","); got != "User,Company,Order,Language,Profile" {
+		t.Errorf("entities = %s", got)
+	}
+
+	// 同一关联从两端声明时只保留一条，has-many 优先于 belongs-to
+	var relations []string
+	for _, rel := range model.Relations {
+		relations = append(relations, rel.From+" "+rel.Kind+" "+rel.To+" "+rel.ForeignKey+rel.JoinTable)
+	}
+	want := []string{
+		"Company has-many User CompanyID",
+		"User has-many Order OwnerID",
+		"User many2many Language user_languages",
+		"User has-one Profile UserID",
+	}
+	if strings.Join(relations, "\n") != strings.Join(want, "\n") {
+		t.Errorf("relations =\n%s\nwant\n%s", strings.Join(relations, "\n"), strings.Join(want, "\n"))
+	}
+
+	// gorm.Model 和 embedded 字段展开为列，gorm:"-" 和未导出字段被忽略
+	var columns []string
+	for _, attr := range entities["User"].Attributes {
+		columns = append(columns, attr.Column+"["+strings.Join(attr.Keys, ",")+"]")
+	}
+	if got := strings.Join(columns, " "); got != "id[PK] created_at[] updated_at[] deleted_at[] name[UK] company_id[FK] audit_created_by[]" {
+		t.Errorf("User columns = %s", got)
+	}
+	if attr := entities["Order"].Attributes[1]; attr.Column != "owner_id" || strings.Join(attr.Keys, ",") != "FK" {
+		t.Errorf("Order.OwnerID = %+v", attr)
+	}
+	if attr := entities["Profile"].Attributes[1]; strings.Join(attr.Keys, ",") != "FK" {
+		t.Errorf("Profile.UserID keys = %v", attr.Keys)
+	}
+	if attr := entities["User"].Attributes[4]; attr.JSON != "name" {
+		t.Errorf("User.Name json = %q", attr.JSON)
+	}
+
+	if _, err := BuildERModel(p, NewScopeFilter(p, NewBlacklist()), "Missing"); err == nil {
+		t.Error("expected error for unknown entity")
+	}
+}
+
+func TestDependencyAnalyzer_RelationDeps(t *testing.T) {
+	p, _ := writeTestProject(t, gormProject())
+	a := NewDependencyAnalyzer(p, NewScopeFilter(p, NewBlacklist()), false)
+	deps := a.AnalyzeStruct(p.GetStruct("User"))
+
+	if dep := findDep(deps, "Order", types.DepTypeRelation); dep == nil || dep.Context != "Orders 字段（gorm foreignkey:OwnerID）" {
+		t.Errorf("Order relation dep = %+v", dep)
+	}
+	if dep := findDep(deps, "Language", types.DepTypeRelation); dep == nil || dep.Context != "Languages 字段（gorm many2many:user_languages）" {
+		t.Errorf("Language relation dep = %+v", dep)
+	}
+	if findDep(deps, "Order", types.DepTypeField) == nil {
+		t.Error("field dep should be kept alongside relation dep")
+	}
+	if findDep(deps, "Profile", types.DepTypeRelation) != nil {
+		t.Error("Profile has no gorm relation tag")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"ID":         "id",
+		"UserID":     "user_id",
+		"CreatedAt":  "created_at",
+		"HTTPServer": "http_server",
+		"Line2Code":  "line2_code",
+	} {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%s) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// EnableExplain 启用 explain 模式：记录每个候选类型的判定规则和出现位置
+func (a *DependencyAnalyzer) EnableExplain() {
+	a.explain = true
+	a.seen = make(map[string]int)
+}
+
+// Decisions 返回已记录的判定
+func (a *DependencyAnalyzer) Decisions() []types.TypeDecision {
+	return a.decisions
+}
+
+// resetDecisions 清空已记录的判定
+func (a *DependencyAnalyzer) resetDecisions() {
+	a.decisions = nil
+	a.seen = make(map[string]int)
+}
+
+// accept 通过范围过滤器判定候选类型，explain 模式下同时记录判定
+func (a *DependencyAnalyzer) accept(typeName, filePath, from, depType, context string) bool {
+	accepted, rule, detail := a.filter.Classify(typeName, filePath)
+	a.record(typeName, filePath, from, depType, context, accepted, rule, detail)
+	return accepted
+}
+
+// record 记录一条判定
+// 同一位置的同一类型只保留一条，后出现的判定（如边规则）覆盖先前的结果
+func (a *DependencyAnalyzer) record(typeName, filePath, from, depType, context string, accepted bool, rule, detail string) {
+	if !a.explain {
+		return
+	}
+
+	decision := types.TypeDecision{
+		Type:     typeName,
+		Accepted: accepted,
+		Rule:     rule,
+		Detail:   detail,
+		From:     from,
+		DepType:  depType,
+		Context:  context,
+		FilePath: filePath,
+	}
+
+	key := from + "|" + context + "|" + depType + "|" + shortTypeName(parser.ExtractBaseType(typeName))
+	if idx, ok := a.seen[key]; ok {
+		if decision.FilePath == "" {
+			decision.FilePath = a.decisions[idx].FilePath
+		}
+		decision.Type = a.decisions[idx].Type
+		a.decisions[idx] = decision
+		return
+	}
+	a.seen[key] = len(a.decisions)
+	a.decisions = append(a.decisions, decision)
+}
+
+// ExplainType 分析项目中的全部结构体，返回与指定类型相关的判定记录
+// typeName 不带包名时按短名匹配，带包名（如 gorm.DB）时按 包名.类型名 匹配
+func ExplainType(p *parser.Parser, filter *ScopeFilter, typeName string) []types.TypeDecision {
+	a := NewDependencyAnalyzer(p, filter, false)
+	a.EnableExplain()
+
+	structs := p.GetAllStructs()
+	names := make([]string, 0, len(structs))
+	for name := range structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		a.AnalyzeStruct(structs[name])
+	}
+
+	var result []types.TypeDecision
+	for _, d := range a.Decisions() {
+		if matchesTypeName(d.Type, typeName) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// matchesTypeName 判断判定记录中的类型是否与查询的类型名一致
+func matchesTypeName(candidate, query string) bool {
+	query = parser.ExtractQualifiedType(query)
+	candidate = parser.ExtractQualifiedType(candidate)
+	if strings.Contains(query, ".") {
+		return candidate == query
+	}
+	return shortTypeName(candidate) == query
+}
@@ -0,0 +1,161 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+const explainProject = `package app
+
+import (
+	"sync"
+	"time"
+)
+
+type Logger struct{}
+type Metrics struct{}
+type Repo struct{}
+
+type Service struct {
+	mu      sync.Mutex
+	name    string
+	timeout time.Duration
+	log     *Logger
+	metrics *Metrics
+	repo    *Repo
+	missing *Unknown
+}
+
+func (s *Service) Run() {
+	_ = NewWidget()
+}
+
+func NewWidget() int { return 0 }
+`
+
+func TestScopeFilter_Classify(t *testing.T) {
+	p, tmpDir := writeTestProject(t, map[string]string{
+		"go.mod":     "module example.com/app\n",
+		"app/app.go": explainProject,
+	})
+	file := tmpDir + "/app/app.go"
+
+	bl := NewBlacklist()
+	bl.AddType("Metrics")
+	sf := NewScopeFilter(p, bl)
+
+	tests := []struct {
+		typeName string
+		accepted bool
+		rule     string
+	}{
+		{"", false, types.DecisionEmpty},
+		{"map[string]int", false, types.DecisionMap},
+		{"string", false, types.DecisionBuiltin},
+		{"sync.Mutex", false, types.DecisionStdlib},
+		{"Metrics", false, types.DecisionBlacklist},
+		{"s.repo", false, types.DecisionLowercase},
+		{"Unknown", false, types.DecisionNotFound},
+		{"*Logger", true, types.DecisionAccepted},
+	}
+
+	for _, tt := range tests {
+		accepted, rule, detail := sf.Classify(tt.typeName, file)
+		if accepted != tt.accepted || rule != tt.rule {
+			t.Errorf("Classify(%q) = %v, %q, want %v, %q", tt.typeName, accepted, rule, tt.accepted, tt.rule)
+		}
+		if detail == "" {
+			t.Errorf("Classify(%q) returned empty detail", tt.typeName)
+		}
+		if got := sf.ShouldAnalyzeInFile(tt.typeName, file); got != tt.accepted {
+			t.Errorf("ShouldAnalyzeInFile(%q) = %v, inconsistent with Classify", tt.typeName, got)
+		}
+	}
+}
+
+func TestTraverser_Explain(t *testing.T) {
+	p, tmpDir := writeTestProject(t, map[string]string{
+		"go.mod":     "module example.com/app\n",
+		"app/app.go": explainProject,
+	})
+
+	bl := NewBlacklist()
+	bl.AddType("Metrics")
+	bl.AddRule(types.BlacklistRule{Type: "Repo", DepTypes: []string{types.DepTypeField}})
+
+	traverser := NewTraverser(p, NewScopeFilter(p, bl), nil, false)
+
+	// 未启用 explain 时不记录
+	if result := traverser.Analyze("Service", 1, tmpDir); len(result.Decisions) != 0 {
+		t.Fatalf("expected no decisions without explain, got %d", len(result.Decisions))
+	}
+
+	traverser.SetExplain(true)
+	result := traverser.Analyze("Service", 1, tmpDir)
+
+	rules := make(map[string]string)
+	for _, d := range result.Decisions {
+		if d.From == "Service" && d.DepType == types.DepTypeField {
+			rules[d.Type] = d.Rule
+		}
+	}
+
+	want := map[string]string{
+		"sync.Mutex":    types.DecisionStdlib,
+		"string":        types.DecisionBuiltin,
+		"time.Duration": types.DecisionStdlib,
+		"Logger":        types.DecisionAccepted,
+		"Metrics":       types.DecisionBlacklist,
+		"Repo":          types.DecisionEdgeRule,
+		"Unknown":       types.DecisionNotFound,
+	}
+	for typeName, rule := range want {
+		if rules[typeName] != rule {
+			t.Errorf("decision for %s = %q, want %q", typeName, rules[typeName], rule)
+		}
+	}
+
+	// 按 New 前缀推断失败的构造函数调用
+	found := false
+	for _, d := range result.Decisions {
+		if d.Type == "Widget" && d.Rule == types.DecisionNotFound && d.Context == "Run -> NewWidget" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected not-found decision for NewWidget")
+	}
+
+	// 再次分析不会累积上一次的记录
+	if again := traverser.Analyze("Service", 1, tmpDir); len(again.Decisions) != len(result.Decisions) {
+		t.Errorf("decisions accumulated across runs: %d vs %d", len(again.Decisions), len(result.Decisions))
+	}
+}
+
+func TestExplainType(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod":     "module example.com/app\n",
+		"app/app.go": explainProject,
+		"app/worker.go": `package app
+
+type Worker struct {
+	log *Logger
+}
+`,
+	})
+
+	decisions := ExplainType(p, NewScopeFilter(p, NewBlacklist()), "Logger")
+	if len(decisions) != 2 {
+		t.Fatalf("expected 2 decisions for Logger, got %+v", decisions)
+	}
+	for _, d := range decisions {
+		if !d.Accepted || d.Context != "log 字段" {
+			t.Errorf("unexpected decision %+v", d)
+		}
+	}
+
+	if got := ExplainType(p, NewScopeFilter(p, NewBlacklist()), "sync.Mutex"); len(got) != 1 || got[0].Rule != types.DecisionStdlib {
+		t.Errorf("ExplainType(sync.Mutex) = %+v", got)
+	}
+}
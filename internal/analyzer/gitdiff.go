@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// GitChanges 返回 dir 下相对 base 的 Go 文件改动（对比 base 与 HEAD 的合并基点，包括未提交的改动和未跟踪的新文件）
+// 文件路径为绝对路径，行号为改动后文件中的行号；已删除的文件不包括在内，未跟踪的文件整个视为改动
+func GitChanges(dir, base string) ([]types.FileChange, error) {
+	mergeBase, err := runGit(dir, "merge-base", base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := runGit(dir, "-c", "core.quotePath=false", "diff", "--unified=0", "--no-color", "--no-ext-diff", "--relative", strings.TrimSpace(mergeBase), "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+	changes := ParseUnifiedDiff(dir, diff)
+
+	untracked, err := runGit(dir, "ls-files", "-z", "--others", "--exclude-standard", "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(untracked, "\x00") {
+		if name != "" {
+			changes = append(changes, types.FileChange{Path: filepath.Join(dir, filepath.FromSlash(name))})
+		}
+	}
+	return changes, nil
+}
+
+// runGit 在 dir 下执行 git 命令并返回标准输出
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// ParseUnifiedDiff 解析 git diff --unified=0 的输出，文件路径相对 dir
+// 纯删除的 hunk 记为删除位置所在的那一行
+func ParseUnifiedDiff(dir, diff string) []types.FileChange {
+	var changes []types.FileChange
+	var current *types.FileChange
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			current = nil
+			// 含空格的文件名后面带有一个制表符
+			name := unquoteDiffPath(strings.TrimSuffix(strings.TrimPrefix(line, "+++ "), "\t"))
+			if name == "/dev/null" {
+				continue
+			}
+			name = strings.TrimPrefix(name, "b/")
+			changes = append(changes, types.FileChange{Path: filepath.Join(dir, filepath.FromSlash(name))})
+			current = &changes[len(changes)-1]
+
+		case strings.HasPrefix(line, "@@ ") && current != nil:
+			if r, ok := parseHunkHeader(line); ok {
+				current.Lines = append(current.Lines, r)
+			}
+		}
+	}
+	return changes
+}
+
+// unquoteDiffPath 还原 git 用双引号和 C 风格转义引起来的文件名（如含制表符、引号或非 ASCII 字符的路径）
+func unquoteDiffPath(name string) string {
+	if strings.HasPrefix(name, "\"") {
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			return unquoted
+		}
+	}
+	return name
+}
+
+// parseHunkHeader 从 "@@ -a,b +c,d @@" 中取出改动后文件的行范围
+func parseHunkHeader(header string) (types.LineRange, bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return types.LineRange{}, false
+	}
+	spec := strings.TrimPrefix(fields[2], "+")
+	count := 1
+	if i := strings.Index(spec, ","); i >= 0 {
+		n, err := strconv.Atoi(spec[i+1:])
+		if err != nil {
+			return types.LineRange{}, false
+		}
+		count = n
+		spec = spec[:i]
+	}
+	start, err := strconv.Atoi(spec)
+	if err != nil {
+		return types.LineRange{}, false
+	}
+
+	if count == 0 {
+		if start < 1 {
+			start = 1
+		}
+		return types.LineRange{Start: start, End: start}, true
+	}
+	return types.LineRange{Start: start, End: start + count - 1}, true
+}
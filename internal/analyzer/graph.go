@@ -0,0 +1,409 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// DefaultCycleLimit 默认最多枚举的基本环数量
+const DefaultCycleLimit = 1000
+
+// Graph 表示结构体之间的有向依赖图
+// 节点和邻接表在访问时按名称排序，保证算法结果与 map 遍历顺序无关
+type Graph struct {
+	nodes map[string]bool
+	edges map[string]map[string][]types.Dependency // from -> to -> 构成该边的依赖
+}
+
+// NewGraph 创建空的依赖图
+func NewGraph() *Graph {
+	return &Graph{
+		nodes: make(map[string]bool),
+		edges: make(map[string]map[string][]types.Dependency),
+	}
+}
+
+// BuildGraph 根据结构体分析结果构建依赖图
+// depTypes 为空时包含全部依赖类型，否则只包含指定类型的边；第三方叶子节点不参与
+func BuildGraph(structs []types.StructAnalysis, depTypes []string) *Graph {
+	allowed := make(map[string]bool)
+	for _, t := range depTypes {
+		allowed[t] = true
+	}
+
+	g := NewGraph()
+	for _, s := range structs {
+		g.AddNode(s.Name)
+		for _, dep := range s.Dependencies {
+			if dep.External {
+				continue
+			}
+			if len(allowed) > 0 && !allowed[dep.Type] {
+				continue
+			}
+			g.AddEdge(dep)
+		}
+	}
+	return g
+}
+
+// AddNode 添加节点
+func (g *Graph) AddNode(name string) {
+	g.nodes[name] = true
+}
+
+// AddEdge 添加一条依赖边，同一对节点之间的多条依赖合并为一条边
+func (g *Graph) AddEdge(dep types.Dependency) {
+	g.AddNode(dep.From)
+	g.AddNode(dep.To)
+	if g.edges[dep.From] == nil {
+		g.edges[dep.From] = make(map[string][]types.Dependency)
+	}
+	g.edges[dep.From][dep.To] = append(g.edges[dep.From][dep.To], dep)
+}
+
+// Nodes 返回全部节点（已排序）
+func (g *Graph) Nodes() []string {
+	nodes := make([]string, 0, len(g.nodes))
+	for n := range g.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Successors 返回节点的后继（已排序）
+func (g *Graph) Successors(node string) []string {
+	succ := make([]string, 0, len(g.edges[node]))
+	for to := range g.edges[node] {
+		succ = append(succ, to)
+	}
+	sort.Strings(succ)
+	return succ
+}
+
+// Predecessors 返回节点的前驱，即依赖该节点的节点（已排序）
+func (g *Graph) Predecessors(node string) []string {
+	var pred []string
+	for from, targets := range g.edges {
+		if _, ok := targets[node]; ok {
+			pred = append(pred, from)
+		}
+	}
+	sort.Strings(pred)
+	return pred
+}
+
+// EdgeDeps 返回构成 from -> to 边的依赖
+func (g *Graph) EdgeDeps(from, to string) []types.Dependency {
+	return g.edges[from][to]
+}
+
+// HasEdge 判断是否存在 from -> to 边
+func (g *Graph) HasEdge(from, to string) bool {
+	_, ok := g.edges[from][to]
+	return ok
+}
+
+// StronglyConnectedComponents 使用 Tarjan 算法计算强连通分量
+// 只返回包含环的分量（节点数大于 1，或存在自环），分量内节点已排序，分量按首节点排序
+func (g *Graph) StronglyConnectedComponents() [][]string {
+	var result [][]string
+	for _, comp := range g.tarjan(g.Nodes(), nil) {
+		if len(comp) > 1 || g.HasEdge(comp[0], comp[0]) {
+			result = append(result, comp)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i][0] < result[j][0] })
+	return result
+}
+
+// tarjan 在 nodes 构成的子图上运行 Tarjan 算法，allowed 为 nil 时不限制节点
+func (g *Graph) tarjan(nodes []string, allowed map[string]bool) [][]string {
+	index := 0
+	indices := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var comps [][]string
+
+	var strongConnect func(v string)
+	strongConnect = func(v string) {
+		indices[v] = index
+		lowlink[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range g.Successors(v) {
+			if allowed != nil && !allowed[w] {
+				continue
+			}
+			if _, visited := indices[w]; !visited {
+				strongConnect(w)
+				if lowlink[w] < lowlink[v] {
+					lowlink[v] = lowlink[w]
+				}
+			} else if onStack[w] && indices[w] < lowlink[v] {
+				lowlink[v] = indices[w]
+			}
+		}
+
+		if lowlink[v] == indices[v] {
+			var comp []string
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				comp = append(comp, w)
+				if w == v {
+					break
+				}
+			}
+			sort.Strings(comp)
+			comps = append(comps, comp)
+		}
+	}
+
+	for _, v := range nodes {
+		if _, visited := indices[v]; !visited {
+			strongConnect(v)
+		}
+	}
+	return comps
+}
+
+// ElementaryCycles 使用 Johnson 算法枚举全部基本环
+// 每个环从其字典序最小的节点开始，且只出现一次；limit > 0 时最多返回 limit 个环，
+// 第二个返回值表示是否因达到上限而截断
+func (g *Graph) ElementaryCycles(limit int) ([][]string, bool) {
+	nodes := g.Nodes()
+	var cycles [][]string
+	truncated := false
+
+	for i, start := range nodes {
+		// 只考虑不小于 start 的节点构成的子图中，包含 start 的强连通分量
+		allowed := make(map[string]bool, len(nodes)-i)
+		for _, n := range nodes[i:] {
+			allowed[n] = true
+		}
+
+		var component map[string]bool
+		for _, comp := range g.tarjan([]string{start}, allowed) {
+			for _, n := range comp {
+				if n == start {
+					component = make(map[string]bool, len(comp))
+					for _, m := range comp {
+						component[m] = true
+					}
+				}
+			}
+		}
+		if len(component) == 1 && !g.HasEdge(start, start) {
+			continue
+		}
+
+		blocked := make(map[string]bool)
+		blockMap := make(map[string]map[string]bool)
+		var path []string
+
+		var unblock func(u string)
+		unblock = func(u string) {
+			blocked[u] = false
+			for w := range blockMap[u] {
+				delete(blockMap[u], w)
+				if blocked[w] {
+					unblock(w)
+				}
+			}
+		}
+
+		var circuit func(v string) bool
+		circuit = func(v string) bool {
+			found := false
+			path = append(path, v)
+			blocked[v] = true
+
+			for _, w := range g.Successors(v) {
+				if !component[w] || truncated {
+					continue
+				}
+				if w == start {
+					// 已达上限时再找到环才算截断
+					if limit > 0 && len(cycles) >= limit {
+						truncated = true
+						continue
+					}
+					cycle := make([]string, len(path))
+					copy(cycle, path)
+					cycles = append(cycles, cycle)
+					found = true
+				} else if !blocked[w] && circuit(w) {
+					found = true
+				}
+			}
+
+			if found {
+				unblock(v)
+			} else {
+				for _, w := range g.Successors(v) {
+					if !component[w] {
+						continue
+					}
+					if blockMap[w] == nil {
+						blockMap[w] = make(map[string]bool)
+					}
+					blockMap[w][v] = true
+				}
+			}
+
+			path = path[:len(path)-1]
+			return found
+		}
+
+		circuit(start)
+		if truncated {
+			break
+		}
+	}
+
+	return cycles, truncated
+}
+
+// CycleDetail 生成环的详细信息：每一步的依赖类型和依据
+func (g *Graph) CycleDetail(nodes []string) types.CycleDetail {
+	detail := types.CycleDetail{Nodes: nodes}
+	typeSet := make(map[string]bool)
+
+	for i, from := range nodes {
+		to := nodes[(i+1)%len(nodes)]
+		edgeTypes, evidence := g.edgeEvidence(from, to)
+		for _, t := range edgeTypes {
+			typeSet[t] = true
+		}
+		detail.Edges = append(detail.Edges, types.CycleEdge{From: from, To: to, Types: edgeTypes, Evidence: evidence})
+	}
+
+	for t := range typeSet {
+		detail.EdgeTypes = append(detail.EdgeTypes, t)
+	}
+	sort.Strings(detail.EdgeTypes)
+	return detail
+}
+
+// PathDetail 生成依赖路径的详细信息：每一跳的依赖类型和依据
+func (g *Graph) PathDetail(nodes []string) types.DependencyPath {
+	path := types.DependencyPath{Nodes: nodes}
+	for i := 0; i+1 < len(nodes); i++ {
+		edgeTypes, evidence := g.edgeEvidence(nodes[i], nodes[i+1])
+		path.Hops = append(path.Hops, types.PathHop{From: nodes[i], To: nodes[i+1], Types: edgeTypes, Evidence: evidence})
+	}
+	return path
+}
+
+// edgeEvidence 返回 from -> to 边的依赖类型（已排序）和依据（去重，保持出现顺序）
+func (g *Graph) edgeEvidence(from, to string) ([]string, []string) {
+	var edgeTypes, evidence []string
+	for _, dep := range g.EdgeDeps(from, to) {
+		if !containsString(edgeTypes, dep.Type) {
+			edgeTypes = append(edgeTypes, dep.Type)
+		}
+		if dep.Context != "" && !containsString(evidence, dep.Context) {
+			evidence = append(evidence, dep.Context)
+		}
+	}
+	sort.Strings(edgeTypes)
+	return edgeTypes, evidence
+}
+
+// ShortestPath 使用 BFS 查找 from 到 to 的最短路径（后继按名称排序，结果确定），不存在时返回 nil
+func (g *Graph) ShortestPath(from, to string) []string {
+	if !g.nodes[from] || !g.nodes[to] {
+		return nil
+	}
+	if from == to {
+		return []string{from}
+	}
+
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range g.Successors(v) {
+			if _, seen := prev[w]; seen {
+				continue
+			}
+			prev[w] = v
+			if w == to {
+				var path []string
+				for n := to; n != from; n = prev[n] {
+					path = append([]string{n}, path...)
+				}
+				return append([]string{from}, path...)
+			}
+			queue = append(queue, w)
+		}
+	}
+	return nil
+}
+
+// SimplePaths 枚举 from 到 to 的全部简单路径（不重复经过节点）
+// maxHops > 0 时只保留不超过该跳数的路径，limit > 0 时最多返回 limit 条；按跳数逐层搜索，
+// 结果按跳数、字典序排序，截断时保留的是最短的路径。第二个返回值表示是否因达到上限而截断
+func (g *Graph) SimplePaths(from, to string, maxHops, limit int) ([][]string, bool) {
+	if !g.nodes[from] || !g.nodes[to] || from == to {
+		return nil, false
+	}
+
+	maxDepth := len(g.nodes) - 1
+	if maxHops > 0 && maxHops < maxDepth {
+		maxDepth = maxHops
+	}
+
+	var paths [][]string
+	truncated := false
+	onPath := map[string]bool{from: true}
+	path := []string{from}
+
+	// 每层用 DFS 只收集恰好 depth 跳的路径；后继按名称排序，同层路径按字典序产生
+	for depth, frontier := 1, true; depth <= maxDepth && frontier && !truncated; depth++ {
+		frontier = false
+		var dfs func(v string)
+		dfs = func(v string) {
+			if len(path) == depth {
+				frontier = true
+			}
+			for _, w := range g.Successors(v) {
+				if truncated {
+					return
+				}
+				if len(path) == depth {
+					if w != to {
+						continue
+					}
+					if limit > 0 && len(paths) >= limit {
+						truncated = true
+						return
+					}
+					p := make([]string, len(path)+1)
+					copy(p, path)
+					p[len(path)] = w
+					paths = append(paths, p)
+					continue
+				}
+				if w == to || onPath[w] {
+					continue
+				}
+				onPath[w] = true
+				path = append(path, w)
+				dfs(w)
+				path = path[:len(path)-1]
+				onPath[w] = false
+			}
+		}
+		dfs(from)
+	}
+	return paths, truncated
+}
@@ -0,0 +1,288 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// buildTestGraph 根据 "from->to:type" 形式的边构建依赖图
+func buildTestGraph(edges ...[3]string) *Graph {
+	g := NewGraph()
+	for _, e := range edges {
+		g.AddEdge(types.Dependency{From: e[0], To: e[1], Type: e[2], Context: e[0] + "." + e[1]})
+	}
+	return g
+}
+
+func TestGraph_StronglyConnectedComponents(t *testing.T) {
+	g := buildTestGraph(
+		[3]string{"A", "B", types.DepTypeField},
+		[3]string{"B", "C", types.DepTypeField},
+		[3]string{"C", "A", types.DepTypeField},
+		[3]string{"C", "D", types.DepTypeField},
+		[3]string{"E", "E", types.DepTypeField},
+		[3]string{"F", "G", types.DepTypeField},
+	)
+
+	want := [][]string{{"A", "B", "C"}, {"E"}}
+	if got := g.StronglyConnectedComponents(); !reflect.DeepEqual(got, want) {
+		t.Errorf("StronglyConnectedComponents() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_ElementaryCycles(t *testing.T) {
+	// 三个节点的完全图：3 个二元环 + 2 个三元环
+	var edges [][3]string
+	for _, from := range []string{"A", "B", "C"} {
+		for _, to := range []string{"A", "B", "C"} {
+			if from != to {
+				edges = append(edges, [3]string{from, to, types.DepTypeField})
+			}
+		}
+	}
+	g := buildTestGraph(edges...)
+
+	cycles, truncated := g.ElementaryCycles(0)
+	if truncated {
+		t.Error("should not be truncated without limit")
+	}
+	want := [][]string{
+		{"A", "B"},
+		{"A", "B", "C"},
+		{"A", "C"},
+		{"A", "C", "B"},
+		{"B", "C"},
+	}
+	if !reflect.DeepEqual(cycles, want) {
+		t.Errorf("ElementaryCycles() = %v, want %v", cycles, want)
+	}
+
+	// 结果与构建顺序无关
+	reversed := make([][3]string, len(edges))
+	for i, e := range edges {
+		reversed[len(edges)-1-i] = e
+	}
+	if again, _ := buildTestGraph(reversed...).ElementaryCycles(0); !reflect.DeepEqual(again, cycles) {
+		t.Errorf("cycles depend on insertion order: %v vs %v", again, cycles)
+	}
+
+	limited, truncated := g.ElementaryCycles(2)
+	if len(limited) != 2 || !truncated {
+		t.Errorf("limit 2: got %d cycles, truncated=%v", len(limited), truncated)
+	}
+	// 环数恰好等于上限时不算截断
+	if exact, truncated := g.ElementaryCycles(len(want)); len(exact) != len(want) || truncated {
+		t.Errorf("limit %d: got %d cycles, truncated=%v", len(want), len(exact), truncated)
+	}
+}
+
+func TestGraph_SelfLoopAndDetail(t *testing.T) {
+	g := buildTestGraph(
+		[3]string{"Node", "Node", types.DepTypeField},
+		[3]string{"A", "B", types.DepTypeField},
+		[3]string{"A", "B", types.DepTypeMethodCall},
+		[3]string{"B", "A", types.DepTypeInterface},
+	)
+
+	cycles, _ := g.ElementaryCycles(0)
+	if !reflect.DeepEqual(cycles, [][]string{{"A", "B"}, {"Node"}}) {
+		t.Fatalf("cycles = %v", cycles)
+	}
+
+	detail := g.CycleDetail(cycles[0])
+	if !reflect.DeepEqual(detail.EdgeTypes, []string{types.DepTypeField, types.DepTypeInterface, types.DepTypeMethodCall}) {
+		t.Errorf("EdgeTypes = %v", detail.EdgeTypes)
+	}
+	if len(detail.Edges) != 2 || detail.Edges[1].From != "B" || detail.Edges[1].To != "A" {
+		t.Fatalf("Edges = %+v", detail.Edges)
+	}
+	if !reflect.DeepEqual(detail.Edges[0].Types, []string{types.DepTypeField, types.DepTypeMethodCall}) {
+		t.Errorf("first step types = %v", detail.Edges[0].Types)
+	}
+	if !reflect.DeepEqual(detail.Edges[0].Evidence, []string{"A.B"}) {
+		t.Errorf("first step evidence = %v", detail.Edges[0].Evidence)
+	}
+}
+
+func TestBuildGraph_FilterByDepType(t *testing.T) {
+	structs := []types.StructAnalysis{
+		{Name: "A", Dependencies: []types.Dependency{
+			{From: "A", To: "B", Type: types.DepTypeField},
+			{From: "A", To: "C", Type: types.DepTypeField},
+			{From: "A", To: "gorm.DB", Type: types.DepTypeField, External: true},
+		}},
+		{Name: "B", Dependencies: []types.Dependency{{From: "B", To: "A", Type: types.DepTypeInterface}}},
+		{Name: "C", Dependencies: []types.Dependency{{From: "C", To: "A", Type: types.DepTypeField}}},
+	}
+
+	all, _ := BuildGraph(structs, nil).ElementaryCycles(0)
+	if len(all) != 2 {
+		t.Errorf("expected 2 cycles over all edge types, got %v", all)
+	}
+
+	fieldOnly, _ := BuildGraph(structs, []string{types.DepTypeField}).ElementaryCycles(0)
+	if !reflect.DeepEqual(fieldOnly, [][]string{{"A", "C"}}) {
+		t.Errorf("field-only cycles = %v", fieldOnly)
+	}
+
+	if g := BuildGraph(structs, nil); g.HasEdge("A", "gorm.DB") {
+		t.Error("external leaf edges must not be part of the graph")
+	}
+}
+
+func TestBuildPackageGraph(t *testing.T) {
+	p, tmpDir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"service/service.go": `package service
+
+import "example.com/app/store"
+
+type Service struct {
+	repo  *store.Repo
+	cache *store.Cache
+	log   *Logger
+}
+
+type Logger struct{}
+`,
+		"store/store.go": `package store
+
+import "example.com/app/service"
+
+type Repo struct {
+	log *service.Logger
+}
+
+type Cache struct{}
+`,
+	})
+
+	filter := NewScopeFilter(p, NewBlacklist())
+	result := NewTraverser(p, filter, nil, false).Analyze("Service", 5, tmpDir)
+	graph := result.PackageGraph
+
+	if len(graph.Packages) != 2 {
+		t.Fatalf("expected 2 packages, got %+v", graph.Packages)
+	}
+	svc := graph.Packages[0]
+	if svc.ImportPath != "example.com/app/service" || svc.Depth != 0 {
+		t.Errorf("unexpected service package: %+v", svc)
+	}
+	if len(svc.Structs) != 2 || svc.Structs[0] != "Logger" || svc.Structs[1] != "Service" {
+		t.Errorf("service package structs = %v", svc.Structs)
+	}
+
+	if len(graph.Edges) != 2 {
+		t.Fatalf("expected 2 package edges (intra-package excluded), got %+v", graph.Edges)
+	}
+	forward := graph.Edges[0]
+	if forward.From != "example.com/app/service" || forward.To != "example.com/app/store" || forward.Weight != 2 || len(forward.Deps) != 2 {
+		t.Errorf("unexpected service -> store edge: %+v", forward)
+	}
+
+	if len(graph.Cycles) != 1 || len(graph.Cycles[0]) != 2 || graph.Cycles[0][0] != "example.com/app/service" {
+		t.Errorf("expected one package cycle starting at service, got %v", graph.Cycles)
+	}
+}
+
+func TestBuildPackageGraph_SameNameInTwoPackages(t *testing.T) {
+	p, tmpDir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"service/service.go": `package service
+
+import (
+	"example.com/app/config"
+	"example.com/app/store"
+)
+
+type Service struct {
+	cfg   *config.Config
+	store *store.Store
+}
+`,
+		"config/config.go": `package config
+
+type Config struct{}
+`,
+		"store/store.go": `package store
+
+type Config struct{}
+
+type Store struct {
+	cfg *Config
+}
+`,
+	})
+
+	filter := NewScopeFilter(p, NewBlacklist())
+	graph := NewTraverser(p, filter, nil, false).Analyze("Service", 5, tmpDir).PackageGraph
+
+	var edges []string
+	for _, e := range graph.Edges {
+		edges = append(edges, e.From+" -> "+e.To)
+	}
+	want := []string{
+		"example.com/app/service -> example.com/app/config",
+		"example.com/app/service -> example.com/app/store",
+	}
+	if !reflect.DeepEqual(edges, want) {
+		t.Errorf("package edges = %v, want %v", edges, want)
+	}
+	if len(graph.Cycles) != 0 {
+		t.Errorf("same-named types must not create package cycles, got %v", graph.Cycles)
+	}
+}
+
+func TestGraph_Paths(t *testing.T) {
+	g := buildTestGraph(
+		[3]string{"A", "B", types.DepTypeField},
+		[3]string{"B", "D", types.DepTypeField},
+		[3]string{"A", "C", types.DepTypeField},
+		[3]string{"C", "E", types.DepTypeField},
+		[3]string{"E", "D", types.DepTypeField},
+		[3]string{"D", "A", types.DepTypeField},
+	)
+
+	if got := g.ShortestPath("A", "D"); !reflect.DeepEqual(got, []string{"A", "B", "D"}) {
+		t.Errorf("ShortestPath(A, D) = %v", got)
+	}
+	if got := g.ShortestPath("B", "C"); !reflect.DeepEqual(got, []string{"B", "D", "A", "C"}) {
+		t.Errorf("ShortestPath(B, C) = %v", got)
+	}
+	if got := g.ShortestPath("D", "F"); got != nil {
+		t.Errorf("ShortestPath to unknown node should be nil, got %v", got)
+	}
+
+	paths, truncated := g.SimplePaths("A", "D", 0, 10)
+	want := [][]string{{"A", "B", "D"}, {"A", "C", "E", "D"}}
+	if !reflect.DeepEqual(paths, want) || truncated {
+		t.Errorf("SimplePaths(A, D) = %v (truncated %v), want %v", paths, truncated, want)
+	}
+	if paths, _ := g.SimplePaths("A", "D", 2, 10); len(paths) != 1 {
+		t.Errorf("max hops 2 should keep only the short path, got %v", paths)
+	}
+	if paths, truncated := g.SimplePaths("A", "D", 0, 1); len(paths) != 1 || !truncated {
+		t.Errorf("limit 1 should truncate, got %v (truncated %v)", paths, truncated)
+	}
+	if paths, truncated := g.SimplePaths("A", "D", 0, 2); len(paths) != 2 || truncated {
+		t.Errorf("limit equal to the number of paths should not truncate, got %v (truncated %v)", paths, truncated)
+	}
+
+	// DFS 先经过 B 找到长路径，截断时仍应保留最短的直达路径
+	long := buildTestGraph(
+		[3]string{"A", "B", types.DepTypeField},
+		[3]string{"B", "C", types.DepTypeField},
+		[3]string{"C", "D", types.DepTypeField},
+		[3]string{"A", "D", types.DepTypeField},
+	)
+	if paths, truncated := long.SimplePaths("A", "D", 0, 1); !reflect.DeepEqual(paths, [][]string{{"A", "D"}}) || !truncated {
+		t.Errorf("SimplePaths(A, D, limit 1) = %v (truncated %v), want the direct path", paths, truncated)
+	}
+
+	detail := g.PathDetail([]string{"A", "B", "D"})
+	if len(detail.Hops) != 2 || detail.Hops[0].Evidence[0] != "A.B" || detail.Hops[1].Types[0] != types.DepTypeField {
+		t.Errorf("unexpected path detail: %+v", detail)
+	}
+}
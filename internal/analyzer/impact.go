@@ -0,0 +1,200 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"sort"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// DefaultImpactDepth 默认的反向依赖深度
+const DefaultImpactDepth = 3
+
+// ImpactQuery 表示改动影响分析选项
+type ImpactQuery struct {
+	Changes  []types.FileChange // 改动的文件和行
+	MaxDepth int                // 反向依赖的最大深度（<= 0 表示不限制）
+	DepTypes []string           // 只沿这些依赖类型的边传播（为空表示全部）
+}
+
+// AnalyzeImpact 将改动行映射到声明，再沿反向依赖找出受影响的类型
+func AnalyzeImpact(p *parser.Parser, filter *ScopeFilter, query ImpactQuery) types.ImpactResult {
+	result := types.ImpactResult{MaxDepth: query.MaxDepth}
+	for _, change := range query.Changes {
+		result.Files = append(result.Files, change.Path)
+	}
+	result.Changed = ChangedDecls(p, query.Changes)
+
+	// 改动涉及的类型作为起点（距离 0）
+	seen := make(map[string]bool)
+	var queue []types.ImpactedType
+	for _, decl := range result.Changed {
+		if seen[decl.Type] {
+			continue
+		}
+		seen[decl.Type] = true
+		queue = append(queue, impactedType(p, decl.Type))
+	}
+	sort.Slice(queue, func(i, j int) bool { return queue[i].Name < queue[j].Name })
+
+	g := BuildGraph(AnalyzeProject(p, filter), query.DepTypes)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		result.Impacted = append(result.Impacted, current)
+		if query.MaxDepth > 0 && current.Distance >= query.MaxDepth {
+			continue
+		}
+		for _, dependent := range g.Predecessors(current.Name) {
+			if seen[dependent] {
+				continue
+			}
+			seen[dependent] = true
+			next := impactedType(p, dependent)
+			next.Distance = current.Distance + 1
+			next.Via = current.Name
+			next.Types, next.Evidence = g.edgeEvidence(dependent, current.Name)
+			queue = append(queue, next)
+		}
+	}
+
+	sort.SliceStable(result.Impacted, func(i, j int) bool {
+		if result.Impacted[i].Distance != result.Impacted[j].Distance {
+			return result.Impacted[i].Distance < result.Impacted[j].Distance
+		}
+		return result.Impacted[i].Name < result.Impacted[j].Name
+	})
+	return result
+}
+
+// impactedType 返回带有包名和文件位置的受影响类型
+func impactedType(p *parser.Parser, name string) types.ImpactedType {
+	t := types.ImpactedType{Name: name}
+	if info := p.GetStruct(name); info != nil {
+		t.Package, t.FilePath = info.Package, info.FilePath
+	} else if iface := p.GetInterface(name); iface != nil {
+		t.Package, t.FilePath = iface.Package, iface.FilePath
+	}
+	return t
+}
+
+// ChangedDecls 返回与改动行重叠的结构体、接口、方法和构造函数声明（按文件、行号排序）
+// 声明的范围包括其文档注释；未解析到的文件（如测试文件、已删除的文件）被忽略
+func ChangedDecls(p *parser.Parser, changes []types.FileChange) []types.ChangedDecl {
+	fset := p.GetFileSet()
+	var decls []types.ChangedDecl
+
+	for _, change := range changes {
+		file := p.GetFile(change.Path)
+		if file == nil {
+			continue
+		}
+
+		// changed 判断声明（含文档注释）是否与改动行重叠，并返回声明起始行
+		changed := func(doc *ast.CommentGroup, node ast.Node) (int, bool) {
+			start := fset.Position(node.Pos()).Line
+			end := fset.Position(node.End()).Line
+			if doc != nil {
+				start = fset.Position(doc.Pos()).Line
+			}
+			if len(change.Lines) == 0 {
+				return start, true
+			}
+			for _, r := range change.Lines {
+				if r.Start <= end && r.End >= start {
+					return start, true
+				}
+			}
+			return start, false
+		}
+		add := func(kind, name, typeName string, line int) {
+			decls = append(decls, types.ChangedDecl{Kind: kind, Name: name, Type: typeName, FilePath: change.Path, Line: line})
+		}
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range d.Specs {
+					ts := spec.(*ast.TypeSpec)
+					doc := ts.Doc
+					var node ast.Node = ts
+					if len(d.Specs) == 1 {
+						doc, node = d.Doc, d
+					}
+					line, ok := changed(doc, node)
+					if !ok {
+						continue
+					}
+					switch ts.Type.(type) {
+					case *ast.StructType:
+						if info := p.GetStruct(ts.Name.Name); info != nil && info.FilePath == change.Path {
+							add(types.ChangedKindStruct, ts.Name.Name, ts.Name.Name, line)
+						}
+					case *ast.InterfaceType:
+						if iface := p.GetInterface(ts.Name.Name); iface != nil && iface.FilePath == change.Path {
+							add(types.ChangedKindInterface, ts.Name.Name, ts.Name.Name, line)
+						}
+					}
+				}
+
+			case *ast.FuncDecl:
+				line, ok := changed(d.Doc, d)
+				if !ok {
+					continue
+				}
+				if d.Recv != nil {
+					recv := receiverTypeName(d)
+					if info := p.GetStruct(recv); info != nil && filepath.Dir(info.FilePath) == filepath.Dir(change.Path) {
+						add(types.ChangedKindMethod, recv+"."+d.Name.Name, recv, line)
+					}
+					continue
+				}
+				fn := p.GetFunction(file.Name.Name + "." + d.Name.Name)
+				if fn == nil || fn.FilePath != change.Path {
+					continue
+				}
+				if ret := parser.ExtractBaseType(fn.ReturnType); p.GetStruct(ret) != nil {
+					add(types.ChangedKindConstructor, d.Name.Name, ret, line)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(decls, func(i, j int) bool {
+		if decls[i].FilePath != decls[j].FilePath {
+			return decls[i].FilePath < decls[j].FilePath
+		}
+		return decls[i].Line < decls[j].Line
+	})
+	return decls
+}
+
+// receiverTypeName 返回方法接收者的类型名（去掉指针和泛型参数）
+func receiverTypeName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+	expr := funcDecl.Recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
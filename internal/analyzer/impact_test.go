@@ -0,0 +1,185 @@
+package analyzer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+const impactServiceFile = `package service
+
+import "example.com/app/internal/repository"
+
+// UserService 用户服务
+type UserService struct {
+	repo *repository.UserRepo
+}
+
+func NewUserService(repo *repository.UserRepo) *UserService {
+	return &UserService{repo: repo}
+}
+
+func (s *UserService) Get() {
+	_ = s.repo
+}
+
+type Notifier interface {
+	Notify()
+}
+`
+
+func TestChangedDecls(t *testing.T) {
+	files := layeredProject(t)
+	files["internal/service/service.go"] = impactServiceFile
+	p, tmpDir := writeTestProject(t, files)
+	path := filepath.Join(tmpDir, "internal/service/service.go")
+
+	tests := []struct {
+		name  string
+		lines []types.LineRange
+		want  []string
+	}{
+		{"doc comment", []types.LineRange{{Start: 5, End: 5}}, []string{"struct UserService"}},
+		{"constructor", []types.LineRange{{Start: 11, End: 11}}, []string{"constructor NewUserService"}},
+		{"method and interface", []types.LineRange{{Start: 15, End: 15}, {Start: 19, End: 20}}, []string{"method UserService.Get", "interface Notifier"}},
+		{"imports only", []types.LineRange{{Start: 3, End: 3}}, nil},
+		{"whole file", nil, []string{"struct UserService", "constructor NewUserService", "method UserService.Get", "interface Notifier"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range ChangedDecls(p, []types.FileChange{{Path: path, Lines: tt.lines}}) {
+				got = append(got, d.Kind+" "+d.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChangedDecls() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeImpact(t *testing.T) {
+	p, tmpDir := writeTestProject(t, layeredProject(t))
+	filter := NewScopeFilter(p, NewBlacklist())
+	change := types.FileChange{Path: filepath.Join(tmpDir, "internal/model/user.go"), Lines: []types.LineRange{{Start: 6, End: 6}}}
+
+	result := AnalyzeImpact(p, filter, ImpactQuery{Changes: []types.FileChange{change}})
+	if len(result.Changed) != 1 || result.Changed[0].Type != "User" {
+		t.Fatalf("expected User to be changed, got %+v", result.Changed)
+	}
+
+	distances := make(map[string]int)
+	for _, it := range result.Impacted {
+		distances[it.Name] = it.Distance
+	}
+	want := map[string]int{"User": 0, "UserRepo": 1, "UserHandler": 2, "UserService": 2}
+	if !reflect.DeepEqual(distances, want) {
+		t.Errorf("impacted = %v, want %v", distances, want)
+	}
+	repo := result.Impacted[1]
+	if repo.Name != "UserRepo" || repo.Via != "User" || repo.Evidence[0] != "user 字段" {
+		t.Errorf("unexpected impacted entry: %+v", repo)
+	}
+
+	result = AnalyzeImpact(p, filter, ImpactQuery{Changes: []types.FileChange{change}, MaxDepth: 1})
+	if len(result.Impacted) != 2 {
+		t.Errorf("depth 1 should stop at direct dependents, got %+v", result.Impacted)
+	}
+}
+
+func TestParseUnifiedDiff(t *testing.T) {
+	diff := `diff --git a/service/user.go b/service/user.go
+index 1111111..2222222 100644
+--- a/service/user.go
++++ b/service/user.go
+@@ -10,0 +11,2 @@ type UserService struct {
++	cache *Cache
++	log   *Logger
+@@ -20 +22 @@ func (s *UserService) Get() {
+-	return nil
++	return s.cache
+@@ -30,2 +31,0 @@ func (s *UserService) Put() {
+diff --git "a/model/\344\275\240\tx.go" "b/model/\344\275\240\tx.go"
+--- "a/model/\344\275\240\tx.go"
++++ "b/model/\344\275\240\tx.go"
+@@ -5 +5 @@ type Order struct {
+diff --git a/model/a b.go b/model/a b.go
+--- a/model/a b.go	
++++ b/model/a b.go	
+@@ -1,0 +2 @@
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,3 +0,0 @@
+`
+	changes := ParseUnifiedDiff("/repo", diff)
+	want := []types.FileChange{{
+		Path:  filepath.Join("/repo", "service", "user.go"),
+		Lines: []types.LineRange{{Start: 11, End: 12}, {Start: 22, End: 22}, {Start: 31, End: 31}},
+	}, {
+		Path:  filepath.Join("/repo", "model", "你\tx.go"),
+		Lines: []types.LineRange{{Start: 5, End: 5}},
+	}, {
+		Path:  filepath.Join("/repo", "model", "a b.go"),
+		Lines: []types.LineRange{{Start: 2, End: 2}},
+	}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("ParseUnifiedDiff() = %+v, want %+v", changes, want)
+	}
+}
+
+// newTestGitRepo 创建包含 files 的临时 git 仓库并提交一次，没有 git 时跳过测试
+func newTestGitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	writeTestFiles(t, dir, files)
+	for _, args := range [][]string{{"init", "-q"}, {"add", "."}, {"commit", "-q", "-m", "init"}} {
+		if _, err := runGit(dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// writeTestFiles 将 files（斜杠分隔的相对路径 -> 内容）写入 dir
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGitChanges_Untracked(t *testing.T) {
+	dir := newTestGitRepo(t, map[string]string{"main.go": "package main\n"})
+	writeTestFiles(t, dir, map[string]string{
+		"main.go":         "package main\n\nfunc main() {}\n",
+		"pkg/new file.go": "package pkg\n",
+		"README.md":       "readme\n",
+	})
+
+	changes, err := GitChanges(dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.FileChange{
+		{Path: filepath.Join(dir, "main.go"), Lines: []types.LineRange{{Start: 2, End: 3}}},
+		{Path: filepath.Join(dir, "pkg", "new file.go")},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("GitChanges() = %+v, want %+v", changes, want)
+	}
+}
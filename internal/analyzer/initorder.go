@@ -0,0 +1,263 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/printer"
+	"go/token"
+	gotypes "go/types"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// InitDepTypes 决定构造顺序的依赖类型：字段、嵌入和构造函数参数
+var InitDepTypes = []string{types.DepTypeField, types.DepTypeEmbed, types.DepTypeConstructorParam}
+
+// ComputeInitOrder 计算构造根结构体所需的全部结构体的构造顺序（叶子在前）
+// 依赖只考虑字段、嵌入和构造函数参数；接口依赖在只有一个实现时解析为该实现。
+// 位于依赖环上的结构体以及依赖它们的结构体无法构造，列入 Blocked
+func ComputeInitOrder(p *parser.Parser, filter *ScopeFilter, root string) (types.InitOrder, error) {
+	order := types.InitOrder{Root: root}
+	if p.GetStruct(root) == nil {
+		return order, fmt.Errorf("struct %q not found", root)
+	}
+
+	structs := AnalyzeProject(p, filter)
+	full := BuildGraph(structs, nil)
+	g := BuildGraph(structs, InitDepTypes)
+
+	// 接口的实现
+	implementers := make(map[string][]string)
+	for _, from := range full.Nodes() {
+		for _, to := range full.Successors(from) {
+			for _, dep := range full.EdgeDeps(from, to) {
+				if dep.Type == types.DepTypeInterface {
+					implementers[to] = append(implementers[to], from)
+					break
+				}
+			}
+		}
+	}
+
+	// deps 返回构造节点前需要先完成的节点：结构体的依赖（不含自身），或接口的唯一实现
+	deps := func(node string) []string {
+		if p.GetStruct(node) == nil {
+			if impls := implementers[node]; len(impls) == 1 {
+				return impls
+			}
+			return nil
+		}
+		var result []string
+		for _, to := range g.Successors(node) {
+			if to != node {
+				result = append(result, to)
+			}
+		}
+		return result
+	}
+
+	// 从根出发可达的节点
+	reachable := map[string]bool{root: true}
+	stack := []string{root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, next := range deps(node) {
+			if !reachable[next] {
+				reachable[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	nodes := make([]string, 0, len(reachable))
+	for n := range reachable {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+
+	for _, n := range nodes {
+		if p.GetStruct(n) == nil && p.GetInterface(n) != nil {
+			binding := types.InterfaceBinding{Interface: n, Candidates: implementers[n]}
+			if len(binding.Candidates) == 1 {
+				binding.Implementer = binding.Candidates[0]
+			}
+			order.Interfaces = append(order.Interfaces, binding)
+		}
+	}
+
+	// 逐轮取出依赖都已完成的节点（每轮按名称排序，结果确定）
+	done := make(map[string]bool)
+	for {
+		var ready []string
+		for _, n := range nodes {
+			if done[n] {
+				continue
+			}
+			ok := true
+			for _, d := range deps(n) {
+				if !done[d] {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				ready = append(ready, n)
+			}
+		}
+		if len(ready) == 0 {
+			break
+		}
+		for _, n := range ready {
+			if info := p.GetStruct(n); info != nil {
+				order.Steps = append(order.Steps, buildInitStep(p, info, deps(n), done, implementers))
+			}
+		}
+		for _, n := range ready {
+			done[n] = true
+		}
+	}
+
+	// 剩余节点无法构造：找出其中的依赖环
+	remaining := NewGraph()
+	for _, n := range nodes {
+		if done[n] {
+			continue
+		}
+		remaining.AddNode(n)
+		if p.GetStruct(n) != nil {
+			order.Blocked = append(order.Blocked, n)
+		}
+		for _, d := range deps(n) {
+			if done[d] {
+				continue
+			}
+			if p.GetStruct(n) == nil {
+				remaining.AddEdge(types.Dependency{From: n, To: d, Type: types.DepTypeInterface, Context: "唯一实现"})
+				continue
+			}
+			for _, dep := range g.EdgeDeps(n, d) {
+				remaining.AddEdge(dep)
+			}
+		}
+	}
+	cycles, _ := remaining.ElementaryCycles(DefaultCycleLimit)
+	for _, cycle := range cycles {
+		order.Cycles = append(order.Cycles, remaining.CycleDetail(cycle))
+	}
+
+	return order, nil
+}
+
+// buildInitStep 选择结构体的构造函数，并为每个参数寻找已构造的来源
+func buildInitStep(p *parser.Parser, info *types.StructInfo, deps []string, done map[string]bool, implementers map[string][]string) types.InitStep {
+	step := types.InitStep{
+		Struct:         info.Name,
+		Package:        info.Package,
+		ImportPath:     info.ImportPath,
+		ReturnsPointer: true,
+	}
+	for _, d := range deps {
+		if p.GetStruct(d) != nil {
+			step.Deps = append(step.Deps, d)
+		} else if impls := implementers[d]; len(impls) == 1 && !containsString(step.Deps, impls[0]) {
+			step.Deps = append(step.Deps, impls[0])
+		}
+	}
+	sort.Strings(step.Deps)
+
+	var fn *types.FunctionInfo
+	for _, candidate := range p.GetFunctionsByReturnType(info.Name) {
+		if candidate.Package == info.Package && filepath.Dir(candidate.FilePath) == filepath.Dir(info.FilePath) {
+			fn = candidate
+			break
+		}
+	}
+	if fn == nil {
+		return step
+	}
+
+	step.Constructor = fn.Name
+	step.ReturnsPointer = strings.HasPrefix(fn.ReturnType, "*")
+	step.ReturnsError = fn.ReturnsError
+	for _, param := range fn.Params {
+		ip := types.InitParam{Name: param.Name, Variadic: strings.HasPrefix(param.Type, "...")}
+		ip.Type, ip.Imports = qualifyType(p, fn.Package, fn.FilePath, param.Type, "")
+
+		// 只有直接使用结构体或接口（可带指针）的参数才能由已构造的结构体提供
+		base := parser.ExtractBaseType(param.Type)
+		if !ip.Variadic && parser.ExtractQualifiedType(param.Type) == strings.TrimPrefix(param.Type, "*") {
+			if p.GetStruct(base) != nil && done[base] {
+				ip.Source = base
+			} else if p.GetInterface(base) != nil {
+				ip.Interface = true
+				if impls := implementers[base]; len(impls) == 1 && done[impls[0]] {
+					ip.Source = impls[0]
+				}
+			}
+		}
+		step.Params = append(step.Params, ip)
+	}
+	return step
+}
+
+// qualifyType 将 filePath（包 pkgName）中的类型写法改写为包外可用的写法，并返回引用的包（限定名 -> 导入路径）
+// 同包中未限定的项目类型加上包名；其他包的限定名按文件的导入解析。
+// localImport 为使用方的包：属于该包的类型去掉限定名，且不计入引用的包
+func qualifyType(p *parser.Parser, pkgName, filePath, typeStr, localImport string) (string, map[string]string) {
+	imports := make(map[string]string)
+	expr, err := goparser.ParseExpr(strings.TrimPrefix(typeStr, "..."))
+	if err != nil {
+		return typeStr, imports
+	}
+
+	fileImports := p.GetImports(filePath)
+	ownImport := p.ImportPathOf(filePath)
+	var rewrite func(n ast.Node) bool
+	rewrite = func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.Field:
+			ast.Inspect(e.Type, rewrite)
+			return false
+		case *ast.SelectorExpr:
+			if ident, ok := e.X.(*ast.Ident); ok {
+				if path, ok := fileImports[ident.Name]; ok {
+					imports[ident.Name] = path
+				}
+			}
+			return false
+		case *ast.Ident:
+			if gotypes.Universe.Lookup(e.Name) != nil || localImport != "" && ownImport == localImport {
+				return false
+			}
+			if info := p.GetStruct(e.Name); info != nil && info.ImportPath == ownImport {
+				imports[pkgName] = ownImport
+				e.Name = pkgName + "." + e.Name
+			} else if iface := p.GetInterface(e.Name); iface != nil && iface.ImportPath == ownImport {
+				imports[pkgName] = ownImport
+				e.Name = pkgName + "." + e.Name
+			}
+		}
+		return true
+	}
+	ast.Inspect(expr, rewrite)
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), expr); err != nil {
+		return typeStr, imports
+	}
+	result := buf.String()
+	for name, path := range imports {
+		if localImport != "" && path == localImport {
+			delete(imports, name)
+			result = regexp.MustCompile(`\b`+regexp.QuoteMeta(name)+`\.`).ReplaceAllString(result, "")
+		}
+	}
+	return result, imports
+}
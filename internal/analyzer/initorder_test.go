@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"testing"
+)
+
+// initOrderProject 创建包含构造函数、接口和依赖环的测试项目
+func initOrderProject() map[string]string {
+	return map[string]string{
+		"go.mod": "module example.com/app\n",
+		"config/config.go": `package config
+
+type Config struct {
+	DSN string
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
+`,
+		"store/store.go": `package store
+
+import (
+	"time"
+
+	"example.com/app/config"
+)
+
+type Store interface {
+	Load() error
+}
+
+type SQLStore struct {
+	cfg *config.Config
+}
+
+func NewSQLStore(cfg *config.Config, timeout time.Duration) (*SQLStore, error) {
+	return &SQLStore{cfg: cfg}, nil
+}
+
+func (s *SQLStore) Load() error { return nil }
+`,
+		"app/app.go": `package app
+
+import "example.com/app/store"
+
+type App struct {
+	store store.Store
+	stats *Stats
+}
+
+func NewApp(s store.Store, stats Stats) *App {
+	return &App{store: s, stats: &stats}
+}
+
+type Stats struct{}
+
+// Ping 和 Pong 相互依赖，无法构造
+type Ping struct {
+	pong *Pong
+}
+
+type Pong struct {
+	ping *Ping
+}
+
+type Game struct {
+	ping *Ping
+	app  *App
+}
+`,
+	}
+}
+
+func TestComputeInitOrder(t *testing.T) {
+	p, _ := writeTestProject(t, initOrderProject())
+	filter := NewScopeFilter(p, NewBlacklist())
+
+	order, err := ComputeInitOrder(p, filter, "App")
+	if err != nil {
+		t.Fatalf("ComputeInitOrder failed: %v", err)
+	}
+
+	position := make(map[string]int)
+	for i, step := range order.Steps {
+		position[step.Struct] = i
+	}
+	if len(order.Steps) != 4 {
+		t.Fatalf("expected 4 steps, got %+v", order.Steps)
+	}
+	if position["Config"] > position["SQLStore"] || position["SQLStore"] > position["App"] || position["Stats"] > position["App"] {
+		t.Errorf("dependencies should be constructed first, got %+v", order.Steps)
+	}
+	if order.Steps[len(order.Steps)-1].Struct != "App" {
+		t.Errorf("root should be constructed last, got %+v", order.Steps)
+	}
+	if len(order.Cycles) != 0 || len(order.Blocked) != 0 {
+		t.Errorf("unexpected cycles: %+v, blocked: %v", order.Cycles, order.Blocked)
+	}
+
+	if len(order.Interfaces) != 1 || order.Interfaces[0].Implementer != "SQLStore" {
+		t.Errorf("Store should bind to SQLStore, got %+v", order.Interfaces)
+	}
+
+	sqlStore := order.Steps[position["SQLStore"]]
+	if sqlStore.Constructor != "NewSQLStore" || !sqlStore.ReturnsError || len(sqlStore.Params) != 2 {
+		t.Fatalf("unexpected SQLStore step: %+v", sqlStore)
+	}
+	if cfg := sqlStore.Params[0]; cfg.Source != "Config" || cfg.Type != "*config.Config" {
+		t.Errorf("cfg should come from Config, got %+v", cfg)
+	}
+	if timeout := sqlStore.Params[1]; timeout.Source != "" || timeout.Type != "time.Duration" || timeout.Imports["time"] != "time" {
+		t.Errorf("timeout should be an unresolved placeholder, got %+v", timeout)
+	}
+
+	app := order.Steps[position["App"]]
+	if s := app.Params[0]; s.Source != "SQLStore" || !s.Interface || s.Type != "store.Store" {
+		t.Errorf("interface param should come from its implementer, got %+v", s)
+	}
+	if stats := app.Params[1]; stats.Source != "Stats" || stats.Type != "app.Stats" || stats.Imports["app"] != "example.com/app/app" {
+		t.Errorf("same-package type should be qualified, got %+v", stats)
+	}
+
+	// 依赖环使根无法构造
+	order, err = ComputeInitOrder(p, filter, "Game")
+	if err != nil {
+		t.Fatalf("ComputeInitOrder failed: %v", err)
+	}
+	if len(order.Cycles) != 1 || len(order.Cycles[0].Nodes) != 2 {
+		t.Errorf("expected the Ping/Pong cycle, got %+v", order.Cycles)
+	}
+	blocked := make(map[string]bool)
+	for _, name := range order.Blocked {
+		blocked[name] = true
+	}
+	if !blocked["Game"] || !blocked["Ping"] || !blocked["Pong"] || blocked["App"] {
+		t.Errorf("unexpected blocked structs: %v", order.Blocked)
+	}
+
+	if _, err := ComputeInitOrder(p, filter, "Missing"); err == nil {
+		t.Error("expected error for unknown root")
+	}
+}
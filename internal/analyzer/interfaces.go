@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// InterfaceSuggestions 为结构体中类型为项目具体结构体的字段建议最小接口（需先调用 AnalyzeStruct）
+// 接口只包含方法体中经由 recv.field.Method() 实际调用的方法；嵌入字段的方法会被提升，不做建议
+func (a *DependencyAnalyzer) InterfaceSuggestions(structInfo *types.StructInfo) []types.InterfaceSuggestion {
+	rec := a.access[structInfo.Name]
+	if rec == nil {
+		return nil
+	}
+	localImport := a.parser.ImportPathOf(structInfo.FilePath)
+	usedNames := make(map[string]bool)
+
+	var suggestions []types.InterfaceSuggestion
+	for _, field := range structInfo.Fields {
+		calls := rec.fieldCalls[field.Name]
+		if field.IsEmbedded || field.Annotations.Ignore || len(calls) == 0 {
+			continue
+		}
+
+		// 只处理直接使用结构体（可带指针）的字段
+		if parser.ExtractQualifiedType(field.Type) != strings.TrimPrefix(field.Type, "*") {
+			continue
+		}
+		target := a.parser.GetStruct(parser.ExtractBaseType(field.Type))
+		if target == nil || !a.filter.ShouldAnalyze(target.Name) {
+			continue
+		}
+
+		targetMethods := make(map[string]types.MethodInfo)
+		targetSigs := make(map[string]string) // 方法名 -> 规范化签名
+		for _, m := range target.Methods {
+			targetMethods[m.Name] = m
+			targetSigs[m.Name] = a.normalizedMethodSignature(target.Package, target.FilePath, m.Signature, localImport)
+		}
+
+		s := types.InterfaceSuggestion{
+			Field:     field.Name,
+			FieldType: target.Package + "." + target.Name,
+		}
+		imports := make(map[string]bool)
+		callers := make(map[string]bool)
+		called := make(map[string]string) // 方法名 -> 规范化签名
+		for name, by := range calls {
+			m, ok := targetMethods[name]
+			if !ok {
+				continue // 经由嵌入提升的方法或函数类型字段，无法确定签名
+			}
+			sig, refs := qualifyType(a.parser, target.Package, target.FilePath, "func"+m.Signature, localImport)
+			for _, path := range refs {
+				imports[path] = true
+			}
+			s.Methods = append(s.Methods, types.InterfaceMethod{Name: name, Signature: strings.TrimPrefix(sig, "func")})
+			called[name] = targetSigs[name]
+			for caller := range by {
+				callers[caller] = true
+			}
+		}
+		if len(s.Methods) == 0 {
+			continue
+		}
+		sort.Slice(s.Methods, func(i, j int) bool { return s.Methods[i].Name < s.Methods[j].Name })
+		s.Callers = sortedKeys(callers)
+		s.Imports = sortedKeys(imports)
+		s.Name = a.suggestInterfaceName(structInfo, field.Name, usedNames)
+
+		// 已有接口：方法集合（名称和签名）相同，或包含全部调用方法且被字段类型实现
+		for _, iface := range a.parser.GetAllInterfaces() {
+			if len(iface.Methods) == 0 || !a.filter.ShouldAnalyze(iface.Name) {
+				continue
+			}
+			covers, implemented := 0, true
+			for _, m := range iface.Methods {
+				sig := a.normalizedMethodSignature(iface.Package, iface.FilePath, m.Signature, localImport)
+				if want, ok := called[m.Name]; ok && want == sig {
+					covers++
+				}
+				if targetSigs[m.Name] != sig {
+					implemented = false
+				}
+			}
+			switch {
+			case covers < len(called):
+			case len(iface.Methods) == len(called):
+				s.Matching = append(s.Matching, iface.Package+"."+iface.Name)
+			case implemented:
+				s.Covering = append(s.Covering, iface.Package+"."+iface.Name)
+			}
+		}
+		sort.Strings(s.Matching)
+		sort.Strings(s.Covering)
+
+		suggestions = append(suggestions, s)
+	}
+	return suggestions
+}
+
+// normalizedMethodSignature 将 pkgName 包中 filePath 文件里的方法签名按建议接口所在的包 localImport 限定类型名，
+// 并去掉参数名，用于比较不同包中声明的方法签名
+func (a *DependencyAnalyzer) normalizedMethodSignature(pkgName, filePath, sig, localImport string) string {
+	qualified, _ := qualifyType(a.parser, pkgName, filePath, "func"+sig, localImport)
+	return normalizeSignature(strings.TrimPrefix(qualified, "func"))
+}
+
+// suggestInterfaceName 以首字母大写的字段名作为接口名，与同包中已有类型或已建议的名称冲突时加 Interface 后缀，
+// 仍然冲突时再加数字后缀
+func (a *DependencyAnalyzer) suggestInterfaceName(structInfo *types.StructInfo, fieldName string, used map[string]bool) string {
+	runes := []rune(fieldName)
+	runes[0] = unicode.ToUpper(runes[0])
+	name := string(runes)
+
+	dir := filepath.Dir(structInfo.FilePath)
+	taken := func(n string) bool {
+		if used[n] {
+			return true
+		}
+		if info := a.parser.GetStruct(n); info != nil && filepath.Dir(info.FilePath) == dir {
+			return true
+		}
+		if iface := a.parser.GetInterface(n); iface != nil && filepath.Dir(iface.FilePath) == dir {
+			return true
+		}
+		return false
+	}
+	if taken(name) {
+		base := name + "Interface"
+		name = base
+		for i := 2; taken(name); i++ {
+			name = base + strconv.Itoa(i)
+		}
+	}
+	used[name] = true
+	return name
+}
+
+// sortedKeys 返回集合中已排序的元素
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
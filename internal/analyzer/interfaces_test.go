@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDependencyAnalyzer_InterfaceSuggestions(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"model/user.go": `package model
+
+type User struct{ ID int64 }
+`,
+		"repository/repo.go": `package repository
+
+import "example.com/app/model"
+
+type Finder interface {
+	FindByID(id int64) (*model.User, error)
+}
+
+// LegacyFinder 方法名相同但参数类型不同，不能匹配
+type LegacyFinder interface {
+	FindByID(id string) (*model.User, error)
+}
+
+type Store interface {
+	FindByID(id int64) (*model.User, error)
+	Save(u *model.User) error
+}
+
+type UserRepo struct{}
+
+func (r *UserRepo) FindByID(id int64) (*model.User, error) { return nil, nil }
+func (r *UserRepo) Save(u *model.User) error             { return nil }
+func (r *UserRepo) Filter(f Filter) []model.User         { return nil }
+
+type Filter struct{ Name string }
+`,
+		"service/service.go": `package service
+
+import (
+	"example.com/app/model"
+	"example.com/app/repository"
+)
+
+// UserFinder 参数名不同，签名相同
+type UserFinder interface {
+	FindByID(userID int64) (*model.User, error)
+}
+
+type Cache struct{}
+
+type CacheInterface struct{}
+
+func (c *Cache) Get(key string) string { return "" }
+
+type UserService struct {
+	repo   *repository.UserRepo
+	search repository.UserRepo
+	cache  Cache
+	store  repository.Store
+	idle   *repository.UserRepo
+}
+
+func (s *UserService) Get(id int64) {
+	s.repo.FindByID(id)
+	s.cache.Get("k")
+	s.store.Save(nil)
+}
+
+func (s *UserService) Search() {
+	s.search.Filter(repository.Filter{})
+}
+`,
+	})
+
+	a := NewDependencyAnalyzer(p, NewScopeFilter(p, NewBlacklist()), false)
+	info := p.GetStruct("UserService")
+	a.AnalyzeStruct(info)
+	suggestions := a.InterfaceSuggestions(info)
+
+	// store 已是接口，idle 没有调用，均不产生建议
+	if len(suggestions) != 3 {
+		t.Fatalf("expected 3 suggestions, got %+v", suggestions)
+	}
+
+	repo := suggestions[0]
+	if repo.Field != "repo" || repo.FieldType != "repository.UserRepo" || repo.Name != "Repo" {
+		t.Errorf("unexpected repo suggestion: %+v", repo)
+	}
+	if len(repo.Methods) != 1 || repo.Methods[0].Name != "FindByID" || repo.Methods[0].Signature != "(id int64) (*model.User, error)" {
+		t.Errorf("unexpected methods: %+v", repo.Methods)
+	}
+	if !reflect.DeepEqual(repo.Callers, []string{"Get"}) || !reflect.DeepEqual(repo.Imports, []string{"example.com/app/model"}) {
+		t.Errorf("unexpected callers or imports: %+v", repo)
+	}
+	if !reflect.DeepEqual(repo.Matching, []string{"repository.Finder", "service.UserFinder"}) || !reflect.DeepEqual(repo.Covering, []string{"repository.Store"}) {
+		t.Errorf("unexpected existing interfaces: matching %v, covering %v", repo.Matching, repo.Covering)
+	}
+
+	// 同包的参数类型需要加上字段类型所在包的限定名
+	search := suggestions[1]
+	if search.Field != "search" || search.Methods[0].Signature != "(f repository.Filter) []model.User" {
+		t.Errorf("unexpected search suggestion: %+v", search)
+	}
+	if !reflect.DeepEqual(search.Imports, []string{"example.com/app/model", "example.com/app/repository"}) {
+		t.Errorf("unexpected imports: %v", search.Imports)
+	}
+
+	// 与同包已有类型重名时加后缀，加后缀后仍然重名时再加数字
+	if cache := suggestions[2]; cache.Name != "CacheInterface2" || len(cache.Imports) != 0 {
+		t.Errorf("unexpected cache suggestion: %+v", cache)
+	}
+}
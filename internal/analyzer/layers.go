@@ -0,0 +1,341 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// LayerChecker 按架构分层规则检查依赖边
+type LayerChecker struct {
+	parser *parser.Parser
+	layers []compiledLayer
+	order  map[string]int // 层名 -> 从上到下的序号
+	strict bool
+	allow  []types.LayerRule
+	forbid []types.LayerRule
+	cache  map[string]string // 类型名 -> 所属层
+	roles  *RoleClassifier   // 判定结构体角色（有层按角色归属时使用）
+}
+
+// compiledLayer 表示一个已编译的架构层
+type compiledLayer struct {
+	name     string
+	packages []*pattern
+	structs  []*pattern
+	roles    []string
+}
+
+// NewLayerChecker 编译分层规则；p 为 nil 时只校验配置
+// 有层按角色归属时使用内置角色规则，可用 SetRoleClassifier 替换
+func NewLayerChecker(p *parser.Parser, config types.ArchitectureConfig) (*LayerChecker, error) {
+	c := &LayerChecker{
+		parser: p,
+		order:  make(map[string]int),
+		strict: config.Strict,
+		allow:  config.Allow,
+		forbid: config.Forbid,
+		cache:  make(map[string]string),
+	}
+
+	defined := make(map[string]bool)
+	for _, l := range config.Layers {
+		if l.Name == "" {
+			return nil, errors.New("layer name is required")
+		}
+		if defined[l.Name] {
+			return nil, fmt.Errorf("duplicate layer %q", l.Name)
+		}
+		defined[l.Name] = true
+
+		layer := compiledLayer{name: l.Name, roles: l.Roles}
+		for _, role := range l.Roles {
+			if role == "" {
+				return nil, fmt.Errorf("layer %q: empty role", l.Name)
+			}
+		}
+		for _, raw := range l.Packages {
+			pat, err := compilePattern(raw)
+			if err != nil {
+				return nil, fmt.Errorf("layer %q: %w", l.Name, err)
+			}
+			layer.packages = append(layer.packages, pat)
+		}
+		for _, raw := range l.Structs {
+			pat, err := compilePattern(raw)
+			if err != nil {
+				return nil, fmt.Errorf("layer %q: %w", l.Name, err)
+			}
+			layer.structs = append(layer.structs, pat)
+		}
+		c.layers = append(c.layers, layer)
+	}
+
+	// 分层顺序：显式 order 或声明顺序
+	var order []string
+	if strings.TrimSpace(config.Order) != "" {
+		for _, name := range strings.Split(config.Order, "->") {
+			order = append(order, strings.TrimSpace(name))
+		}
+	} else {
+		for _, l := range config.Layers {
+			order = append(order, l.Name)
+		}
+	}
+	for i, name := range order {
+		if !defined[name] {
+			return nil, fmt.Errorf("order: undefined layer %q", name)
+		}
+		if _, dup := c.order[name]; dup {
+			return nil, fmt.Errorf("order: layer %q appears twice", name)
+		}
+		c.order[name] = i
+	}
+
+	if p != nil {
+		for _, l := range c.layers {
+			if len(l.roles) > 0 {
+				c.roles, _ = NewRoleClassifier(p, types.RoleConfig{})
+				break
+			}
+		}
+	}
+
+	for _, rule := range append(append([]types.LayerRule{}, config.Allow...), config.Forbid...) {
+		for _, name := range []string{rule.From, rule.To} {
+			if name != "*" && !defined[name] {
+				return nil, fmt.Errorf("rule %s -> %s: undefined layer %q", rule.From, rule.To, name)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// SetRoleClassifier 设置按角色归属层时使用的角色分类器
+func (c *LayerChecker) SetRoleClassifier(roles *RoleClassifier) {
+	c.roles = roles
+	c.cache = make(map[string]string)
+}
+
+// LayerOf 返回类型所属的层，不属于任何层时返回空字符串
+func (c *LayerChecker) LayerOf(typeName string) string {
+	if layer, ok := c.cache[typeName]; ok {
+		return layer
+	}
+	layer := c.resolveLayer(typeName)
+	c.cache[typeName] = layer
+	return layer
+}
+
+// resolveLayer 依次按源码注释、类型名模式、结构体角色、导入路径模式判定类型所属层
+func (c *LayerChecker) resolveLayer(typeName string) string {
+	var pkgName, importPath string
+	if info := c.parser.GetStruct(typeName); info != nil {
+		pkgName, importPath = info.Package, info.ImportPath
+	} else if iface := c.parser.GetInterface(typeName); iface != nil {
+		pkgName, importPath = iface.Package, iface.ImportPath
+	} else {
+		return ""
+	}
+
+	if layer := c.parser.GetAnnotations(typeName).Layer; layer != "" {
+		return layer
+	}
+
+	qualified := pkgName + "." + typeName
+	for _, l := range c.layers {
+		for _, pat := range l.structs {
+			if pat.matchType(qualified) {
+				return l.name
+			}
+		}
+	}
+	if c.roles != nil {
+		if role := c.roles.RoleOf(typeName); role != "" {
+			for _, l := range c.layers {
+				if containsString(l.roles, role) {
+					return l.name
+				}
+			}
+		}
+	}
+	for _, l := range c.layers {
+		for _, pat := range l.packages {
+			if importPath != "" && pat.match(importPath) {
+				return l.name
+			}
+		}
+	}
+	return ""
+}
+
+// CheckEdge 检查一条依赖边，不违规时返回 nil
+// 未归属任何层的类型和第三方类型不受约束；同层依赖只受禁止规则约束
+func (c *LayerChecker) CheckEdge(dep types.Dependency) *types.LayerViolation {
+	if dep.External {
+		return nil
+	}
+	fromLayer, toLayer := c.LayerOf(dep.From), c.LayerOf(dep.To)
+	if fromLayer == "" || toLayer == "" {
+		return nil
+	}
+
+	violation := func(rule, reason string) *types.LayerViolation {
+		v := &types.LayerViolation{
+			From:      dep.From,
+			To:        dep.To,
+			FromLayer: fromLayer,
+			ToLayer:   toLayer,
+			DepType:   dep.Type,
+			Context:   dep.Context,
+			Rule:      rule,
+			Reason:    reason,
+		}
+		if info := c.parser.GetStruct(dep.From); info != nil {
+			v.FilePath = projectRelPath(c.parser.GetProjectPath(), info.FilePath)
+		}
+		return v
+	}
+
+	for _, rule := range c.forbid {
+		if ruleMatches(rule, fromLayer, toLayer, dep.Type) {
+			reason := rule.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("禁止 %s 依赖 %s", rule.From, rule.To)
+			}
+			return violation(types.LayerRuleForbid, reason)
+		}
+	}
+	if fromLayer == toLayer {
+		return nil
+	}
+	for _, rule := range c.allow {
+		if ruleMatches(rule, fromLayer, toLayer, dep.Type) {
+			return nil
+		}
+	}
+
+	fromIdx, fromOK := c.order[fromLayer]
+	toIdx, toOK := c.order[toLayer]
+	if !fromOK || !toOK {
+		return nil
+	}
+	if toIdx < fromIdx {
+		return violation(types.LayerRuleUpward, fmt.Sprintf("下层 %s 不能依赖上层 %s", fromLayer, toLayer))
+	}
+	if c.strict && toIdx > fromIdx+1 {
+		return violation(types.LayerRuleSkipLayer, fmt.Sprintf("严格分层下 %s 只能依赖紧邻的下一层", fromLayer))
+	}
+	return nil
+}
+
+// ruleMatches 判断规则是否匹配层之间的某种依赖
+func ruleMatches(rule types.LayerRule, fromLayer, toLayer, depType string) bool {
+	if rule.From != "*" && rule.From != fromLayer {
+		return false
+	}
+	if rule.To != "*" && rule.To != toLayer {
+		return false
+	}
+	return len(rule.DepTypes) == 0 || containsString(rule.DepTypes, depType)
+}
+
+// CheckArchitecture 分析项目中全部结构体的依赖边，返回违反分层规则的依赖（按源、目标、依赖类型排序）
+func CheckArchitecture(p *parser.Parser, filter *ScopeFilter, checker *LayerChecker) []types.LayerViolation {
+	var violations []types.LayerViolation
+	for _, s := range AnalyzeProject(p, filter) {
+		for _, dep := range s.Dependencies {
+			if v := checker.CheckEdge(dep); v != nil {
+				violations = append(violations, *v)
+			}
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return ViolationKey(violations[i]) < ViolationKey(violations[j])
+	})
+	return violations
+}
+
+// ViolationKey 返回违规的稳定标识，用于与基线比对（文件路径相对项目根目录，基线可以在不同位置的检出中复用）
+func ViolationKey(v types.LayerViolation) string {
+	key := v.From + " -> " + v.To + " [" + v.DepType + "] " + v.Context
+	if v.FilePath != "" {
+		key += " @ " + filepath.ToSlash(v.FilePath)
+	}
+	return key
+}
+
+// projectRelPath 返回 path 相对项目根目录 root 的路径，无法计算时原样返回
+func projectRelPath(root, path string) string {
+	if root == "" || path == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// Baseline 表示基线文件：记录已知的分层违规
+type Baseline struct {
+	Violations []types.LayerViolation
+}
+
+// LoadBaseline 读取基线文件，文件不存在时返回空基线
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Baseline{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("invalid baseline %s: %w", path, err)
+	}
+	return &baseline, nil
+}
+
+// SaveBaseline 将当前的违规写入基线文件
+func SaveBaseline(path string, violations []types.LayerViolation) error {
+	data, err := json.MarshalIndent(Baseline{Violations: violations}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Apply 用基线过滤违规：返回新增的违规、被基线忽略的违规，以及基线中已不再出现的条目
+func (b *Baseline) Apply(violations []types.LayerViolation) (remaining, suppressed, stale []types.LayerViolation) {
+	known := make(map[string]bool, len(b.Violations))
+	for _, v := range b.Violations {
+		known[ViolationKey(v)] = true
+	}
+
+	current := make(map[string]bool, len(violations))
+	for _, v := range violations {
+		key := ViolationKey(v)
+		current[key] = true
+		if known[key] {
+			suppressed = append(suppressed, v)
+		} else {
+			remaining = append(remaining, v)
+		}
+	}
+
+	for _, v := range b.Violations {
+		if !current[ViolationKey(v)] {
+			stale = append(stale, v)
+		}
+	}
+	return remaining, suppressed, stale
+}
@@ -0,0 +1,193 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// layeredProject 创建 handler -> service -> repository -> model 分层的测试项目
+func layeredProject(t *testing.T) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"go.mod": "module example.com/app\n",
+		"internal/handler/handler.go": `package handler
+
+import (
+	"example.com/app/internal/repository"
+	"example.com/app/internal/service"
+)
+
+type UserHandler struct {
+	svc  *service.UserService
+	repo *repository.UserRepo
+}
+`,
+		"internal/service/service.go": `package service
+
+import "example.com/app/internal/repository"
+
+type UserService struct {
+	repo *repository.UserRepo
+}
+`,
+		"internal/repository/repo.go": `package repository
+
+import (
+	"example.com/app/internal/model"
+	"example.com/app/internal/service"
+)
+
+type UserRepo struct {
+	user  *model.User
+	audit *service.AuditService
+}
+`,
+		"internal/service/audit.go": `package service
+
+type AuditService struct{}
+`,
+		"internal/model/user.go": `package model
+
+//structanalyzer:layer=service
+type Clock struct{}
+
+type User struct {
+	clock *Clock
+}
+`,
+	}
+}
+
+func layerConfig() types.ArchitectureConfig {
+	return types.ArchitectureConfig{
+		Layers: []types.LayerConfig{
+			{Name: "handler", Structs: []string{"*Handler"}},
+			{Name: "service", Packages: []string{".../service"}},
+			{Name: "repository", Packages: []string{"example.com/app/internal/repository"}},
+			{Name: "model", Packages: []string{".../model"}},
+		},
+		Order: "handler -> service -> repository -> model",
+	}
+}
+
+func TestLayerChecker_LayerOf(t *testing.T) {
+	p, _ := writeTestProject(t, layeredProject(t))
+	checker, err := NewLayerChecker(p, layerConfig())
+	if err != nil {
+		t.Fatalf("NewLayerChecker failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"UserHandler":  "handler",
+		"UserService":  "service",
+		"UserRepo":     "repository",
+		"User":         "model",
+		"Clock":        "service", // 源码注释优先于包路径
+		"NotExisting":  "",
+		"AuditService": "service",
+	}
+	for name, want := range tests {
+		if got := checker.LayerOf(name); got != want {
+			t.Errorf("LayerOf(%s) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCheckArchitecture(t *testing.T) {
+	p, _ := writeTestProject(t, layeredProject(t))
+	filter := NewScopeFilter(p, NewBlacklist())
+
+	checker, _ := NewLayerChecker(p, layerConfig())
+	violations := CheckArchitecture(p, filter, checker)
+
+	// UserRepo -> AuditService 依赖上层；User -> Clock（注释标为 service 层）同样依赖上层
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", violations)
+	}
+	v := violations[1]
+	if v.From != "UserRepo" || v.To != "AuditService" || v.Rule != types.LayerRuleUpward || v.Context != "audit 字段" {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+	if filepath.Base(v.FilePath) != "repo.go" || filepath.IsAbs(v.FilePath) {
+		t.Errorf("violation should point to repo.go relative to the project root, got %s", v.FilePath)
+	}
+
+	// 严格分层 + allow + forbid
+	cfg := layerConfig()
+	cfg.Strict = true
+	cfg.Allow = []types.LayerRule{{From: "repository", To: "service"}, {From: "model", To: "*"}}
+	cfg.Forbid = []types.LayerRule{{From: "service", To: "repository", DepTypes: []string{types.DepTypeField}, Reason: "service 只能通过接口访问仓储"}}
+	checker, _ = NewLayerChecker(p, cfg)
+	violations = CheckArchitecture(p, filter, checker)
+
+	rules := make(map[string]string)
+	for _, v := range violations {
+		rules[v.From+"->"+v.To] = v.Rule
+	}
+	want := map[string]string{
+		"UserHandler->UserRepo": types.LayerRuleSkipLayer,
+		"UserService->UserRepo": types.LayerRuleForbid,
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("expected %v, got %+v", want, violations)
+	}
+	for edge, rule := range want {
+		if rules[edge] != rule {
+			t.Errorf("%s rule = %q, want %q", edge, rules[edge], rule)
+		}
+	}
+}
+
+func TestNewLayerChecker_Invalid(t *testing.T) {
+	tests := []types.ArchitectureConfig{
+		{Layers: []types.LayerConfig{{Name: "a"}, {Name: "a"}}},
+		{Layers: []types.LayerConfig{{Name: "a"}}, Order: "a -> b"},
+		{Layers: []types.LayerConfig{{Name: "a", Packages: []string{"re:("}}}},
+		{Layers: []types.LayerConfig{{Name: "a"}}, Forbid: []types.LayerRule{{From: "a", To: "b"}}},
+	}
+	for i, cfg := range tests {
+		if _, err := NewLayerChecker(nil, cfg); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	baseline, err := LoadBaseline(path)
+	if err != nil || len(baseline.Violations) != 0 {
+		t.Fatalf("missing baseline should be empty, got %v, %v", baseline, err)
+	}
+
+	known := types.LayerViolation{From: "UserRepo", To: "AuditService", DepType: types.DepTypeField, Context: "audit 字段"}
+	fixed := types.LayerViolation{From: "User", To: "Clock", DepType: types.DepTypeField, Context: "clock 字段"}
+	if err := SaveBaseline(path, []types.LayerViolation{known, fixed}); err != nil {
+		t.Fatalf("SaveBaseline failed: %v", err)
+	}
+	baseline, err = LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline failed: %v", err)
+	}
+
+	added := types.LayerViolation{From: "UserRepo", To: "UserService", DepType: types.DepTypeMethodCall, Context: "Save -> Notify"}
+	remaining, suppressed, stale := baseline.Apply([]types.LayerViolation{known, added})
+	if len(remaining) != 1 || remaining[0].To != "UserService" {
+		t.Errorf("remaining = %+v", remaining)
+	}
+	if len(suppressed) != 1 || len(stale) != 1 || stale[0].To != "Clock" {
+		t.Errorf("suppressed = %+v, stale = %+v", suppressed, stale)
+	}
+
+	// 按相对路径比对：同一违规出现在另一个文件中不被基线忽略
+	known.FilePath = filepath.Join("repo", "repo.go")
+	moved := known
+	moved.FilePath = filepath.Join("legacy", "repo.go")
+	baseline = &Baseline{Violations: []types.LayerViolation{known}}
+	remaining, suppressed, _ = baseline.Apply([]types.LayerViolation{known, moved})
+	if len(suppressed) != 1 || len(remaining) != 1 || remaining[0].FilePath != moved.FilePath {
+		t.Errorf("baseline should match on relative path: remaining = %+v, suppressed = %+v", remaining, suppressed)
+	}
+}
@@ -0,0 +1,283 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/token"
+	gotypes "go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// DefaultArch 未指定时计算内存布局使用的 GOARCH
+const DefaultArch = "amd64"
+
+// typeDecl 表示项目中的一个类型声明
+type typeDecl struct {
+	expr     ast.Expr // 类型表达式
+	filePath string   // 所在文件
+}
+
+// LayoutCalculator 根据 go/types 的 gc 尺寸规则（StdSizes 加上结构体结尾填充）计算结构体的大小、对齐、字段偏移和填充
+// 项目中的类型从 AST 构造，标准库类型通过源码导入器加载；存在无法确定大小的字段时不计算布局
+type LayoutCalculator struct {
+	parser   *parser.Parser
+	arch     string
+	sizes    gotypes.Sizes
+	decls    map[string]map[string]typeDecl // 包目录 -> 类型名 -> 声明
+	dirs     map[string]string              // 导入路径 -> 包目录
+	importer gotypes.Importer
+	imported map[string]*gotypes.Package // 已加载的外部包（加载失败为 nil）
+	cache    map[string]gotypes.Type     // 包目录.类型名 -> 类型
+	building map[string]bool             // 正在构造的项目类型（防止递归）
+}
+
+// NewLayoutCalculator 创建指定 GOARCH 的布局计算器
+func NewLayoutCalculator(p *parser.Parser, arch string) (*LayoutCalculator, error) {
+	if arch == "" {
+		arch = DefaultArch
+	}
+	sizes := gotypes.SizesFor("gc", arch)
+	if sizes == nil {
+		return nil, fmt.Errorf("unsupported GOARCH %q", arch)
+	}
+
+	c := &LayoutCalculator{
+		parser:   p,
+		arch:     arch,
+		sizes:    sizes,
+		decls:    make(map[string]map[string]typeDecl),
+		dirs:     make(map[string]string),
+		importer: importer.ForCompiler(token.NewFileSet(), "source", nil),
+		imported: make(map[string]*gotypes.Package),
+		cache:    make(map[string]gotypes.Type),
+		building: make(map[string]bool),
+	}
+	for filePath, file := range p.GetAllFiles() {
+		dir := filepath.Dir(filePath)
+		if importPath := p.ImportPathOf(filePath); importPath != "" {
+			c.dirs[importPath] = dir
+		}
+		if c.decls[dir] == nil {
+			c.decls[dir] = make(map[string]typeDecl)
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.TypeParams == nil {
+					c.decls[dir][ts.Name.Name] = typeDecl{expr: ts.Type, filePath: filePath}
+				}
+			}
+		}
+	}
+	return c, nil
+}
+
+// ValidateArch 检查 GOARCH 是否受支持
+func ValidateArch(arch string) error {
+	if arch != "" && gotypes.SizesFor("gc", arch) == nil {
+		return fmt.Errorf("unsupported GOARCH %q", arch)
+	}
+	return nil
+}
+
+// Compute 计算结构体的内存布局，并给出使大小最小的字段顺序
+func (c *LayoutCalculator) Compute(structInfo *types.StructInfo) types.StructLayout {
+	layout := types.StructLayout{Arch: c.arch}
+	decl, ok := c.decls[filepath.Dir(structInfo.FilePath)][structInfo.Name]
+	if !ok {
+		layout.Unknown = append(layout.Unknown, structInfo.Name)
+		return layout
+	}
+	st, ok := decl.expr.(*ast.StructType)
+	if !ok {
+		layout.Unknown = append(layout.Unknown, structInfo.Name)
+		return layout
+	}
+
+	var vars []*gotypes.Var
+	var typeStrs []string
+	for _, field := range st.Fields.List {
+		t, err := c.typeOf(field.Type, decl.filePath)
+		if err != nil {
+			layout.Unknown = append(layout.Unknown, err.Error())
+			continue
+		}
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(baseTypeName(field.Type))}
+		}
+		for _, name := range names {
+			vars = append(vars, gotypes.NewField(token.NoPos, nil, name.Name, t, len(field.Names) == 0))
+			typeStrs = append(typeStrs, gotypes.ExprString(field.Type))
+		}
+	}
+	if len(layout.Unknown) > 0 {
+		return layout
+	}
+
+	offsets := c.sizes.Offsetsof(vars)
+	layout.Size = c.sizes.Sizeof(gotypes.NewStruct(vars, nil))
+	layout.Align = c.sizes.Alignof(gotypes.NewStruct(vars, nil))
+	for i, v := range vars {
+		f := types.FieldLayout{
+			Name:   v.Name(),
+			Type:   typeStrs[i],
+			Offset: offsets[i],
+			Size:   c.sizes.Sizeof(v.Type()),
+			Align:  c.sizes.Alignof(v.Type()),
+		}
+		end := layout.Size
+		if i+1 < len(vars) {
+			end = offsets[i+1]
+		}
+		f.Padding = end - f.Offset - f.Size
+		layout.Padding += f.Padding
+		layout.Fields = append(layout.Fields, f)
+	}
+
+	// 按对齐、大小降序重排即可得到最小大小（各类型大小都是其对齐的整数倍）；
+	// 零大小字段放在最前面，gc 会在结尾的零大小字段之后补齐，避免其地址越过结构体末尾。
+	// 建议顺序的大小仍用同一尺寸规则重新计算，只有确实变小时才给出建议
+	sorted := make([]int, len(vars))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := layout.Fields[sorted[i]], layout.Fields[sorted[j]]
+		if (a.Size == 0) != (b.Size == 0) {
+			return a.Size == 0
+		}
+		if a.Align != b.Align {
+			return a.Align > b.Align
+		}
+		return a.Size > b.Size
+	})
+	reordered := make([]*gotypes.Var, len(vars))
+	for i, idx := range sorted {
+		reordered[i] = vars[idx]
+	}
+	layout.OptimalSize = c.sizes.Sizeof(gotypes.NewStruct(reordered, nil))
+	if layout.OptimalSize < layout.Size {
+		for _, idx := range sorted {
+			layout.SuggestedOrder = append(layout.SuggestedOrder, layout.Fields[idx].Name)
+		}
+	}
+	return layout
+}
+
+// typeOf 将 filePath 中的类型表达式转换为 go/types 类型（只保证大小和对齐正确）
+func (c *LayoutCalculator) typeOf(expr ast.Expr, filePath string) (gotypes.Type, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if decl, ok := c.decls[filepath.Dir(filePath)][t.Name]; ok {
+			return c.projectType(filepath.Dir(filePath), t.Name, decl)
+		}
+		if obj := gotypes.Universe.Lookup(t.Name); obj != nil {
+			if _, ok := obj.(*gotypes.TypeName); ok {
+				return obj.Type(), nil
+			}
+		}
+	case *ast.SelectorExpr:
+		pkgIdent, ok := t.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		importPath, ok := c.parser.GetImports(filePath)[pkgIdent.Name]
+		if !ok {
+			break
+		}
+		if dir, ok := c.dirs[importPath]; ok {
+			if decl, ok := c.decls[dir][t.Sel.Name]; ok {
+				return c.projectType(dir, t.Sel.Name, decl)
+			}
+			break
+		}
+		if pkg := c.importPackage(importPath); pkg != nil {
+			if obj, ok := pkg.Scope().Lookup(t.Sel.Name).(*gotypes.TypeName); ok {
+				return obj.Type(), nil
+			}
+		}
+	case *ast.ParenExpr:
+		return c.typeOf(t.X, filePath)
+	case *ast.StarExpr, *ast.FuncType, *ast.MapType, *ast.ChanType:
+		return gotypes.Typ[gotypes.UnsafePointer], nil
+	case *ast.InterfaceType:
+		return gotypes.NewInterfaceType(nil, nil).Complete(), nil
+	case *ast.ArrayType:
+		elem, err := c.typeOf(t.Elt, filePath)
+		if err != nil {
+			return nil, err
+		}
+		if t.Len == nil {
+			return gotypes.NewSlice(elem), nil
+		}
+		if lit, ok := t.Len.(*ast.BasicLit); ok && lit.Kind == token.INT {
+			if n, err := strconv.ParseInt(lit.Value, 0, 64); err == nil {
+				return gotypes.NewArray(elem, n), nil
+			}
+		}
+	case *ast.StructType:
+		var fields []*gotypes.Var
+		for _, field := range t.Fields.List {
+			ft, err := c.typeOf(field.Type, filePath)
+			if err != nil {
+				return nil, err
+			}
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				fields = append(fields, gotypes.NewField(token.NoPos, nil, "_", ft, false))
+			}
+		}
+		return gotypes.NewStruct(fields, nil), nil
+	}
+	return nil, fmt.Errorf("%s", gotypes.ExprString(expr))
+}
+
+// projectType 构造项目中声明的类型
+func (c *LayoutCalculator) projectType(dir, name string, decl typeDecl) (gotypes.Type, error) {
+	key := dir + "." + name
+	if t, ok := c.cache[key]; ok {
+		return t, nil
+	}
+	if c.building[key] {
+		return nil, fmt.Errorf("%s (recursive)", name)
+	}
+	c.building[key] = true
+	defer delete(c.building, key)
+
+	t, err := c.typeOf(decl.expr, decl.filePath)
+	if err != nil {
+		return nil, err
+	}
+	c.cache[key] = t
+	return t, nil
+}
+
+// importPackage 通过源码导入器加载标准库包；第三方模块的类型视为无法确定大小
+func (c *LayoutCalculator) importPackage(importPath string) *gotypes.Package {
+	if pkg, ok := c.imported[importPath]; ok {
+		return pkg
+	}
+	if !isStandardImport(c.parser, loadStdlib(), importPath) {
+		c.imported[importPath] = nil
+		return nil
+	}
+	pkg, err := c.importer.Import(importPath)
+	if err != nil {
+		pkg = nil
+	}
+	c.imported[importPath] = pkg
+	return pkg
+}
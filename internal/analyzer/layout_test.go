@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLayoutCalculator_Compute(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"model/model.go": `package model
+
+type ID int64
+
+type Point struct {
+	X, Y int32
+}
+`,
+		"service/service.go": `package service
+
+import (
+	"sync"
+
+	"example.com/app/model"
+	"github.com/other/lib"
+)
+
+type Padded struct {
+	A bool
+	B int64
+	C bool
+}
+
+type Compact struct {
+	ID    model.ID
+	Point model.Point
+	Name  string
+	Tags  []string
+	Mu    sync.Mutex
+	Ok    bool
+}
+
+type Remote struct {
+	Client lib.Client
+}
+
+type Flagged struct {
+	A    int64
+	B    int32
+	C    int32
+	Done struct{}
+}
+`,
+	})
+
+	c, err := NewLayoutCalculator(p, "")
+	if err != nil {
+		t.Fatalf("NewLayoutCalculator failed: %v", err)
+	}
+
+	padded := c.Compute(p.GetStruct("Padded"))
+	if padded.Arch != DefaultArch || padded.Size != 24 || padded.Align != 8 || padded.Padding != 14 {
+		t.Errorf("unexpected Padded layout: %+v", padded)
+	}
+	offsets := make([]int64, 0, len(padded.Fields))
+	for _, f := range padded.Fields {
+		offsets = append(offsets, f.Offset)
+	}
+	if !reflect.DeepEqual(offsets, []int64{0, 8, 16}) {
+		t.Errorf("Padded offsets = %v", offsets)
+	}
+	if padded.OptimalSize != 16 || !reflect.DeepEqual(padded.SuggestedOrder, []string{"B", "A", "C"}) {
+		t.Errorf("unexpected Padded suggestion: %d %v", padded.OptimalSize, padded.SuggestedOrder)
+	}
+
+	// 项目类型、标准库类型；已是最优顺序时不建议
+	compact := c.Compute(p.GetStruct("Compact"))
+	if len(compact.Unknown) > 0 {
+		t.Fatalf("Compact should be computable, unknown: %v", compact.Unknown)
+	}
+	// 8 (ID) + 8 (Point) + 16 (string) + 24 (slice) + 8 (Mutex) + 1 (bool) -> 72
+	if compact.Size != 72 || compact.Padding != 7 || len(compact.SuggestedOrder) != 0 {
+		t.Errorf("unexpected Compact layout: %+v", compact)
+	}
+
+	// 结尾的零大小字段会使 gc 补齐到下一个对齐边界，建议把它移到最前面
+	flagged := c.Compute(p.GetStruct("Flagged"))
+	if flagged.Size != 24 || flagged.OptimalSize != 16 || !reflect.DeepEqual(flagged.SuggestedOrder, []string{"Done", "A", "B", "C"}) {
+		t.Errorf("unexpected Flagged layout: size %d, optimal %d, order %v", flagged.Size, flagged.OptimalSize, flagged.SuggestedOrder)
+	}
+
+	// 第三方类型无法确定大小
+	remote := c.Compute(p.GetStruct("Remote"))
+	if len(remote.Unknown) != 1 || !strings.Contains(remote.Unknown[0], "lib.Client") || remote.Size != 0 {
+		t.Errorf("Remote should report unknown type, got %+v", remote)
+	}
+
+	// 32 位平台
+	c386, err := NewLayoutCalculator(p, "386")
+	if err != nil {
+		t.Fatalf("NewLayoutCalculator failed: %v", err)
+	}
+	if l := c386.Compute(p.GetStruct("Padded")); l.Size != 16 || l.OptimalSize != 12 {
+		t.Errorf("unexpected 386 layout: %+v", l)
+	}
+
+	if _, err := NewLayoutCalculator(p, "pdp11"); err == nil {
+		t.Error("expected error for unsupported GOARCH")
+	}
+	if err := ValidateArch("arm64"); err != nil {
+		t.Errorf("ValidateArch(arm64) = %v", err)
+	}
+}
+
+func TestTraverser_LayoutOptIn(t *testing.T) {
+	p, dir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"model/model.go": `package model
+
+type Point struct {
+	A bool
+	B int64
+}
+`,
+	})
+	filter := NewScopeFilter(p, NewBlacklist())
+
+	traverser := NewTraverser(p, filter, nil, false)
+	if layout := traverser.Analyze("Point", 1, dir).Structs[0].Layout; layout.Arch != "" {
+		t.Errorf("layout should not be computed by default, got %+v", layout)
+	}
+
+	traverser.SetLayout(true)
+	traverser.SetArch("386")
+	layout := traverser.Analyze("Point", 1, dir).Structs[0].Layout
+	if layout.Arch != "386" || layout.Size != 12 {
+		t.Errorf("layout = %+v, want 386 with size 12", layout)
+	}
+}
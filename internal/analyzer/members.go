@@ -0,0 +1,361 @@
+package analyzer
+
+import (
+	"go/ast"
+	"path/filepath"
+	"sort"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// wellKnownMethods 常见的标准库和第三方接口方法，可能通过无法解析的接口被调用，总是视为被使用
+var wellKnownMethods = map[string]bool{
+	"String": true, "GoString": true, "Format": true, "Error": true, "Unwrap": true, "Is": true, "As": true,
+	"MarshalJSON": true, "UnmarshalJSON": true, "MarshalText": true, "UnmarshalText": true,
+	"MarshalYAML": true, "UnmarshalYAML": true, "MarshalBinary": true, "UnmarshalBinary": true,
+	"Scan": true, "Value": true, "ServeHTTP": true, "Read": true, "Write": true, "Close": true,
+	"Len": true, "Less": true, "Swap": true, "TableName": true,
+}
+
+// externalUse 表示来自任意包的使用（接口实现、反射等）
+const externalUse = "*"
+
+// memberUsage 记录结构体成员被哪些包目录使用
+type memberUsage struct {
+	parser *parser.Parser
+	uses   map[string]map[string]map[string]bool // 结构体 -> 成员 -> 使用方包目录
+	owners map[string][]string                   // 成员名 -> 拥有该成员的结构体（无法解析接收者类型时按名称记录）
+}
+
+// FindUnusedMembers 将结构体的字段和方法与项目中全部选择器、结构体字面量的键和接口实现比对，
+// 报告没有被其他包使用的导出成员（可改为未导出）和完全没有被使用的未导出成员。
+// 选择器的接收者类型可以从接收者、参数、局部变量和字段链推断时精确计入，否则按成员名计入所有同名成员
+func FindUnusedMembers(p *parser.Parser, filter *ScopeFilter) types.MemberUsageReport {
+	u := &memberUsage{
+		parser: p,
+		uses:   make(map[string]map[string]map[string]bool),
+		owners: make(map[string][]string),
+	}
+	for name, info := range p.GetAllStructs() {
+		for _, f := range info.Fields {
+			if !f.IsEmbedded {
+				u.owners[f.Name] = append(u.owners[f.Name], name)
+			}
+		}
+		for _, m := range info.Methods {
+			u.owners[m.Name] = append(u.owners[m.Name], name)
+		}
+	}
+
+	// 1. 选择器和结构体字面量的键
+	for filePath, file := range p.GetAllFiles() {
+		dir := filepath.Dir(filePath)
+		imports := p.GetImports(filePath)
+		for _, decl := range file.Decls {
+			vars := make(map[string]string)
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+				vars = u.funcVars(funcDecl, filePath)
+			}
+			u.collect(decl, vars, imports, dir)
+		}
+	}
+
+	// 2. 接口实现、常见接口方法和带标签的字段（反射）视为来自任意包的使用
+	for name, info := range p.GetAllStructs() {
+		methods := make(map[string]bool)
+		for _, m := range info.Methods {
+			methods[m.Name] = true
+			if wellKnownMethods[m.Name] {
+				u.use(name, m.Name, externalUse)
+			}
+		}
+		for _, iface := range p.GetAllInterfaces() {
+			implemented := len(iface.Methods) > 0
+			for _, m := range iface.Methods {
+				if !methods[m.Name] {
+					implemented = false
+					break
+				}
+			}
+			if implemented {
+				for _, m := range iface.Methods {
+					u.use(name, m.Name, externalUse)
+				}
+			}
+		}
+		for _, f := range info.Fields {
+			if f.Tag != "" {
+				u.use(name, f.Name, externalUse)
+			}
+		}
+	}
+
+	// 3. 汇总
+	var report types.MemberUsageReport
+	for name, info := range p.GetAllStructs() {
+		if !filter.ShouldAnalyze(name) {
+			continue
+		}
+		dir := filepath.Dir(info.FilePath)
+		check := func(member, kind string, exported bool) {
+			m := types.UnusedMember{Struct: name, Package: info.Package, Name: member, Kind: kind, FilePath: info.FilePath}
+			users := u.uses[name][member]
+			if !exported {
+				if len(users) == 0 {
+					report.Unused = append(report.Unused, m)
+				}
+				return
+			}
+			// main 包不能被导入，其导出成员不做建议
+			if info.Package == "main" {
+				return
+			}
+			for d := range users {
+				if d != dir {
+					return
+				}
+			}
+			report.UnexportCandidates = append(report.UnexportCandidates, m)
+		}
+		for _, f := range info.Fields {
+			if f.IsEmbedded || f.Annotations.Ignore || f.Name == "_" {
+				continue
+			}
+			check(f.Name, types.MemberKindField, f.IsExported)
+		}
+		for _, m := range info.Methods {
+			check(m.Name, types.MemberKindMethod, m.IsExported)
+		}
+	}
+
+	for _, list := range [][]types.UnusedMember{report.UnexportCandidates, report.Unused} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Package != list[j].Package {
+				return list[i].Package < list[j].Package
+			}
+			if list[i].Struct != list[j].Struct {
+				return list[i].Struct < list[j].Struct
+			}
+			return list[i].Name < list[j].Name
+		})
+	}
+	return report
+}
+
+// use 记录 dir 中的代码使用了结构体的成员
+func (u *memberUsage) use(structName, member, dir string) {
+	if u.uses[structName] == nil {
+		u.uses[structName] = make(map[string]map[string]bool)
+	}
+	if u.uses[structName][member] == nil {
+		u.uses[structName][member] = make(map[string]bool)
+	}
+	u.uses[structName][member][dir] = true
+}
+
+// useMember 记录对 structName（为空表示无法解析）的成员的使用；
+// 成员不直接属于该结构体时沿嵌入字段查找，仍找不到时按名称计入所有同名成员
+func (u *memberUsage) useMember(structName, member, dir string) {
+	if structName != "" && u.useResolved(structName, member, dir, make(map[string]bool)) {
+		return
+	}
+	for _, owner := range u.owners[member] {
+		u.use(owner, member, dir)
+	}
+}
+
+// useResolved 在结构体及其嵌入字段中查找成员并记录使用，返回是否找到
+func (u *memberUsage) useResolved(structName, member, dir string, visited map[string]bool) bool {
+	info := u.parser.GetStruct(structName)
+	if info == nil || visited[structName] {
+		return false
+	}
+	visited[structName] = true
+	for _, f := range info.Fields {
+		if !f.IsEmbedded && f.Name == member {
+			u.use(structName, member, dir)
+			return true
+		}
+	}
+	for _, m := range info.Methods {
+		if m.Name == member {
+			u.use(structName, member, dir)
+			return true
+		}
+	}
+	for _, f := range info.Fields {
+		if f.IsEmbedded && u.useResolved(parser.ExtractBaseType(f.Type), member, dir, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// funcVars 返回函数中接收者、参数、返回值和局部变量的结构体类型（基础类型名）
+func (u *memberUsage) funcVars(funcDecl *ast.FuncDecl, filePath string) map[string]string {
+	vars := make(map[string]string)
+	addFields := func(list *ast.FieldList) {
+		if list == nil {
+			return
+		}
+		for _, field := range list.List {
+			typeName := baseTypeName(field.Type)
+			for _, name := range field.Names {
+				vars[name.Name] = typeName
+			}
+		}
+	}
+	addFields(funcDecl.Recv)
+	addFields(funcDecl.Type.Params)
+	addFields(funcDecl.Type.Results)
+
+	ctx := parser.NewTypeResolver(u.parser).ForFile(filePath).BuildTypeContext(funcDecl.Body)
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			if typeName := ctx.GetType(ident.Name); typeName != "" {
+				vars[ident.Name] = parser.ExtractBaseType(typeName)
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// collect 记录声明中的选择器和结构体字面量的键
+func (u *memberUsage) collect(node ast.Node, vars, imports map[string]string, dir string) {
+	elided := make(map[*ast.CompositeLit]string) // 省略类型的元素字面量 -> 元素类型
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := e.X.(*ast.Ident); ok {
+				if _, isVar := vars[ident.Name]; !isVar {
+					if _, isPkg := imports[ident.Name]; isPkg {
+						return false // 包限定名，不是成员访问
+					}
+				}
+			}
+			u.useMember(u.resolve(e.X, vars), e.Sel.Name, dir)
+		case *ast.CompositeLit:
+			// 切片、数组和 map 字面量：键不是字段，省略类型的元素使用元素类型
+			var elem ast.Expr
+			switch t := e.Type.(type) {
+			case *ast.ArrayType:
+				elem = t.Elt
+			case *ast.MapType:
+				elem = t.Value
+			}
+			if elem != nil {
+				for _, elt := range e.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						elt = kv.Value
+					}
+					if lit, ok := elt.(*ast.CompositeLit); ok && lit.Type == nil {
+						elided[lit] = baseTypeName(elem)
+					}
+				}
+				return true
+			}
+
+			structName := elided[e]
+			if e.Type != nil {
+				structName = baseTypeName(e.Type)
+			}
+			for _, elt := range e.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok {
+						u.useMember(structName, key.Name, dir)
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// resolve 推断表达式的结构体类型（基础类型名），无法推断时返回空字符串
+func (u *memberUsage) resolve(expr ast.Expr, vars map[string]string) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return vars[e.Name]
+	case *ast.ParenExpr:
+		return u.resolve(e.X, vars)
+	case *ast.StarExpr:
+		return u.resolve(e.X, vars)
+	case *ast.IndexExpr:
+		return u.resolve(e.X, vars)
+	case *ast.SelectorExpr:
+		info := u.parser.GetStruct(u.resolve(e.X, vars))
+		if info == nil {
+			return ""
+		}
+		for _, f := range info.Fields {
+			if f.Name == e.Sel.Name {
+				return parser.ExtractBaseType(f.Type)
+			}
+		}
+	case *ast.CompositeLit, *ast.UnaryExpr, *ast.CallExpr, *ast.TypeAssertExpr:
+		return parser.ExtractBaseType(parser.NewTypeResolver(u.parser).InferTypeFromExpr(e))
+	}
+	return ""
+}
+
+// baseTypeName 返回类型表达式的基础类型名（去掉指针、切片、包限定名和泛型参数）
+func baseTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return baseTypeName(t.X)
+	case *ast.ArrayType:
+		return baseTypeName(t.Elt)
+	case *ast.MapType:
+		return baseTypeName(t.Value)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return baseTypeName(t.X)
+	case *ast.IndexListExpr:
+		return baseTypeName(t.X)
+	case *ast.ParenExpr:
+		return baseTypeName(t.X)
+	}
+	return ""
+}
+
+// filterMembers 只保留 structs 中结构体的成员
+func filterMembers(report types.MemberUsageReport, structs map[string]bool) types.MemberUsageReport {
+	keep := func(members []types.UnusedMember) []types.UnusedMember {
+		var result []types.UnusedMember
+		for _, m := range members {
+			if structs[m.Struct] {
+				result = append(result, m)
+			}
+		}
+		return result
+	}
+	return types.MemberUsageReport{
+		UnexportCandidates: keep(report.UnexportCandidates),
+		Unused:             keep(report.Unused),
+	}
+}
+
+// UnusedMemberViolations 将成员使用情况转换为 check 子命令的违规（文件路径相对项目根目录 root）
+func UnusedMemberViolations(report types.MemberUsageReport, root string) []types.LayerViolation {
+	var violations []types.LayerViolation
+	add := func(members []types.UnusedMember, rule, reason string) {
+		for _, m := range members {
+			violations = append(violations, types.LayerViolation{
+				From:     m.Struct + "." + m.Name,
+				DepType:  m.Kind,
+				Context:  m.Package,
+				Rule:     rule,
+				Reason:   reason,
+				FilePath: projectRelPath(root, m.FilePath),
+			})
+		}
+	}
+	add(report.UnexportCandidates, types.MemberRuleUnexport, "没有被其他包使用的导出成员，可改为未导出")
+	add(report.Unused, types.MemberRuleUnused, "没有被使用的未导出成员")
+	return violations
+}
@@ -0,0 +1,150 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+func memberNames(members []types.UnusedMember) map[string]bool {
+	names := make(map[string]bool)
+	for _, m := range members {
+		names[m.Struct+"."+m.Name] = true
+	}
+	return names
+}
+
+func TestFindUnusedMembers(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"store/store.go": `package store
+
+type Loader interface {
+	Load() error
+}
+
+type Base struct{}
+
+func (b *Base) Ping() {}
+
+type Store struct {
+	Base
+	Name    string
+	Limit   int
+	Tagged  string ` + "`json:\"tagged\"`" + `
+	cache   map[string]string
+	dirty   bool
+}
+
+func NewStore() *Store {
+	return &Store{cache: map[string]string{}}
+}
+
+func (s *Store) Load() error   { return nil }
+func (s *Store) Flush()        { s.reset() }
+func (s *Store) reset()        {}
+func (s *Store) unusedHelper() {}
+func (s *Store) String() string { return s.Name }
+
+type Item struct {
+	Key   string
+	Value string
+}
+
+func Defaults() []Item {
+	return []Item{{Key: "a"}}
+}
+`,
+		"quota/quota.go": `package quota
+
+type Quota struct {
+	Limit int
+}
+`,
+		"app/app.go": `package app
+
+import "example.com/app/store"
+
+type App struct {
+	store *store.Store
+}
+
+func (a *App) Run() {
+	a.store.Flush()
+	a.store.Ping()
+	s := store.NewStore()
+	_ = s.Limit
+	for _, item := range store.Defaults() {
+		_ = item
+	}
+}
+`,
+	})
+	filter := NewScopeFilter(p, NewBlacklist())
+	report := FindUnusedMembers(p, filter)
+
+	candidates := memberNames(report.UnexportCandidates)
+	// Name 只在 store 包内使用（String 方法），Key 只在 store 包的字面量中使用
+	for _, name := range []string{"Store.Name", "Item.Key", "Item.Value"} {
+		if !candidates[name] {
+			t.Errorf("%s should be an unexport candidate, got %v", name, candidates)
+		}
+	}
+	// Flush/Limit/Ping 被 app 包使用；Load 实现接口；Tagged 带标签；String 是常见接口方法
+	for _, name := range []string{"Store.Flush", "Store.Limit", "Base.Ping", "Store.Load", "Store.Tagged", "Store.String"} {
+		if candidates[name] {
+			t.Errorf("%s is used from another package and should not be a candidate", name)
+		}
+	}
+
+	unused := memberNames(report.Unused)
+	if !unused["Store.unusedHelper"] || !unused["Store.dirty"] {
+		t.Errorf("unexpected unused members: %v", unused)
+	}
+	if unused["Store.reset"] || unused["Store.cache"] {
+		t.Errorf("reset and cache are used: %v", unused)
+	}
+
+	if unused["App.store"] {
+		t.Error("App.store is used via the receiver")
+	}
+
+	// s.Limit 的接收者类型可以推断为 Store，不计入其他包中同名的 Quota.Limit
+	if !candidates["Quota.Limit"] {
+		t.Errorf("Quota.Limit should not be counted by name, got %v", candidates)
+	}
+
+	violations := UnusedMemberViolations(report, "")
+	if len(violations) != len(report.UnexportCandidates)+len(report.Unused) || violations[0].Rule != types.MemberRuleUnexport {
+		t.Errorf("unexpected violations: %+v", violations)
+	}
+}
+
+func TestTraverser_MembersOptIn(t *testing.T) {
+	p, dir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"store/store.go": `package store
+
+type Store struct {
+	dirty bool
+}
+
+type Orphan struct {
+	unused int
+}
+`,
+	})
+	traverser := NewTraverser(p, NewScopeFilter(p, NewBlacklist()), nil, false)
+	if members := traverser.Analyze("Store", 1, dir).Members; len(members.Unused) != 0 || len(members.UnexportCandidates) != 0 {
+		t.Errorf("members should not be reported by default, got %+v", members)
+	}
+
+	traverser.SetMembers(true)
+	unused := memberNames(traverser.Analyze("Store", 1, dir).Members.Unused)
+	if !unused["Store.dirty"] {
+		t.Errorf("Store.dirty should be unused, got %v", unused)
+	}
+	if unused["Orphan.unused"] {
+		t.Error("members of structs outside the analysis should not be reported")
+	}
+}
@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// ComputeMetrics 计算结构体和包的耦合、内聚度量
+// 结构体度量基于已分析的依赖边和字段访问矩阵，中心性基于依赖图 centrality（遍历到的子图或全项目依赖图）；
+// 包度量基于包级依赖图，只输出项目内的包
+func (a *DependencyAnalyzer) ComputeMetrics(result *types.AnalysisResult, centrality *Graph) types.Metrics {
+	var metrics types.Metrics
+
+	// 结构体 fan-in / fan-out
+	fanIn := make(map[string]map[string]bool)
+	for _, s := range result.Structs {
+		for _, dep := range s.Dependencies {
+			if dep.To == s.Name {
+				continue
+			}
+			if fanIn[dep.To] == nil {
+				fanIn[dep.To] = make(map[string]bool)
+			}
+			fanIn[dep.To][s.Name] = true
+		}
+	}
+
+	// 中心性：PageRank、介数和传递闭包大小
+	pageRank := centrality.PageRank()
+	betweenness := centrality.Betweenness()
+	reach := centrality.ReachCount()
+
+	for _, s := range result.Structs {
+		m := types.StructMetrics{
+			Name:        s.Name,
+			Package:     s.Package,
+			FanIn:       len(fanIn[s.Name]),
+			Fields:      len(s.FieldAccess.Fields),
+			Methods:     len(s.FieldAccess.Methods),
+			LCOM:        lcom(s.FieldAccess),
+			Reach:       reach[s.Name],
+			PageRank:    round4(pageRank[s.Name]),
+			Betweenness: round4(betweenness[s.Name]),
+		}
+		targets := make(map[string]bool)
+		for _, dep := range s.Dependencies {
+			if dep.To != s.Name {
+				targets[dep.To] = true
+			}
+		}
+		m.FanOut = len(targets)
+		metrics.Structs = append(metrics.Structs, m)
+	}
+	sort.Slice(metrics.Structs, func(i, j int) bool { return metrics.Structs[i].Name < metrics.Structs[j].Name })
+
+	metrics.Packages = packageMetrics(a.parser, result.PackageGraph)
+	return metrics
+}
+
+// lcom 计算 Henderson-Sellers LCOM：(平均每个字段被访问的方法数 - m) / (1 - m)
+// 方法少于两个或没有字段时视为完全内聚
+func lcom(matrix types.FieldAccessMatrix) float64 {
+	m := len(matrix.Methods)
+	f := len(matrix.Fields)
+	if m < 2 || f == 0 {
+		return 0
+	}
+
+	mean := float64(len(matrix.Accesses)) / float64(f)
+	return round2((mean - float64(m)) / (1 - float64(m)))
+}
+
+// packageMetrics 根据包级依赖图计算 Martin 包度量
+func packageMetrics(p *parser.Parser, graph types.PackageGraph) []types.PackageMetrics {
+	// 统计每个包内的结构体和接口总数（包括未被分析到的类型）
+	typeCount := make(map[string]int)
+	ifaceCount := make(map[string]int)
+	for _, s := range p.GetAllStructs() {
+		typeCount[packageKey(s.ImportPath, s.Package)]++
+	}
+	for _, iface := range p.GetAllInterfaces() {
+		key := packageKey(iface.ImportPath, iface.Package)
+		typeCount[key]++
+		ifaceCount[key]++
+	}
+
+	afferent := make(map[string]map[string]bool)
+	efferent := make(map[string]map[string]bool)
+	fanIn := make(map[string]int)
+	fanOut := make(map[string]int)
+	for _, edge := range graph.Edges {
+		if afferent[edge.To] == nil {
+			afferent[edge.To] = make(map[string]bool)
+		}
+		afferent[edge.To][edge.From] = true
+		if efferent[edge.From] == nil {
+			efferent[edge.From] = make(map[string]bool)
+		}
+		efferent[edge.From][edge.To] = true
+		fanIn[edge.To] += edge.Weight
+		fanOut[edge.From] += edge.Weight
+	}
+
+	var result []types.PackageMetrics
+	for _, pkg := range graph.Packages {
+		if pkg.External {
+			continue
+		}
+		m := types.PackageMetrics{
+			ImportPath: pkg.ImportPath,
+			Name:       pkg.Name,
+			FanIn:      fanIn[pkg.ImportPath],
+			FanOut:     fanOut[pkg.ImportPath],
+			Ca:         len(afferent[pkg.ImportPath]),
+			Ce:         len(efferent[pkg.ImportPath]),
+			Interfaces: ifaceCount[pkg.ImportPath],
+			Types:      typeCount[pkg.ImportPath],
+		}
+		if m.Ca+m.Ce > 0 {
+			m.Instability = round2(float64(m.Ce) / float64(m.Ca+m.Ce))
+		}
+		if m.Types > 0 {
+			m.Abstractness = round2(float64(m.Interfaces) / float64(m.Types))
+		}
+		m.Distance = round2(math.Abs(m.Abstractness + m.Instability - 1))
+		result = append(result, m)
+	}
+	return result
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
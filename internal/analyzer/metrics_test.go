@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+func TestComputeMetrics(t *testing.T) {
+	p, tmpDir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"service/service.go": `package service
+
+import "example.com/app/store"
+
+type Service struct {
+	repo  *store.Repo
+	limit int
+}
+
+func (s *Service) Load() { s.repo.Get(s.limit) }
+
+func (s *Service) Close() { s.repo.Close() }
+`,
+		"store/store.go": `package store
+
+type Reader interface{ Get(n int) }
+
+type Repo struct{}
+
+func (r *Repo) Get(n int) {}
+
+func (r *Repo) Close() {}
+`,
+	})
+
+	filter := NewScopeFilter(p, NewBlacklist())
+	result := NewTraverser(p, filter, nil, false).Analyze("Service", 3, tmpDir)
+
+	structs := make(map[string]types.StructMetrics)
+	for _, m := range result.Metrics.Structs {
+		structs[m.Name] = m
+	}
+
+	svc := structs["Service"]
+	if svc.FanOut != 1 || svc.FanIn != 0 || svc.Fields != 2 || svc.Methods != 2 {
+		t.Errorf("unexpected Service metrics: %+v", svc)
+	}
+	// repo 被 2 个方法访问，limit 被 1 个：(1.5 - 2) / (1 - 2) = 0.5
+	if svc.LCOM != 0.5 {
+		t.Errorf("Service LCOM = %v, want 0.5", svc.LCOM)
+	}
+	if repo := structs["Repo"]; repo.FanIn != 1 || repo.LCOM != 0 {
+		t.Errorf("unexpected Repo metrics: %+v", repo)
+	}
+	if repo := structs["Repo"]; repo.Reach != 1 || repo.PageRank <= svc.PageRank || svc.Reach != 0 {
+		t.Errorf("Repo should be reached by Service and rank above it: Repo %+v, Service %+v", repo, svc)
+	}
+
+	packages := make(map[string]types.PackageMetrics)
+	for _, m := range result.Metrics.Packages {
+		packages[m.Name] = m
+	}
+
+	store := packages["store"]
+	if store.Ca != 1 || store.Ce != 0 || store.FanIn != 1 || store.Instability != 0 {
+		t.Errorf("unexpected store coupling: %+v", store)
+	}
+	if store.Abstractness != 0.5 || store.Distance != 0.5 {
+		t.Errorf("store A=%v D=%v, want 0.5/0.5", store.Abstractness, store.Distance)
+	}
+
+	service := packages["service"]
+	if service.Ca != 0 || service.Ce != 1 || service.Instability != 1 || service.Distance != 0 {
+		t.Errorf("unexpected service metrics: %+v", service)
+	}
+}
+
+func TestComputeMetrics_CentralityOutsideBFS(t *testing.T) {
+	p, tmpDir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"api/handler.go": `package api
+
+import "example.com/app/service"
+
+type Handler struct {
+	svc *service.Service
+}
+`,
+		"service/service.go": `package service
+
+type Service struct {
+	repo *Repo
+}
+
+type Repo struct{}
+`,
+	})
+
+	filter := NewScopeFilter(p, NewBlacklist())
+	traverser := NewTraverser(p, filter, nil, false)
+	metricsOf := func(result *types.AnalysisResult) map[string]types.StructMetrics {
+		structs := make(map[string]types.StructMetrics)
+		for _, m := range result.Metrics.Structs {
+			structs[m.Name] = m
+		}
+		return structs
+	}
+
+	// 默认只在遍历到的子图上计算，不构建全项目依赖图
+	structs := metricsOf(traverser.Analyze("Service", 3, tmpDir))
+	if traverser.project != nil {
+		t.Error("project graph should not be built unless project centrality is enabled")
+	}
+	if svc, repo := structs["Service"], structs["Repo"]; svc.Reach != 0 || repo.Reach != 1 {
+		t.Errorf("subgraph centrality: Service reach = %d, Repo reach = %d, want 0 and 1", svc.Reach, repo.Reach)
+	}
+
+	traverser.SetProjectCentrality(true)
+	structs = metricsOf(traverser.Analyze("Service", 3, tmpDir))
+	if _, ok := structs["Handler"]; ok {
+		t.Error("Handler is outside the BFS and should not be listed")
+	}
+	// Handler 不在起点的 BFS 中，但仍然计入中心性
+	if svc := structs["Service"]; svc.Reach != 1 || svc.Betweenness == 0 {
+		t.Errorf("Service should be reached by Handler and lie on its path to Repo: %+v", svc)
+	}
+	if repo := structs["Repo"]; repo.Reach != 2 {
+		t.Errorf("Repo reach = %d, want 2", repo.Reach)
+	}
+}
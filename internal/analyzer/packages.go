@@ -0,0 +1,206 @@
+package analyzer
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// BuildPackageGraph 将结构体依赖聚合为以导入路径为键的包级依赖图
+// 依赖目标未被分析时（超出深度或为接口）通过解析器查找所属包；limit 为包级环枚举上限
+func BuildPackageGraph(p *parser.Parser, result *types.AnalysisResult, limit int) types.PackageGraph {
+	packages := make(map[string]*types.PackageNode)
+	edges := make(map[string]map[string]*types.PackageEdge)
+
+	// 导入路径.结构体名 -> 包节点
+	structPackages := make(map[string]*types.PackageNode)
+	addMember := func(name, importPath, pkgName string, depth int, external bool) *types.PackageNode {
+		node := packages[importPath]
+		if node == nil {
+			node = &types.PackageNode{ImportPath: importPath, Name: pkgName, Depth: depth, External: external}
+			packages[importPath] = node
+		}
+		if !containsString(node.Structs, name) {
+			node.Structs = append(node.Structs, name)
+		}
+		if depth < node.Depth {
+			node.Depth = depth
+		}
+		structPackages[importPath+"."+name] = node
+		return node
+	}
+
+	for _, s := range result.Structs {
+		addMember(s.Name, packageKey(s.ImportPath, s.Package), s.Package, s.Depth, false)
+	}
+
+	refs := newPackageTypeRefs(p)
+
+	externals := make(map[string]types.ExternalType)
+	for _, ext := range result.ExternalTypes {
+		externals[ext.Name] = ext
+	}
+
+	// 查找依赖目标所属的包：依赖只记录了类型名，按源结构体所在包的导入确定目标包，
+	// 避免同名类型存在于多个包时归到错误的包
+	resolve := func(from types.StructAnalysis, dep types.Dependency) *types.PackageNode {
+		if dep.External {
+			if ext, ok := externals[dep.To]; ok {
+				return addMember(dep.To, ext.ImportPath, externalPackageName(ext.Name), dep.Depth, true)
+			}
+			return nil
+		}
+		// 没有 go.mod 时无法按导入路径区分包，退回按名称查找
+		if from.ImportPath != "" {
+			if importPath, pkgName := refs.resolve(from.ImportPath, dep.To); importPath != "" {
+				if node, ok := structPackages[importPath+"."+dep.To]; ok {
+					return node
+				}
+				return addMember(dep.To, importPath, pkgName, dep.Depth, false)
+			}
+		}
+		if info := p.GetStruct(dep.To); info != nil {
+			return addMember(dep.To, packageKey(info.ImportPath, info.Package), info.Package, dep.Depth, false)
+		}
+		if iface := p.GetInterface(dep.To); iface != nil {
+			return addMember(dep.To, packageKey(iface.ImportPath, iface.Package), iface.Package, dep.Depth, false)
+		}
+		return nil
+	}
+
+	for _, s := range result.Structs {
+		from := structPackages[packageKey(s.ImportPath, s.Package)+"."+s.Name]
+		for _, dep := range s.Dependencies {
+			to := resolve(s, dep)
+			if to == nil || to.ImportPath == from.ImportPath {
+				continue
+			}
+			if edges[from.ImportPath] == nil {
+				edges[from.ImportPath] = make(map[string]*types.PackageEdge)
+			}
+			edge := edges[from.ImportPath][to.ImportPath]
+			if edge == nil {
+				edge = &types.PackageEdge{From: from.ImportPath, To: to.ImportPath}
+				edges[from.ImportPath][to.ImportPath] = edge
+			}
+			edge.Weight++
+			edge.Deps = append(edge.Deps, dep)
+		}
+	}
+
+	// 排序输出
+	var graph types.PackageGraph
+	g := NewGraph()
+	for _, node := range packages {
+		sort.Strings(node.Structs)
+		graph.Packages = append(graph.Packages, *node)
+		g.AddNode(node.ImportPath)
+	}
+	sort.Slice(graph.Packages, func(i, j int) bool { return graph.Packages[i].ImportPath < graph.Packages[j].ImportPath })
+
+	for _, targets := range edges {
+		for _, edge := range targets {
+			graph.Edges = append(graph.Edges, *edge)
+			g.AddEdge(types.Dependency{From: edge.From, To: edge.To})
+		}
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+
+	graph.Cycles, _ = g.ElementaryCycles(limit)
+	return graph
+}
+
+// packageTypeRefs 按包记录代码中对项目类型的引用方式，用于确定只有类型名的依赖目标属于哪个包
+type packageTypeRefs struct {
+	parser *parser.Parser
+	files  map[string][]string            // 导入路径 -> 文件
+	refs   map[string]map[string][]string // 导入路径 -> (类型名 -> 以 pkg.T 形式引用时 pkg 的导入路径)
+	bare   map[string]map[string]bool     // 导入路径 -> 以裸名引用的标识符
+}
+
+func newPackageTypeRefs(p *parser.Parser) *packageTypeRefs {
+	r := &packageTypeRefs{
+		parser: p,
+		files:  make(map[string][]string),
+		refs:   make(map[string]map[string][]string),
+		bare:   make(map[string]map[string]bool),
+	}
+	for filePath := range p.GetAllFiles() {
+		importPath := p.ImportPathOf(filePath)
+		r.files[importPath] = append(r.files[importPath], filePath)
+	}
+	return r
+}
+
+// resolve 返回导入路径为 from 的包中的代码以 name 引用的项目类型所在包的导入路径和包名，无法确定时返回空
+// 优先采用包内以 pkg.name 形式引用的导入包；同包也声明了 name 且以裸名引用时取同包
+func (r *packageTypeRefs) resolve(from, name string) (string, string) {
+	r.collect(from)
+	own := r.parser.TypePackage(from, name)
+	if own != "" && (r.bare[from][name] || len(r.refs[from][name]) == 0) {
+		return from, own
+	}
+	if targets := r.refs[from][name]; len(targets) > 0 {
+		return targets[0], r.parser.TypePackage(targets[0], name)
+	}
+	if own != "" {
+		return from, own
+	}
+	return "", ""
+}
+
+// collect 扫描包中的所有文件，记录以 pkg.T 形式引用的项目类型和以裸名引用的标识符
+func (r *packageTypeRefs) collect(importPath string) {
+	if _, ok := r.refs[importPath]; ok {
+		return
+	}
+	refs := make(map[string][]string)
+	bare := make(map[string]bool)
+	r.refs[importPath], r.bare[importPath] = refs, bare
+
+	sort.Strings(r.files[importPath])
+	for _, filePath := range r.files[importPath] {
+		imports := r.parser.GetImports(filePath)
+		ast.Inspect(r.parser.GetFile(filePath), func(n ast.Node) bool {
+			switch e := n.(type) {
+			case *ast.SelectorExpr:
+				if ident, ok := e.X.(*ast.Ident); ok {
+					if target, ok := imports[ident.Name]; ok {
+						name := e.Sel.Name
+						if r.parser.TypePackage(target, name) != "" && !containsString(refs[name], target) {
+							refs[name] = append(refs[name], target)
+						}
+						return false
+					}
+				}
+			case *ast.Ident:
+				bare[e.Name] = true
+			}
+			return true
+		})
+	}
+}
+
+// externalPackageName 从 包名.类型名 形式的外部类型名中取出包名
+func externalPackageName(name string) string {
+	if idx := strings.Index(name, "."); idx != -1 {
+		return name[:idx]
+	}
+	return name
+}
+
+// packageKey 返回包节点的键：优先使用导入路径，缺失时（如没有 go.mod）退回包名
+func packageKey(importPath, pkgName string) string {
+	if importPath != "" {
+		return importPath
+	}
+	return pkgName
+}
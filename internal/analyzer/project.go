@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// DefaultPathLimit 默认最多列出的路径数量
+const DefaultPathLimit = 100
+
+// AnalyzeProject 分析项目中全部结构体的依赖（不受起点和深度限制，不调用 LLM），按名称排序
+func AnalyzeProject(p *parser.Parser, filter *ScopeFilter) []types.StructAnalysis {
+	a := NewDependencyAnalyzer(p, filter, false)
+
+	structs := p.GetAllStructs()
+	names := make([]string, 0, len(structs))
+	for name := range structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]types.StructAnalysis, 0, len(names))
+	for _, name := range names {
+		info := structs[name]
+		result = append(result, types.StructAnalysis{
+			Name:         info.Name,
+			Package:      info.Package,
+			ImportPath:   info.ImportPath,
+			Dependencies: a.AnalyzeStruct(info),
+		})
+	}
+	return result
+}
+
+// PathQuery 表示路径查询选项
+type PathQuery struct {
+	From     string   // 起点结构体
+	To       string   // 终点类型
+	DepTypes []string // 只沿这些依赖类型的边查找（为空表示全部）
+	All      bool     // 是否列出全部简单路径（否则只返回最短路径）
+	MaxHops  int      // 列出全部路径时的最大跳数（<= 0 表示不限制）
+	Limit    int      // 列出全部路径时的最大数量（<= 0 时使用 DefaultPathLimit）
+}
+
+// FindPaths 在项目依赖图上查找两个类型之间的依赖路径，遵循黑名单和依赖类型过滤
+func FindPaths(p *parser.Parser, filter *ScopeFilter, query PathQuery) (types.PathResult, error) {
+	result := types.PathResult{From: query.From, To: query.To, DepTypes: query.DepTypes}
+
+	g := BuildGraph(AnalyzeProject(p, filter), query.DepTypes)
+	for _, name := range []string{query.From, query.To} {
+		if !g.nodes[name] {
+			return result, fmt.Errorf("type %q not found in dependency graph", name)
+		}
+	}
+
+	shortest := g.ShortestPath(query.From, query.To)
+	if shortest == nil {
+		return result, nil
+	}
+	if !query.All {
+		result.Paths = []types.DependencyPath{g.PathDetail(shortest)}
+		return result, nil
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultPathLimit
+	}
+	paths, truncated := g.SimplePaths(query.From, query.To, query.MaxHops, limit)
+	result.Truncated = truncated
+	for _, path := range paths {
+		result.Paths = append(result.Paths, g.PathDetail(path))
+	}
+	// 最大跳数小于最短路径时仍然给出最短路径
+	if len(result.Paths) == 0 {
+		result.Paths = []types.DependencyPath{g.PathDetail(shortest)}
+	}
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+func TestFindPaths(t *testing.T) {
+	p, _ := writeTestProject(t, layeredProject(t))
+	filter := NewScopeFilter(p, NewBlacklist())
+
+	result, err := FindPaths(p, filter, PathQuery{From: "UserHandler", To: "UserRepo"})
+	if err != nil {
+		t.Fatalf("FindPaths failed: %v", err)
+	}
+	if len(result.Paths) != 1 || !reflect.DeepEqual(result.Paths[0].Nodes, []string{"UserHandler", "UserRepo"}) {
+		t.Fatalf("expected the direct path only, got %+v", result.Paths)
+	}
+	if hop := result.Paths[0].Hops[0]; hop.Evidence[0] != "repo 字段" {
+		t.Errorf("unexpected hop evidence: %+v", hop)
+	}
+
+	result, _ = FindPaths(p, filter, PathQuery{From: "UserHandler", To: "UserRepo", All: true})
+	if len(result.Paths) != 2 || !reflect.DeepEqual(result.Paths[1].Nodes, []string{"UserHandler", "UserService", "UserRepo"}) {
+		t.Errorf("expected direct and service paths, got %+v", result.Paths)
+	}
+
+	// 依赖类型过滤：没有构造函数依赖
+	result, err = FindPaths(p, filter, PathQuery{From: "UserHandler", To: "UserRepo", DepTypes: []string{types.DepTypeConstructor}})
+	if err != nil || len(result.Paths) != 0 {
+		t.Errorf("expected no constructor path, got %+v (err %v)", result.Paths, err)
+	}
+
+	// 黑名单：屏蔽 UserService 后只剩直接路径
+	blacklist := NewBlacklist()
+	blacklist.AddType("UserService")
+	result, _ = FindPaths(p, NewScopeFilter(p, blacklist), PathQuery{From: "UserHandler", To: "UserRepo", All: true})
+	if len(result.Paths) != 1 {
+		t.Errorf("blacklisted type should not appear on paths, got %+v", result.Paths)
+	}
+
+	if _, err := FindPaths(p, filter, PathQuery{From: "UserHandler", To: "Missing"}); err == nil {
+		t.Error("expected error for unknown type")
+	}
+}
@@ -0,0 +1,231 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// DiffResults 比较两次分析结果：新增和删除的结构体、字段、方法和依赖边，深度变化，
+// 新出现和已消除的循环依赖，以及两次都存在的结构体和包的度量变化
+func DiffResults(oldResult, newResult *types.AnalysisResult) types.ResultDiff {
+	diff := types.ResultDiff{
+		Old:        oldResult.GeneratedAt,
+		New:        newResult.GeneratedAt,
+		OldStructs: oldResult.TotalStructs,
+		NewStructs: newResult.TotalStructs,
+		OldDeps:    oldResult.TotalDeps,
+		NewDeps:    newResult.TotalDeps,
+	}
+	if oldResult.StartStruct != newResult.StartStruct {
+		diff.Warnings = append(diff.Warnings, fmt.Sprintf("起点结构体不同: %s → %s", oldResult.StartStruct, newResult.StartStruct))
+	}
+	if oldResult.MaxDepth != newResult.MaxDepth {
+		diff.Warnings = append(diff.Warnings, fmt.Sprintf("分析深度不同: %d → %d", oldResult.MaxDepth, newResult.MaxDepth))
+	}
+
+	// 1. 结构体、成员和深度
+	oldStructs := structsByKey(oldResult)
+	newStructs := structsByKey(newResult)
+	for _, key := range sortedStructKeys(oldStructs) {
+		o := oldStructs[key]
+		n, ok := newStructs[key]
+		if !ok {
+			diff.RemovedStructs = append(diff.RemovedStructs, types.StructRef{Name: o.Name, Package: o.Package, Depth: o.Depth})
+			continue
+		}
+		if o.Depth != n.Depth {
+			diff.DepthChanges = append(diff.DepthChanges, types.DepthChange{Struct: o.Name, Old: o.Depth, New: n.Depth})
+		}
+		diff.Members = append(diff.Members, diffMembers(o, n)...)
+	}
+	for _, key := range sortedStructKeys(newStructs) {
+		if _, ok := oldStructs[key]; !ok {
+			n := newStructs[key]
+			diff.AddedStructs = append(diff.AddedStructs, types.StructRef{Name: n.Name, Package: n.Package, Depth: n.Depth})
+		}
+	}
+
+	// 2. 依赖边
+	oldEdges, newEdges := edgesByKey(oldResult), edgesByKey(newResult)
+	for _, key := range sortedEdgeKeys(oldEdges) {
+		if _, ok := newEdges[key]; !ok {
+			diff.RemovedEdges = append(diff.RemovedEdges, oldEdges[key])
+		}
+	}
+	for _, key := range sortedEdgeKeys(newEdges) {
+		if _, ok := oldEdges[key]; !ok {
+			diff.AddedEdges = append(diff.AddedEdges, newEdges[key])
+		}
+	}
+
+	// 3. 循环依赖（基本环从字典序最小的节点开始，可以直接比较）
+	oldCycles, newCycles := cycleSet(oldResult.Cycles), cycleSet(newResult.Cycles)
+	for _, cycle := range newResult.Cycles {
+		if !oldCycles[strings.Join(cycle, " -> ")] {
+			diff.NewCycles = append(diff.NewCycles, cycle)
+		}
+	}
+	for _, cycle := range oldResult.Cycles {
+		if !newCycles[strings.Join(cycle, " -> ")] {
+			diff.ResolvedCycles = append(diff.ResolvedCycles, cycle)
+		}
+	}
+
+	// 4. 度量
+	diff.MetricDeltas = diffMetrics(oldResult.Metrics, newResult.Metrics)
+	return diff
+}
+
+// diffMembers 比较同一结构体两次的字段和方法
+func diffMembers(o, n types.StructAnalysis) []types.MemberChange {
+	var changes []types.MemberChange
+	compare := func(kind string, oldMembers, newMembers map[string]string, order []string) {
+		for _, name := range order {
+			oldDecl, inOld := oldMembers[name]
+			newDecl, inNew := newMembers[name]
+			c := types.MemberChange{Struct: n.Name, Name: name, Kind: kind, Old: oldDecl, New: newDecl}
+			switch {
+			case !inNew:
+				c.Change = types.APIChangeRemoved
+			case !inOld:
+				c.Change = types.APIChangeAdded
+			case oldDecl != newDecl:
+				c.Change = types.APIChangeChanged
+			default:
+				continue
+			}
+			changes = append(changes, c)
+		}
+	}
+
+	oldFields, newFields := make(map[string]string), make(map[string]string)
+	var fieldOrder []string
+	for _, f := range o.Fields {
+		oldFields[f.Name] = f.Type
+		fieldOrder = append(fieldOrder, f.Name)
+	}
+	for _, f := range n.Fields {
+		newFields[f.Name] = f.Type
+		if _, ok := oldFields[f.Name]; !ok {
+			fieldOrder = append(fieldOrder, f.Name)
+		}
+	}
+	compare(types.APIKindField, oldFields, newFields, fieldOrder)
+
+	oldMethods, newMethods := make(map[string]string), make(map[string]string)
+	var methodOrder []string
+	for _, m := range o.Methods {
+		oldMethods[m.Name] = m.Signature
+		methodOrder = append(methodOrder, m.Name)
+	}
+	for _, m := range n.Methods {
+		newMethods[m.Name] = m.Signature
+		if _, ok := oldMethods[m.Name]; !ok {
+			methodOrder = append(methodOrder, m.Name)
+		}
+	}
+	compare(types.APIKindMethod, oldMethods, newMethods, methodOrder)
+	return changes
+}
+
+// diffMetrics 返回两次都存在的结构体和包中发生变化的度量
+func diffMetrics(oldMetrics, newMetrics types.Metrics) []types.MetricDelta {
+	var deltas []types.MetricDelta
+	add := func(scope, subject, metric string, oldValue, newValue float64) {
+		if math.Abs(newValue-oldValue) > 1e-9 {
+			deltas = append(deltas, types.MetricDelta{Scope: scope, Subject: subject, Metric: metric, Old: oldValue, New: newValue})
+		}
+	}
+
+	oldStructs := make(map[string]types.StructMetrics)
+	for _, m := range oldMetrics.Structs {
+		oldStructs[m.Package+"."+m.Name] = m
+	}
+	for _, n := range newMetrics.Structs {
+		o, ok := oldStructs[n.Package+"."+n.Name]
+		if !ok {
+			continue
+		}
+		add(types.MetricScopeStruct, n.Name, "fan-in", float64(o.FanIn), float64(n.FanIn))
+		add(types.MetricScopeStruct, n.Name, "fan-out", float64(o.FanOut), float64(n.FanOut))
+		add(types.MetricScopeStruct, n.Name, "fields", float64(o.Fields), float64(n.Fields))
+		add(types.MetricScopeStruct, n.Name, "methods", float64(o.Methods), float64(n.Methods))
+		add(types.MetricScopeStruct, n.Name, "lcom", o.LCOM, n.LCOM)
+		add(types.MetricScopeStruct, n.Name, "reach", float64(o.Reach), float64(n.Reach))
+		add(types.MetricScopeStruct, n.Name, "pagerank", o.PageRank, n.PageRank)
+		add(types.MetricScopeStruct, n.Name, "betweenness", o.Betweenness, n.Betweenness)
+	}
+
+	oldPackages := make(map[string]types.PackageMetrics)
+	for _, m := range oldMetrics.Packages {
+		oldPackages[m.ImportPath] = m
+	}
+	for _, n := range newMetrics.Packages {
+		o, ok := oldPackages[n.ImportPath]
+		if !ok {
+			continue
+		}
+		add(types.MetricScopePackage, n.ImportPath, "ca", float64(o.Ca), float64(n.Ca))
+		add(types.MetricScopePackage, n.ImportPath, "ce", float64(o.Ce), float64(n.Ce))
+		add(types.MetricScopePackage, n.ImportPath, "instability", o.Instability, n.Instability)
+		add(types.MetricScopePackage, n.ImportPath, "abstractness", o.Abstractness, n.Abstractness)
+		add(types.MetricScopePackage, n.ImportPath, "distance", o.Distance, n.Distance)
+	}
+	return deltas
+}
+
+// structsByKey 按 导入路径.名称 索引分析结果中的结构体
+func structsByKey(result *types.AnalysisResult) map[string]types.StructAnalysis {
+	structs := make(map[string]types.StructAnalysis, len(result.Structs))
+	for _, s := range result.Structs {
+		structs[s.ImportPath+"."+s.Name] = s
+	}
+	return structs
+}
+
+// sortedStructKeys 返回已排序的结构体键
+func sortedStructKeys(structs map[string]types.StructAnalysis) []string {
+	keys := make([]string, 0, len(structs))
+	for k := range structs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// edgesByKey 按 起点|终点|依赖类型 索引依赖边，同一条边只保留第一个上下文
+func edgesByKey(result *types.AnalysisResult) map[string]types.Dependency {
+	edges := make(map[string]types.Dependency)
+	for _, s := range result.Structs {
+		for _, dep := range s.Dependencies {
+			key := dep.From + "|" + dep.To + "|" + dep.Type
+			if _, ok := edges[key]; !ok {
+				edges[key] = dep
+			}
+		}
+	}
+	return edges
+}
+
+// sortedEdgeKeys 返回已排序的依赖边键
+func sortedEdgeKeys(edges map[string]types.Dependency) []string {
+	keys := make([]string, 0, len(edges))
+	for k := range edges {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cycleSet 将循环依赖转换为集合
+func cycleSet(cycles [][]string) map[string]bool {
+	set := make(map[string]bool, len(cycles))
+	for _, cycle := range cycles {
+		set[strings.Join(cycle, " -> ")] = true
+	}
+	return set
+}
@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+func TestDiffResults(t *testing.T) {
+	oldResult := &types.AnalysisResult{
+		StartStruct: "Service", MaxDepth: 3, TotalStructs: 3, TotalDeps: 3,
+		Structs: []types.StructAnalysis{
+			{Name: "Service", Package: "app", ImportPath: "example.com/app", Depth: 0,
+				Fields:  []types.FieldAnalysis{{Name: "repo", Type: "*Repo"}, {Name: "cache", Type: "*Cache"}},
+				Methods: []types.MethodAnalysis{{Name: "Run", Signature: "func (s *Service) Run() error"}},
+				Dependencies: []types.Dependency{
+					{From: "Service", To: "Repo", Type: types.DepTypeField, Context: "repo"},
+					{From: "Service", To: "Cache", Type: types.DepTypeField, Context: "cache"},
+				}},
+			{Name: "Repo", Package: "app", ImportPath: "example.com/app", Depth: 1,
+				Dependencies: []types.Dependency{{From: "Repo", To: "Service", Type: types.DepTypeField, Context: "svc"}}},
+			{Name: "Cache", Package: "app", ImportPath: "example.com/app", Depth: 1},
+		},
+		Cycles: [][]string{{"Repo", "Service"}},
+		Metrics: types.Metrics{
+			Structs:  []types.StructMetrics{{Name: "Service", Package: "app", FanOut: 2, Fields: 2, Methods: 1, Reach: 1, PageRank: 0.2}},
+			Packages: []types.PackageMetrics{{ImportPath: "example.com/app", Ce: 1, Instability: 0.5}},
+		},
+	}
+	newResult := &types.AnalysisResult{
+		StartStruct: "Service", MaxDepth: 4, TotalStructs: 3, TotalDeps: 2,
+		Structs: []types.StructAnalysis{
+			{Name: "Service", Package: "app", ImportPath: "example.com/app", Depth: 0,
+				Fields:  []types.FieldAnalysis{{Name: "repo", Type: "Repository"}, {Name: "log", Type: "*Logger"}},
+				Methods: []types.MethodAnalysis{{Name: "Run", Signature: "func (s *Service) Run(ctx context.Context) error"}, {Name: "Stop", Signature: "func (s *Service) Stop()"}},
+				Dependencies: []types.Dependency{
+					{From: "Service", To: "Repo", Type: types.DepTypeField, Context: "repo"},
+					{From: "Service", To: "Logger", Type: types.DepTypeField, Context: "log"},
+				}},
+			{Name: "Repo", Package: "app", ImportPath: "example.com/app", Depth: 2},
+			{Name: "Logger", Package: "app", ImportPath: "example.com/app", Depth: 1},
+		},
+		Metrics: types.Metrics{
+			Structs:  []types.StructMetrics{{Name: "Service", Package: "app", FanOut: 2, Fields: 2, Methods: 2, Reach: 1, PageRank: 0.15, Betweenness: 0.5}},
+			Packages: []types.PackageMetrics{{ImportPath: "example.com/app", Ce: 1, Instability: 0.25}},
+		},
+	}
+
+	diff := DiffResults(oldResult, newResult)
+
+	if len(diff.Warnings) != 1 {
+		t.Errorf("expected 1 warning for different depth, got %v", diff.Warnings)
+	}
+	if len(diff.AddedStructs) != 1 || diff.AddedStructs[0].Name != "Logger" {
+		t.Errorf("AddedStructs = %+v, want Logger", diff.AddedStructs)
+	}
+	if len(diff.RemovedStructs) != 1 || diff.RemovedStructs[0].Name != "Cache" {
+		t.Errorf("RemovedStructs = %+v, want Cache", diff.RemovedStructs)
+	}
+
+	wantMembers := []types.MemberChange{
+		{Struct: "Service", Name: "repo", Kind: types.APIKindField, Change: types.APIChangeChanged, Old: "*Repo", New: "Repository"},
+		{Struct: "Service", Name: "cache", Kind: types.APIKindField, Change: types.APIChangeRemoved, Old: "*Cache"},
+		{Struct: "Service", Name: "log", Kind: types.APIKindField, Change: types.APIChangeAdded, New: "*Logger"},
+		{Struct: "Service", Name: "Run", Kind: types.APIKindMethod, Change: types.APIChangeChanged,
+			Old: "func (s *Service) Run() error", New: "func (s *Service) Run(ctx context.Context) error"},
+		{Struct: "Service", Name: "Stop", Kind: types.APIKindMethod, Change: types.APIChangeAdded, New: "func (s *Service) Stop()"},
+	}
+	if !reflect.DeepEqual(diff.Members, wantMembers) {
+		t.Errorf("Members = %+v\nwant %+v", diff.Members, wantMembers)
+	}
+
+	edge := func(deps []types.Dependency) []string {
+		var names []string
+		for _, d := range deps {
+			names = append(names, d.From+"->"+d.To)
+		}
+		return names
+	}
+	if got := edge(diff.AddedEdges); !reflect.DeepEqual(got, []string{"Service->Logger"}) {
+		t.Errorf("AddedEdges = %v", got)
+	}
+	if got := edge(diff.RemovedEdges); !reflect.DeepEqual(got, []string{"Repo->Service", "Service->Cache"}) {
+		t.Errorf("RemovedEdges = %v", got)
+	}
+
+	if !reflect.DeepEqual(diff.DepthChanges, []types.DepthChange{{Struct: "Repo", Old: 1, New: 2}}) {
+		t.Errorf("DepthChanges = %+v", diff.DepthChanges)
+	}
+	if len(diff.NewCycles) != 0 || !reflect.DeepEqual(diff.ResolvedCycles, [][]string{{"Repo", "Service"}}) {
+		t.Errorf("NewCycles = %v, ResolvedCycles = %v", diff.NewCycles, diff.ResolvedCycles)
+	}
+
+	wantMetrics := []types.MetricDelta{
+		{Scope: types.MetricScopeStruct, Subject: "Service", Metric: "methods", Old: 1, New: 2},
+		{Scope: types.MetricScopeStruct, Subject: "Service", Metric: "pagerank", Old: 0.2, New: 0.15},
+		{Scope: types.MetricScopeStruct, Subject: "Service", Metric: "betweenness", Old: 0, New: 0.5},
+		{Scope: types.MetricScopePackage, Subject: "example.com/app", Metric: "instability", Old: 0.5, New: 0.25},
+	}
+	if !reflect.DeepEqual(diff.MetricDeltas, wantMetrics) {
+		t.Errorf("MetricDeltas = %+v\nwant %+v", diff.MetricDeltas, wantMetrics)
+	}
+
+	same := DiffResults(oldResult, oldResult)
+	if len(same.Members)+len(same.AddedEdges)+len(same.RemovedEdges)+len(same.MetricDeltas)+len(same.Warnings) != 0 {
+		t.Errorf("diffing a result with itself should be empty, got %+v", same)
+	}
+}
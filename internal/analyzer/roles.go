@@ -0,0 +1,282 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// DefaultRoleMinScore 判定角色所需的默认最低得分
+const DefaultRoleMinScore = 2
+
+// 角色线索的得分
+const (
+	roleScoreStrong = 3 // 名称、实现的接口、方法、字段类型
+	roleScoreWeak   = 2 // 包路径、字段标签
+)
+
+// DefaultRoleRules 返回内置的角色判定规则，同分时靠前的规则胜出
+func DefaultRoleRules() []types.RoleRule {
+	return []types.RoleRule{
+		{
+			Role:       types.RoleHandler,
+			Names:      []string{"*Handler", "*Controller", "*Endpoint"},
+			Packages:   []string{"re:(^|/)(handlers?|controllers?|api|http|web|rest|transport)$"},
+			Implements: []string{"*Handler", "*Controller"},
+			Methods:    []string{"ServeHTTP"},
+		},
+		{
+			Role:       types.RoleService,
+			Names:      []string{"*Service", "*UseCase", "*Usecase", "*Interactor"},
+			Packages:   []string{"re:(^|/)(services?|usecases?|biz|logic)$"},
+			Implements: []string{"*Service", "*UseCase", "*Usecase"},
+		},
+		{
+			Role:       types.RoleRepository,
+			Names:      []string{"*Repository", "*Repo", "*Store", "*DAO", "*Dao"},
+			Packages:   []string{"re:(^|/)(repository|repositories|repo|store|storage|dao|persistence)$"},
+			Implements: []string{"*Repository", "*Repo", "*Store"},
+			FieldTypes: []string{"sql.DB", "sql.Tx", "sql.Conn", "sqlx.DB", "sqlx.Tx", "gorm.DB", "pgxpool.Pool", "pgx.Conn",
+				"mongo.Collection", "mongo.Database", "redis.Client", "bun.DB"},
+		},
+		{
+			Role:     types.RoleModel,
+			Names:    []string{"*Entity", "*Model"},
+			Packages: []string{"re:(^|/)(models?|entity|entities|domain)$"},
+			Tags:     []string{"gorm", "db", "bson"},
+		},
+		{
+			Role:     types.RoleDTO,
+			Names:    []string{"*Request", "*Response", "*Req", "*Resp", "*DTO", "*Dto", "*Payload"},
+			Packages: []string{"re:(^|/)dtos?$"},
+			Tags:     []string{"json", "form", "query"},
+		},
+	}
+}
+
+// RoleClassifier 按注释指令和启发式规则判定结构体的架构角色
+type RoleClassifier struct {
+	parser   *parser.Parser
+	rules    []compiledRoleRule
+	roles    []string // 全部可用角色（内置角色在前）
+	minScore int
+	cache    map[string]roleMatch // 导入路径.结构体名 -> 判定结果
+}
+
+// compiledRoleRule 表示一条已编译的角色规则
+type compiledRoleRule struct {
+	role       string
+	names      []*pattern
+	packages   []*pattern
+	implements []*pattern
+	methods    []*pattern
+	fieldTypes []*pattern
+	tags       []string
+}
+
+// roleMatch 表示一个结构体的角色判定结果
+type roleMatch struct {
+	role   string
+	reason string
+}
+
+// NewRoleClassifier 编译角色规则：自定义规则在前，内置规则在后；p 为 nil 时只校验配置
+func NewRoleClassifier(p *parser.Parser, config types.RoleConfig) (*RoleClassifier, error) {
+	c := &RoleClassifier{
+		parser:   p,
+		minScore: config.MinScore,
+		cache:    make(map[string]roleMatch),
+	}
+	if c.minScore <= 0 {
+		c.minScore = DefaultRoleMinScore
+	}
+
+	rules := append([]types.RoleRule{}, config.Rules...)
+	if !config.NoDefaults {
+		rules = append(rules, DefaultRoleRules()...)
+	}
+
+	known := make(map[string]bool)
+	for _, role := range []string{types.RoleHandler, types.RoleService, types.RoleRepository, types.RoleModel, types.RoleDTO} {
+		known[role] = true
+		c.roles = append(c.roles, role)
+	}
+	for i, r := range rules {
+		if r.Role == "" {
+			return nil, fmt.Errorf("rule #%d: role is required", i+1)
+		}
+		if !known[r.Role] {
+			known[r.Role] = true
+			c.roles = append(c.roles, r.Role)
+		}
+
+		rule := compiledRoleRule{role: r.Role, tags: r.Tags}
+		for _, group := range []struct {
+			raw []string
+			dst *[]*pattern
+		}{
+			{r.Names, &rule.names},
+			{r.Packages, &rule.packages},
+			{r.Implements, &rule.implements},
+			{r.Methods, &rule.methods},
+			{r.FieldTypes, &rule.fieldTypes},
+		} {
+			for _, raw := range group.raw {
+				pat, err := compilePattern(raw)
+				if err != nil {
+					return nil, fmt.Errorf("role %q: %w", r.Role, err)
+				}
+				*group.dst = append(*group.dst, pat)
+			}
+		}
+		c.rules = append(c.rules, rule)
+	}
+	return c, nil
+}
+
+// Roles 返回全部可用角色：内置角色和自定义规则中出现的角色
+func (c *RoleClassifier) Roles() []string {
+	return c.roles
+}
+
+// ValidateRoles 检查角色名是否都是可用角色
+func (c *RoleClassifier) ValidateRoles(roles []string) error {
+	for _, role := range roles {
+		if !containsString(c.roles, role) {
+			return fmt.Errorf("unknown role %q (available: %s)", role, strings.Join(c.roles, ", "))
+		}
+	}
+	return nil
+}
+
+// RoleOf 返回项目内结构体的角色，不是结构体或无法判定时返回空字符串
+func (c *RoleClassifier) RoleOf(typeName string) string {
+	info := c.parser.GetStruct(typeName)
+	if info == nil {
+		return ""
+	}
+	role, _ := c.Classify(info)
+	return role
+}
+
+// Classify 判定结构体的角色并给出依据
+// 源码注释优先；否则对每条规则累计命中线索的得分，取得分最高且不低于最低得分的规则
+func (c *RoleClassifier) Classify(info *types.StructInfo) (role, reason string) {
+	key := info.ImportPath + "." + info.Name
+	if m, ok := c.cache[key]; ok {
+		return m.role, m.reason
+	}
+
+	m := roleMatch{}
+	if info.Annotations.Role != "" {
+		m = roleMatch{role: info.Annotations.Role, reason: "源码注释"}
+	} else {
+		implemented := c.implementedInterfaces(info)
+		best := 0
+		for _, rule := range c.rules {
+			score, clues := rule.score(info, implemented)
+			if score > best && score >= c.minScore {
+				best = score
+				m = roleMatch{role: rule.role, reason: strings.Join(clues, "；")}
+			}
+		}
+	}
+	c.cache[key] = m
+	return m.role, m.reason
+}
+
+// implementedInterfaces 返回结构体实现的项目接口（包名.接口名），只比较方法名
+func (c *RoleClassifier) implementedInterfaces(info *types.StructInfo) []string {
+	methods := make(map[string]bool, len(info.Methods))
+	for _, m := range info.Methods {
+		methods[m.Name] = true
+	}
+
+	var result []string
+	for _, iface := range c.parser.GetAllInterfaces() {
+		if len(iface.Methods) == 0 {
+			continue
+		}
+		implemented := true
+		for _, m := range iface.Methods {
+			if !methods[m.Name] {
+				implemented = false
+				break
+			}
+		}
+		if implemented {
+			result = append(result, iface.Package+"."+iface.Name)
+		}
+	}
+	return result
+}
+
+// score 计算规则对结构体的得分，并返回命中的线索
+func (r compiledRoleRule) score(info *types.StructInfo, implemented []string) (int, []string) {
+	score := 0
+	var clues []string
+
+	if matchAny(r.names, info.Package+"."+info.Name, true) {
+		score += roleScoreStrong
+		clues = append(clues, "名称 "+info.Name)
+	}
+	for _, iface := range implemented {
+		if matchAny(r.implements, iface, true) {
+			score += roleScoreStrong
+			clues = append(clues, "实现接口 "+shortTypeName(iface))
+			break
+		}
+	}
+	for _, m := range info.Methods {
+		if matchAny(r.methods, m.Name, false) {
+			score += roleScoreStrong
+			clues = append(clues, "方法 "+m.Name)
+			break
+		}
+	}
+	for _, f := range info.Fields {
+		if matchAny(r.fieldTypes, strings.TrimLeft(f.Type, "*[]"), true) {
+			score += roleScoreStrong
+			clues = append(clues, "字段类型 "+f.Type)
+			break
+		}
+	}
+
+	pkgPath := info.ImportPath
+	if pkgPath == "" {
+		pkgPath = info.Package
+	}
+	if matchAny(r.packages, pkgPath, false) {
+		score += roleScoreWeak
+		clues = append(clues, "包 "+pkgPath)
+	}
+	if tag := r.matchTag(info.Fields); tag != "" {
+		score += roleScoreWeak
+		clues = append(clues, "标签 "+tag)
+	}
+	return score, clues
+}
+
+// matchTag 返回字段上出现的第一个规则标签键，没有时返回空字符串
+func (r compiledRoleRule) matchTag(fields []types.FieldInfo) string {
+	for _, key := range r.tags {
+		for _, f := range fields {
+			if _, ok := f.Tags.Values[key]; ok {
+				return key
+			}
+		}
+	}
+	return ""
+}
+
+// matchAny 判断字符串是否匹配任一模式；isType 为 true 时按类型名匹配（同时尝试不带包前缀的短名）
+func matchAny(patterns []*pattern, s string, isType bool) bool {
+	for _, pat := range patterns {
+		if isType && pat.matchType(s) || !isType && pat.match(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// roleProject 创建包含各种角色线索的测试项目
+func roleProject() map[string]string {
+	return map[string]string{
+		"go.mod": "module example.com/app\n",
+		"internal/web/router.go": `package web
+
+import "net/http"
+
+type Router struct{}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {}
+
+type CreateUserRequest struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`,
+		"internal/app/users.go": `package app
+
+import "database/sql"
+
+type UserFinder interface {
+	FindUser(id int64) error
+}
+
+type Users struct {
+	db *sql.DB
+}
+
+func (u *Users) FindUser(id int64) error { return nil }
+
+type Billing struct {
+	users *Users
+}
+
+type Account struct {
+	ID int64 ` + "`gorm:\"primaryKey\" json:\"id\"`" + `
+}
+
+type Plain struct {
+	n int
+}
+
+//structanalyzer:role=service
+type Clock struct{}
+`,
+		"internal/service/billing.go": `package service
+
+type Mailer struct{}
+`,
+	}
+}
+
+func TestRoleClassifier_Classify(t *testing.T) {
+	p, _ := writeTestProject(t, roleProject())
+	c, err := NewRoleClassifier(p, types.RoleConfig{})
+	if err != nil {
+		t.Fatalf("NewRoleClassifier failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Router", types.RoleHandler},        // 方法 ServeHTTP
+		{"CreateUserRequest", types.RoleDTO}, // 名称 *Request + json 标签
+		{"Users", types.RoleRepository},      // 字段类型 *sql.DB
+		{"Account", types.RoleModel},         // gorm 与 json 标签同分，model 规则在前
+		{"Mailer", types.RoleService},        // 包路径 service
+		{"Clock", types.RoleService},         // 源码注释
+		{"Plain", ""},                        // 没有任何线索
+		{"Billing", ""},                      // 没有任何线索
+	}
+	for _, tt := range tests {
+		if got := c.RoleOf(tt.name); got != tt.want {
+			_, reason := c.Classify(p.GetStruct(tt.name))
+			t.Errorf("RoleOf(%s) = %q (%s), want %q", tt.name, got, reason, tt.want)
+		}
+	}
+
+	if _, reason := c.Classify(p.GetStruct("Users")); reason != "字段类型 *sql.DB" {
+		t.Errorf("Users reason = %q", reason)
+	}
+}
+
+func TestRoleClassifier_CustomRules(t *testing.T) {
+	p, _ := writeTestProject(t, roleProject())
+	c, err := NewRoleClassifier(p, types.RoleConfig{
+		Rules: []types.RoleRule{
+			{Role: "finder", Implements: []string{"*Finder"}},
+			{Role: types.RoleService, Names: []string{"Billing"}},
+		},
+		NoDefaults: true,
+		MinScore:   3,
+	})
+	if err != nil {
+		t.Fatalf("NewRoleClassifier failed: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"Users":   "finder",          // 实现 UserFinder
+		"Billing": types.RoleService, // 自定义名称规则
+		"Mailer":  "",                // 不使用内置规则
+		"Router":  "",                // 不使用内置规则
+	} {
+		if got := c.RoleOf(name); got != want {
+			t.Errorf("RoleOf(%s) = %q, want %q", name, got, want)
+		}
+	}
+
+	if err := c.ValidateRoles([]string{"finder", types.RoleDTO}); err != nil {
+		t.Errorf("ValidateRoles: %v", err)
+	}
+	if err := c.ValidateRoles([]string{"gateway"}); err == nil {
+		t.Error("expected error for unknown role")
+	}
+	if _, err := NewRoleClassifier(nil, types.RoleConfig{Rules: []types.RoleRule{{Names: []string{"*X"}}}}); err == nil {
+		t.Error("expected error for rule without role")
+	}
+	if _, err := NewRoleClassifier(nil, types.RoleConfig{Rules: []types.RoleRule{{Role: "x", Names: []string{"re:("}}}}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestLayerChecker_Roles(t *testing.T) {
+	p, _ := writeTestProject(t, roleProject())
+	checker, err := NewLayerChecker(p, types.ArchitectureConfig{
+		Layers: []types.LayerConfig{
+			{Name: "api", Roles: []string{types.RoleHandler, types.RoleDTO}},
+			{Name: "core", Structs: []string{"Users"}, Roles: []string{types.RoleService}},
+			{Name: "data", Roles: []string{types.RoleRepository}, Packages: []string{".../app"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewLayerChecker failed: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"Router":            "api",
+		"CreateUserRequest": "api",
+		"Users":             "core", // 类型名模式优先于角色
+		"Mailer":            "core",
+		"Plain":             "data", // 无角色时按包路径
+	} {
+		if got := checker.LayerOf(name); got != want {
+			t.Errorf("LayerOf(%s) = %q, want %q", name, got, want)
+		}
+	}
+
+	custom, _ := NewRoleClassifier(p, types.RoleConfig{Rules: []types.RoleRule{{Role: types.RoleHandler, Names: []string{"Mailer"}}}})
+	checker.SetRoleClassifier(custom)
+	if got := checker.LayerOf("Mailer"); got != "api" {
+		t.Errorf("LayerOf(Mailer) with custom roles = %q, want api", got)
+	}
+}
+
+// fakeRoleClient 总是判定为同一角色并统计调用次数的 LLM 客户端
+type fakeRoleClient struct {
+	role  string
+	calls int
+}
+
+func (c *fakeRoleClient) AnalyzeStruct(info *types.StructInfo) (*types.LLMAnalysisResult, error) {
+	return &types.LLMAnalysisResult{}, nil
+}
+
+func (c *fakeRoleClient) ClassifyRole(info *types.StructInfo, roles []string) (string, error) {
+	c.calls++
+	return c.role, nil
+}
+
+func (c *fakeRoleClient) IsConfigured() bool { return true }
+func (c *fakeRoleClient) Name() string       { return "fake" }
+func (c *fakeRoleClient) Model() string      { return "fake-model" }
+
+func TestTraverser_RoleLLMCache(t *testing.T) {
+	p, dir := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"widget/widget.go": `package widget
+
+type Widget struct {
+	size int
+}
+`,
+	})
+	roles, err := NewRoleClassifier(p, types.RoleConfig{})
+	if err != nil {
+		t.Fatalf("NewRoleClassifier failed: %v", err)
+	}
+	client := &fakeRoleClient{role: types.RoleService}
+	cache := NewAnalysisCache(dir)
+
+	for run := 0; run < 2; run++ {
+		traverser := NewTraverser(p, NewScopeFilter(p, NewBlacklist()), client, false)
+		traverser.SetRoles(roles, true)
+		traverser.SetCache(cache)
+		s := traverser.Analyze("Widget", 1, dir).Structs[0]
+		if s.Role != types.RoleService {
+			t.Errorf("run %d: role = %q, want %q", run, s.Role, types.RoleService)
+		}
+	}
+	if client.calls != 1 {
+		t.Errorf("ClassifyRole called %d times, want 1 (second run should hit the cache)", client.calls)
+	}
+}
@@ -2,9 +2,11 @@ package analyzer
 
 import (
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/user/go-struct-analyzer/internal/parser"
+	"github.com/user/go-struct-analyzer/internal/types"
 )
 
 // ScopeFilter 用于过滤分析范围
@@ -13,15 +15,22 @@ type ScopeFilter struct {
 	blacklist       *Blacklist
 	projectPackages []string
 	moduleName      string
+	externalOpts    ExternalOptions
+	stdlib          *stdlibSet                     // 标准库包集合（为 nil 时从 GOROOT 加载）
+	externals       map[string]*types.ExternalType // 已登记的第三方类型
+	externalsMu     sync.Mutex                     // 保护 externals（遍历器可能并发解析依赖）
 }
 
-// NewScopeFilter 创建范围过滤器
+// NewScopeFilter 创建范围过滤器；p 为 nil 时只按黑名单和类型名判断
 func NewScopeFilter(p *parser.Parser, blacklist *Blacklist) *ScopeFilter {
 	sf := &ScopeFilter{
-		parser:     p,
-		blacklist:  blacklist,
-		moduleName: p.GetModuleName(),
+		parser:    p,
+		blacklist: blacklist,
 	}
+	if p == nil {
+		return sf
+	}
+	sf.moduleName = p.GetModuleName()
 
 	// 收集项目内的所有包
 	sf.collectProjectPackages()
@@ -44,9 +53,21 @@ func (sf *ScopeFilter) collectProjectPackages() {
 
 // ShouldAnalyze 判断类型是否应该被分析
 func (sf *ScopeFilter) ShouldAnalyze(typeName string) bool {
+	return sf.ShouldAnalyzeInFile(typeName, "")
+}
+
+// ShouldAnalyzeInFile 判断类型是否应该被分析
+// filePath 为引用该类型的源文件，用于通过导入表解析包别名；为空时在全项目导入中查找
+func (sf *ScopeFilter) ShouldAnalyzeInFile(typeName, filePath string) bool {
+	accepted, _, _ := sf.Classify(typeName, filePath)
+	return accepted
+}
+
+// Classify 判断类型是否应该被分析，并返回命中的判定规则（types.Decision*）和说明
+func (sf *ScopeFilter) Classify(typeName, filePath string) (accepted bool, rule, detail string) {
 	// 0. 跳过空类型名
 	if typeName == "" {
-		return false
+		return false, types.DecisionEmpty, "无法推断类型"
 	}
 
 	// 1. 清理类型名
@@ -55,22 +76,26 @@ func (sf *ScopeFilter) ShouldAnalyze(typeName string) bool {
 
 	// 去掉 map 的类型
 	if strings.HasPrefix(typeName, "map[") {
-		return false
+		return false, types.DecisionMap, "map 类型不展开"
 	}
 
 	// 2. 跳过基础类型
 	if isBuiltinType(typeName) {
-		return false
+		return false, types.DecisionBuiltin, "内置类型"
 	}
 
 	// 3. 跳过标准库类型
-	if isStandardLibrary(typeName) {
-		return false
+	if sf.isStandardLibrary(typeName, filePath) {
+		detail := "标准库类型"
+		if importPath := sf.resolveImportPath(typeName, filePath); importPath != "" {
+			detail += "，导入路径 " + importPath
+		}
+		return false, types.DecisionStdlib, detail
 	}
 
-	// 4. 检查黑名单
-	if sf.blacklist != nil && sf.blacklist.IsBlocked(typeName) {
-		return false
+	// 4. 检查黑名单（包括导入路径模式）
+	if sf.blacklist != nil && sf.blacklist.IsBlockedPath(typeName, sf.typeImportPath(typeName, filePath)) {
+		return false, types.DecisionBlacklist, "命中黑名单"
 	}
 
 	// 5. 验证类型名格式：Go 导出类型必须首字母大写
@@ -80,23 +105,33 @@ func (sf *ScopeFilter) ShouldAnalyze(typeName string) bool {
 		baseTypeName = typeName[idx+1:]
 	}
 	if len(baseTypeName) > 0 && !unicode.IsUpper(rune(baseTypeName[0])) {
-		return false
+		return false, types.DecisionLowercase, "首字母小写，可能是变量名或未导出类型"
 	}
 
-	// 6. 检查是否为项目内部类型
-	return sf.isInternalType(typeName)
+	// 6. 包别名解析到项目外的导入路径时，不是内部类型
+	if importPath := sf.resolveImportPath(typeName, filePath); importPath != "" && !sf.isProjectImportPath(importPath) {
+		return false, types.DecisionNonProject, "导入路径 " + importPath + " 不属于当前模块"
+	}
+
+	// 7. 检查是否为项目内部类型
+	if !sf.isInternalType(typeName) {
+		return false, types.DecisionNotFound, "项目中未找到该结构体或接口"
+	}
+
+	// 8. 源码中标注了 //structanalyzer:ignore 的类型不参与分析
+	if sf.parser != nil && sf.parser.GetAnnotations(typeName).Ignore {
+		return false, types.DecisionIgnored, "源码标注了 //structanalyzer:ignore"
+	}
+
+	return true, types.DecisionAccepted, "项目内部类型"
 }
 
 // isInternalType 判断是否为项目内部类型
 func (sf *ScopeFilter) isInternalType(typeName string) bool {
 	// 如果类型名不包含点，可能是当前包的类型
 	if !strings.Contains(typeName, ".") {
-		// 检查是否在已知结构体中
-		if sf.parser.GetStruct(typeName) != nil {
-			return true
-		}
-		// 检查是否在已知接口中
-		if sf.parser.GetInterface(typeName) != nil {
+		// 检查是否在已知结构体或接口中
+		if sf.hasType(typeName) {
 			return true
 		}
 		// 如果在已知包中，也认为是内部类型
@@ -124,18 +159,21 @@ func (sf *ScopeFilter) isInternalType(typeName string) bool {
 	}
 
 	// 如果不包含 "/" 且解析器能找到该结构体或接口，认为是内部类型
-	if !strings.Contains(typeName, "/") {
-		if sf.parser.GetStruct(typeName) != nil {
-			return true
-		}
-		if sf.parser.GetInterface(typeName) != nil {
-			return true
-		}
+	if !strings.Contains(typeName, "/") && sf.hasType(typeName) {
+		return true
 	}
 
 	return false
 }
 
+// hasType 判断解析器中是否有该名称的结构体或接口
+func (sf *ScopeFilter) hasType(typeName string) bool {
+	if sf.parser == nil {
+		return false
+	}
+	return sf.parser.GetStruct(typeName) != nil || sf.parser.GetInterface(typeName) != nil
+}
+
 // isBuiltinType 判断是否为内置类型
 func isBuiltinType(typeName string) bool {
 	builtins := map[string]bool{
@@ -167,67 +205,181 @@ func isBuiltinType(typeName string) bool {
 }
 
 // isStandardLibrary 判断是否为标准库类型
-func isStandardLibrary(typeName string) bool {
-	stdLibs := []string{
-		"context",
-		"net",
-		"io",
-		"os",
-		"fmt",
-		"time",
-		"sync",
-		"errors",
-		"strings",
-		"bytes",
-		"http",
-		"json",
-		"xml",
-		"sql",
-		"crypto",
-		"encoding",
-		"bufio",
-		"log",
-		"path",
-		"filepath",
-		"regexp",
-		"sort",
-		"strconv",
-		"testing",
-		"reflect",
-		"runtime",
-		"unsafe",
-		"math",
-		"flag",
-		"html",
-		"image",
-		"mime",
-		"template",
-		"text",
-		"unicode",
-		"archive",
-		"compress",
-		"container",
-		"database",
-		"debug",
-		"embed",
-		"expvar",
-		"go",
-		"hash",
-		"index",
-		"plugin",
-		"syscall",
-	}
-
-	// 检查是否以标准库包名开头
-	parts := strings.Split(typeName, ".")
-	if len(parts) > 0 {
-		basePkg := parts[0]
-		for _, std := range stdLibs {
-			if basePkg == std {
-				return true
-			}
+// 先通过导入表把包别名解析为导入路径再按 GOROOT 包列表判断；
+// 与项目包同名的别名（如项目内的 index、debug 包）不会被当作标准库
+func (sf *ScopeFilter) isStandardLibrary(typeName, filePath string) bool {
+	idx := strings.LastIndex(typeName, ".")
+	if idx <= 0 {
+		return false
+	}
+	alias := typeName[:idx]
+
+	if importPath := sf.resolveImportPath(typeName, filePath); importPath != "" {
+		return isStandardImport(sf.parser, sf.stdlibSet(), importPath)
+	}
+
+	// 无法解析导入路径：项目包优先，其次按标准库包名判断
+	for _, pkg := range sf.projectPackages {
+		if pkg == alias {
+			return false
 		}
 	}
+	return sf.stdlibSet().isStandardPackageName(alias)
+}
 
-	return false
+// stdlibSet 返回标准库包集合
+func (sf *ScopeFilter) stdlibSet() *stdlibSet {
+	if sf.stdlib == nil {
+		sf.stdlib = loadStdlib()
+	}
+	return sf.stdlib
+}
+
+// resolveImportPath 将类型的包别名解析为导入路径，无法解析时返回空字符串
+func (sf *ScopeFilter) resolveImportPath(typeName, filePath string) string {
+	idx := strings.LastIndex(typeName, ".")
+	if idx <= 0 || sf.parser == nil {
+		return ""
+	}
+	alias := typeName[:idx]
+
+	if filePath != "" {
+		return sf.parser.GetImports(filePath)[alias]
+	}
+
+	// 没有文件上下文时，在全项目的导入中查找该别名；多个导入路径使用同一别名时无法确定，不做猜测
+	if paths := sf.parser.ResolveImportAlias(alias); len(paths) == 1 {
+		return paths[0]
+	}
+	return ""
+}
+
+// typeImportPath 获取类型所属包的导入路径
+// 带包前缀的类型通过导入表解析，项目内的短类型名通过解析器记录的结构体/接口查找
+func (sf *ScopeFilter) typeImportPath(typeName, filePath string) string {
+	if strings.Contains(typeName, ".") {
+		return sf.resolveImportPath(typeName, filePath)
+	}
+	if sf.parser == nil {
+		return ""
+	}
+	if info := sf.parser.GetStruct(typeName); info != nil {
+		return info.ImportPath
+	}
+	if info := sf.parser.GetInterface(typeName); info != nil {
+		return info.ImportPath
+	}
+	return ""
+}
+
+// AllowDependency 检查依赖边是否被按依赖类型生效的黑名单规则忽略
+func (sf *ScopeFilter) AllowDependency(dep types.Dependency) bool {
+	if sf.blacklist == nil {
+		return true
+	}
+
+	importPath := ""
+	if ext := sf.GetExternal(dep.To); dep.External && ext != nil {
+		importPath = ext.ImportPath
+	} else {
+		importPath = sf.typeImportPath(dep.To, "")
+	}
+	return !sf.blacklist.IsEdgeBlocked(dep.To, importPath, dep.Type)
+}
+
+// ShouldStop 检查类型是否命中 stop 规则或标注了 //structanalyzer:stop：保留该节点，但不再遍历它的依赖
+func (sf *ScopeFilter) ShouldStop(typeName string) bool {
+	if sf.parser != nil && sf.parser.GetAnnotations(typeName).Stop {
+		return true
+	}
+	if sf.blacklist == nil {
+		return false
+	}
+	return sf.blacklist.IsStopped(typeName, sf.typeImportPath(typeName, ""))
+}
+
+// isProjectImportPath 判断导入路径是否属于当前模块
+func (sf *ScopeFilter) isProjectImportPath(importPath string) bool {
+	return sf.moduleName != "" && (importPath == sf.moduleName || strings.HasPrefix(importPath, sf.moduleName+"/"))
+}
+
+// ExternalOptions 第三方模块类型的处理选项
+type ExternalOptions struct {
+	Enabled   bool   // 是否将第三方模块类型保留为叶子节点
+	UseVendor bool   // 是否从 vendor/ 读取结构体定义
+	ModCache  string // 本地 GOMODCACHE 目录（为空则不读取）
+}
+
+// SetExternalOptions 设置第三方模块类型的处理选项
+func (sf *ScopeFilter) SetExternalOptions(opts ExternalOptions) {
+	sf.externalOpts = opts
+	sf.externalsMu.Lock()
+	defer sf.externalsMu.Unlock()
+	if sf.externals == nil {
+		sf.externals = make(map[string]*types.ExternalType)
+	}
+}
+
+// IncludeExternal 是否启用第三方类型叶子节点模式
+func (sf *ScopeFilter) IncludeExternal() bool {
+	return sf.externalOpts.Enabled
+}
+
+// ResolveExternal 判断类型是否为第三方模块类型
+// filePath 为引用该类型的源文件，用于解析包别名；返回登记后的外部类型
+func (sf *ScopeFilter) ResolveExternal(typeName, filePath string) *types.ExternalType {
+	if !sf.externalOpts.Enabled || sf.parser == nil {
+		return nil
+	}
+
+	qualified := parser.ExtractQualifiedType(typeName)
+	idx := strings.LastIndex(qualified, ".")
+	if idx <= 0 {
+		return nil
+	}
+	alias, name := qualified[:idx], qualified[idx+1:]
+	if name == "" || !unicode.IsUpper(rune(name[0])) {
+		return nil
+	}
+
+	importPath := sf.parser.GetImports(filePath)[alias]
+	if importPath == "" {
+		return nil
+	}
+	module, version := sf.parser.ModuleForImport(importPath)
+	if module == "" {
+		return nil
+	}
+	if sf.blacklist != nil && sf.blacklist.IsBlockedPath(qualified, importPath) {
+		return nil
+	}
+
+	sf.externalsMu.Lock()
+	defer sf.externalsMu.Unlock()
+	if ext, ok := sf.externals[qualified]; ok {
+		return ext
+	}
+
+	ext := &types.ExternalType{
+		Name:       qualified,
+		ImportPath: importPath,
+		Module:     module,
+		Version:    version,
+	}
+	if sf.externalOpts.UseVendor || sf.externalOpts.ModCache != "" {
+		if info, source := sf.parser.LoadExternalStruct(importPath, name, sf.externalOpts.UseVendor, sf.externalOpts.ModCache); info != nil {
+			ext.Fields = info.Fields
+			ext.Source = source
+		}
+	}
+	sf.externals[qualified] = ext
+
+	return ext
+}
+
+// GetExternal 获取已登记的第三方类型
+func (sf *ScopeFilter) GetExternal(name string) *types.ExternalType {
+	sf.externalsMu.Lock()
+	defer sf.externalsMu.Unlock()
+	return sf.externals[name]
 }
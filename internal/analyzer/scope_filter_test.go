@@ -2,6 +2,8 @@ package analyzer
 
 import (
 	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
 )
 
 func TestIsBuiltinType(t *testing.T) {
@@ -48,9 +50,11 @@ func TestIsStandardLibrary(t *testing.T) {
 		{"empty", "", false},
 	}
 
+	sf := &ScopeFilter{blacklist: NewBlacklist()}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := isStandardLibrary(tt.typeName)
+			result := sf.isStandardLibrary(tt.typeName, "")
 			if result != tt.expected {
 				t.Errorf("isStandardLibrary(%q) = %v, want %v", tt.typeName, result, tt.expected)
 			}
@@ -58,6 +62,141 @@ func TestIsStandardLibrary(t *testing.T) {
 	}
 }
 
+func TestIsStandardImportPath(t *testing.T) {
+	std := newStdlibSet([]string{"context", "net/http", "encoding/json", "index/suffixarray", "internal/abi"})
+	tests := []struct {
+		importPath string
+		expected   bool
+	}{
+		{"context", true},
+		{"net/http", true},
+		{"encoding/json", true},
+		{"index/suffixarray", true},
+		{"index", false},
+		{"debug", false},
+		{"github.com/foo/bar", false},
+		{"gorm.io/gorm", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			if got := std.isStandardImportPath(tt.importPath); got != tt.expected {
+				t.Errorf("isStandardImportPath(%q) = %v, want %v", tt.importPath, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsStandardImport_Fallback(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": "module myapp\n\nrequire tools v1.0.0\n",
+		"index/index.go": `package index
+
+type Entry struct{}
+`,
+	})
+
+	// 没有标准库包列表时按首段判断，但项目模块和 require 的模块优先
+	std := newStdlibSet(nil)
+	for importPath, want := range map[string]bool{
+		"context":            true,
+		"myapp":              false,
+		"myapp/index":        false,
+		"tools/cmd":          false,
+		"github.com/foo/bar": false,
+	} {
+		if got := isStandardImport(p, std, importPath); got != want {
+			t.Errorf("isStandardImport(%q) = %v, want %v", importPath, got, want)
+		}
+	}
+
+	if !newStdlibSet([]string{"encoding/json"}).isStandardPackageName("json") {
+		t.Error("package names should come from the injected set")
+	}
+	if newStdlibSet([]string{"internal/abi"}).isStandardPackageName("abi") {
+		t.Error("internal packages should not be treated as stdlib package names")
+	}
+}
+
+func TestScopeFilter_AmbiguousAlias(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"a/a.go": `package a
+
+import "example.com/app/v1/client"
+
+type A struct{ c *client.Conn }
+`,
+		"b/b.go": `package b
+
+import "example.com/app/v2/client"
+
+type B struct{ c *client.Conn }
+`,
+		"v1/client/client.go": "package client\n\ntype Conn struct{}\n",
+		"v2/client/client.go": "package client\n\ntype Conn struct{}\n",
+	})
+
+	sf := NewScopeFilter(p, NewBlacklist())
+	if got := sf.resolveImportPath("client.Conn", ""); got != "" {
+		t.Errorf("ambiguous alias should not resolve, got %q", got)
+	}
+	if got := sf.resolveImportPath("client.Conn", p.GetStruct("A").FilePath); got != "example.com/app/v1/client" {
+		t.Errorf("alias with file context = %q", got)
+	}
+}
+
+func TestScopeFilter_ProjectPackageNamedLikeStdlib(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": "module example.com/app\n",
+		"index/index.go": `package index
+
+type Entry struct{}
+`,
+		"debug/debug.go": `package debug
+
+type Probe struct{}
+`,
+		"app/app.go": `package app
+
+import (
+	"example.com/app/debug"
+	"example.com/app/index"
+	"text/template"
+)
+
+type App struct {
+	entry *index.Entry
+	probe debug.Probe
+	tmpl  *template.Template
+}
+`,
+	})
+
+	filter := NewScopeFilter(p, NewBlacklist())
+	appFile := p.GetStruct("App").FilePath
+
+	if !filter.ShouldAnalyzeInFile("index.Entry", appFile) {
+		t.Error("project package index must not be treated as standard library")
+	}
+	if !filter.ShouldAnalyzeInFile("debug.Probe", appFile) {
+		t.Error("project package debug must not be treated as standard library")
+	}
+	if filter.ShouldAnalyzeInFile("template.Template", appFile) {
+		t.Error("text/template must be treated as standard library")
+	}
+	// 没有文件上下文时同样通过项目导入解析别名
+	if !filter.ShouldAnalyze("index.Entry") {
+		t.Error("ShouldAnalyze(index.Entry) should resolve alias through project imports")
+	}
+
+	deps := NewDependencyAnalyzer(p, filter, false).AnalyzeStruct(p.GetStruct("App"))
+	if findDep(deps, "Entry", "field") == nil || findDep(deps, "Probe", "field") == nil {
+		t.Errorf("expected field deps to Entry and Probe, got %+v", deps)
+	}
+}
+
 func TestShouldAnalyze_EmptyTypeName(t *testing.T) {
 	sf := &ScopeFilter{
 		blacklist: NewBlacklist(),
@@ -202,3 +341,96 @@ func TestShouldAnalyze_PackageQualifiedTypes(t *testing.T) {
 		t.Error("ShouldAnalyze(\"model.user\") should return false for lowercase type")
 	}
 }
+
+func TestScopeFilter_ExternalTypes(t *testing.T) {
+	p, _ := writeTestProject(t, map[string]string{
+		"go.mod": `module example.com/app
+
+require (
+	gorm.io/gorm v1.25.0
+	github.com/redis/go-redis/v9 v9.0.5 // indirect
+)
+`,
+		"repo/repo.go": `package repo
+
+import (
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type Repo struct {
+	db    *gorm.DB
+	cache *redis.Client
+	ttl   time.Duration
+}
+`,
+		"vendor/gorm.io/gorm/gorm.go": `package gorm
+
+type DB struct {
+	Error        error
+	RowsAffected int64
+}
+`,
+	})
+
+	filter := NewScopeFilter(p, NewBlacklist())
+	da := NewDependencyAnalyzer(p, filter, false)
+
+	// 默认模式下外部类型被丢弃
+	deps := da.AnalyzeStruct(p.GetStruct("Repo"))
+	if len(deps) != 0 {
+		t.Errorf("expected no deps without external mode, got %+v", deps)
+	}
+
+	filter.SetExternalOptions(ExternalOptions{Enabled: true, UseVendor: true})
+	deps = da.AnalyzeStruct(p.GetStruct("Repo"))
+
+	gormDep := findDep(deps, "gorm.DB", "field")
+	if gormDep == nil || !gormDep.External {
+		t.Fatalf("expected external field dep to gorm.DB, got %+v", deps)
+	}
+	if dep := findDep(deps, "redis.Client", "field"); dep == nil || !dep.External {
+		t.Errorf("expected external field dep to redis.Client, got %+v", deps)
+	}
+	if findDep(deps, "time.Duration", "field") != nil {
+		t.Error("standard library types must not become external nodes")
+	}
+
+	gormType := filter.GetExternal("gorm.DB")
+	if gormType.Module != "gorm.io/gorm" || gormType.Version != "v1.25.0" {
+		t.Errorf("gorm.DB module = %s@%s", gormType.Module, gormType.Version)
+	}
+	if gormType.Source != "vendor" || len(gormType.Fields) != 2 {
+		t.Errorf("gorm.DB should be loaded from vendor with 2 fields, got source=%q fields=%d", gormType.Source, len(gormType.Fields))
+	}
+
+	redisType := filter.GetExternal("redis.Client")
+	if redisType.Module != "github.com/redis/go-redis/v9" || redisType.Source != "" {
+		t.Errorf("redis.Client module = %q source = %q", redisType.Module, redisType.Source)
+	}
+
+	// 类型名为空（如 "gorm."）时不登记
+	if ext := filter.ResolveExternal("gorm.", p.GetStruct("Repo").FilePath); ext != nil {
+		t.Errorf("ResolveExternal(gorm.) = %+v, want nil", ext)
+	}
+}
+
+func TestScopeFilter_NilParser(t *testing.T) {
+	bl := NewBlacklist()
+	if err := bl.AddRule(types.BlacklistRule{Type: "*Gateway", Action: types.BlacklistActionStop}); err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+	sf := NewScopeFilter(nil, bl)
+
+	if sf.ShouldAnalyze("User") {
+		t.Error("types cannot be resolved without a parser")
+	}
+	if !sf.ShouldStop("PaymentGateway") {
+		t.Error("blacklist stop rules should still apply without a parser")
+	}
+	if sf.ShouldAnalyze("context.Context") {
+		t.Error("standard library types should be filtered without a parser")
+	}
+}
@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/user/go-struct-analyzer/internal/parser"
+)
+
+var (
+	stdlibOnce sync.Once
+	// defaultStdlib 从 $GOROOT/src 加载的标准库包集合
+	defaultStdlib *stdlibSet
+)
+
+// stdlibSet 标准库包集合
+type stdlibSet struct {
+	packages map[string]bool // 标准库包导入路径
+	names    map[string]bool // 标准库包名（导入路径最后一段），用于无法解析别名时的兜底判断
+}
+
+// newStdlibSet 根据标准库包导入路径列表创建集合；列表为空时按导入路径首段是否包含 "." 判断
+func newStdlibSet(importPaths []string) *stdlibSet {
+	s := &stdlibSet{
+		packages: make(map[string]bool),
+		names:    make(map[string]bool),
+	}
+	for _, importPath := range importPaths {
+		s.packages[importPath] = true
+		parts := strings.Split(importPath, "/")
+		if !containsString(parts, "internal") {
+			s.names[parts[len(parts)-1]] = true
+		}
+	}
+	return s
+}
+
+// loadStdlib 扫描 $GOROOT/src 收集标准库包列表（离线可用，只加载一次）
+func loadStdlib() *stdlibSet {
+	stdlibOnce.Do(func() {
+		var importPaths []string
+		if root := goroot(); root != "" {
+			importPaths = scanStdlib(filepath.Join(root, "src"))
+		}
+		defaultStdlib = newStdlibSet(importPaths)
+	})
+	return defaultStdlib
+}
+
+// scanStdlib 返回 srcDir 下包含 Go 源文件的包的导入路径
+func scanStdlib(srcDir string) []string {
+	var importPaths []string
+	filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(srcDir, path)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		// 跳过 cmd（工具链）、vendor、testdata 以及隐藏目录
+		name := info.Name()
+		if rel == "cmd" || rel == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			return filepath.SkipDir
+		}
+
+		if hasGoFiles(path) {
+			importPaths = append(importPaths, rel)
+		}
+		return nil
+	})
+	return importPaths
+}
+
+// goroot 返回 Go 安装目录
+func goroot() string {
+	if root := os.Getenv("GOROOT"); root != "" {
+		return root
+	}
+	return runtime.GOROOT()
+}
+
+// hasGoFiles 判断目录中是否包含非测试 Go 源文件
+func hasGoFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			return true
+		}
+	}
+	return false
+}
+
+// isStandardImportPath 判断导入路径是否属于标准库
+// 集合非空时按标准库包列表判断，否则按首段是否包含 "." 判断
+func (s *stdlibSet) isStandardImportPath(importPath string) bool {
+	if importPath == "" {
+		return false
+	}
+	if len(s.packages) > 0 {
+		return s.packages[importPath]
+	}
+
+	first := strings.SplitN(importPath, "/", 2)[0]
+	return !strings.Contains(first, ".")
+}
+
+// isStandardPackageName 判断包名是否为某个标准库包的名称（无法解析导入路径时使用）
+func (s *stdlibSet) isStandardPackageName(pkgName string) bool {
+	return s.names[pkgName]
+}
+
+// isStandardImport 判断导入路径是否属于标准库
+// 属于项目模块或 go.mod 中 require 的模块时不是标准库（首段不含 "." 的模块路径如 myapp 不会被误判），其余按标准库包集合判断
+func isStandardImport(p *parser.Parser, std *stdlibSet, importPath string) bool {
+	if p != nil {
+		if module := p.GetModuleName(); module != "" && (importPath == module || strings.HasPrefix(importPath, module+"/")) {
+			return false
+		}
+		if module, _ := p.ModuleForImport(importPath); module != "" {
+			return false
+		}
+	}
+	return std.isStandardImportPath(importPath)
+}
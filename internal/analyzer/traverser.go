@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -26,24 +27,98 @@ type Traverser struct {
 	llmClient   llm.LLMClient
 	cache       *AnalysisCache
 	verbose     bool
+
+	cycleDepTypes []string // 参与环检测的依赖类型（为空表示全部）
+	cycleLimit    int      // 最多枚举的基本环数量
+
+	deadCode bool     // 是否进行死代码检测（设置了 roots 时总是进行）
+	roots    []string // 死代码检测的根（为空时使用 DefaultRoots）
+	members  bool     // 是否报告被分析结构体的未使用成员
+	layout   bool     // 是否计算内存布局（标准库类型需要从源码加载，较慢）
+	arch     string   // 计算内存布局使用的 GOARCH（为空时使用 DefaultArch）
+
+	projectCentrality bool   // 是否在全项目依赖图上计算中心性度量（默认只用遍历到的子图）
+	project           *Graph // 本次分析的全项目依赖图（全项目中心性和死代码检测共用，见 projectGraph）
+
+	roles   *RoleClassifier // 结构体角色分类器
+	roleLLM bool            // 启发式规则无法判定的角色交给 LLM
 }
 
-// NewTraverser 创建遍历器
+// NewTraverser 创建遍历器（使用内置角色规则）
 func NewTraverser(p *parser.Parser, filter *ScopeFilter, llmClient llm.LLMClient, verbose bool) *Traverser {
+	roles, _ := NewRoleClassifier(p, types.RoleConfig{})
 	return &Traverser{
 		parser:      p,
 		depAnalyzer: NewDependencyAnalyzer(p, filter, verbose),
 		filter:      filter,
 		llmClient:   llmClient,
 		verbose:     verbose,
+		cycleLimit:  DefaultCycleLimit,
+		roles:       roles,
 	}
 }
 
+// SetCycleOptions 设置环检测选项
+// depTypes 为空表示所有依赖类型都参与，否则只检测完全由这些类型的边构成的环；limit <= 0 时使用默认上限
+func (t *Traverser) SetCycleOptions(depTypes []string, limit int) {
+	if limit <= 0 {
+		limit = DefaultCycleLimit
+	}
+	t.cycleDepTypes = depTypes
+	t.cycleLimit = limit
+}
+
+// SetDeadCode 设置是否在分析结果中进行死代码检测（默认不进行，需要分析全部结构体和函数体）
+func (t *Traverser) SetDeadCode(enabled bool) {
+	t.deadCode = enabled
+}
+
+// SetRoots 设置死代码检测的根（见 FindDeadCode），根不为空时启用死代码检测
+func (t *Traverser) SetRoots(roots []string) {
+	t.roots = roots
+}
+
+// SetMembers 设置是否报告被分析结构体的未使用成员（默认不报告，需要扫描全部函数体）
+func (t *Traverser) SetMembers(enabled bool) {
+	t.members = enabled
+}
+
+// SetArch 设置计算内存布局使用的 GOARCH
+func (t *Traverser) SetArch(arch string) {
+	t.arch = arch
+}
+
+// SetLayout 设置是否计算结构体的内存布局（默认不计算）
+func (t *Traverser) SetLayout(enabled bool) {
+	t.layout = enabled
+}
+
+// SetProjectCentrality 设置是否在全项目依赖图上计算中心性度量
+// 默认只在遍历到的子图上计算；开启后包括起点 BFS 之外的调用方，需要分析全部结构体
+func (t *Traverser) SetProjectCentrality(enabled bool) {
+	t.projectCentrality = enabled
+}
+
+// SetRoles 设置结构体角色分类器；useLLM 为 true 时启发式规则无法判定的结构体交给 LLM 判定
+func (t *Traverser) SetRoles(roles *RoleClassifier, useLLM bool) {
+	t.roles = roles
+	t.roleLLM = useLLM
+}
+
 // SetCache 设置缓存
 func (t *Traverser) SetCache(cache *AnalysisCache) {
 	t.cache = cache
 }
 
+// SetExplain 设置 explain 模式：在结果中记录每个候选类型的判定
+func (t *Traverser) SetExplain(enabled bool) {
+	if enabled {
+		t.depAnalyzer.EnableExplain()
+	} else {
+		t.depAnalyzer.explain = false
+	}
+}
+
 // SaveCache 保存缓存
 func (t *Traverser) SaveCache() error {
 	if t.cache != nil {
@@ -66,7 +141,17 @@ func (t *Traverser) Analyze(startStruct string, maxDepth int, projectPath string
 	}
 
 	// 收集需要 LLM 分析的结构体信息
-	var llmTasks []llmTask
+	var llmTasks, roleTasks []llmTask
+	t.depAnalyzer.resetDecisions()
+	t.project = nil
+
+	var layouts *LayoutCalculator
+	if t.layout {
+		var err error
+		if layouts, err = NewLayoutCalculator(t.parser, t.arch); err != nil && t.verbose {
+			println("Warning: memory layout skipped:", err.Error())
+		}
+	}
 
 	for len(queue) > 0 {
 		task := queue[0]
@@ -106,6 +191,11 @@ func (t *Traverser) Analyze(startStruct string, maxDepth int, projectPath string
 
 		// 构建分析结果（不包含 LLM 分析）
 		structAnalysis := t.buildStructAnalysisWithoutLLM(structInfo, deps, task.Depth)
+		structAnalysis.FieldAccess = t.depAnalyzer.FieldAccess(structInfo.Name)
+		structAnalysis.InterfaceSuggestions = t.depAnalyzer.InterfaceSuggestions(structInfo)
+		if layouts != nil {
+			structAnalysis.Layout = layouts.Compute(structInfo)
+		}
 		result.Structs = append(result.Structs, structAnalysis)
 		result.TotalDeps += len(deps)
 
@@ -115,10 +205,25 @@ func (t *Traverser) Analyze(startStruct string, maxDepth int, projectPath string
 				index: len(result.Structs) - 1,
 				info:  structInfo,
 			})
+			if t.roleLLM && t.roles != nil && structAnalysis.Role == "" {
+				roleTasks = append(roleTasks, llmTasks[len(llmTasks)-1])
+			}
+		}
+
+		// 命中 stop 规则：保留节点，但不继续遍历其依赖
+		if t.filter.ShouldStop(structInfo.Name) {
+			result.Structs[len(result.Structs)-1].Stopped = true
+			if t.verbose {
+				println("Stop traversal at:", structInfo.Name)
+			}
+			continue
 		}
 
 		// 将依赖加入队列
 		for _, dep := range deps {
+			if dep.External {
+				continue
+			}
 			if !visited[dep.To] && t.filter.ShouldAnalyze(dep.To) {
 				queue = append(queue, types.AnalysisTask{
 					StructName: dep.To,
@@ -134,13 +239,108 @@ func (t *Traverser) Analyze(startStruct string, maxDepth int, projectPath string
 	if len(llmTasks) > 0 {
 		t.enrichWithLLMConcurrently(result, llmTasks)
 	}
+	if len(roleTasks) > 0 {
+		t.classifyRolesWithLLM(result, roleTasks)
+	}
 
 	// 检测循环依赖
-	result.Cycles = t.detectCycles(result.Structs)
+	t.detectCycles(result)
+
+	// 汇总第三方模块类型
+	result.ExternalTypes = t.collectExternalTypes(result.Structs)
+
+	// 聚合包级依赖图
+	result.PackageGraph = BuildPackageGraph(t.parser, result, t.cycleLimit)
+
+	// 计算耦合与内聚度量
+	centrality := BuildGraph(result.Structs, nil)
+	if t.projectCentrality {
+		centrality = t.projectGraph()
+	}
+	result.Metrics = t.depAnalyzer.ComputeMetrics(result, centrality)
+
+	// 从根出发检测不可达的声明
+	if t.deadCode || len(t.roots) > 0 {
+		if report, err := findDeadCode(t.parser, t.filter, t.projectGraph(), t.roots); err == nil {
+			result.DeadCode = report
+		} else if t.verbose {
+			println("Warning: dead code detection skipped:", err.Error())
+		}
+	}
+
+	// 被分析结构体中未被使用的成员
+	if t.members {
+		analyzed := make(map[string]bool, len(result.Structs))
+		for _, s := range result.Structs {
+			analyzed[s.Name] = true
+		}
+		result.Members = filterMembers(FindUnusedMembers(t.parser, t.filter), analyzed)
+	}
+
+	// explain 模式下附带候选类型的判定记录
+	if t.depAnalyzer.explain {
+		result.Decisions = t.depAnalyzer.Decisions()
+	}
+
+	return result
+}
+
+// projectGraph 返回全项目依赖图，每次分析只构建一次
+func (t *Traverser) projectGraph() *Graph {
+	if t.project == nil {
+		t.project = BuildGraph(AnalyzeProject(t.parser, t.filter), nil)
+	}
+	return t.project
+}
+
+// collectExternalTypes 汇总依赖中出现的第三方模块类型（按模块和名称排序）
+func (t *Traverser) collectExternalTypes(structs []types.StructAnalysis) []types.ExternalType {
+	byName := make(map[string]*types.ExternalType)
+	for _, s := range structs {
+		for _, dep := range s.Dependencies {
+			if !dep.External {
+				continue
+			}
+			ext, ok := byName[dep.To]
+			if !ok {
+				info := t.filter.GetExternal(dep.To)
+				if info == nil {
+					continue
+				}
+				copied := *info
+				copied.UsedBy = nil
+				ext = &copied
+				byName[dep.To] = ext
+			}
+			if !containsString(ext.UsedBy, s.Name) {
+				ext.UsedBy = append(ext.UsedBy, s.Name)
+			}
+		}
+	}
 
+	result := make([]types.ExternalType, 0, len(byName))
+	for _, ext := range byName {
+		result = append(result, *ext)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Module != result[j].Module {
+			return result[i].Module < result[j].Module
+		}
+		return result[i].Name < result[j].Name
+	})
 	return result
 }
 
+// containsString 判断字符串切片是否包含指定值
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
 // enrichWithLLMConcurrently 并发调用 LLM 分析（支持缓存）
 func (t *Traverser) enrichWithLLMConcurrently(result *types.AnalysisResult, tasks []llmTask) {
 	llmProvider := ""
@@ -160,6 +360,7 @@ func (t *Traverser) enrichWithLLMConcurrently(result *types.AnalysisResult, task
 				// 缓存命中
 				mu.Lock()
 				t.applyLLMResult(&result.Structs[task.index], cached)
+				applyAnnotations(&result.Structs[task.index], task.info)
 				mu.Unlock()
 				cacheHits++
 				continue
@@ -205,6 +406,7 @@ func (t *Traverser) enrichWithLLMConcurrently(result *types.AnalysisResult, task
 			// 更新结果
 			mu.Lock()
 			t.applyLLMResult(&result.Structs[idx], llmResult)
+			applyAnnotations(&result.Structs[idx], info)
 			mu.Unlock()
 
 			// 保存到缓存
@@ -221,6 +423,61 @@ func (t *Traverser) enrichWithLLMConcurrently(result *types.AnalysisResult, task
 	}
 }
 
+// classifyRolesWithLLM 并发调用 LLM 判定启发式规则无法判定的结构体角色
+func (t *Traverser) classifyRolesWithLLM(result *types.AnalysisResult, tasks []llmTask) {
+	if t.verbose {
+		println("Classifying roles with LLM for", len(tasks), "structs")
+	}
+
+	llmProvider := t.llmClient.Name()
+	roles := t.roles.Roles()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex // 保护 result.Structs
+	sem := make(chan struct{}, LLMConcurrency)
+
+	for _, task := range tasks {
+		// 先检查缓存（包括 LLM 无法判定的结果）
+		if t.cache != nil {
+			if role, ok := t.cache.GetRole(task.info.Name, task.info.SourceCode, llmProvider, roles); ok {
+				if role != "" {
+					result.Structs[task.index].Role = role
+					result.Structs[task.index].RoleReason = "LLM 判定"
+				}
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func(idx int, info *types.StructInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			role, err := t.llmClient.ClassifyRole(info, roles)
+			if err != nil {
+				if t.verbose {
+					println("LLM role classification failed for", info.Name, ":", err.Error())
+				}
+				return
+			}
+			if t.cache != nil {
+				t.cache.SetRole(info.Name, info.SourceCode, llmProvider, roles, role)
+			}
+			if role == "" {
+				return
+			}
+
+			mu.Lock()
+			result.Structs[idx].Role = role
+			result.Structs[idx].RoleReason = "LLM 判定"
+			mu.Unlock()
+		}(task.index, task.info)
+	}
+
+	wg.Wait()
+}
+
 // applyLLMResult 应用 LLM 分析结果到结构体分析
 func (t *Traverser) applyLLMResult(analysis *types.StructAnalysis, llmResult *types.LLMAnalysisResult) {
 	// 更新结构体描述
@@ -254,21 +511,29 @@ func (t *Traverser) buildStructAnalysisWithoutLLM(info *types.StructInfo, deps [
 	analysis := types.StructAnalysis{
 		Name:         info.Name,
 		Package:      info.Package,
+		ImportPath:   info.ImportPath,
 		Description:  "待分析",
 		Fields:       make([]types.FieldAnalysis, 0, len(info.Fields)),
 		Methods:      make([]types.MethodAnalysis, 0, len(info.Methods)),
 		Dependencies: deps,
 		Depth:        depth,
 	}
+	if t.roles != nil {
+		analysis.Role, analysis.RoleReason = t.roles.Classify(info)
+	}
 
-	// 转换字段
+	// 转换字段（跳过标注了 ignore 的字段）
 	for _, field := range info.Fields {
+		if field.Annotations.Ignore {
+			continue
+		}
 		analysis.Fields = append(analysis.Fields, types.FieldAnalysis{
-			Name:        field.Name,
-			Type:        field.Type,
-			Description: "待分析",
-			IsExported:  field.IsExported,
-			IsEmbedded:  field.IsEmbedded,
+			Name:            field.Name,
+			Type:            field.Type,
+			Description:     "待分析",
+			IsExported:      field.IsExported,
+			IsEmbedded:      field.IsEmbedded,
+			SerializedNames: field.Tags.Names,
 		})
 	}
 
@@ -283,57 +548,37 @@ func (t *Traverser) buildStructAnalysisWithoutLLM(info *types.StructInfo, deps [
 		})
 	}
 
+	applyAnnotations(&analysis, info)
 	return analysis
 }
 
-// detectCycles 检测循环依赖
-func (t *Traverser) detectCycles(structs []types.StructAnalysis) [][]string {
-	// 构建依赖图
-	graph := make(map[string][]string)
-	for _, s := range structs {
-		for _, dep := range s.Dependencies {
-			graph[s.Name] = append(graph[s.Name], dep.To)
-		}
+// applyAnnotations 应用源码注释指令：架构层和人工描述（人工描述优先于 LLM 结果）
+func applyAnnotations(analysis *types.StructAnalysis, info *types.StructInfo) {
+	analysis.Layer = info.Annotations.Layer
+	if info.Annotations.Desc != "" {
+		analysis.Description = info.Annotations.Desc
 	}
 
-	var cycles [][]string
-	visited := make(map[string]bool)
-	recStack := make(map[string]bool)
-
-	var dfs func(node string, path []string)
-	dfs = func(node string, path []string) {
-		visited[node] = true
-		recStack[node] = true
-		path = append(path, node)
-
-		for _, neighbor := range graph[node] {
-			if !visited[neighbor] {
-				dfs(neighbor, path)
-			} else if recStack[neighbor] {
-				// 找到循环
-				cycleStart := -1
-				for i, p := range path {
-					if p == neighbor {
-						cycleStart = i
-						break
-					}
-				}
-				if cycleStart != -1 {
-					cycle := make([]string, len(path)-cycleStart)
-					copy(cycle, path[cycleStart:])
-					cycles = append(cycles, cycle)
-				}
-			}
+	fieldDescs := make(map[string]string)
+	for _, field := range info.Fields {
+		if field.Annotations.Desc != "" {
+			fieldDescs[field.Name] = field.Annotations.Desc
 		}
-
-		recStack[node] = false
 	}
-
-	for node := range graph {
-		if !visited[node] {
-			dfs(node, []string{})
+	for i := range analysis.Fields {
+		if desc, ok := fieldDescs[analysis.Fields[i].Name]; ok {
+			analysis.Fields[i].Description = desc
 		}
 	}
+}
+
+// detectCycles 检测循环依赖：Tarjan 强连通分量 + Johnson 基本环枚举
+func (t *Traverser) detectCycles(result *types.AnalysisResult) {
+	g := BuildGraph(result.Structs, t.cycleDepTypes)
 
-	return cycles
+	result.Components = g.StronglyConnectedComponents()
+	result.Cycles, result.CyclesTruncated = g.ElementaryCycles(t.cycleLimit)
+	for _, cycle := range result.Cycles {
+		result.CycleDetails = append(result.CycleDetails, g.CycleDetail(cycle))
+	}
 }
@@ -0,0 +1,323 @@
+// Package config 加载项目配置文件 .struct-analyzer.yaml
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/reporter"
+	"github.com/user/go-struct-analyzer/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName 项目配置文件名
+const FileName = ".struct-analyzer.yaml"
+
+// Config 表示项目配置
+type Config struct {
+	Start             string `yaml:"start"`              // 起点结构体名称
+	Depth             int    `yaml:"depth"`              // 分析深度
+	Format            string `yaml:"format"`             // 输出格式：markdown, json
+	Output            string `yaml:"output"`             // 报告输出路径
+	Mermaid           string `yaml:"mermaid"`            // Mermaid 图输出路径（可选）
+	Visualizer        string `yaml:"visualizer"`         // 可视化工具 JSON 输出路径（可选）
+	PackageMermaid    string `yaml:"package_mermaid"`    // 包级依赖 Mermaid 图输出路径（可选）
+	PackageVisualizer string `yaml:"package_visualizer"` // 包级依赖可视化 JSON 输出路径（可选）
+	Verbose           bool   `yaml:"verbose"`            // 详细输出模式
+	Explain           bool   `yaml:"explain"`            // 在报告中附带候选类型的过滤判定
+	MetricsSort       string `yaml:"metrics_sort"`       // 度量表格排序键
+	RankBy            string `yaml:"rank_by"`            // 关键结构体排行和可视化节点大小依据的度量
+	ProjectCentrality bool   `yaml:"project_centrality"` // 在全项目依赖图上计算中心性度量（默认只用遍历到的子图）
+	Layout            bool   `yaml:"layout"`             // 计算结构体内存布局
+	Arch              string `yaml:"arch"`               // 计算内存布局使用的 GOARCH（默认 amd64）
+
+	LLM      LLMConfig      `yaml:"llm"`      // LLM 配置
+	External ExternalConfig `yaml:"external"` // 第三方模块类型配置
+	Cycles   CycleConfig    `yaml:"cycles"`   // 循环依赖检测配置
+
+	Architecture types.ArchitectureConfig `yaml:"architecture"` // 架构分层规则（check 子命令使用）
+	Reachability types.ReachabilityConfig `yaml:"reachability"` // 死代码检测的根
+	Members      types.MemberConfig       `yaml:"members"`      // 结构体成员使用检查
+	Roles        types.RoleConfig         `yaml:"roles"`        // 结构体架构角色的分类规则
+
+	Blacklist string                `yaml:"blacklist"` // 黑名单文件路径（可选）
+	Filters   types.BlacklistConfig `yaml:"filters"`   // 内联过滤规则，语法与黑名单文件相同
+
+	Profiles map[string]yaml.Node `yaml:"profiles,omitempty"` // 命名配置，覆盖上面的同名字段
+
+	Source  string `yaml:"-"` // 配置文件路径（未找到时为空）
+	Profile string `yaml:"-"` // 已应用的 profile 名称
+}
+
+// LLMConfig 表示 LLM 配置
+type LLMConfig struct {
+	Provider  string `yaml:"provider"`    // LLM 后端：glm, claude
+	Model     string `yaml:"model"`       // 模型（为空时使用后端默认模型）
+	APIKeyEnv string `yaml:"api_key_env"` // 读取 API Key 的环境变量名（不建议把 Key 写进配置文件）
+	Cache     bool   `yaml:"cache"`       // 是否启用 LLM 结果缓存
+}
+
+// ExternalConfig 表示第三方模块类型配置
+type ExternalConfig struct {
+	Enabled    bool   `yaml:"enabled"`    // 保留第三方模块类型作为叶子节点
+	Vendor     bool   `yaml:"vendor"`     // 从 vendor/ 读取第三方结构体定义
+	GoModCache string `yaml:"gomodcache"` // 从本地 GOMODCACHE 读取第三方结构体定义
+}
+
+// CycleConfig 表示循环依赖检测配置
+type CycleConfig struct {
+	DepTypes []string `yaml:"dep_types"` // 只检测完全由这些依赖类型构成的环（为空表示全部）
+	Limit    int      `yaml:"limit"`     // 最多枚举的基本环数量
+}
+
+// Default 返回默认配置（与命令行参数默认值一致）
+func Default() *Config {
+	return &Config{
+		Depth:       2,
+		Format:      "markdown",
+		Output:      "./analysis_report.md",
+		MetricsSort: reporter.SortByFanIn,
+		RankBy:      reporter.SortByReach,
+		Arch:        analyzer.DefaultArch,
+		LLM: LLMConfig{
+			Provider: "glm",
+			Cache:    true,
+		},
+		Cycles: CycleConfig{
+			Limit: analyzer.DefaultCycleLimit,
+		},
+	}
+}
+
+// Find 从 startDir 开始逐级向上查找配置文件，未找到时返回空字符串
+func Find(startDir string) string {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, FileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Load 加载配置文件并应用指定的 profile（为空表示不使用 profile）
+// 配置文件中的相对路径以配置文件所在目录为基准
+func Load(path, profile string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Source = path
+
+	// 所有 profile 都按同样的规则检查未知字段，未选中的 profile 中的拼写错误也能被发现
+	for _, name := range cfg.ProfileNames() {
+		node := cfg.Profiles[name]
+		if err := decodeStrict(&node, Default()); err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %w", path, name, err)
+		}
+	}
+
+	if profile != "" {
+		if err := cfg.ApplyProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.resolvePaths(filepath.Dir(path))
+	return cfg, nil
+}
+
+// ApplyProfile 将命名 profile 覆盖到当前配置上，profile 中未出现的字段保持不变
+func (c *Config) ApplyProfile(name string) error {
+	node, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile %q 不存在，可用: %v", name, c.ProfileNames())
+	}
+	if err := decodeStrict(&node, c); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	c.Profile = name
+	return nil
+}
+
+// decodeStrict 将 YAML 节点解码到 out，遇到未知字段时报错（yaml.Node.Decode 不支持 KnownFields）
+func decodeStrict(node *yaml.Node, out interface{}) error {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// ProfileNames 返回已定义的 profile 名称（已排序）
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolvePaths 将相对路径解析为以 baseDir 为基准的路径
+func (c *Config) resolvePaths(baseDir string) {
+	for _, p := range []*string{&c.Output, &c.Mermaid, &c.Visualizer, &c.PackageMermaid, &c.PackageVisualizer, &c.Blacklist, &c.External.GoModCache, &c.Architecture.Baseline} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(baseDir, *p)
+		}
+	}
+}
+
+// Validate 校验配置
+func (c *Config) Validate() error {
+	if c.Depth < 0 {
+		return fmt.Errorf("depth 不能为负数: %d", c.Depth)
+	}
+
+	switch c.Format {
+	case "markdown", "json":
+	default:
+		return fmt.Errorf("不支持的输出格式: %q（可选: markdown, json）", c.Format)
+	}
+
+	switch c.LLM.Provider {
+	case "glm", "zhipu", "claude", "anthropic":
+	default:
+		return fmt.Errorf("不支持的 LLM 后端: %q（可选: glm, claude）", c.LLM.Provider)
+	}
+
+	if !reporter.IsMetricsSortKey(c.MetricsSort) {
+		return fmt.Errorf("不支持的度量排序键: %q（可选: %s）", c.MetricsSort, strings.Join(reporter.MetricsSortKeys(), ", "))
+	}
+
+	if !reporter.IsRankKey(c.RankBy) {
+		return fmt.Errorf("不支持的排行度量: %q（可选: %s）", c.RankBy, strings.Join(reporter.RankKeys(), ", "))
+	}
+
+	if err := analyzer.ValidateArch(c.Arch); err != nil {
+		return fmt.Errorf("arch: %w", err)
+	}
+
+	for _, t := range c.Cycles.DepTypes {
+		if !isDepType(t) {
+			return fmt.Errorf("cycles.dep_types: 未知的依赖类型 %q", t)
+		}
+	}
+
+	if c.Blacklist != "" {
+		if _, err := os.Stat(c.Blacklist); err != nil {
+			return fmt.Errorf("黑名单文件不可用: %w", err)
+		}
+	}
+
+	if err := analyzer.NewBlacklist().ApplyConfig(c.Filters); err != nil {
+		return fmt.Errorf("filters: %w", err)
+	}
+
+	roles, err := analyzer.NewRoleClassifier(nil, c.Roles)
+	if err != nil {
+		return fmt.Errorf("roles: %w", err)
+	}
+
+	if _, err := analyzer.NewLayerChecker(nil, c.Architecture); err != nil {
+		return fmt.Errorf("architecture: %w", err)
+	}
+	for _, l := range c.Architecture.Layers {
+		if err := roles.ValidateRoles(l.Roles); err != nil {
+			return fmt.Errorf("architecture: 层 %q: %w", l.Name, err)
+		}
+	}
+	for _, rule := range append(append([]types.LayerRule{}, c.Architecture.Allow...), c.Architecture.Forbid...) {
+		for _, t := range rule.DepTypes {
+			if !isDepType(t) {
+				return fmt.Errorf("architecture: 规则 %s -> %s 中未知的依赖类型 %q", rule.From, rule.To, t)
+			}
+		}
+	}
+
+	if err := analyzer.ValidateRoots(c.Reachability.Roots); err != nil {
+		return fmt.Errorf("reachability: %w", err)
+	}
+
+	return nil
+}
+
+// isDepType 判断是否为已知的依赖类型
+func isDepType(t string) bool {
+	switch t {
+	case types.DepTypeField, types.DepTypeInit, types.DepTypeMethodCall, types.DepTypeInterface,
+		types.DepTypeEmbed, types.DepTypeConstructor, types.DepTypeConstructorParam,
+		types.DepTypeRelation:
+		return true
+	}
+	return false
+}
+
+// APIKey 获取 LLM API Key：优先读取 api_key_env 指定的环境变量，否则按后端读取默认环境变量
+func (c *Config) APIKey() string {
+	if c.LLM.APIKeyEnv != "" {
+		return os.Getenv(c.LLM.APIKeyEnv)
+	}
+
+	switch c.LLM.Provider {
+	case "claude", "anthropic":
+		return os.Getenv("CLAUDE_API_KEY")
+	default:
+		return os.Getenv("GLM_API_KEY")
+	}
+}
+
+// LoadBlacklist 根据配置创建黑名单：先加载黑名单文件，再应用内联过滤规则
+func (c *Config) LoadBlacklist() (*analyzer.Blacklist, error) {
+	blacklist := analyzer.NewBlacklist()
+	if c.Blacklist != "" {
+		if err := blacklist.LoadFromFile(c.Blacklist); err != nil {
+			return blacklist, err
+		}
+	}
+	if err := blacklist.ApplyConfig(c.Filters); err != nil {
+		return blacklist, err
+	}
+	return blacklist, nil
+}
+
+// YAML 以 YAML 形式输出生效的配置（不含 profiles 定义）
+func (c *Config) YAML() (string, error) {
+	effective := *c
+	effective.Profiles = nil
+
+	data, err := yaml.Marshal(&effective)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
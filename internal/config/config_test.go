@@ -0,0 +1,180 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+const testConfig = `start: UserService
+depth: 1
+output: reports/report.md
+verbose: true
+llm:
+  provider: claude
+filters:
+  types: [Config]
+  rules:
+    - type: "*Logger"
+      action: stop
+profiles:
+  ci:
+    depth: 4
+    format: json
+    verbose: false
+    filters:
+      types: [Config, Metrics]
+`
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	return path
+}
+
+func TestFind_WalksUp(t *testing.T) {
+	root := t.TempDir()
+	path := writeConfig(t, root, testConfig)
+
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := Find(nested); got != path {
+		t.Errorf("Find() = %q, want %q", got, path)
+	}
+	if got := Find(t.TempDir()); got != "" {
+		t.Errorf("Find() in unrelated dir = %q, want empty", got)
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := Load(writeConfig(t, dir, testConfig), "")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Start != "UserService" || cfg.Depth != 1 || !cfg.Verbose {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	// 未出现的字段保留默认值
+	if cfg.Format != "markdown" || !cfg.LLM.Cache {
+		t.Errorf("defaults not kept: format=%q cache=%v", cfg.Format, cfg.LLM.Cache)
+	}
+	if cfg.LLM.Provider != "claude" {
+		t.Errorf("provider = %q", cfg.LLM.Provider)
+	}
+	if want := filepath.Join(dir, "reports", "report.md"); cfg.Output != want {
+		t.Errorf("Output = %q, want %q", cfg.Output, want)
+	}
+	if len(cfg.Filters.Rules) != 1 {
+		t.Errorf("filters.rules = %+v", cfg.Filters.Rules)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+}
+
+func TestLoad_Profile(t *testing.T) {
+	cfg, err := Load(writeConfig(t, t.TempDir(), testConfig), "ci")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Profile != "ci" || cfg.Depth != 4 || cfg.Format != "json" {
+		t.Errorf("profile not applied: %+v", cfg)
+	}
+	if cfg.Verbose {
+		t.Error("profile should be able to turn a bool off")
+	}
+	if cfg.Start != "UserService" || cfg.LLM.Provider != "claude" {
+		t.Error("fields missing from profile should keep base values")
+	}
+	if len(cfg.Filters.Types) != 2 || len(cfg.Filters.Rules) != 1 {
+		t.Errorf("filters = %+v", cfg.Filters)
+	}
+
+	if _, err := Load(cfg.Source, "missing"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestLoad_UnknownField(t *testing.T) {
+	_, err := Load(writeConfig(t, t.TempDir(), "depht: 3\n"), "")
+	if err == nil || !strings.Contains(err.Error(), "depht") {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestLoad_UnknownProfileField(t *testing.T) {
+	content := testConfig + `  local:
+    depht: 2
+`
+	path := writeConfig(t, t.TempDir(), content)
+	for _, profile := range []string{"local", "ci", ""} {
+		_, err := Load(path, profile)
+		if err == nil || !strings.Contains(err.Error(), "depht") || !strings.Contains(err.Error(), `profile "local"`) {
+			t.Errorf("Load(%q): expected unknown field error in profile local, got %v", profile, err)
+		}
+	}
+
+	cfg := Default()
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte("formt: json\n"), &node); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	cfg.Profiles = map[string]yaml.Node{"typo": *node.Content[0]}
+	if err := cfg.ApplyProfile("typo"); err == nil || !strings.Contains(err.Error(), "formt") {
+		t.Errorf("ApplyProfile: expected unknown field error, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+	}{
+		{"negative depth", func(c *Config) { c.Depth = -1 }},
+		{"bad format", func(c *Config) { c.Format = "xml" }},
+		{"bad provider", func(c *Config) { c.LLM.Provider = "gpt" }},
+		{"missing blacklist", func(c *Config) { c.Blacklist = "/nonexistent/blacklist.yaml" }},
+		{"bad rule", func(c *Config) { c.Filters.Types = nil; c.Filters.Patterns = []string{"re:("} }},
+		{"undefined layer", func(c *Config) {
+			c.Architecture.Layers = []types.LayerConfig{{Name: "service"}}
+			c.Architecture.Order = "handler -> service"
+		}},
+		{"bad layer dep type", func(c *Config) {
+			c.Architecture.Layers = []types.LayerConfig{{Name: "service"}}
+			c.Architecture.Forbid = []types.LayerRule{{From: "service", To: "*", DepTypes: []string{"call"}}}
+		}},
+		{"bad reachability root", func(c *Config) { c.Reachability.Roots = []string{"main", "re:("} }},
+		{"bad arch", func(c *Config) { c.Arch = "pdp11" }},
+		{"bad rank key", func(c *Config) { c.RankBy = "lcom" }},
+		{"role rule without role", func(c *Config) { c.Roles.Rules = []types.RoleRule{{Names: []string{"*Gateway"}}} }},
+		{"unknown layer role", func(c *Config) {
+			c.Architecture.Layers = []types.LayerConfig{{Name: "api", Roles: []string{"gateway"}}}
+		}},
+	}
+
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(cfg)
+			if err := cfg.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
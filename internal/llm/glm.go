@@ -98,6 +98,25 @@ func (c *GLMClient) AnalyzeStruct(info *types.StructInfo) (*types.LLMAnalysisRes
 	}, nil
 }
 
+// ClassifyRole 从可选角色中判定结构体的架构角色，无法判定时返回空字符串
+func (c *GLMClient) ClassifyRole(info *types.StructInfo, roles []string) (string, error) {
+	prompt := buildRolePrompt(info, roles)
+
+	var lastErr error
+	for i := 0; i < c.maxRetries; i++ {
+		response, err := c.callAPI(prompt)
+		if err == nil {
+			return parseRoleResponse(response, roles)
+		}
+
+		lastErr = err
+		// 指数退避
+		sleepDuration := time.Duration(math.Pow(2, float64(i))) * time.Second
+		time.Sleep(sleepDuration)
+	}
+	return "", lastErr
+}
+
 // callAPI 调用 GLM API
 func (c *GLMClient) callAPI(prompt string) (string, error) {
 	request := GLMAPIRequest{
@@ -7,6 +7,9 @@ type LLMClient interface {
 	// AnalyzeStruct 分析结构体并返回描述
 	AnalyzeStruct(info *types.StructInfo) (*types.LLMAnalysisResult, error)
 
+	// ClassifyRole 从可选角色中判定结构体的架构角色，无法判定时返回空字符串
+	ClassifyRole(info *types.StructInfo, roles []string) (string, error)
+
 	// IsConfigured 检查客户端是否已配置
 	IsConfigured() bool
 
@@ -157,6 +157,46 @@ func TestParseResponse_EmptyFields(t *testing.T) {
 	}
 }
 
+func TestParseRoleResponse(t *testing.T) {
+	roles := []string{"handler", "service", "gateway", "ReadModel"}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"role": "service"}`, "service"},
+		{"```json\n{\"role\": \" Gateway \"}\n```", "gateway"},
+		{`{"role": ""}`, ""},
+		{`{"role": "controller"}`, ""},
+		{`{"role": "readmodel"}`, "ReadModel"},
+	}
+	for _, tt := range tests {
+		got, err := parseRoleResponse(tt.input, roles)
+		if err != nil {
+			t.Errorf("parseRoleResponse(%q) failed: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("parseRoleResponse(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+	if _, err := parseRoleResponse("not json", roles); err == nil {
+		t.Error("parseRoleResponse should return error for invalid JSON")
+	}
+}
+
+func TestBuildRolePrompt(t *testing.T) {
+	info := &types.StructInfo{
+		Name:       "UserService",
+		Package:    "service",
+		SourceCode: "type UserService struct{}",
+	}
+	prompt := buildRolePrompt(info, []string{"handler", "service"})
+	for _, check := range []string{"UserService", "type UserService struct{}", "可选角色: handler, service", `"role"`} {
+		if !strings.Contains(prompt, check) {
+			t.Errorf("Prompt should contain %q", check)
+		}
+	}
+}
+
 // ==================== buildPrompt 测试 ====================
 
 func TestBuildPrompt(t *testing.T) {
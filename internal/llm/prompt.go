@@ -117,3 +117,59 @@ func buildSimplePrompt(info *types.StructInfo) string {
 
 	return sb.String()
 }
+
+const rolePromptTemplate = `你是一个 Go 语言代码分析专家。请判断以下结构体在项目架构中承担的角色。
+
+结构体名称: {{.StructName}}
+所属包: {{.Package}}
+
+结构体定义:
+` + "```go" + `
+{{.StructCode}}
+` + "```" + `
+
+方法实现:
+` + "```go" + `
+{{.MethodsCode}}
+` + "```" + `
+
+可选角色: {{.Roles}}
+
+请以 JSON 格式返回判定结果，包括：
+1. role: 可选角色之一；都不合适时返回空字符串
+
+要求：
+- 只返回纯 JSON，不要包含任何其他文本或 Markdown 标记
+
+输出格式示例：
+{"role": "service"}`
+
+// RolePromptData 表示角色判定 prompt 模板数据
+type RolePromptData struct {
+	PromptData
+	Roles string
+}
+
+// buildRolePrompt 构建角色判定的提示词
+func buildRolePrompt(info *types.StructInfo, roles []string) string {
+	data := RolePromptData{
+		PromptData: PromptData{
+			StructName:  info.Name,
+			Package:     info.Package,
+			StructCode:  info.SourceCode,
+			MethodsCode: buildMethodsCode(info.Methods),
+		},
+		Roles: strings.Join(roles, ", "),
+	}
+
+	tmpl, err := template.New("role").Parse(rolePromptTemplate)
+	if err != nil {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return ""
+	}
+	return buf.String()
+}
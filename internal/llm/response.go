@@ -21,6 +21,26 @@ func parseResponse(response string) (*types.LLMAnalysisResult, error) {
 	return &result, nil
 }
 
+// parseRoleResponse 解析角色判定响应，按不区分大小写匹配可选角色并返回配置中的写法，不在可选角色中的结果视为无法判定
+func parseRoleResponse(response string, roles []string) (string, error) {
+	response = cleanResponse(response)
+
+	var result struct {
+		Role string `json:"role"`
+	}
+	if err := json.Unmarshal([]byte(response), &result); err != nil {
+		return "", fmt.Errorf("failed to parse JSON response: %w\nResponse: %s", err, response)
+	}
+
+	role := strings.TrimSpace(result.Role)
+	for _, r := range roles {
+		if strings.EqualFold(r, role) {
+			return r, nil
+		}
+	}
+	return "", nil
+}
+
 // cleanResponse 清理响应中的 Markdown 标记
 func cleanResponse(response string) string {
 	response = strings.TrimSpace(response)
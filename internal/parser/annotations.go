@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// DirectivePrefix 源码注释指令前缀
+const DirectivePrefix = "structanalyzer:"
+
+// parseAnnotations 从多个注释组中解析 //structanalyzer: 指令
+// 支持: ignore、stop、layer=<name>、role=<name>、desc "<text>"
+func parseAnnotations(groups ...*ast.CommentGroup) types.Annotations {
+	var ann types.Annotations
+
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, comment := range group.List {
+			text := strings.TrimPrefix(comment.Text, "//")
+			text = strings.TrimSpace(text)
+			if !strings.HasPrefix(text, DirectivePrefix) {
+				continue
+			}
+			applyDirective(&ann, strings.TrimPrefix(text, DirectivePrefix))
+		}
+	}
+
+	return ann
+}
+
+// applyDirective 应用单条指令
+func applyDirective(ann *types.Annotations, directive string) {
+	name, arg := directive, ""
+	if idx := strings.IndexAny(directive, "= "); idx != -1 {
+		name, arg = directive[:idx], strings.TrimSpace(directive[idx+1:])
+	}
+
+	switch name {
+	case "ignore":
+		ann.Ignore = true
+	case "stop":
+		ann.Stop = true
+	case "layer":
+		ann.Layer = arg
+	case "role":
+		ann.Role = arg
+	case "desc":
+		if unquoted, err := strconv.Unquote(arg); err == nil {
+			arg = unquoted
+		}
+		ann.Desc = arg
+	}
+}
+
+// typeDoc 获取类型声明的文档注释
+// 单独声明（type X struct）的注释挂在 GenDecl 上，分组声明（type ( ... )）的注释挂在 TypeSpec 上
+func typeDoc(genDecl *ast.GenDecl, typeSpec *ast.TypeSpec) *ast.CommentGroup {
+	if typeSpec.Doc != nil {
+		return typeSpec.Doc
+	}
+	if len(genDecl.Specs) == 1 {
+		return genDecl.Doc
+	}
+	return nil
+}
+
+// GetAnnotations 获取项目内结构体或接口上的注释指令
+func (p *Parser) GetAnnotations(typeName string) types.Annotations {
+	if info := p.GetStruct(typeName); info != nil {
+		return info.Annotations
+	}
+	name := strings.TrimPrefix(typeName, "*")
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		name = name[idx+1:]
+	}
+	if iface := p.GetInterface(name); iface != nil {
+		return iface.Annotations
+	}
+	return types.Annotations{}
+}
@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// getRequires 从 go.mod 读取 require 列表（模块路径 -> 版本）
+func (p *Parser) getRequires(projectPath string) map[string]string {
+	requires := make(map[string]string)
+
+	data, err := os.ReadFile(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return requires
+	}
+
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		// 去掉行尾注释（如 // indirect）
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require "))
+		case !inBlock:
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			requires[fields[0]] = fields[1]
+		}
+	}
+
+	return requires
+}
+
+// GetRequires 返回 go.mod 中声明的依赖模块（模块路径 -> 版本）
+func (p *Parser) GetRequires() map[string]string {
+	return p.requires
+}
+
+// GetProjectPath 返回项目根目录
+func (p *Parser) GetProjectPath() string {
+	return p.projectPath
+}
+
+// ModuleForImport 根据导入路径查找所属的依赖模块（最长前缀匹配）
+func (p *Parser) ModuleForImport(importPath string) (module, version string) {
+	for mod, ver := range p.requires {
+		if importPath != mod && !strings.HasPrefix(importPath, mod+"/") {
+			continue
+		}
+		if len(mod) > len(module) {
+			module, version = mod, ver
+		}
+	}
+	return module, version
+}
+
+// LoadExternalStruct 从 vendor 目录或本地模块缓存读取外部结构体定义
+// 返回结构体信息和来源（"vendor" 或 "modcache"），找不到时返回 nil
+func (p *Parser) LoadExternalStruct(importPath, typeName string, useVendor bool, modCache string) (*types.StructInfo, string) {
+	var candidates []struct{ dir, source string }
+
+	if useVendor && p.projectPath != "" {
+		candidates = append(candidates, struct{ dir, source string }{
+			filepath.Join(p.projectPath, "vendor", filepath.FromSlash(importPath)), "vendor",
+		})
+	}
+
+	if modCache != "" {
+		if module, version := p.ModuleForImport(importPath); module != "" {
+			subPath := strings.TrimPrefix(importPath, module)
+			dir := filepath.Join(modCache, filepath.FromSlash(escapeModulePath(module)+"@"+version+subPath))
+			candidates = append(candidates, struct{ dir, source string }{dir, "modcache"})
+		}
+	}
+
+	for _, c := range candidates {
+		if info := p.loadExternalDir(c.dir)[typeName]; info != nil {
+			loaded := *info
+			loaded.ImportPath = importPath
+			return &loaded, c.source
+		}
+	}
+	return nil, ""
+}
+
+// loadExternalDir 解析外部包目录中的结构体定义（带缓存）
+func (p *Parser) loadExternalDir(dir string) map[string]*types.StructInfo {
+	p.mu.RLock()
+	cached, ok := p.externals[dir]
+	p.mu.RUnlock()
+	if ok {
+		return cached
+	}
+
+	result := make(map[string]*types.StructInfo)
+
+	entries, err := os.ReadDir(dir)
+	if err == nil {
+		var names []string
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			filePath := filepath.Join(dir, name)
+			file, err := parser.ParseFile(p.fset, filePath, nil, parser.ParseComments)
+			if err != nil {
+				continue
+			}
+			for typeName, info := range p.extractStructsFromFile(file, filePath) {
+				if _, exists := result[typeName]; !exists && ast.IsExported(typeName) {
+					result[typeName] = info
+				}
+			}
+		}
+	}
+
+	p.mu.Lock()
+	p.externals[dir] = result
+	p.mu.Unlock()
+
+	return result
+}
+
+// escapeModulePath 按模块缓存规则转义模块路径（大写字母 -> !小写字母）
+func escapeModulePath(path string) string {
+	var sb strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('!')
+			sb.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// ResolveImportAlias 在全项目的导入中查找包别名对应的导入路径（去重并排序）
+func (p *Parser) ResolveImportAlias(alias string) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, importMap := range p.imports {
+		if path, ok := importMap[alias]; ok && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// ImportPathOf 返回项目内文件所属包的导入路径，文件不在项目内时返回空字符串
+func (p *Parser) ImportPathOf(filePath string) string {
+	return p.importPathForFile(filePath)
+}
+
+// importPathForFile 根据文件所在目录推导包导入路径（模块名 + 相对目录）
+func (p *Parser) importPathForFile(filePath string) string {
+	if p.projectPath == "" {
+		return p.moduleName
+	}
+	rel, err := filepath.Rel(p.projectPath, filepath.Dir(filePath))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	if rel == "." {
+		return p.moduleName
+	}
+	return p.moduleName + "/" + filepath.ToSlash(rel)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 
@@ -17,17 +18,22 @@ import (
 
 // Parser 是 Go 源码解析器
 type Parser struct {
-	fset       *token.FileSet
-	files      map[string]*ast.File      // 文件路径 -> AST
-	packages   map[string]*ast.Package   // 包名 -> 包
-	structs    map[string]*types.StructInfo // 结构体名 -> 结构体信息
-	methods    map[string][]types.MethodInfo // 结构体名 -> 方法列表
-	interfaces map[string]*types.InterfaceInfo // 接口名 -> 接口信息
-	functions  map[string]*types.FunctionInfo  // 函数名 -> 函数信息（用于构造函数检测）
-	imports    map[string]map[string]string  // 文件路径 -> (别名 -> 导入路径)
-	moduleName string                        // 项目模块名
-	verbose    bool
-	mu         sync.RWMutex                  // 保护并发写入
+	fset        *token.FileSet
+	files       map[string]*ast.File                    // 文件路径 -> AST
+	packages    map[string]*ast.Package                 // 包名 -> 包
+	structs     map[string]*types.StructInfo            // 结构体名 -> 结构体信息
+	methods     map[string][]types.MethodInfo           // 结构体名 -> 方法列表
+	interfaces  map[string]*types.InterfaceInfo         // 接口名 -> 接口信息
+	functions   map[string]*types.FunctionInfo          // 函数名 -> 函数信息（用于构造函数检测）
+	ctorIndex   map[string]*types.FunctionInfo          // 导入路径.函数名 -> 构造函数
+	typeIndex   map[string]string                       // 导入路径.类型名 -> 声明该结构体或接口的包名
+	imports     map[string]map[string]string            // 文件路径 -> (别名 -> 导入路径)
+	moduleName  string                                  // 项目模块名
+	projectPath string                                  // 项目根目录
+	requires    map[string]string                       // go.mod require: 模块路径 -> 版本
+	externals   map[string]map[string]*types.StructInfo // 外部包目录 -> (类型名 -> 结构体信息)
+	verbose     bool
+	mu          sync.RWMutex // 保护并发写入
 }
 
 // NewParser 创建一个新的解析器
@@ -40,7 +46,11 @@ func NewParser(verbose bool) *Parser {
 		methods:    make(map[string][]types.MethodInfo),
 		interfaces: make(map[string]*types.InterfaceInfo),
 		functions:  make(map[string]*types.FunctionInfo),
+		ctorIndex:  make(map[string]*types.FunctionInfo),
+		typeIndex:  make(map[string]string),
 		imports:    make(map[string]map[string]string),
+		requires:   make(map[string]string),
+		externals:  make(map[string]map[string]*types.StructInfo),
 		verbose:    verbose,
 	}
 }
@@ -54,6 +64,8 @@ func (p *Parser) ParseProject(projectPath string) error {
 		moduleName = filepath.Base(projectPath)
 	}
 	p.moduleName = moduleName
+	p.projectPath = projectPath
+	p.requires = p.getRequires(projectPath)
 
 	// 2. 递归扫描所有 .go 文件
 	goFiles, err := p.findGoFiles(projectPath)
@@ -78,6 +90,9 @@ func (p *Parser) ParseProject(projectPath string) error {
 	}
 	p.mu.Unlock()
 
+	// 6. 筛选构造函数（需要知道所有项目结构体）
+	p.filterConstructors()
+
 	return nil
 }
 
@@ -175,12 +190,14 @@ func (p *Parser) extractAllConcurrently() {
 			p.mu.Lock()
 			for name, info := range structs {
 				p.structs[name] = info
+				p.typeIndex[info.ImportPath+"."+name] = info.Package
 			}
 			for name, methodList := range methods {
 				p.methods[name] = append(p.methods[name], methodList...)
 			}
 			for name, info := range interfaces {
 				p.interfaces[name] = info
+				p.typeIndex[info.ImportPath+"."+name] = info.Package
 			}
 			for name, info := range functions {
 				p.functions[name] = info
@@ -215,11 +232,13 @@ func (p *Parser) extractStructsFromFile(file *ast.File, filePath string) map[str
 			}
 
 			info := &types.StructInfo{
-				Name:       typeSpec.Name.Name,
-				Package:    packageName,
-				FilePath:   filePath,
-				SourceCode: p.nodeToString(genDecl),
-				Fields:     p.extractFields(structType),
+				Name:        typeSpec.Name.Name,
+				Package:     packageName,
+				ImportPath:  p.importPathForFile(filePath),
+				FilePath:    filePath,
+				SourceCode:  p.nodeToString(genDecl),
+				Fields:      p.extractFields(structType),
+				Annotations: parseAnnotations(typeDoc(genDecl, typeSpec), typeSpec.Comment),
 			}
 
 			result[info.Name] = info
@@ -283,11 +302,13 @@ func (p *Parser) extractInterfacesFromFile(file *ast.File, filePath string) map[
 			}
 
 			info := &types.InterfaceInfo{
-				Name:       typeSpec.Name.Name,
-				Package:    packageName,
-				FilePath:   filePath,
-				Methods:    p.extractInterfaceMethods(interfaceType),
-				SourceCode: p.nodeToString(genDecl),
+				Name:        typeSpec.Name.Name,
+				Package:     packageName,
+				ImportPath:  p.importPathForFile(filePath),
+				FilePath:    filePath,
+				Methods:     p.extractInterfaceMethods(interfaceType),
+				SourceCode:  p.nodeToString(genDecl),
+				Annotations: parseAnnotations(typeDoc(genDecl, typeSpec), typeSpec.Comment),
 			}
 
 			result[info.Name] = info
@@ -300,6 +321,7 @@ func (p *Parser) extractInterfacesFromFile(file *ast.File, filePath string) map[
 }
 
 // extractFunctionsFromFile 从单个文件提取函数（返回结果而非直接写入）
+// 这里先收集所有有返回值的包级函数，构造函数的筛选在 filterConstructors 中完成
 func (p *Parser) extractFunctionsFromFile(file *ast.File, filePath string) map[string]*types.FunctionInfo {
 	result := make(map[string]*types.FunctionInfo)
 	packageName := file.Name.Name
@@ -311,7 +333,7 @@ func (p *Parser) extractFunctionsFromFile(file *ast.File, filePath string) map[s
 		}
 
 		funcName := funcDecl.Name.Name
-		if !strings.HasPrefix(funcName, "New") {
+		if funcName == "init" || funcName == "main" {
 			return true
 		}
 
@@ -321,11 +343,15 @@ func (p *Parser) extractFunctionsFromFile(file *ast.File, filePath string) map[s
 		}
 
 		info := &types.FunctionInfo{
-			Name:       funcName,
-			Package:    packageName,
-			FilePath:   filePath,
-			ReturnType: returnType,
-			Signature:  p.getMethodSignature(funcDecl),
+			Name:         funcName,
+			Package:      packageName,
+			FilePath:     filePath,
+			ReturnType:   returnType,
+			Signature:    p.getMethodSignature(funcDecl),
+			TypeParams:   p.getTypeParams(funcDecl.Type),
+			Params:       p.getParams(funcDecl.Type),
+			ReturnsError: p.returnsError(funcDecl),
+			Results:      funcDecl.Type.Results.NumFields(),
 		}
 
 		key := packageName + "." + funcName
@@ -337,6 +363,42 @@ func (p *Parser) extractFunctionsFromFile(file *ast.File, filePath string) map[s
 	return result
 }
 
+// filterConstructors 只保留构造函数：返回项目结构体的 New*/new* 函数，
+// 以及与结构体同包、结果恰好为 T 或 *T（可附带 error）的工厂函数（Open、Must*、FromConfig、Build 等）
+// 同时建立按导入路径查找的索引
+func (p *Parser) filterConstructors() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, fn := range p.functions {
+		if !p.isConstructor(fn) {
+			delete(p.functions, key)
+			continue
+		}
+		p.ctorIndex[p.importPathForFile(fn.FilePath)+"."+fn.Name] = fn
+	}
+}
+
+// isConstructor 判断函数是否为构造函数（见 filterConstructors）
+func (p *Parser) isConstructor(fn *types.FunctionInfo) bool {
+	baseType := ExtractBaseType(fn.ReturnType)
+	named := strings.HasPrefix(fn.Name, "New") || strings.HasPrefix(fn.Name, "new")
+	if info, ok := p.structs[baseType]; ok {
+		if named {
+			return true
+		}
+		// 工厂函数：T、*T、(T, error) 或 (*T, error)，不包括切片、map 等
+		elem := strings.TrimPrefix(fn.ReturnType, "*")
+		single := fn.Results == 1 || fn.Results == 2 && fn.ReturnsError
+		return single && elem != "" && !strings.ContainsAny(elem[:1], "[*") && !strings.HasPrefix(elem, "map[") &&
+			!strings.HasPrefix(elem, "chan ") && !strings.HasPrefix(elem, "func(") &&
+			filepath.Dir(info.FilePath) == filepath.Dir(fn.FilePath)
+	}
+	// 保留返回项目接口的 New* 函数，兼容原有行为
+	_, ok := p.interfaces[baseType]
+	return ok && strings.HasPrefix(fn.Name, "New")
+}
+
 // GetModuleName 返回项目模块名
 func (p *Parser) GetModuleName() string {
 	return p.moduleName
@@ -363,6 +425,11 @@ func (p *Parser) GetFile(path string) *ast.File {
 	return p.files[path]
 }
 
+// GetAllFiles 获取所有已解析文件的 AST（文件路径 -> AST）
+func (p *Parser) GetAllFiles() map[string]*ast.File {
+	return p.files
+}
+
 // GetFileSet 获取 FileSet
 func (p *Parser) GetFileSet() *token.FileSet {
 	return p.fset
@@ -431,6 +498,11 @@ func (p *Parser) buildImportMap(file *ast.File) map[string]string {
 			// 无别名: import "github.com/bar/baz"
 			parts := strings.Split(path, "/")
 			pkgName = parts[len(parts)-1]
+
+			// 按惯例推断包名: github.com/redis/go-redis/v9 -> redis
+			if guessed := guessPackageName(path); guessed != pkgName {
+				importMap[guessed] = path
+			}
 		}
 
 		importMap[pkgName] = path
@@ -439,6 +511,19 @@ func (p *Parser) buildImportMap(file *ast.File) map[string]string {
 	return importMap
 }
 
+// guessPackageName 根据导入路径按惯例推断包名
+// 去掉主版本后缀（/v2）、go- 前缀和 -go/.go 后缀
+func guessPackageName(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = parts[len(parts)-2]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	name = strings.TrimSuffix(name, ".go")
+	return strings.ReplaceAll(name, "-", "")
+}
 
 // extractFields 从结构体中提取字段
 func (p *Parser) extractFields(structType *ast.StructType) []types.FieldInfo {
@@ -454,24 +539,29 @@ func (p *Parser) extractFields(structType *ast.StructType) []types.FieldInfo {
 		if field.Tag != nil {
 			tag = field.Tag.Value
 		}
+		annotations := parseAnnotations(field.Doc, field.Comment)
 
 		if len(field.Names) == 0 {
 			// 嵌入字段
 			fields = append(fields, types.FieldInfo{
-				Name:       typeName,
-				Type:       typeName,
-				Tag:        tag,
-				IsExported: isExported(typeName),
-				IsEmbedded: true,
+				Name:        typeName,
+				Type:        typeName,
+				Tag:         tag,
+				IsExported:  isExported(typeName),
+				IsEmbedded:  true,
+				Tags:        ParseFieldTag(tag, typeName),
+				Annotations: annotations,
 			})
 		} else {
 			for _, name := range field.Names {
 				fields = append(fields, types.FieldInfo{
-					Name:       name.Name,
-					Type:       typeName,
-					Tag:        tag,
-					IsExported: isExported(name.Name),
-					IsEmbedded: false,
+					Name:        name.Name,
+					Type:        typeName,
+					Tag:         tag,
+					IsExported:  isExported(name.Name),
+					IsEmbedded:  false,
+					Tags:        ParseFieldTag(tag, name.Name),
+					Annotations: annotations,
 				})
 			}
 		}
@@ -480,7 +570,6 @@ func (p *Parser) extractFields(structType *ast.StructType) []types.FieldInfo {
 	return fields
 }
 
-
 // getReceiverType 获取方法接收者类型
 func (p *Parser) getReceiverType(recv *ast.FieldList) string {
 	if recv == nil || len(recv.List) == 0 {
@@ -558,6 +647,16 @@ func (p *Parser) getTypeName(expr ast.Expr) string {
 		return "chan " + p.getTypeName(t.Value)
 	case *ast.Ellipsis:
 		return "..." + p.getTypeName(t.Elt)
+	case *ast.IndexExpr:
+		// 泛型实例化: Box[T]
+		return p.getTypeName(t.X) + "[" + p.getTypeName(t.Index) + "]"
+	case *ast.IndexListExpr:
+		// 多类型参数泛型实例化: Pair[K, V]
+		args := make([]string, 0, len(t.Indices))
+		for _, idx := range t.Indices {
+			args = append(args, p.getTypeName(idx))
+		}
+		return p.getTypeName(t.X) + "[" + strings.Join(args, ", ") + "]"
 	default:
 		return "unknown"
 	}
@@ -583,7 +682,6 @@ func (p *Parser) GetImports(filePath string) map[string]string {
 	return p.imports[filePath]
 }
 
-
 // extractInterfaceMethods 提取接口方法
 func (p *Parser) extractInterfaceMethods(interfaceType *ast.InterfaceType) []types.InterfaceMethod {
 	var methods []types.InterfaceMethod
@@ -655,16 +753,69 @@ func (p *Parser) getFuncSignature(funcType *ast.FuncType) string {
 	return sig.String()
 }
 
-
-// getReturnType 获取函数的主要返回类型
+// getReturnType 获取函数的主要返回类型（第一个非 error 返回值）
 func (p *Parser) getReturnType(funcDecl *ast.FuncDecl) string {
 	if funcDecl.Type.Results == nil || len(funcDecl.Type.Results.List) == 0 {
 		return ""
 	}
 
-	// 获取第一个返回值类型（通常是主要类型，error 一般在后面）
-	firstResult := funcDecl.Type.Results.List[0]
-	return p.getTypeName(firstResult.Type)
+	for _, result := range funcDecl.Type.Results.List {
+		typeName := p.getTypeName(result.Type)
+		if typeName != "error" {
+			return typeName
+		}
+	}
+	return ""
+}
+
+// returnsError 判断函数是否返回 error
+func (p *Parser) returnsError(funcDecl *ast.FuncDecl) bool {
+	if funcDecl.Type.Results == nil {
+		return false
+	}
+	for _, result := range funcDecl.Type.Results.List {
+		if p.getTypeName(result.Type) == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// getParams 获取函数参数列表
+func (p *Parser) getParams(funcType *ast.FuncType) []types.ParamInfo {
+	var params []types.ParamInfo
+	if funcType.Params == nil {
+		return params
+	}
+
+	for _, field := range funcType.Params.List {
+		typeName := p.getTypeName(field.Type)
+		if len(field.Names) == 0 {
+			params = append(params, types.ParamInfo{Type: typeName})
+			continue
+		}
+		for _, name := range field.Names {
+			params = append(params, types.ParamInfo{Name: name.Name, Type: typeName})
+		}
+	}
+	return params
+}
+
+// getTypeParams 获取泛型类型参数，如 "[T any, K comparable]"
+func (p *Parser) getTypeParams(funcType *ast.FuncType) string {
+	if funcType.TypeParams == nil || len(funcType.TypeParams.List) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, field := range funcType.TypeParams.List {
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		parts = append(parts, strings.Join(names, ", ")+" "+p.nodeToString(field.Type))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
 }
 
 // GetAllInterfaces 获取所有接口信息
@@ -677,6 +828,12 @@ func (p *Parser) GetInterface(name string) *types.InterfaceInfo {
 	return p.interfaces[name]
 }
 
+// TypePackage 返回导入路径为 importPath 的包中声明的结构体或接口 name 所在的包名，未声明时返回空
+// GetStruct/GetInterface 按名称查找，同名类型存在于多个包时只能取到其中一个
+func (p *Parser) TypePackage(importPath, name string) string {
+	return p.typeIndex[importPath+"."+name]
+}
+
 // GetFunction 根据名称获取函数信息
 func (p *Parser) GetFunction(name string) *types.FunctionInfo {
 	return p.functions[name]
@@ -688,15 +845,65 @@ func (p *Parser) GetAllFunctions() map[string]*types.FunctionInfo {
 }
 
 // GetFunctionByReturnType 根据返回类型查找构造函数
+// 存在多个候选时优先返回 New<Type>，其次是 New 前缀函数，最后按名称排序
 func (p *Parser) GetFunctionByReturnType(returnType string) *types.FunctionInfo {
-	// 标准化返回类型（去除指针符号）
-	baseType := strings.TrimPrefix(returnType, "*")
+	candidates := p.GetFunctionsByReturnType(returnType)
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[0]
+}
+
+// GetFunctionsByReturnType 获取返回指定类型的所有构造函数（按优先级排序）
+func (p *Parser) GetFunctionsByReturnType(returnType string) []*types.FunctionInfo {
+	baseType := ExtractBaseType(returnType)
 
+	var result []*types.FunctionInfo
 	for _, fn := range p.functions {
-		fnBaseType := strings.TrimPrefix(fn.ReturnType, "*")
-		if fnBaseType == baseType || fnBaseType == returnType {
-			return fn
+		if ExtractBaseType(fn.ReturnType) == baseType {
+			result = append(result, fn)
 		}
 	}
-	return nil
+
+	rank := func(fn *types.FunctionInfo) int {
+		switch {
+		case fn.Name == "New"+baseType:
+			return 0
+		case strings.HasPrefix(fn.Name, "New"):
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ri, rj := rank(result[i]), rank(result[j])
+		if ri != rj {
+			return ri < rj
+		}
+		if result[i].Package != result[j].Package {
+			return result[i].Package < result[j].Package
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// LookupConstructor 根据包导入路径和函数名查找构造函数
+// importPath 为空时按函数名在所有包中查找，存在多个同名函数时按包名排序取第一个
+func (p *Parser) LookupConstructor(importPath, funcName string) *types.FunctionInfo {
+	if importPath != "" {
+		return p.ctorIndex[importPath+"."+funcName]
+	}
+
+	var keys []string
+	for key, fn := range p.functions {
+		if fn.Name == funcName {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	return p.functions[keys[0]]
 }
@@ -1,9 +1,12 @@
 package parser
 
 import (
+	"go/ast"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/user/go-struct-analyzer/internal/types"
 )
 
 func TestParser_ParseProject(t *testing.T) {
@@ -188,3 +191,227 @@ func TestParser_FindGoFiles(t *testing.T) {
 		}
 	}
 }
+
+func TestParser_ConstructorDetection(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := `package store
+
+import "errors"
+
+type Store struct{ db *DB }
+type DB struct{}
+type Box[T any] struct{ v T }
+
+func Open(path string) (*Store, error) { return nil, errors.New("x") }
+func MustStore(db *DB) *Store           { return &Store{db: db} }
+func NewStore(db *DB) *Store            { return &Store{db: db} }
+func FromConfig(cfg map[string]string) (*DB, error) { return &DB{}, nil }
+func NewBox[T any](v T) *Box[T]         { return &Box[T]{v: v} }
+func Helper() string                    { return "" }
+func Fail() error                       { return nil }
+`
+	os.WriteFile(filepath.Join(tmpDir, "store.go"), []byte(src), 0644)
+
+	p := NewParser(false)
+	if err := p.ParseProject(tmpDir); err != nil {
+		t.Fatalf("ParseProject failed: %v", err)
+	}
+
+	for _, key := range []string{"store.Open", "store.MustStore", "store.NewStore", "store.FromConfig", "store.NewBox"} {
+		if p.GetFunction(key) == nil {
+			t.Errorf("expected %s to be detected as constructor", key)
+		}
+	}
+	for _, key := range []string{"store.Helper", "store.Fail"} {
+		if p.GetFunction(key) != nil {
+			t.Errorf("%s should not be detected as constructor", key)
+		}
+	}
+
+	open := p.GetFunction("store.Open")
+	if open != nil {
+		if open.ReturnType != "*Store" || !open.ReturnsError {
+			t.Errorf("Open: ReturnType=%q ReturnsError=%v", open.ReturnType, open.ReturnsError)
+		}
+		if len(open.Params) != 1 || open.Params[0].Name != "path" || open.Params[0].Type != "string" {
+			t.Errorf("Open params = %+v", open.Params)
+		}
+	}
+
+	if box := p.GetFunction("store.NewBox"); box != nil {
+		if box.TypeParams != "[T any]" || box.ReturnType != "*Box[T]" {
+			t.Errorf("NewBox: TypeParams=%q ReturnType=%q", box.TypeParams, box.ReturnType)
+		}
+	}
+
+	// 多个候选时优先 New<Type>
+	if fn := p.GetFunctionByReturnType("*Store"); fn == nil || fn.Name != "NewStore" {
+		t.Errorf("GetFunctionByReturnType(*Store) = %+v, want NewStore", fn)
+	}
+	if got := len(p.GetFunctionsByReturnType("Store")); got != 3 {
+		t.Errorf("GetFunctionsByReturnType(Store) returned %d, want 3", got)
+	}
+}
+
+func TestParser_ConstructorLookupByImportPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/m\n",
+		"store/store.go": `package store
+
+type Store struct{}
+
+func Open() (*Store, error) { return &Store{}, nil }
+func All() []*Store         { return nil }
+`,
+		"util/util.go": `package util
+
+import "example.com/m/store"
+
+func Load() *store.Store { return nil }
+`,
+		"app/app.go": `package app
+
+import st "example.com/m/store"
+
+func run() {
+	s, _ := st.Open()
+	_ = s
+}
+`,
+	}
+	for name, src := range files {
+		path := filepath.Join(tmpDir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(src), 0644)
+	}
+
+	p := NewParser(false)
+	if err := p.ParseProject(tmpDir); err != nil {
+		t.Fatalf("ParseProject failed: %v", err)
+	}
+
+	if fn := p.LookupConstructor("example.com/m/store", "Open"); fn == nil || fn.Name != "Open" {
+		t.Errorf("LookupConstructor(example.com/m/store, Open) = %+v, want Open", fn)
+	}
+	if fn := p.LookupConstructor("store", "Open"); fn != nil {
+		t.Errorf("LookupConstructor should be keyed by import path, got %+v for package name", fn)
+	}
+	// 其他包中返回项目结构体的普通函数、返回切片的函数都不是构造函数
+	for _, key := range []string{"util.Load", "store.All"} {
+		if p.GetFunction(key) != nil {
+			t.Errorf("%s should not be detected as constructor", key)
+		}
+	}
+
+	// 别名导入: st.Open() 按导入路径解析到 store.Open
+	appFile := filepath.Join(tmpDir, "app", "app.go")
+	var call *ast.CallExpr
+	ast.Inspect(p.GetAllFiles()[appFile], func(n ast.Node) bool {
+		if ce, ok := n.(*ast.CallExpr); ok && call == nil {
+			call = ce
+		}
+		return true
+	})
+	if call == nil {
+		t.Fatal("Failed to find call expression")
+	}
+	if got := NewTypeResolver(p).ForFile(appFile).InferTypeFromExpr(call); got != "*st.Store" {
+		t.Errorf("InferTypeFromExpr(st.Open()) = %q, want %q", got, "*st.Store")
+	}
+}
+
+func TestParser_Annotations(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := `package domain
+
+// Order 订单聚合根
+//structanalyzer:layer=domain
+//structanalyzer:desc "订单聚合根，负责订单状态流转"
+type Order struct {
+	//structanalyzer:ignore
+	Audit *AuditLog
+	Items []Item // structanalyzer:desc "订单明细"
+}
+
+type (
+	//structanalyzer:stop
+	//structanalyzer:role=model
+	AuditLog struct{}
+
+	Item struct{}
+)
+
+//structanalyzer:ignore
+type Repository interface {
+	Save(o *Order) error
+}
+`
+	os.WriteFile(filepath.Join(tmpDir, "order.go"), []byte(src), 0644)
+
+	p := NewParser(false)
+	if err := p.ParseProject(tmpDir); err != nil {
+		t.Fatalf("ParseProject failed: %v", err)
+	}
+
+	order := p.GetStruct("Order")
+	if order == nil {
+		t.Fatal("Order not found")
+	}
+	if order.Annotations.Layer != "domain" || order.Annotations.Desc != "订单聚合根，负责订单状态流转" {
+		t.Errorf("Order annotations = %+v", order.Annotations)
+	}
+	if !order.Fields[0].Annotations.Ignore {
+		t.Error("Audit field should be ignored")
+	}
+	if order.Fields[1].Annotations.Desc != "订单明细" {
+		t.Errorf("Items field desc = %q", order.Fields[1].Annotations.Desc)
+	}
+	if role := p.GetStruct("AuditLog").Annotations.Role; role != "model" {
+		t.Errorf("AuditLog role = %q, want model", role)
+	}
+
+	if !p.GetAnnotations("AuditLog").Stop {
+		t.Error("AuditLog in grouped declaration should carry stop")
+	}
+	if ann := p.GetAnnotations("Item"); ann != (types.Annotations{}) {
+		t.Errorf("Item should have no annotations, got %+v", ann)
+	}
+	if !p.GetAnnotations("Repository").Ignore {
+		t.Error("Repository interface should be ignored")
+	}
+}
+
+func TestParseFieldTag(t *testing.T) {
+	tags := ParseFieldTag("`json:\"user_id,omitempty\" yaml:\",flow\" db:\"uid\" gorm:\"column:uid;primaryKey;type:bigint\" validate:\"required, min=1\" x:\"a\\\"b\"`", "UserID")
+
+	if tags.Values["x"] != `a"b` {
+		t.Errorf("Values[x] = %q", tags.Values["x"])
+	}
+	for key, want := range map[string]string{"json": "user_id", "yaml": "userid", "db": "uid", "gorm": "uid"} {
+		if got := tags.Names[key]; got != want {
+			t.Errorf("Names[%s] = %q, want %q", key, got, want)
+		}
+	}
+	if opts := tags.Options["json"]; len(opts) != 1 || opts[0] != "omitempty" {
+		t.Errorf("Options[json] = %v", opts)
+	}
+	if _, ok := tags.Gorm["primarykey"]; !ok || tags.Gorm["type"] != "bigint" {
+		t.Errorf("Gorm = %v", tags.Gorm)
+	}
+	if len(tags.Validate) != 2 || tags.Validate[1] != "min=1" {
+		t.Errorf("Validate = %v", tags.Validate)
+	}
+
+	// json 未写名称时使用字段名；格式错误的部分之后停止解析
+	tags = ParseFieldTag("`json:\",omitempty\" bad gorm:\"column:x\"`", "Name")
+	if tags.Names["json"] != "Name" {
+		t.Errorf("Names[json] = %q, want Name", tags.Names["json"])
+	}
+	if _, ok := tags.Values["gorm"]; ok {
+		t.Error("gorm after malformed part should not be parsed")
+	}
+	if tags := ParseFieldTag("", "Name"); tags.Values != nil || tags.Names != nil {
+		t.Errorf("empty tag = %+v", tags)
+	}
+}
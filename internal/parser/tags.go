@@ -0,0 +1,147 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// serializationKeys 以逗号分隔 "名称,选项..." 的序列化标签
+var serializationKeys = []string{"json", "yaml", "db", "bson"}
+
+// ParseFieldTag 按 reflect.StructTag 规则解析字段标签
+// raw 为源码中的标签字面量（含反引号或引号）；fieldName 用于推导未写名称时的默认序列化名
+func ParseFieldTag(raw, fieldName string) types.FieldTags {
+	var tags types.FieldTags
+	if raw == "" {
+		return tags
+	}
+	tag, err := strconv.Unquote(raw)
+	if err != nil {
+		tag = raw
+	}
+
+	tags.Values = splitStructTag(tag)
+	if len(tags.Values) == 0 {
+		return tags
+	}
+
+	for _, key := range serializationKeys {
+		value, ok := tags.Values[key]
+		if !ok {
+			continue
+		}
+		parts := strings.Split(value, ",")
+		name := parts[0]
+		if name == "" {
+			name = defaultSerializedName(key, fieldName)
+		}
+		if tags.Names == nil {
+			tags.Names = make(map[string]string)
+			tags.Options = make(map[string][]string)
+		}
+		tags.Names[key] = name
+		for _, opt := range parts[1:] {
+			if opt = strings.TrimSpace(opt); opt != "" {
+				tags.Options[key] = append(tags.Options[key], opt)
+			}
+		}
+	}
+
+	if value, ok := tags.Values["gorm"]; ok {
+		tags.Gorm = parseGormTag(value)
+		if column := tags.Gorm["column"]; column != "" {
+			if tags.Names == nil {
+				tags.Names = make(map[string]string)
+			}
+			tags.Names["gorm"] = column
+		}
+	}
+
+	if value, ok := tags.Values["validate"]; ok {
+		for _, rule := range strings.Split(value, ",") {
+			if rule = strings.TrimSpace(rule); rule != "" {
+				tags.Validate = append(tags.Validate, rule)
+			}
+		}
+	}
+	return tags
+}
+
+// defaultSerializedName 返回标签未写名称时的默认序列化名：json 使用字段名，yaml、db、bson 使用小写字段名
+func defaultSerializedName(key, fieldName string) string {
+	if key == "json" {
+		return fieldName
+	}
+	return strings.ToLower(fieldName)
+}
+
+// splitStructTag 将 `key:"value" key2:"value2"` 形式的标签拆分为键值对（与 reflect.StructTag.Lookup 的规则相同）
+// 遇到格式错误时停止解析，返回已解析的部分
+func splitStructTag(tag string) map[string]string {
+	values := make(map[string]string)
+	for tag != "" {
+		// 跳过前导空格
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// 键：非控制字符、非空格、非引号、非冒号
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		// 值：带引号的字符串
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		quoted := tag[:i+1]
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			break
+		}
+		if _, dup := values[key]; !dup {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// parseGormTag 解析 gorm 标签："column:name;type:varchar(100);not null;primaryKey"
+// 键统一为小写；没有值的设置值为空字符串
+func parseGormTag(value string) map[string]string {
+	settings := make(map[string]string)
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, val := item, ""
+		if idx := strings.Index(item, ":"); idx != -1 {
+			key, val = item[:idx], strings.TrimSpace(item[idx+1:])
+		}
+		settings[strings.ToLower(strings.TrimSpace(key))] = val
+	}
+	return settings
+}
@@ -8,7 +8,9 @@ import (
 
 // TypeResolver 用于解析和推断类型
 type TypeResolver struct {
-	parser *Parser
+	parser     *Parser
+	imports    map[string]string // 所在文件的导入（别名 -> 导入路径），未绑定文件时为 nil
+	importPath string            // 所在文件的包导入路径
 }
 
 // NewTypeResolver 创建类型解析器
@@ -16,6 +18,15 @@ func NewTypeResolver(p *Parser) *TypeResolver {
 	return &TypeResolver{parser: p}
 }
 
+// ForFile 返回绑定到 filePath 的类型解析器：按文件的导入（包括别名）解析 pkg.Func() 形式的构造函数调用
+func (r *TypeResolver) ForFile(filePath string) *TypeResolver {
+	return &TypeResolver{
+		parser:     r.parser,
+		imports:    r.parser.GetImports(filePath),
+		importPath: r.parser.ImportPathOf(filePath),
+	}
+}
+
 // TypeContext 表示方法体中的变量类型上下文
 type TypeContext struct {
 	variables map[string]string // 变量名 -> 类型名
@@ -146,20 +157,52 @@ func (r *TypeResolver) inferTypeFromCall(call *ast.CallExpr) string {
 		if fun.Name == "make" && len(call.Args) > 0 {
 			return r.parser.getTypeName(call.Args[0])
 		}
+		// 已知构造函数: Open() -> 解析得到的返回类型
+		if fn := r.parser.LookupConstructor(r.importPath, fun.Name); fn != nil {
+			return fn.ReturnType
+		}
 		// NewXxx() -> 可能返回 *Xxx
 		if len(fun.Name) > 3 && strings.HasPrefix(fun.Name, "New") {
 			return "*" + fun.Name[3:]
 		}
 	case *ast.SelectorExpr:
+		pkgName := r.parser.getTypeName(fun.X)
+		// 已知构造函数: pkg.Open() -> 解析得到的返回类型（补全包前缀）
+		if importPath, ok := r.imports[pkgName]; ok {
+			if fn := r.parser.LookupConstructor(importPath, fun.Sel.Name); fn != nil {
+				return qualifyType(fn.ReturnType, pkgName)
+			}
+		}
 		// pkg.NewXxx() -> 可能返回 *pkg.Xxx
 		if len(fun.Sel.Name) > 3 && strings.HasPrefix(fun.Sel.Name, "New") {
-			pkgName := r.parser.getTypeName(fun.X)
 			return "*" + pkgName + "." + fun.Sel.Name[3:]
 		}
 	}
 	return ""
 }
 
+// qualifyType 为未带包前缀的类型名补全包前缀，保留指针和切片修饰符
+func qualifyType(typeName, pkgName string) string {
+	prefix := ""
+	for {
+		switch {
+		case strings.HasPrefix(typeName, "*"):
+			prefix += "*"
+			typeName = typeName[1:]
+			continue
+		case strings.HasPrefix(typeName, "[]"):
+			prefix += "[]"
+			typeName = typeName[2:]
+			continue
+		}
+		break
+	}
+	if strings.Contains(typeName, ".") {
+		return prefix + typeName
+	}
+	return prefix + pkgName + "." + typeName
+}
+
 // InferType 从表达式和上下文推断类型
 func (r *TypeResolver) InferType(expr ast.Expr, ctx *TypeContext) string {
 	// 先尝试直接推断
@@ -181,12 +224,37 @@ func (r *TypeResolver) InferType(expr ast.Expr, ctx *TypeContext) string {
 	return r.parser.getTypeName(expr)
 }
 
-// ExtractBaseType 提取基础类型名（去掉指针、切片等修饰符）
+// ExtractQualifiedType 提取带包前缀的基础类型名（如 *[]gorm.DB -> gorm.DB）
+func ExtractQualifiedType(typeName string) string {
+	base := ExtractBaseType(typeName)
+	typeName = strings.TrimPrefix(typeName, "...")
+	for _, prefix := range []string{"*", "[]", "*"} {
+		typeName = strings.TrimPrefix(typeName, prefix)
+	}
+	if strings.HasPrefix(typeName, "map[") {
+		if idx := strings.Index(typeName, "]"); idx != -1 {
+			typeName = strings.TrimPrefix(typeName[idx+1:], "*")
+		}
+	}
+	if idx := strings.Index(typeName, "["); idx > 0 {
+		typeName = typeName[:idx]
+	}
+	if idx := strings.LastIndex(typeName, "."); idx != -1 && typeName[idx+1:] == base {
+		return typeName
+	}
+	return base
+}
+
+// ExtractBaseType 提取基础类型名（去掉指针、切片、可变参数、泛型参数等修饰符）
 func ExtractBaseType(typeName string) string {
+	// 去掉可变参数
+	typeName = strings.TrimPrefix(typeName, "...")
 	// 去掉指针
 	typeName = strings.TrimPrefix(typeName, "*")
-	// 去掉切片
-	typeName = strings.TrimPrefix(typeName, "[]")
+	// 去掉切片（以及切片元素的指针: []*User）
+	if strings.HasPrefix(typeName, "[]") {
+		typeName = strings.TrimPrefix(typeName[2:], "*")
+	}
 	// 去掉 map
 	if strings.HasPrefix(typeName, "map[") {
 		// 简化处理，取值类型
@@ -194,6 +262,10 @@ func ExtractBaseType(typeName string) string {
 			typeName = typeName[idx+1:]
 		}
 	}
+	// 去掉泛型参数: Box[T] -> Box
+	if idx := strings.Index(typeName, "["); idx > 0 {
+		typeName = typeName[:idx]
+	}
 	// 取最后一部分（结构体名）
 	parts := strings.Split(typeName, ".")
 	return parts[len(parts)-1]
@@ -20,6 +20,10 @@ func TestExtractBaseType(t *testing.T) {
 		{"package type", "model.User", "User"},
 		{"pointer package type", "*model.User", "User"},
 		{"double pointer", "**User", "*User"}, // ExtractBaseType only removes one level
+		{"slice of pointers", "[]*model.User", "User"},
+		{"variadic", "...Option", "Option"},
+		{"generic type", "*Box[T]", "Box"},
+		{"generic package type", "cache.Pair[K, V]", "Pair"},
 		{"empty string", "", ""},
 	}
 
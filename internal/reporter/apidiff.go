@@ -0,0 +1,97 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// GenerateAPIDiff 生成导出 API 差异的 Markdown（不兼容的变化在前）
+func (r *MarkdownReporter) GenerateAPIDiff(result *types.APIDiffResult) string {
+	r.builder.Reset()
+
+	r.builder.WriteString("## 导出 API 变化\n\n")
+	r.builder.WriteString(fmt.Sprintf("**对比**: `%s` → `%s`\n", result.Old, result.New))
+	r.builder.WriteString(fmt.Sprintf("**不兼容变化**: %d | **兼容变化**: %d\n\n", result.Breaking, result.Compatible))
+
+	if len(result.Changes) == 0 {
+		r.builder.WriteString("导出 API 没有变化。\n")
+		return r.builder.String()
+	}
+
+	for _, section := range []struct {
+		title    string
+		breaking bool
+		count    int
+	}{
+		{"### 不兼容的变化", true, result.Breaking},
+		{"### 兼容的变化", false, result.Compatible},
+	} {
+		if section.count == 0 {
+			continue
+		}
+		r.builder.WriteString(fmt.Sprintf("%s (%d)\n\n", section.title, section.count))
+		r.builder.WriteString("| 声明 | 包 | 种类 | 变化 | 变化前 | 变化后 | 说明 | 位置 |\n")
+		r.builder.WriteString("|------|----|------|------|--------|--------|------|------|\n")
+		for _, c := range result.Changes {
+			if c.Breaking != section.breaking {
+				continue
+			}
+			location := ""
+			if c.FilePath != "" {
+				location = fmt.Sprintf("%s:%d", c.FilePath, c.Line)
+			}
+			r.builder.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | %s | %s | %s | %s |\n",
+				c.Name, c.Package, apiKindLabel(c.Kind), apiChangeLabel(c.Change),
+				codeCell(c.Old), codeCell(c.New), c.Reason, location))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	return r.builder.String()
+}
+
+// codeCell 将类型或签名写成表格中的行内代码，为空时留空（行内代码中只需转义竖线）
+func codeCell(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(s, "|", "\\|") + "`"
+}
+
+// apiKindLabel 获取 API 声明种类的中文标签
+func apiKindLabel(kind string) string {
+	switch kind {
+	case types.APIKindStruct:
+		return "结构体"
+	case types.APIKindInterface:
+		return "接口"
+	case types.APIKindType:
+		return "类型"
+	case types.APIKindFunction:
+		return "函数"
+	case types.APIKindField:
+		return "字段"
+	case types.APIKindEmbedded:
+		return "嵌入类型"
+	case types.APIKindMethod:
+		return "方法"
+	default:
+		return kind
+	}
+}
+
+// apiChangeLabel 获取 API 变化种类的中文标签
+func apiChangeLabel(change string) string {
+	switch change {
+	case types.APIChangeAdded:
+		return "新增"
+	case types.APIChangeRemoved:
+		return "删除"
+	case types.APIChangeChanged:
+		return "修改"
+	default:
+		return change
+	}
+}
@@ -0,0 +1,179 @@
+package reporter
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// clusterFills 社区在 Mermaid 图中的填充色（循环使用）
+var clusterFills = []string{"#ffcccc", "#cce5ff", "#ccffcc", "#ffe5cc", "#e5ccff", "#ffffcc", "#ccffff", "#eeeeee"}
+
+// clusterColors 社区在可视化工具中的颜色（循环使用）
+var clusterColors = []string{"red", "blue", "green", "orange", "gray", "black"}
+
+// GenerateClusters 生成社区划分的 Markdown：模块度对比、每个社区的成员、位置不当的结构体和建议拆分的包
+func (r *MarkdownReporter) GenerateClusters(result *types.ClusterResult) string {
+	r.builder.Reset()
+
+	r.builder.WriteString("## 模块边界建议\n\n")
+	if len(result.DepTypes) > 0 {
+		r.builder.WriteString(fmt.Sprintf("**依赖类型**: %s\n\n", strings.Join(result.DepTypes, ", ")))
+	}
+	r.builder.WriteString(fmt.Sprintf("**社区**: %d | **位置不当的结构体**: %d | **建议拆分的包**: %d | **孤立结构体**: %d\n\n",
+		len(result.Clusters), len(result.Misplaced), len(result.SplitPackages), len(result.Isolated)))
+	if len(result.Clusters) == 0 {
+		r.builder.WriteString("结构体之间没有依赖，无法划分社区。\n")
+		return r.builder.String()
+	}
+	r.builder.WriteString(fmt.Sprintf("**模块度**: 检测到的划分 %.4f，实际包布局 %.4f\n\n", result.Modularity, result.PackageModularity))
+	r.builder.WriteString("> 模块度越高，社区内部的依赖越密集、社区之间的依赖越少；检测到的划分明显高于包布局时，说明按包划分模块会留下较多跨模块依赖。\n\n")
+
+	// 1. 社区
+	r.builder.WriteString("### 社区\n\n")
+	r.builder.WriteString("| 社区 | 主要包 | 结构体数 | 内部依赖 | 外部依赖 | 成员 |\n")
+	r.builder.WriteString("|------|--------|----------|----------|----------|------|\n")
+	for _, c := range result.Clusters {
+		var groups []string
+		for _, group := range c.Packages {
+			groups = append(groups, fmt.Sprintf("`%s`: %s", group.ImportPath, strings.Join(group.Structs, ", ")))
+		}
+		r.builder.WriteString(fmt.Sprintf("| %d | `%s` | %d | %d | %d | %s |\n",
+			c.ID, c.Package, len(c.Structs), c.Internal, c.External, strings.Join(groups, "<br/>")))
+	}
+	r.builder.WriteString("\n")
+
+	// 2. 位置不当的结构体
+	if len(result.Misplaced) > 0 {
+		r.builder.WriteString("### 位置不当的结构体\n\n")
+		r.builder.WriteString("这些结构体与另一个包的结构体联系更紧密，可以考虑移入建议的包。\n\n")
+		r.builder.WriteString("| 结构体 | 当前包 | 社区 | 建议移入 | 与当前包的依赖 | 与社区的依赖 |\n")
+		r.builder.WriteString("|--------|--------|------|----------|----------------|--------------|\n")
+		for _, m := range result.Misplaced {
+			r.builder.WriteString(fmt.Sprintf("| %s | `%s` | %d | `%s` | %d | %d |\n",
+				m.Struct, m.Package, m.Cluster, m.Suggested, m.PackageLinks, m.ClusterLinks))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	// 3. 建议拆分的包
+	if len(result.SplitPackages) > 0 {
+		r.builder.WriteString("### 建议拆分的包\n\n")
+		for _, split := range result.SplitPackages {
+			r.builder.WriteString(fmt.Sprintf("- `%s`\n", split.Package))
+			for _, part := range split.Parts {
+				r.builder.WriteString(fmt.Sprintf("  - 社区 %d: %s\n", part.Cluster, strings.Join(part.Structs, ", ")))
+			}
+		}
+		r.builder.WriteString("\n")
+	}
+
+	if len(result.Isolated) > 0 {
+		r.builder.WriteString(fmt.Sprintf("**孤立结构体**（与其他结构体没有依赖，未参与划分）: %s\n\n", strings.Join(result.Isolated, ", ")))
+	}
+
+	// 4. 社区图
+	r.builder.WriteString("### 社区图\n\n")
+	r.builder.WriteString("```mermaid\n")
+	r.builder.WriteString(NewMermaidGenerator().GenerateClusters(result))
+	r.builder.WriteString("```\n")
+
+	return r.builder.String()
+}
+
+// GenerateClusters 生成按社区分组着色的 Mermaid 图，边上标注依赖数，位置不当的结构体用红色粗边框标出
+func (m *MermaidGenerator) GenerateClusters(result *types.ClusterResult) string {
+	m.builder.Reset()
+	m.builder.WriteString("graph LR\n")
+
+	pkgOf := make(map[string]string)
+	for _, c := range result.Clusters {
+		m.builder.WriteString(fmt.Sprintf("    subgraph cluster_%d[\"社区 %d · %s\"]\n", c.ID, c.ID, path.Base(c.Package)))
+		for _, group := range c.Packages {
+			for _, name := range group.Structs {
+				pkgOf[name] = group.ImportPath
+				m.builder.WriteString(fmt.Sprintf("        %s[\"%s<br/>%s\"]\n", sanitizeID(name), name, path.Base(group.ImportPath)))
+			}
+		}
+		m.builder.WriteString("    end\n")
+	}
+	m.builder.WriteString("\n")
+
+	for _, e := range result.Edges {
+		m.builder.WriteString(fmt.Sprintf("    %s ---|%d| %s\n", sanitizeID(e.From), e.Weight, sanitizeID(e.To)))
+	}
+	m.builder.WriteString("\n")
+
+	for _, c := range result.Clusters {
+		ids := make([]string, 0, len(c.Structs))
+		for _, name := range c.Structs {
+			ids = append(ids, sanitizeID(name))
+		}
+		m.builder.WriteString(fmt.Sprintf("    classDef c%d fill:%s\n", c.ID, clusterFills[(c.ID-1)%len(clusterFills)]))
+		m.builder.WriteString(fmt.Sprintf("    class %s c%d\n", strings.Join(ids, ","), c.ID))
+	}
+	for _, mis := range result.Misplaced {
+		m.builder.WriteString(fmt.Sprintf("    style %s stroke:#cf222e,stroke-width:3px\n", sanitizeID(mis.Struct)))
+	}
+
+	return m.builder.String()
+}
+
+// GenerateClusters 生成社区视图：每个社区一行，节点颜色表示社区，位置不当的结构体在描述中给出建议的包
+func (r *VisualizerReporter) GenerateClusters(result *types.ClusterResult) *VisualizerOutput {
+	output := &VisualizerOutput{
+		Structs:     make([]VisualizerStruct, 0),
+		Connections: make([]VisualizerConnect, 0, len(result.Edges)),
+	}
+
+	rows := make(map[int][]string)
+	for i, c := range result.Clusters {
+		rows[i] = c.Structs
+	}
+	positions := r.calculateLayout(rows)
+
+	misplaced := make(map[string]types.MisplacedStruct)
+	for _, m := range result.Misplaced {
+		misplaced[m.Struct] = m
+	}
+
+	for _, c := range result.Clusters {
+		color := clusterColors[(c.ID-1)%len(clusterColors)]
+		for _, group := range c.Packages {
+			for _, name := range group.Structs {
+				description := fmt.Sprintf("社区 %d", c.ID)
+				if m, ok := misplaced[name]; ok {
+					description += "，建议移入 " + m.Suggested
+				}
+				pos := positions[name]
+				output.Structs = append(output.Structs, VisualizerStruct{
+					ID: "struct-" + name,
+					X:  pos.X,
+					Y:  pos.Y,
+					Metadata: StructBoxMetadata{
+						Type:             "struct-box",
+						Name:             name,
+						Description:      description,
+						DescriptionTitle: group.ImportPath,
+						Fields:           []FieldInfo{},
+						Methods:          []MethodInfo{},
+						CurrentView:      "fields",
+						FontSize:         "m",
+						Color:            color,
+					},
+				})
+			}
+		}
+	}
+
+	for _, e := range result.Edges {
+		output.Connections = append(output.Connections, VisualizerConnect{
+			FromID: "struct-" + e.From,
+			ToID:   "struct-" + e.To,
+			Label:  fmt.Sprintf("%d", e.Weight),
+		})
+	}
+	return output
+}
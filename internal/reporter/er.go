@@ -0,0 +1,128 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// erCardinality 关联类型在 Mermaid erDiagram 中的基数表示（From 在左，To 在右）
+var erCardinality = map[string]string{
+	types.RelationHasOne:     "||--o|",
+	types.RelationHasMany:    "||--o{",
+	types.RelationBelongsTo:  "}o--||",
+	types.RelationManyToMany: "}o--o{",
+}
+
+// GenerateER 生成持久化模型的 Mermaid erDiagram：每个实体列出列（类型、列名、PK/FK/UK、json 名），关联上标注字段名或连接表
+func (m *MermaidGenerator) GenerateER(model *types.ERModel) string {
+	m.builder.Reset()
+	m.builder.WriteString("erDiagram\n")
+
+	for _, e := range model.Entities {
+		if len(e.Attributes) == 0 {
+			m.builder.WriteString(fmt.Sprintf("    %s {\n    }\n", sanitizeID(e.Name)))
+			continue
+		}
+		m.builder.WriteString(fmt.Sprintf("    %s {\n", sanitizeID(e.Name)))
+		for _, attr := range e.Attributes {
+			line := fmt.Sprintf("        %s %s", erAttributeType(attr.Type), sanitizeID(attr.Column))
+			if len(attr.Keys) > 0 {
+				line += " " + strings.Join(attr.Keys, ",")
+			}
+			if attr.JSON != "" {
+				line += fmt.Sprintf(" \"json: %s\"", attr.JSON)
+			}
+			m.builder.WriteString(line + "\n")
+		}
+		m.builder.WriteString("    }\n")
+	}
+
+	if len(model.Relations) > 0 {
+		m.builder.WriteString("\n")
+	}
+	for _, rel := range model.Relations {
+		label := rel.Field
+		if rel.Kind == types.RelationManyToMany && rel.JoinTable != "" {
+			label = rel.JoinTable
+		}
+		m.builder.WriteString(fmt.Sprintf("    %s %s %s : \"%s\"\n",
+			sanitizeID(rel.From), erCardinality[rel.Kind], sanitizeID(rel.To), label))
+	}
+
+	return m.builder.String()
+}
+
+// erAttributeType 将 Go 类型转换为 erDiagram 可接受的类型名：*time.Time -> time_Time，[]string -> string[]
+func erAttributeType(t string) string {
+	t = strings.TrimLeft(t, "*")
+	suffix := ""
+	for strings.HasPrefix(t, "[]") {
+		t = strings.TrimLeft(t[2:], "*")
+		suffix += "[]"
+	}
+	t = strings.NewReplacer(".", "_", "{}", "", " ", "", "*", "", "[", "_", "]", "_").Replace(t)
+	return t + suffix
+}
+
+// GenerateER 生成持久化模型的 Markdown：实体列表、关联列表和 erDiagram
+func (r *MarkdownReporter) GenerateER(model *types.ERModel) string {
+	r.builder.Reset()
+
+	r.builder.WriteString("## 持久化模型\n\n")
+	r.builder.WriteString(fmt.Sprintf("**起点**: %s | **实体**: %d | **关联**: %d\n\n", model.Root, len(model.Entities), len(model.Relations)))
+
+	// 1. 关联
+	if len(model.Relations) > 0 {
+		r.builder.WriteString("### 关联\n\n")
+		r.builder.WriteString("| 实体 | 关联 | 关联实体 | 字段 | 外键 / 连接表 |\n")
+		r.builder.WriteString("|------|------|----------|------|---------------|\n")
+		for _, rel := range model.Relations {
+			key := rel.ForeignKey
+			if rel.Kind == types.RelationManyToMany {
+				key = rel.JoinTable
+			}
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", rel.From, rel.Kind, rel.To, rel.Field, key))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	// 2. 实体
+	r.builder.WriteString("### 实体\n\n")
+	for _, e := range model.Entities {
+		r.builder.WriteString(fmt.Sprintf("#### %s\n\n", e.Name))
+		if e.ImportPath != "" {
+			r.builder.WriteString(fmt.Sprintf("**包**: `%s`\n\n", e.ImportPath))
+		}
+		if len(e.Attributes) == 0 {
+			r.builder.WriteString("没有列。\n\n")
+			continue
+		}
+		r.builder.WriteString("| 列名 | 字段 | 类型 | 键 | json |\n")
+		r.builder.WriteString("|------|------|------|----|------|\n")
+		for _, attr := range e.Attributes {
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				attr.Column, attr.Field, escapeMarkdown(attr.Type), strings.Join(attr.Keys, ", "), attr.JSON))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	// 3. ER 图
+	r.builder.WriteString("### ER 图\n\n")
+	r.builder.WriteString("```mermaid\n")
+	r.builder.WriteString(NewMermaidGenerator().GenerateER(model))
+	r.builder.WriteString("```\n")
+
+	return r.builder.String()
+}
+
+// GenerateER 生成持久化模型的 JSON
+func (r *JSONReporter) GenerateER(model *types.ERModel) (string, error) {
+	data, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
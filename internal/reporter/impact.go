@@ -0,0 +1,86 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// GenerateImpact 生成改动影响分析的 Markdown（适合作为 PR 评论）
+func (r *MarkdownReporter) GenerateImpact(result *types.ImpactResult) string {
+	r.builder.Reset()
+
+	r.builder.WriteString("## 改动影响分析\n\n")
+	if result.Base != "" {
+		r.builder.WriteString(fmt.Sprintf("**对比基准**: `%s`\n", result.Base))
+	}
+	depth := "不限"
+	if result.MaxDepth > 0 {
+		depth = fmt.Sprintf("%d", result.MaxDepth)
+	}
+	impacted := 0
+	for _, t := range result.Impacted {
+		if t.Distance > 0 {
+			impacted++
+		}
+	}
+	r.builder.WriteString(fmt.Sprintf("**改动文件**: %d | **改动声明**: %d | **受影响类型**: %d | **反向依赖深度**: %s\n\n",
+		len(result.Files), len(result.Changed), impacted, depth))
+
+	if len(result.Changed) == 0 {
+		r.builder.WriteString("改动没有涉及结构体、接口、方法或构造函数。\n")
+		return r.builder.String()
+	}
+
+	r.builder.WriteString("### 改动的声明\n\n")
+	r.builder.WriteString("| 声明 | 种类 | 位置 |\n")
+	r.builder.WriteString("|------|------|------|\n")
+	for _, d := range result.Changed {
+		r.builder.WriteString(fmt.Sprintf("| `%s` | %s | %s:%d |\n", d.Name, changedKindLabel(d.Kind), d.FilePath, d.Line))
+	}
+	r.builder.WriteString("\n")
+
+	r.builder.WriteString("### 受影响的类型\n\n")
+	r.builder.WriteString("| 类型 | 包 | 距离 | 经由 | 依据 |\n")
+	r.builder.WriteString("|------|----|------|------|------|\n")
+	for _, t := range result.Impacted {
+		via, evidence := "（改动）", ""
+		if t.Distance > 0 {
+			labels := make([]string, 0, len(t.Types))
+			for _, depType := range t.Types {
+				labels = append(labels, getDepTypeLabel(depType))
+			}
+			via = fmt.Sprintf("%s（%s）", t.Via, strings.Join(labels, "、"))
+			evidence = escapeMarkdown(strings.Join(t.Evidence, "<br/>"))
+		}
+		r.builder.WriteString(fmt.Sprintf("| %s | %s | %d | %s | %s |\n", t.Name, t.Package, t.Distance, via, evidence))
+	}
+	r.builder.WriteString("\n")
+
+	if len(result.Files) > 0 {
+		r.builder.WriteString("<details>\n<summary>改动的文件</summary>\n\n")
+		for _, f := range result.Files {
+			r.builder.WriteString(fmt.Sprintf("- %s\n", f))
+		}
+		r.builder.WriteString("\n</details>\n")
+	}
+
+	return r.builder.String()
+}
+
+// changedKindLabel 获取改动声明种类的中文标签
+func changedKindLabel(kind string) string {
+	switch kind {
+	case types.ChangedKindStruct:
+		return "结构体"
+	case types.ChangedKindInterface:
+		return "接口"
+	case types.ChangedKindMethod:
+		return "方法"
+	case types.ChangedKindConstructor:
+		return "构造函数"
+	default:
+		return kind
+	}
+}
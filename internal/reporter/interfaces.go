@@ -0,0 +1,68 @@
+package reporter
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// writeInterfaceSuggestions 写入为具体类型字段建议的最小接口及其 Go 源码
+func (r *MarkdownReporter) writeInterfaceSuggestions(result *types.AnalysisResult) {
+	var structs []types.StructAnalysis
+	for _, s := range result.Structs {
+		if len(s.InterfaceSuggestions) > 0 {
+			structs = append(structs, s)
+		}
+	}
+	if len(structs) == 0 {
+		return
+	}
+
+	r.builder.WriteString("## 最小接口建议\n\n")
+	r.builder.WriteString("> 以下字段的类型是具体结构体，接口只包含方法中实际调用的方法。将字段类型改为该接口后，可在测试中替换实现。\n\n")
+
+	for _, s := range structs {
+		r.builder.WriteString(fmt.Sprintf("### %s\n\n", s.Name))
+		for _, sug := range s.InterfaceSuggestions {
+			methods := make([]string, 0, len(sug.Methods))
+			for _, m := range sug.Methods {
+				methods = append(methods, m.Name)
+			}
+			r.builder.WriteString(fmt.Sprintf("#### %s `%s` → %s\n\n", sug.Field, sug.FieldType, sug.Name))
+			r.builder.WriteString(fmt.Sprintf("- **调用的方法**: %s（%s 中调用）\n", strings.Join(methods, ", "), strings.Join(sug.Callers, ", ")))
+			if len(sug.Matching) > 0 {
+				r.builder.WriteString(fmt.Sprintf("- **已有相同的接口**: %s，可直接使用\n", strings.Join(sug.Matching, ", ")))
+			}
+			if len(sug.Covering) > 0 {
+				r.builder.WriteString(fmt.Sprintf("- **已有更大的接口**: %s\n", strings.Join(sug.Covering, ", ")))
+			}
+			if len(sug.Imports) > 0 {
+				r.builder.WriteString(fmt.Sprintf("- **需要导入**: %s\n", strings.Join(sug.Imports, ", ")))
+			}
+			r.builder.WriteString("\n```go\n")
+			r.builder.WriteString(InterfaceSource(s.Name, sug))
+			r.builder.WriteString("```\n\n")
+		}
+	}
+
+	r.builder.WriteString("---\n\n")
+}
+
+// InterfaceSource 生成建议接口的 Go 声明
+func InterfaceSource(structName string, sug types.InterfaceSuggestion) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("// %s 是 %s 经由 %s 字段使用的 %s 方法\n", sug.Name, structName, sug.Field, sug.FieldType))
+	b.WriteString(fmt.Sprintf("type %s interface {\n", sug.Name))
+	for _, m := range sug.Methods {
+		b.WriteString(fmt.Sprintf("\t%s%s\n", m.Name, m.Signature))
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return b.String()
+	}
+	return string(src)
+}
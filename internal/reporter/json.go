@@ -2,6 +2,7 @@ package reporter
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/user/go-struct-analyzer/internal/types"
@@ -24,6 +25,15 @@ func (r *JSONReporter) Generate(result *types.AnalysisResult) (string, error) {
 	return string(data), nil
 }
 
+// GenerateImpact 生成改动影响分析的 JSON
+func (r *JSONReporter) GenerateImpact(result *types.ImpactResult) (string, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // SaveToFile 保存报告到文件
 func (r *JSONReporter) SaveToFile(result *types.AnalysisResult, filePath string) error {
 	data, err := json.MarshalIndent(result, "", "  ")
@@ -32,3 +42,43 @@ func (r *JSONReporter) SaveToFile(result *types.AnalysisResult, filePath string)
 	}
 	return os.WriteFile(filePath, data, 0644)
 }
+
+// GenerateAPIDiff 生成导出 API 差异的 JSON
+func (r *JSONReporter) GenerateAPIDiff(result *types.APIDiffResult) (string, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GenerateDiff 生成两次分析结果对比的 JSON
+func (r *JSONReporter) GenerateDiff(diff *types.ResultDiff) (string, error) {
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GenerateClusters 生成社区划分的 JSON
+func (r *JSONReporter) GenerateClusters(result *types.ClusterResult) (string, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// LoadFromFile 读取 SaveToFile（或 --format json）保存的分析结果
+func (r *JSONReporter) LoadFromFile(filePath string) (*types.AnalysisResult, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var result types.AnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	return &result, nil
+}
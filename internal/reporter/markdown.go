@@ -3,6 +3,7 @@ package reporter
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -11,7 +12,9 @@ import (
 
 // MarkdownReporter 生成 Markdown 格式的报告
 type MarkdownReporter struct {
-	builder strings.Builder
+	builder     strings.Builder
+	metricsSort string // 度量表格的排序键
+	rankBy      string // 关键结构体排行使用的度量键
 }
 
 // NewMarkdownReporter 创建 Markdown 报告生成器
@@ -19,6 +22,16 @@ func NewMarkdownReporter() *MarkdownReporter {
 	return &MarkdownReporter{}
 }
 
+// SetMetricsSort 设置度量表格的排序键（见 MetricsSortKeys）
+func (r *MarkdownReporter) SetMetricsSort(key string) {
+	r.metricsSort = key
+}
+
+// SetRankBy 设置关键结构体排行使用的度量键（见 RankKeys，默认 reach）
+func (r *MarkdownReporter) SetRankBy(key string) {
+	r.rankBy = key
+}
+
 // Generate 生成 Markdown 报告
 func (r *MarkdownReporter) Generate(result *types.AnalysisResult, blacklist []string) string {
 	r.builder.Reset()
@@ -26,8 +39,16 @@ func (r *MarkdownReporter) Generate(result *types.AnalysisResult, blacklist []st
 	r.writeHeader(result)
 	r.writeOverview(result, blacklist)
 	r.writeStructsByDepth(result)
+	r.writeExternalTypes(result)
+	r.writePackageGraph(result)
 	r.writeDependencyGraph(result)
 	r.writeStatistics(result, blacklist)
+	r.writeMetrics(result)
+	r.writeFieldAccess(result)
+	r.writeInterfaceSuggestions(result)
+	r.writeDeadCode(result)
+	r.writeUnusedMembers(result)
+	r.writeDecisions(result)
 	r.writeFooter(result)
 
 	return r.builder.String()
@@ -72,8 +93,25 @@ func (r *MarkdownReporter) writeOverview(result *types.AnalysisResult, blacklist
 		r.builder.WriteString(fmt.Sprintf("  - 深度 %d: %d 个\n", d, depthCount[d]))
 	}
 
+	if roles := structRoles(result.Structs); len(roles) > 0 {
+		roleCount := make(map[string]int)
+		for _, s := range result.Structs {
+			roleCount[s.Role]++
+		}
+		r.builder.WriteString("- **架构角色分布**:\n")
+		for _, role := range roles {
+			r.builder.WriteString(fmt.Sprintf("  - %s: %d 个\n", role, roleCount[role]))
+		}
+		if n := roleCount[""]; n > 0 {
+			r.builder.WriteString(fmt.Sprintf("  - 未判定: %d 个\n", n))
+		}
+	}
+
 	r.builder.WriteString(fmt.Sprintf("- **总依赖关系数**: %d\n", result.TotalDeps))
 	r.builder.WriteString(fmt.Sprintf("- **循环依赖**: %d 个\n", len(result.Cycles)))
+	if len(result.ExternalTypes) > 0 {
+		r.builder.WriteString(fmt.Sprintf("- **外部依赖类型**: %d 个\n", len(result.ExternalTypes)))
+	}
 
 	if len(blacklist) > 0 {
 		r.builder.WriteString(fmt.Sprintf("- **忽略类型**: %s\n", strings.Join(blacklist, ", ")))
@@ -111,12 +149,35 @@ func (r *MarkdownReporter) writeStructDetail(s types.StructAnalysis) {
 	r.builder.WriteString(fmt.Sprintf("### %s\n\n", s.Name))
 	r.builder.WriteString(fmt.Sprintf("**功能**: %s\n\n", s.Description))
 	r.builder.WriteString(fmt.Sprintf("**所属包**: `%s`\n\n", s.Package))
+	if s.Layer != "" {
+		r.builder.WriteString(fmt.Sprintf("**架构层**: `%s`\n\n", s.Layer))
+	}
+	if s.Role != "" {
+		r.builder.WriteString(fmt.Sprintf("**架构角色**: `%s`（%s）\n\n", s.Role, s.RoleReason))
+	}
+	if s.Stopped {
+		r.builder.WriteString("**遍历**: 命中 stop 规则，依赖未继续展开\n\n")
+	}
 
 	// 字段列表
 	if len(s.Fields) > 0 {
+		// 有字段带序列化标签时增加序列化名列
+		serialized := false
+		for _, field := range s.Fields {
+			if len(field.SerializedNames) > 0 {
+				serialized = true
+				break
+			}
+		}
+
 		r.builder.WriteString("#### 字段列表\n\n")
-		r.builder.WriteString("| 字段名 | 类型 | 导出 | 描述 |\n")
-		r.builder.WriteString("|--------|------|------|------|\n")
+		if serialized {
+			r.builder.WriteString("| 字段名 | 类型 | 导出 | 序列化名 | 描述 |\n")
+			r.builder.WriteString("|--------|------|------|----------|------|\n")
+		} else {
+			r.builder.WriteString("| 字段名 | 类型 | 导出 | 描述 |\n")
+			r.builder.WriteString("|--------|------|------|------|\n")
+		}
 
 		for _, field := range s.Fields {
 			exported := "✗"
@@ -127,6 +188,11 @@ func (r *MarkdownReporter) writeStructDetail(s types.StructAnalysis) {
 			if field.IsEmbedded {
 				fieldName = fmt.Sprintf("*%s* (嵌入)", field.Name)
 			}
+			if serialized {
+				r.builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+					fieldName, escapeMarkdown(field.Type), exported, formatSerializedNames(field.SerializedNames), field.Description))
+				continue
+			}
 			r.builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
 				fieldName, escapeMarkdown(field.Type), exported, field.Description))
 		}
@@ -164,6 +230,112 @@ func (r *MarkdownReporter) writeStructDetail(s types.StructAnalysis) {
 		r.builder.WriteString("\n")
 	}
 
+	r.writeLayout(s.Layout)
+
+	r.builder.WriteString("---\n\n")
+}
+
+// writeLayout 写入结构体的内存布局和建议的字段顺序
+func (r *MarkdownReporter) writeLayout(layout types.StructLayout) {
+	if layout.Arch == "" {
+		return
+	}
+	r.builder.WriteString(fmt.Sprintf("#### 内存布局 (%s)\n\n", layout.Arch))
+	if len(layout.Unknown) > 0 {
+		r.builder.WriteString(fmt.Sprintf("无法确定大小的类型: `%s`，未计算布局\n\n", strings.Join(layout.Unknown, "`, `")))
+		return
+	}
+	r.builder.WriteString(fmt.Sprintf("大小 %d 字节，对齐 %d 字节，填充 %d 字节\n\n", layout.Size, layout.Align, layout.Padding))
+	if len(layout.Fields) > 0 {
+		r.builder.WriteString("| 字段 | 类型 | 偏移 | 大小 | 对齐 | 填充 |\n")
+		r.builder.WriteString("|------|------|------|------|------|------|\n")
+		for _, f := range layout.Fields {
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %d | %d | %d | %d |\n",
+				f.Name, escapeMarkdown(f.Type), f.Offset, f.Size, f.Align, f.Padding))
+		}
+		r.builder.WriteString("\n")
+	}
+	if len(layout.SuggestedOrder) > 0 {
+		r.builder.WriteString(fmt.Sprintf("**建议字段顺序**（大小 %d → %d 字节）: %s\n\n",
+			layout.Size, layout.OptimalSize, strings.Join(layout.SuggestedOrder, ", ")))
+	}
+}
+
+// writeExternalTypes 写入第三方模块依赖（按模块分组）
+func (r *MarkdownReporter) writeExternalTypes(result *types.AnalysisResult) {
+	if len(result.ExternalTypes) == 0 {
+		return
+	}
+
+	r.builder.WriteString("## 外部依赖\n\n")
+
+	for i, ext := range result.ExternalTypes {
+		if i == 0 || result.ExternalTypes[i-1].Module != ext.Module {
+			r.builder.WriteString(fmt.Sprintf("### %s %s\n\n", ext.Module, ext.Version))
+			r.builder.WriteString("| 类型 | 导入路径 | 使用者 | 字段数 |\n")
+			r.builder.WriteString("|------|----------|--------|--------|\n")
+		}
+
+		fieldCount := "-"
+		if ext.Source != "" {
+			fieldCount = fmt.Sprintf("%d (%s)", len(ext.Fields), ext.Source)
+		}
+		r.builder.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s |\n",
+			ext.Name, ext.ImportPath, strings.Join(ext.UsedBy, ", "), fieldCount))
+
+		if i == len(result.ExternalTypes)-1 || result.ExternalTypes[i+1].Module != ext.Module {
+			r.builder.WriteString("\n")
+		}
+	}
+
+	r.builder.WriteString("---\n\n")
+}
+
+// writePackageGraph 写入包级依赖：包列表、包之间的依赖及其来源、包级循环依赖和包依赖图
+func (r *MarkdownReporter) writePackageGraph(result *types.AnalysisResult) {
+	graph := result.PackageGraph
+	if len(graph.Edges) == 0 {
+		return
+	}
+
+	r.builder.WriteString("## 包依赖\n\n")
+
+	r.builder.WriteString("| 包 | 导入路径 | 类型 |\n")
+	r.builder.WriteString("|----|----------|------|\n")
+	for _, pkg := range graph.Packages {
+		name := pkg.Name
+		if pkg.External {
+			name += "（外部）"
+		}
+		r.builder.WriteString(fmt.Sprintf("| %s | `%s` | %s |\n", name, pkg.ImportPath, strings.Join(pkg.Structs, ", ")))
+	}
+	r.builder.WriteString("\n")
+
+	r.builder.WriteString("### 包之间的依赖\n\n")
+	r.builder.WriteString("| 源包 | 目标包 | 权重 | 来源 |\n")
+	r.builder.WriteString("|------|--------|------|------|\n")
+	for _, edge := range graph.Edges {
+		evidence := make([]string, 0, len(edge.Deps))
+		for _, dep := range edge.Deps {
+			evidence = append(evidence, fmt.Sprintf("%s -> %s (%s)", dep.From, dep.To, getDepTypeLabel(dep.Type)))
+		}
+		r.builder.WriteString(fmt.Sprintf("| `%s` | `%s` | %d | %s |\n", edge.From, edge.To, edge.Weight, strings.Join(evidence, "<br/>")))
+	}
+	r.builder.WriteString("\n")
+
+	if len(graph.Cycles) > 0 {
+		r.builder.WriteString("### 包级循环依赖\n")
+		for i, cycle := range graph.Cycles {
+			path := append(append([]string{}, cycle...), cycle[0])
+			r.builder.WriteString(fmt.Sprintf("%d. %s\n", i+1, strings.Join(path, " -> ")))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	r.builder.WriteString("### 包依赖图\n\n")
+	r.builder.WriteString("```mermaid\n")
+	r.builder.WriteString(NewMermaidGenerator().GeneratePackages(result))
+	r.builder.WriteString("```\n\n")
 	r.builder.WriteString("---\n\n")
 }
 
@@ -201,57 +373,334 @@ func (r *MarkdownReporter) writeStatistics(result *types.AnalysisResult, blackli
 	}
 	r.builder.WriteString("\n")
 
-	// 被依赖次数排行
-	r.builder.WriteString("### 被依赖次数排行\n")
-	depCount := make(map[string]int)
-	for _, s := range result.Structs {
-		for _, dep := range s.Dependencies {
-			depCount[dep.To]++
+	// 关键结构体排行（影响范围越大，越需要测试覆盖）
+	r.writeRanking(result)
+
+	// 黑名单类型
+	if len(blacklist) > 0 {
+		r.builder.WriteString("### 黑名单类型\n")
+		for _, b := range blacklist {
+			r.builder.WriteString(fmt.Sprintf("- %s (已忽略)\n", b))
 		}
+		r.builder.WriteString("\n")
 	}
 
-	// 排序
-	type depItem struct {
-		name  string
-		count int
+	// 循环依赖
+	if len(result.Cycles) > 0 {
+		r.writeCycles(result)
 	}
-	var items []depItem
-	for name, count := range depCount {
-		items = append(items, depItem{name, count})
+
+	r.builder.WriteString("---\n\n")
+}
+
+// writeRanking 按排行度量列出最关键的前 10 个结构体（值为 0 的不列出）
+func (r *MarkdownReporter) writeRanking(result *types.AnalysisResult) {
+	key := r.rankBy
+	if !IsRankKey(key) {
+		key = SortByReach
 	}
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].count > items[j].count
-	})
 
-	for i, item := range items {
-		if i >= 10 { // 只显示前 10
+	structs := append([]types.StructMetrics{}, result.Metrics.Structs...)
+	SortStructMetrics(structs, key)
+	var top []types.StructMetrics
+	for _, m := range structs {
+		if len(top) == 10 || rankValue(m, key) == 0 {
 			break
 		}
-		r.builder.WriteString(fmt.Sprintf("%d. %s - 被依赖 %d 次\n", i+1, item.name, item.count))
+		top = append(top, m)
+	}
+	if len(top) == 0 {
+		return
 	}
-	r.builder.WriteString("\n")
 
-	// 黑名单类型
-	if len(blacklist) > 0 {
-		r.builder.WriteString("### 黑名单类型\n")
-		for _, b := range blacklist {
-			r.builder.WriteString(fmt.Sprintf("- %s (已忽略)\n", b))
+	r.builder.WriteString(fmt.Sprintf("### 关键结构体排行（按%s）\n\n", rankLabel(key)))
+	r.builder.WriteString("| 排名 | 结构体 | 包 | 影响范围 | PageRank | 介数中心性 | Fan-in |\n")
+	r.builder.WriteString("|------|--------|----|----------|----------|------------|--------|\n")
+	for i, m := range top {
+		r.builder.WriteString(fmt.Sprintf("| %d | %s | %s | %d | %.4f | %.4f | %d |\n",
+			i+1, m.Name, m.Package, m.Reach, m.PageRank, m.Betweenness, m.FanIn))
+	}
+	r.builder.WriteString("\n> 影响范围为直接或间接依赖该结构体的结构体数；介数中心性为经过该结构体的最短依赖路径比例。\n\n")
+}
+
+// writeMetrics 写入耦合与内聚度量表格
+func (r *MarkdownReporter) writeMetrics(result *types.AnalysisResult) {
+	metrics := result.Metrics
+	if len(metrics.Structs) == 0 && len(metrics.Packages) == 0 {
+		return
+	}
+
+	r.builder.WriteString("## 耦合与内聚度量\n\n")
+
+	if len(metrics.Packages) > 0 {
+		packages := append([]types.PackageMetrics{}, metrics.Packages...)
+		SortPackageMetrics(packages, r.metricsSort)
+
+		r.builder.WriteString("### 包度量\n\n")
+		r.builder.WriteString("| 包 | Fan-in | Fan-out | Ca | Ce | 不稳定性 I | 抽象度 A | 距离 D |\n")
+		r.builder.WriteString("|----|--------|---------|----|----|------------|----------|--------|\n")
+		for _, m := range packages {
+			r.builder.WriteString(fmt.Sprintf("| `%s` | %d | %d | %d | %d | %.2f | %.2f | %.2f |\n",
+				m.ImportPath, m.FanIn, m.FanOut, m.Ca, m.Ce, m.Instability, m.Abstractness, m.Distance))
 		}
 		r.builder.WriteString("\n")
 	}
 
-	// 循环依赖
-	if len(result.Cycles) > 0 {
-		r.builder.WriteString("### 循环依赖\n")
+	if len(metrics.Structs) > 0 {
+		structs := append([]types.StructMetrics{}, metrics.Structs...)
+		SortStructMetrics(structs, r.metricsSort)
+
+		r.builder.WriteString("### 结构体度量\n\n")
+		r.builder.WriteString("| 结构体 | 包 | Fan-in | Fan-out | 字段数 | 方法数 | LCOM | 影响范围 | PageRank | 介数中心性 |\n")
+		r.builder.WriteString("|--------|----|--------|---------|--------|--------|------|----------|----------|------------|\n")
+		for _, m := range structs {
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %d | %d | %d | %d | %.2f | %d | %.4f | %.4f |\n",
+				m.Name, m.Package, m.FanIn, m.FanOut, m.Fields, m.Methods, m.LCOM, m.Reach, m.PageRank, m.Betweenness))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	r.builder.WriteString("> I 越接近 1 越不稳定；D 越接近 0 越接近主序列；LCOM 为 Henderson-Sellers 内聚缺乏度，0 表示所有方法访问所有字段。\n\n")
+	r.builder.WriteString("---\n\n")
+}
+
+// writeFieldAccess 写入每个结构体的方法 × 字段访问矩阵及其提示
+func (r *MarkdownReporter) writeFieldAccess(result *types.AnalysisResult) {
+	var structs []types.StructAnalysis
+	for _, s := range result.Structs {
+		if len(s.FieldAccess.Methods) > 0 && len(s.FieldAccess.Fields) > 0 {
+			structs = append(structs, s)
+		}
+	}
+	if len(structs) == 0 {
+		return
+	}
+
+	r.builder.WriteString("## 字段访问矩阵\n\n")
+	r.builder.WriteString("> R: 读取，W: 写入（含经由指针、索引的间接写入），C: 在字段值上调用方法\n\n")
+
+	for _, s := range structs {
+		matrix := s.FieldAccess
+		cells := make(map[string]string)
+		for _, acc := range matrix.Accesses {
+			cells[acc.Method+"."+acc.Field] = accessLabel(acc)
+		}
+
+		r.builder.WriteString(fmt.Sprintf("### %s\n\n", s.Name))
+		r.builder.WriteString("| 方法 | " + strings.Join(matrix.Fields, " | ") + " |\n")
+		r.builder.WriteString("|------|" + strings.Repeat("------|", len(matrix.Fields)) + "\n")
+		for _, m := range matrix.Methods {
+			row := make([]string, 0, len(matrix.Fields))
+			for _, f := range matrix.Fields {
+				row = append(row, cells[m+"."+f])
+			}
+			r.builder.WriteString(fmt.Sprintf("| %s | %s |\n", m, strings.Join(row, " | ")))
+		}
+		r.builder.WriteString("\n")
+
+		if len(matrix.UnreadFields) > 0 {
+			r.builder.WriteString(fmt.Sprintf("- **未被读取的字段**: %s\n", strings.Join(matrix.UnreadFields, ", ")))
+		}
+		if len(matrix.MultiWriterFields) > 0 {
+			r.builder.WriteString(fmt.Sprintf("- **多处写入的字段**: %s\n", strings.Join(matrix.MultiWriterFields, ", ")))
+		}
+		if len(matrix.FieldlessMethods) > 0 {
+			r.builder.WriteString(fmt.Sprintf("- **未访问字段的方法**（可考虑改为函数）: %s\n", strings.Join(matrix.FieldlessMethods, ", ")))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	r.builder.WriteString("---\n\n")
+}
+
+// accessLabel 返回访问矩阵单元格的标记
+func accessLabel(acc types.FieldAccess) string {
+	label := ""
+	if acc.Read {
+		label += "R"
+	}
+	if acc.Write {
+		label += "W"
+	}
+	if acc.Call {
+		label += "C"
+	}
+	return label
+}
+
+// writeCycles 写入循环依赖：强连通分量和每个基本环的依赖类型与依据
+func (r *MarkdownReporter) writeCycles(result *types.AnalysisResult) {
+	if len(result.Components) > 0 {
+		r.builder.WriteString("### 强连通分量\n")
+		for i, comp := range result.Components {
+			r.builder.WriteString(fmt.Sprintf("%d. %s（%d 个结构体）\n", i+1, strings.Join(comp, ", "), len(comp)))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	r.builder.WriteString("### 循环依赖\n")
+	if len(result.CycleDetails) == 0 {
 		for i, cycle := range result.Cycles {
 			r.builder.WriteString(fmt.Sprintf("%d. %s\n", i+1, strings.Join(cycle, " -> ")))
 		}
 		r.builder.WriteString("\n")
+		return
+	}
+
+	for i, cycle := range result.CycleDetails {
+		path := append(append([]string{}, cycle.Nodes...), cycle.Nodes[0])
+		labels := make([]string, 0, len(cycle.EdgeTypes))
+		for _, t := range cycle.EdgeTypes {
+			labels = append(labels, getDepTypeLabel(t))
+		}
+		r.builder.WriteString(fmt.Sprintf("%d. %s（%s）\n", i+1, strings.Join(path, " -> "), strings.Join(labels, ", ")))
+
+		for _, edge := range cycle.Edges {
+			stepLabels := make([]string, 0, len(edge.Types))
+			for _, t := range edge.Types {
+				stepLabels = append(stepLabels, getDepTypeLabel(t))
+			}
+			r.builder.WriteString(fmt.Sprintf("   - %s -> %s: %s", edge.From, edge.To, strings.Join(stepLabels, ", ")))
+			if len(edge.Evidence) > 0 {
+				r.builder.WriteString(fmt.Sprintf("（%s）", strings.Join(edge.Evidence, "; ")))
+			}
+			r.builder.WriteString("\n")
+		}
+	}
+	if result.CyclesTruncated {
+		r.builder.WriteString(fmt.Sprintf("\n> 基本环数量超过上限，仅列出前 %d 个\n", len(result.Cycles)))
+	}
+	r.builder.WriteString("\n")
+}
+
+// writeDecisions 写入候选类型的过滤判定（explain 模式）
+func (r *MarkdownReporter) writeDecisions(result *types.AnalysisResult) {
+	if len(result.Decisions) == 0 {
+		return
+	}
+
+	r.builder.WriteString("## 过滤决策\n\n")
+
+	// 按规则汇总
+	ruleCount := make(map[string]int)
+	var rules []string
+	for _, d := range result.Decisions {
+		if ruleCount[d.Rule] == 0 {
+			rules = append(rules, d.Rule)
+		}
+		ruleCount[d.Rule]++
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		r.builder.WriteString(fmt.Sprintf("- `%s`: %d\n", rule, ruleCount[rule]))
+	}
+	r.builder.WriteString("\n")
+
+	r.builder.WriteString("| 类型 | 结果 | 规则 | 说明 | 来源 | 位置 | 依赖类型 |\n")
+	r.builder.WriteString("|------|------|------|------|------|------|----------|\n")
+	for _, d := range result.Decisions {
+		verdict := "忽略"
+		if d.Accepted {
+			verdict = "保留"
+		}
+		r.builder.WriteString(fmt.Sprintf("| `%s` | %s | `%s` | %s | %s | %s | %s |\n",
+			d.Type, verdict, d.Rule, d.Detail, d.From, d.Context, getDepTypeLabel(d.DepType)))
+	}
+	r.builder.WriteString("\n---\n\n")
+}
+
+// writeDeadCode 写入从根不可达的声明和实现数过少的接口
+func (r *MarkdownReporter) writeDeadCode(result *types.AnalysisResult) {
+	dead := result.DeadCode
+	if len(dead.UnreachableStructs) == 0 && len(dead.UnreachableInterfaces) == 0 && len(dead.UnusedConstructors) == 0 &&
+		len(dead.UnimplementedInterfaces) == 0 && len(dead.SingleImplInterfaces) == 0 {
+		return
+	}
+
+	r.builder.WriteString("## 死代码与不可达类型\n\n")
+	r.builder.WriteString(fmt.Sprintf("基于全项目依赖图，从以下根出发: %s\n\n", strings.Join(dead.Roots, ", ")))
+
+	sections := []struct {
+		title string
+		decls []types.DeadDecl
+	}{
+		{"不可达的结构体", dead.UnreachableStructs},
+		{"不可达的接口", dead.UnreachableInterfaces},
+		{"未使用的构造函数", dead.UnusedConstructors},
+		{"没有实现的接口", dead.UnimplementedInterfaces},
+	}
+	for _, section := range sections {
+		if len(section.decls) == 0 {
+			continue
+		}
+		r.builder.WriteString(fmt.Sprintf("### %s (%d)\n\n", section.title, len(section.decls)))
+		r.builder.WriteString("| 名称 | 包 | 文件 |\n")
+		r.builder.WriteString("|------|----|------|\n")
+		for _, d := range section.decls {
+			path := d.FilePath
+			if rel, err := filepath.Rel(result.ProjectPath, path); err == nil {
+				path = rel
+			}
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %s |\n", d.Name, d.Package, path))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	if len(dead.SingleImplInterfaces) > 0 {
+		r.builder.WriteString(fmt.Sprintf("### 只有一个实现的接口 (%d)\n\n", len(dead.SingleImplInterfaces)))
+		r.builder.WriteString("| 接口 | 包 | 唯一实现 |\n")
+		r.builder.WriteString("|------|----|----------|\n")
+		for _, impl := range dead.SingleImplInterfaces {
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %s |\n", impl.Interface, impl.Package, impl.Implementer))
+		}
+		r.builder.WriteString("\n")
+		r.builder.WriteString("> 只有一个实现的接口可能是过度抽象，也可能是为测试替身预留的扩展点，请结合实际情况判断。\n\n")
 	}
 
 	r.builder.WriteString("---\n\n")
 }
 
+// writeUnusedMembers 写入未被其他包使用的导出成员和未被使用的未导出成员
+func (r *MarkdownReporter) writeUnusedMembers(result *types.AnalysisResult) {
+	members := result.Members
+	if len(members.UnexportCandidates) == 0 && len(members.Unused) == 0 {
+		return
+	}
+
+	r.builder.WriteString("## 未使用的结构体成员\n\n")
+
+	sections := []struct {
+		title   string
+		members []types.UnusedMember
+	}{
+		{"可改为未导出的成员（没有被其他包使用）", members.UnexportCandidates},
+		{"未被使用的未导出成员", members.Unused},
+	}
+	for _, section := range sections {
+		if len(section.members) == 0 {
+			continue
+		}
+		r.builder.WriteString(fmt.Sprintf("### %s (%d)\n\n", section.title, len(section.members)))
+		r.builder.WriteString("| 结构体 | 成员 | 类别 | 包 | 文件 |\n")
+		r.builder.WriteString("|--------|------|------|----|------|\n")
+		for _, m := range section.members {
+			path := m.FilePath
+			if rel, err := filepath.Rel(result.ProjectPath, path); err == nil {
+				path = rel
+			}
+			kind := "字段"
+			if m.Kind == types.MemberKindMethod {
+				kind = "方法"
+			}
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", m.Struct, m.Name, kind, m.Package, path))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	r.builder.WriteString("> 选择器的接收者类型无法推断时按成员名计入使用；接口实现、常见接口方法（String、MarshalJSON 等）和带标签的字段视为被使用。\n\n")
+	r.builder.WriteString("---\n\n")
+}
+
 // writeFooter 写入页脚
 func (r *MarkdownReporter) writeFooter(result *types.AnalysisResult) {
 	r.builder.WriteString(fmt.Sprintf("生成于: %s\n", result.GeneratedAt))
@@ -272,11 +721,32 @@ func getDepTypeLabel(depType string) string {
 		return "结构体嵌入"
 	case types.DepTypeConstructor:
 		return "构造函数调用"
+	case types.DepTypeConstructorParam:
+		return "构造函数参数"
+	case types.DepTypeRelation:
+		return "ORM 关联"
 	default:
 		return "依赖"
 	}
 }
 
+// serializedNameKeys 序列化名在报告中的显示顺序
+var serializedNameKeys = []string{"json", "yaml", "db", "bson", "gorm"}
+
+// formatSerializedNames 将序列化名格式化为 `json:id` `db:user_id` 形式，没有时返回 "-"
+func formatSerializedNames(names map[string]string) string {
+	var parts []string
+	for _, key := range serializedNameKeys {
+		if name, ok := names[key]; ok {
+			parts = append(parts, fmt.Sprintf("`%s:%s`", key, escapeMarkdown(name)))
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
 // escapeMarkdown 转义 Markdown 特殊字符
 func escapeMarkdown(s string) string {
 	s = strings.ReplaceAll(s, "|", "\\|")
@@ -33,9 +33,15 @@ func (m *MermaidGenerator) Generate(result *types.AnalysisResult) string {
 	for _, s := range result.Structs {
 		nodeID := sanitizeID(s.Name)
 		label := fmt.Sprintf("%s<br/>%s", s.Name, truncate(s.Description, 15))
+		if s.Layer != "" {
+			label = fmt.Sprintf("%s<br/>«%s»", label, s.Layer)
+		}
 		m.builder.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", nodeID, label))
 	}
 
+	// 第三方模块类型按模块分组
+	m.addExternalNodes(result)
+
 	m.builder.WriteString("\n")
 
 	// 生成边
@@ -53,14 +59,22 @@ func (m *MermaidGenerator) Generate(result *types.AnalysisResult) string {
 			edgeSet[edgeKey] = true
 
 			edgeLabel := m.getEdgeLabel(dep.Type)
-			m.builder.WriteString(fmt.Sprintf("    %s -->|%s| %s\n", fromID, edgeLabel, toID))
+			arrow := "-->"
+			if dep.External {
+				arrow = "-.->"
+			}
+			m.builder.WriteString(fmt.Sprintf("    %s %s|%s| %s\n", fromID, arrow, edgeLabel, toID))
 		}
 	}
 
 	m.builder.WriteString("\n")
 
-	// 添加样式 - 按深度着色
-	m.addStyles(result)
+	// 添加样式 - 判定出角色时按角色着色，否则按深度着色
+	if roles := structRoles(result.Structs); len(roles) > 0 {
+		m.addRoleStyles(result, roles)
+	} else {
+		m.addStyles(result)
+	}
 
 	return m.builder.String()
 }
@@ -71,6 +85,150 @@ func (m *MermaidGenerator) GenerateToFile(result *types.AnalysisResult, filePath
 	return os.WriteFile(filePath, []byte(content), 0644)
 }
 
+// GeneratePackages 生成包级依赖图：边上标注贡献的结构体依赖数量，包级循环依赖用粗线标出
+func (m *MermaidGenerator) GeneratePackages(result *types.AnalysisResult) string {
+	m.builder.Reset()
+	m.builder.WriteString("graph TD\n")
+
+	graph := result.PackageGraph
+	for _, pkg := range graph.Packages {
+		label := fmt.Sprintf("%s<br/>%s<br/>%d 个类型", pkg.Name, pkg.ImportPath, len(pkg.Structs))
+		if pkg.External {
+			m.builder.WriteString(fmt.Sprintf("    %s[[\"%s\"]]\n", packageNodeID(pkg.ImportPath), label))
+		} else {
+			m.builder.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", packageNodeID(pkg.ImportPath), label))
+		}
+	}
+
+	m.builder.WriteString("\n")
+
+	cycleEdges := packageCycleEdges(graph)
+	external := make(map[string]bool)
+	for _, pkg := range graph.Packages {
+		external[pkg.ImportPath] = pkg.External
+	}
+	for _, edge := range graph.Edges {
+		arrow := "-->"
+		if cycleEdges[edge.From+"->"+edge.To] {
+			arrow = "==>"
+		} else if external[edge.To] {
+			arrow = "-.->"
+		}
+		m.builder.WriteString(fmt.Sprintf("    %s %s|%d| %s\n", packageNodeID(edge.From), arrow, edge.Weight, packageNodeID(edge.To)))
+	}
+
+	m.builder.WriteString("\n")
+
+	inCycle := make(map[string]bool)
+	for _, cycle := range graph.Cycles {
+		for _, p := range cycle {
+			inCycle[p] = true
+		}
+	}
+	for _, pkg := range graph.Packages {
+		id := packageNodeID(pkg.ImportPath)
+		switch {
+		case pkg.External:
+			m.builder.WriteString(fmt.Sprintf("    style %s fill:#eeeeee,stroke-dasharray: 5 5\n", id))
+		case inCycle[pkg.ImportPath]:
+			m.builder.WriteString(fmt.Sprintf("    style %s fill:#ffcccc,stroke:#cc0000\n", id))
+		default:
+			m.builder.WriteString(fmt.Sprintf("    style %s fill:#99ccff\n", id))
+		}
+	}
+
+	return m.builder.String()
+}
+
+// GeneratePackagesToFile 生成包级依赖图并保存到文件
+func (m *MermaidGenerator) GeneratePackagesToFile(result *types.AnalysisResult, filePath string) error {
+	content := m.GeneratePackages(result)
+	return os.WriteFile(filePath, []byte(content), 0644)
+}
+
+// packageNodeID 返回包节点 ID（加前缀以免与结构体节点冲突）
+func packageNodeID(importPath string) string {
+	return "pkg_" + sanitizeID(importPath)
+}
+
+// packageCycleEdges 返回位于包级循环上的边
+func packageCycleEdges(graph types.PackageGraph) map[string]bool {
+	edges := make(map[string]bool)
+	for _, cycle := range graph.Cycles {
+		for i, from := range cycle {
+			edges[from+"->"+cycle[(i+1)%len(cycle)]] = true
+		}
+	}
+	return edges
+}
+
+// GeneratePath 生成路径查询结果的 Mermaid 图：列出全部路径上的边，第一条（最短）路径高亮
+func (m *MermaidGenerator) GeneratePath(result types.PathResult) string {
+	m.builder.Reset()
+	m.builder.WriteString("graph LR\n")
+	if len(result.Paths) == 0 {
+		return m.builder.String()
+	}
+
+	var nodes []string
+	seenNode := make(map[string]bool)
+	var hops []types.PathHop
+	edgeIndex := make(map[string]int)
+	for _, path := range result.Paths {
+		for _, n := range path.Nodes {
+			if !seenNode[n] {
+				seenNode[n] = true
+				nodes = append(nodes, n)
+			}
+		}
+		for _, hop := range path.Hops {
+			key := hop.From + "->" + hop.To
+			if _, ok := edgeIndex[key]; !ok {
+				edgeIndex[key] = len(hops)
+				hops = append(hops, hop)
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		m.builder.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", sanitizeID(n), n))
+	}
+	m.builder.WriteString("\n")
+
+	for _, hop := range hops {
+		labels := make([]string, 0, len(hop.Types))
+		for _, t := range hop.Types {
+			labels = append(labels, m.getEdgeLabel(t))
+		}
+		m.builder.WriteString(fmt.Sprintf("    %s -->|%s| %s\n", sanitizeID(hop.From), strings.Join(labels, "/"), sanitizeID(hop.To)))
+	}
+	m.builder.WriteString("\n")
+
+	// 高亮最短路径
+	shortest := result.Paths[0]
+	for _, hop := range shortest.Hops {
+		m.builder.WriteString(fmt.Sprintf("    linkStyle %d stroke:#cc0000,stroke-width:3px\n", edgeIndex[hop.From+"->"+hop.To]))
+	}
+	for _, n := range shortest.Nodes {
+		m.builder.WriteString(fmt.Sprintf("    style %s fill:#ffcccc,stroke:#cc0000\n", sanitizeID(n)))
+	}
+
+	return m.builder.String()
+}
+
+// addExternalNodes 添加第三方模块类型节点（每个模块一个子图）
+func (m *MermaidGenerator) addExternalNodes(result *types.AnalysisResult) {
+	for i, ext := range result.ExternalTypes {
+		if i == 0 || result.ExternalTypes[i-1].Module != ext.Module {
+			m.builder.WriteString(fmt.Sprintf("    subgraph ext_%s[\"%s\"]\n", sanitizeID(ext.Module), ext.Module))
+		}
+		m.builder.WriteString(fmt.Sprintf("        %s[[\"%s\"]]\n", sanitizeID(ext.Name), ext.Name))
+		if i == len(result.ExternalTypes)-1 || result.ExternalTypes[i+1].Module != ext.Module {
+			m.builder.WriteString("    end\n")
+		}
+	}
+}
+
 // getEdgeLabel 获取边的标签
 func (m *MermaidGenerator) getEdgeLabel(depType string) string {
 	switch depType {
@@ -86,6 +244,10 @@ func (m *MermaidGenerator) getEdgeLabel(depType string) string {
 		return "嵌入"
 	case types.DepTypeConstructor:
 		return "构造"
+	case types.DepTypeConstructorParam:
+		return "注入"
+	case types.DepTypeRelation:
+		return "关联"
 	default:
 		return "依赖"
 	}
@@ -111,6 +273,29 @@ func (m *MermaidGenerator) addStyles(result *types.AnalysisResult) {
 		}
 		m.builder.WriteString(fmt.Sprintf("    style %s fill:%s\n", nodeID, colors[colorIdx]))
 	}
+
+	for _, ext := range result.ExternalTypes {
+		m.builder.WriteString(fmt.Sprintf("    style %s fill:#eeeeee,stroke-dasharray: 5 5\n", sanitizeID(ext.Name)))
+	}
+}
+
+// addRoleStyles 按架构角色着色：每个角色一个 classDef，未判定角色的结构体保持默认样式
+func (m *MermaidGenerator) addRoleStyles(result *types.AnalysisResult, roles []string) {
+	for _, role := range roles {
+		var ids []string
+		for _, s := range result.Structs {
+			if s.Role == role {
+				ids = append(ids, sanitizeID(s.Name))
+			}
+		}
+		class := "role_" + sanitizeID(role)
+		m.builder.WriteString(fmt.Sprintf("    classDef %s fill:%s\n", class, roleFill(role, roles)))
+		m.builder.WriteString(fmt.Sprintf("    class %s %s\n", strings.Join(ids, ","), class))
+	}
+
+	for _, ext := range result.ExternalTypes {
+		m.builder.WriteString(fmt.Sprintf("    style %s fill:#eeeeee,stroke-dasharray: 5 5\n", sanitizeID(ext.Name)))
+	}
 }
 
 // sanitizeID 清理节点 ID，移除特殊字符
@@ -121,6 +306,8 @@ func sanitizeID(name string) string {
 	name = strings.ReplaceAll(name, "]", "_")
 	name = strings.ReplaceAll(name, " ", "_")
 	name = strings.ReplaceAll(name, "-", "_")
+	name = strings.ReplaceAll(name, "/", "_")
+	name = strings.ReplaceAll(name, "~", "_")
 	return name
 }
 
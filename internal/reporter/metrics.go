@@ -0,0 +1,138 @@
+package reporter
+
+import (
+	"sort"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// 度量表格的排序键
+const (
+	SortByName         = "name"
+	SortByFanIn        = "fan-in"
+	SortByFanOut       = "fan-out"
+	SortByLCOM         = "lcom"
+	SortByCa           = "ca"
+	SortByCe           = "ce"
+	SortByInstability  = "instability"
+	SortByAbstractness = "abstractness"
+	SortByDistance     = "distance"
+	SortByReach        = "reach"
+	SortByPageRank     = "pagerank"
+	SortByBetweenness  = "betweenness"
+)
+
+// MetricsSortKeys 返回全部可用的度量排序键
+func MetricsSortKeys() []string {
+	return []string{SortByName, SortByFanIn, SortByFanOut, SortByLCOM, SortByCa, SortByCe, SortByInstability, SortByAbstractness, SortByDistance,
+		SortByReach, SortByPageRank, SortByBetweenness}
+}
+
+// IsMetricsSortKey 判断是否为有效的度量排序键
+func IsMetricsSortKey(key string) bool {
+	for _, k := range MetricsSortKeys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// RankKeys 返回可用于关键结构体排行和可视化节点大小的度量键
+func RankKeys() []string {
+	return []string{SortByReach, SortByPageRank, SortByBetweenness, SortByFanIn}
+}
+
+// IsRankKey 判断是否为有效的排行度量键
+func IsRankKey(key string) bool {
+	for _, k := range RankKeys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// rankLabel 返回排行度量键的中文名称
+func rankLabel(key string) string {
+	switch key {
+	case SortByPageRank:
+		return "PageRank"
+	case SortByBetweenness:
+		return "介数中心性"
+	case SortByFanIn:
+		return "Fan-in"
+	default:
+		return "影响范围"
+	}
+}
+
+// rankValue 返回结构体度量中排行键对应的值，未知键按影响范围
+func rankValue(m types.StructMetrics, key string) float64 {
+	switch key {
+	case SortByPageRank:
+		return m.PageRank
+	case SortByBetweenness:
+		return m.Betweenness
+	case SortByFanIn:
+		return float64(m.FanIn)
+	default:
+		return float64(m.Reach)
+	}
+}
+
+// SortStructMetrics 按指定键排序结构体度量（名称升序，其余降序，相同时按名称）
+// 键不适用于结构体（如 distance）时按 fan-in 排序
+func SortStructMetrics(metrics []types.StructMetrics, key string) {
+	value := func(m types.StructMetrics) float64 {
+		switch key {
+		case SortByFanOut:
+			return float64(m.FanOut)
+		case SortByLCOM:
+			return m.LCOM
+		case SortByReach, SortByPageRank, SortByBetweenness:
+			return rankValue(m, key)
+		default:
+			return float64(m.FanIn)
+		}
+	}
+	sort.SliceStable(metrics, func(i, j int) bool {
+		if key != SortByName {
+			if vi, vj := value(metrics[i]), value(metrics[j]); vi != vj {
+				return vi > vj
+			}
+		}
+		return metrics[i].Name < metrics[j].Name
+	})
+}
+
+// SortPackageMetrics 按指定键排序包度量（名称升序，其余降序，相同时按导入路径）
+// 键为空时与命令行默认一致按 fan-in 排序，键不适用于包（如 lcom、reach）时按 distance 排序
+func SortPackageMetrics(metrics []types.PackageMetrics, key string) {
+	value := func(m types.PackageMetrics) float64 {
+		switch key {
+		case "", SortByFanIn:
+			return float64(m.FanIn)
+		case SortByFanOut:
+			return float64(m.FanOut)
+		case SortByCa:
+			return float64(m.Ca)
+		case SortByCe:
+			return float64(m.Ce)
+		case SortByInstability:
+			return m.Instability
+		case SortByAbstractness:
+			return m.Abstractness
+		default:
+			return m.Distance
+		}
+	}
+	sort.SliceStable(metrics, func(i, j int) bool {
+		if key != SortByName {
+			if vi, vj := value(metrics[i]), value(metrics[j]); vi != vj {
+				return vi > vj
+			}
+		}
+		return metrics[i].ImportPath < metrics[j].ImportPath
+	})
+}
@@ -318,6 +318,8 @@ func TestGetDepTypeLabel(t *testing.T) {
 		{types.DepTypeInterface, "接口实现"},
 		{types.DepTypeEmbed, "结构体嵌入"},
 		{types.DepTypeConstructor, "构造函数调用"},
+		{types.DepTypeConstructorParam, "构造函数参数"},
+		{types.DepTypeRelation, "ORM 关联"},
 		{"unknown", "依赖"},
 	}
 
@@ -346,6 +348,7 @@ func TestMermaidGenerator_getEdgeLabel(t *testing.T) {
 		{types.DepTypeInterface, "实现"},
 		{types.DepTypeEmbed, "嵌入"},
 		{types.DepTypeConstructor, "构造"},
+		{types.DepTypeRelation, "关联"},
 		{"unknown", "依赖"},
 	}
 
@@ -379,6 +382,35 @@ func TestMarkdownReporter_WithCycles(t *testing.T) {
 	}
 }
 
+func TestMarkdownReporter_CycleDetails(t *testing.T) {
+	result := createTestAnalysisResult()
+	result.Cycles = [][]string{{"A", "B"}}
+	result.Components = [][]string{{"A", "B"}}
+	result.CycleDetails = []types.CycleDetail{{
+		Nodes:     []string{"A", "B"},
+		EdgeTypes: []string{types.DepTypeField, types.DepTypeInterface},
+		Edges: []types.CycleEdge{
+			{From: "A", To: "B", Types: []string{types.DepTypeField}, Evidence: []string{"b 字段"}},
+			{From: "B", To: "A", Types: []string{types.DepTypeInterface}, Evidence: []string{"实现接口"}},
+		},
+	}}
+	result.CyclesTruncated = true
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"### 强连通分量",
+		"1. A, B（2 个结构体）",
+		"1. A -> B -> A（字段依赖, 接口实现）",
+		"   - A -> B: 字段依赖（b 字段）",
+		"   - B -> A: 接口实现（实现接口）",
+		"仅列出前 1 个",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+}
+
 // ==================== 嵌入字段测试 ====================
 
 func TestMarkdownReporter_EmbeddedField(t *testing.T) {
@@ -406,3 +438,819 @@ func TestMarkdownReporter_EmbeddedField(t *testing.T) {
 		t.Error("Embedded field should be marked with (嵌入)")
 	}
 }
+
+func TestReporters_ExternalTypes(t *testing.T) {
+	result := createTestAnalysisResult()
+	result.Structs[1].Dependencies = append(result.Structs[1].Dependencies, types.Dependency{
+		From: "UserRepository", To: "gorm.DB", Type: types.DepTypeField, Context: "orm 字段", Depth: 2, External: true,
+	})
+	result.ExternalTypes = []types.ExternalType{
+		{Name: "gorm.DB", ImportPath: "gorm.io/gorm", Module: "gorm.io/gorm", Version: "v1.25.0", UsedBy: []string{"UserRepository"}},
+	}
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{"## 外部依赖", "### gorm.io/gorm v1.25.0", "| gorm.DB | `gorm.io/gorm` | UserRepository | - |"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	mermaid := NewMermaidGenerator().Generate(result)
+	for _, expected := range []string{"subgraph ext_gorm_io_gorm[\"gorm.io/gorm\"]", "gorm_DB[[\"gorm.DB\"]]", "UserRepository -.->|字段| gorm_DB"} {
+		if !strings.Contains(mermaid, expected) {
+			t.Errorf("mermaid should contain %q, got:\n%s", expected, mermaid)
+		}
+	}
+
+	viz := NewVisualizerReporter().Generate(result)
+	found := false
+	for _, s := range viz.Structs {
+		if s.ID == "struct-gorm.DB" && s.Metadata.Color == "gray" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("visualizer output should contain external node struct-gorm.DB")
+	}
+}
+
+func TestMarkdownReporter_Decisions(t *testing.T) {
+	result := createTestAnalysisResult()
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	if strings.Contains(content, "## 过滤决策") {
+		t.Error("decisions section should be omitted when there are no decisions")
+	}
+
+	result.Decisions = []types.TypeDecision{
+		{Type: "sync.Mutex", Rule: types.DecisionStdlib, Detail: "标准库类型", From: "UserService", DepType: types.DepTypeField, Context: "mu 字段"},
+		{Type: "repository.UserRepository", Accepted: true, Rule: types.DecisionAccepted, Detail: "项目内部类型", From: "UserService", DepType: types.DepTypeField, Context: "repo 字段"},
+	}
+	content = NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"## 过滤决策",
+		"- `stdlib`: 1",
+		"| `sync.Mutex` | 忽略 | `stdlib` | 标准库类型 | UserService | mu 字段 | 字段依赖 |",
+		"| `repository.UserRepository` | 保留 | `accepted` |",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+}
+
+func TestReporters_PackageGraph(t *testing.T) {
+	result := createTestAnalysisResult()
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	if strings.Contains(content, "## 包依赖") {
+		t.Error("package section should be omitted when there are no package edges")
+	}
+
+	dep := types.Dependency{From: "UserService", To: "UserRepository", Type: types.DepTypeField, Context: "repo 字段"}
+	result.PackageGraph = types.PackageGraph{
+		Packages: []types.PackageNode{
+			{ImportPath: "example.com/app/repository", Name: "repository", Structs: []string{"UserRepository"}, Depth: 1},
+			{ImportPath: "example.com/app/service", Name: "service", Structs: []string{"UserService"}},
+			{ImportPath: "gorm.io/gorm", Name: "gorm", Structs: []string{"gorm.DB"}, Depth: 2, External: true},
+		},
+		Edges: []types.PackageEdge{
+			{From: "example.com/app/repository", To: "example.com/app/service", Weight: 1, Deps: []types.Dependency{{From: "UserRepository", To: "UserService", Type: types.DepTypeMethodCall}}},
+			{From: "example.com/app/repository", To: "gorm.io/gorm", Weight: 1},
+			{From: "example.com/app/service", To: "example.com/app/repository", Weight: 1, Deps: []types.Dependency{dep}},
+		},
+		Cycles: [][]string{{"example.com/app/repository", "example.com/app/service"}},
+	}
+
+	content = NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"## 包依赖",
+		"| gorm（外部） | `gorm.io/gorm` | gorm.DB |",
+		"| `example.com/app/service` | `example.com/app/repository` | 1 | UserService -> UserRepository (字段依赖) |",
+		"### 包级循环依赖",
+		"1. example.com/app/repository -> example.com/app/service -> example.com/app/repository",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	mermaid := NewMermaidGenerator().GeneratePackages(result)
+	for _, expected := range []string{
+		"pkg_gorm_io_gorm[[",
+		"pkg_example_com_app_service ==>|1| pkg_example_com_app_repository",
+		"pkg_example_com_app_repository -.->|1| pkg_gorm_io_gorm",
+		"style pkg_example_com_app_service fill:#ffcccc",
+	} {
+		if !strings.Contains(mermaid, expected) {
+			t.Errorf("package mermaid should contain %q, got:\n%s", expected, mermaid)
+		}
+	}
+
+	viz := NewVisualizerReporter().GeneratePackages(result)
+	if len(viz.Structs) != 3 || len(viz.Connections) != 3 {
+		t.Fatalf("expected 3 package boxes and 3 connections, got %d/%d", len(viz.Structs), len(viz.Connections))
+	}
+	colors := make(map[string]string)
+	for _, s := range viz.Structs {
+		colors[s.ID] = s.Metadata.Color
+	}
+	if colors["package-gorm.io/gorm"] != "gray" || colors["package-example.com/app/service"] != "red" {
+		t.Errorf("unexpected package colors: %v", colors)
+	}
+}
+
+func TestMarkdownReporter_Metrics(t *testing.T) {
+	result := createTestAnalysisResult()
+	result.Metrics = types.Metrics{
+		Structs: []types.StructMetrics{
+			{Name: "Cache", Package: "cache", FanIn: 1, Fields: 1, Methods: 2, LCOM: 0.8},
+			{Name: "UserService", Package: "service", FanOut: 2, Fields: 2, Methods: 3, LCOM: 0.25},
+		},
+		Packages: []types.PackageMetrics{
+			{ImportPath: "example.com/app/cache", Name: "cache", FanIn: 1, Ca: 1, Instability: 0, Distance: 0},
+			{ImportPath: "example.com/app/service", Name: "service", Ce: 2, Instability: 1, Distance: 1},
+		},
+	}
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"## 耦合与内聚度量",
+		"| `example.com/app/service` | 0 | 0 | 0 | 2 | 1.00 | 0.00 | 1.00 |",
+		"| UserService | service | 0 | 2 | 2 | 3 | 0.25 |",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	// 默认按 fan-in 降序（与命令行 --metrics-sort 默认值一致）
+	cacheRow, serviceRow := "| Cache | cache |", "| UserService | service |"
+	if strings.Index(content, cacheRow) > strings.Index(content, serviceRow) {
+		t.Error("structs should be sorted by fan-in by default")
+	}
+	if strings.Index(content, "| `example.com/app/cache`") > strings.Index(content, "| `example.com/app/service`") {
+		t.Error("packages should be sorted by fan-in by default")
+	}
+
+	r := NewMarkdownReporter()
+	r.SetMetricsSort(SortByFanOut)
+	content = r.Generate(result, nil)
+	if strings.Index(content, serviceRow) > strings.Index(content, cacheRow) {
+		t.Error("structs should be sorted by fan-out")
+	}
+
+	r.SetMetricsSort(SortByDistance)
+	content = r.Generate(result, nil)
+	if strings.Index(content, "| `example.com/app/service`") > strings.Index(content, "| `example.com/app/cache`") {
+		t.Error("packages should be sorted by distance")
+	}
+}
+
+func TestReporters_Ranking(t *testing.T) {
+	result := createTestAnalysisResult()
+	result.Metrics = types.Metrics{
+		Structs: []types.StructMetrics{
+			{Name: "Cache", Package: "cache", FanIn: 1, Reach: 1, PageRank: 0.4, Betweenness: 0},
+			{Name: "UserRepository", Package: "repository", FanIn: 1, Reach: 2, PageRank: 0.3, Betweenness: 0.5},
+			{Name: "UserService", Package: "service", PageRank: 0.1},
+		},
+	}
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"### 关键结构体排行（按影响范围）",
+		"| 1 | UserRepository | repository | 2 | 0.3000 | 0.5000 | 1 |",
+		"| 2 | Cache | cache | 1 | 0.4000 | 0.0000 | 1 |",
+		"| UserRepository | repository | 1 | 0 | 0 | 0 | 0.00 | 2 | 0.3000 | 0.5000 |",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+	if strings.Contains(content, "| 3 | UserService |") {
+		t.Error("structs without reach should not be ranked")
+	}
+
+	r := NewMarkdownReporter()
+	r.SetRankBy(SortByPageRank)
+	content = r.Generate(result, nil)
+	if !strings.Contains(content, "### 关键结构体排行（按PageRank）") || !strings.Contains(content, "| 1 | Cache | cache |") {
+		t.Error("ranking should follow PageRank")
+	}
+
+	viz := NewVisualizerReporter()
+	viz.SizeBy = SortByReach
+	sizes := make(map[string]VisualizerStruct)
+	for _, s := range viz.Generate(result).Structs {
+		sizes[s.Metadata.Name] = s
+	}
+	if repo := sizes["UserRepository"]; repo.Width != viz.BoxWidth*1.25 || repo.Weight != 2 || repo.Metadata.FontSize != "l" {
+		t.Errorf("UserRepository should be the largest node, got %+v", repo)
+	}
+	if svc := sizes["UserService"]; svc.Width != viz.BoxWidth*0.75 || svc.Metadata.FontSize != "s" {
+		t.Errorf("UserService should be the smallest node, got %+v", svc)
+	}
+	for _, s := range NewVisualizerReporter().Generate(result).Structs {
+		if s.Width != 0 || s.Metadata.FontSize != "m" {
+			t.Errorf("nodes should keep the default size without SizeBy, got %+v", s)
+		}
+	}
+}
+
+func TestMarkdownReporter_FieldAccess(t *testing.T) {
+	result := createTestAnalysisResult()
+	result.Structs[0].FieldAccess = types.FieldAccessMatrix{
+		Methods: []string{"GetUser", "Name"},
+		Fields:  []string{"repo", "cache"},
+		Accesses: []types.FieldAccess{
+			{Method: "GetUser", Field: "repo", Call: true},
+			{Method: "GetUser", Field: "cache", Read: true, Write: true},
+		},
+		FieldlessMethods: []string{"Name"},
+	}
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"## 字段访问矩阵",
+		"| 方法 | repo | cache |",
+		"| GetUser | C | RW |",
+		"| Name |  |  |",
+		"- **未访问字段的方法**（可考虑改为函数）: Name",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+}
+
+func TestMermaidGenerator_GeneratePath(t *testing.T) {
+	result := types.PathResult{
+		From: "UserHandler",
+		To:   "UserRepo",
+		Paths: []types.DependencyPath{
+			{
+				Nodes: []string{"UserHandler", "UserRepo"},
+				Hops:  []types.PathHop{{From: "UserHandler", To: "UserRepo", Types: []string{types.DepTypeField}}},
+			},
+			{
+				Nodes: []string{"UserHandler", "UserService", "UserRepo"},
+				Hops: []types.PathHop{
+					{From: "UserHandler", To: "UserService", Types: []string{types.DepTypeField}},
+					{From: "UserService", To: "UserRepo", Types: []string{types.DepTypeField, types.DepTypeMethodCall}},
+				},
+			},
+		},
+	}
+
+	mermaid := NewMermaidGenerator().GeneratePath(result)
+	for _, expected := range []string{
+		"graph LR",
+		"UserHandler -->|字段| UserRepo",
+		"UserService -->|字段/调用| UserRepo",
+		"linkStyle 0 stroke:#cc0000",
+		"style UserRepo fill:#ffcccc",
+	} {
+		if !strings.Contains(mermaid, expected) {
+			t.Errorf("path mermaid should contain %q, got:\n%s", expected, mermaid)
+		}
+	}
+	if strings.Contains(mermaid, "linkStyle 1 ") || strings.Contains(mermaid, "style UserService fill") {
+		t.Error("only the shortest path should be highlighted")
+	}
+}
+
+func TestReporters_Impact(t *testing.T) {
+	result := &types.ImpactResult{
+		Base:  "main",
+		Files: []string{"model/user.go"},
+		Changed: []types.ChangedDecl{
+			{Kind: types.ChangedKindMethod, Name: "User.Validate", Type: "User", FilePath: "model/user.go", Line: 18},
+		},
+		Impacted: []types.ImpactedType{
+			{Name: "User", Package: "model"},
+			{Name: "UserRepository", Package: "repository", Distance: 1, Via: "User", Types: []string{types.DepTypeField}, Evidence: []string{"user 字段"}},
+		},
+		MaxDepth: 3,
+	}
+
+	content := NewMarkdownReporter().GenerateImpact(result)
+	for _, expected := range []string{
+		"**对比基准**: `main`",
+		"**受影响类型**: 1",
+		"| `User.Validate` | 方法 | model/user.go:18 |",
+		"| User | model | 0 | （改动） |  |",
+		"| UserRepository | repository | 1 | User（字段依赖） | user 字段 |",
+		"- model/user.go",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("impact markdown should contain %q, got:\n%s", expected, content)
+		}
+	}
+
+	data, err := NewJSONReporter().GenerateImpact(result)
+	if err != nil {
+		t.Fatalf("GenerateImpact() failed: %v", err)
+	}
+	var decoded types.ImpactResult
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil || len(decoded.Impacted) != 2 {
+		t.Errorf("impact JSON should round-trip, err %v", err)
+	}
+}
+
+func TestReporters_Roles(t *testing.T) {
+	result := createTestAnalysisResult()
+	for i := range result.Structs {
+		switch result.Structs[i].Name {
+		case "UserService":
+			result.Structs[i].Role, result.Structs[i].RoleReason = types.RoleService, "名称 UserService"
+		case "UserRepository":
+			result.Structs[i].Role, result.Structs[i].RoleReason = "gateway", "LLM 判定"
+		}
+	}
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"- **架构角色分布**:\n  - service: 1 个\n  - gateway: 1 个\n  - 未判定: 1 个\n",
+		"**架构角色**: `service`（名称 UserService）",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	mermaid := NewMermaidGenerator().Generate(result)
+	for _, expected := range []string{
+		"classDef role_service fill:#99ccff",
+		"class UserService role_service",
+		"classDef role_gateway fill:#ffff99",
+		"class UserRepository role_gateway",
+	} {
+		if !strings.Contains(mermaid, expected) {
+			t.Errorf("mermaid should contain %q, got:\n%s", expected, mermaid)
+		}
+	}
+	if strings.Contains(mermaid, "style UserService fill:") {
+		t.Error("nodes should be colored by role instead of depth")
+	}
+
+	colors := make(map[string]string)
+	for _, s := range NewVisualizerReporter().Generate(result).Structs {
+		colors[s.Metadata.Name] = s.Metadata.Color
+	}
+	if colors["UserService"] != "blue" || colors["UserRepository"] != "black" || colors["Cache"] != "black" {
+		t.Errorf("visualizer should color by role, got %v", colors)
+	}
+}
+
+func TestMarkdownReporter_DeadCode(t *testing.T) {
+	result := createTestAnalysisResult()
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	if strings.Contains(content, "## 死代码与不可达类型") {
+		t.Error("dead code section should be omitted when nothing is reported")
+	}
+
+	result.DeadCode = types.DeadCodeReport{
+		Roots:                   []string{types.RootMain, types.RootExported},
+		UnreachableStructs:      []types.DeadDecl{{Name: "LegacyService", Package: "service", FilePath: filepath.Join(result.ProjectPath, "service", "legacy.go")}},
+		UnusedConstructors:      []types.DeadDecl{{Name: "NewLegacyService", Package: "service"}},
+		UnimplementedInterfaces: []types.DeadDecl{{Name: "Notifier", Package: "service"}},
+		SingleImplInterfaces:    []types.InterfaceImpl{{Interface: "Store", Package: "repository", Implementer: "UserRepository"}},
+	}
+	content = NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"## 死代码与不可达类型",
+		"从以下根出发: main, exported",
+		"### 不可达的结构体 (1)",
+		"| LegacyService | service | " + filepath.Join("service", "legacy.go") + " |",
+		"### 未使用的构造函数 (1)",
+		"### 没有实现的接口 (1)",
+		"| Store | repository | UserRepository |",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+	if strings.Contains(content, "### 不可达的接口") {
+		t.Error("empty subsections should be omitted")
+	}
+
+	result.DeadCode.SingleImplInterfaces = nil
+	content = NewMarkdownReporter().Generate(result, nil)
+	if strings.Contains(content, "只有一个实现的接口可能是过度抽象") {
+		t.Error("single-implementation note should be omitted when no such interface is reported")
+	}
+}
+
+func TestWireGenerator_Generate(t *testing.T) {
+	order := &types.InitOrder{
+		Root: "App",
+		Steps: []types.InitStep{
+			{Struct: "Config", Package: "config", ImportPath: "example.com/app/config", ReturnsPointer: true},
+			{Struct: "SQLStore", Package: "store", ImportPath: "example.com/app/store", Constructor: "NewSQLStore", ReturnsPointer: true, ReturnsError: true, Params: []types.InitParam{
+				{Name: "cfg", Type: "*config.Config", Imports: map[string]string{"config": "example.com/app/config"}, Source: "Config"},
+				{Name: "timeout", Type: "time.Duration", Imports: map[string]string{"time": "time"}},
+			}},
+			{Struct: "Stats", Package: "app", ImportPath: "example.com/app/app", Constructor: "NewStats"},
+			{Struct: "App", Package: "app", ImportPath: "example.com/app/app", Constructor: "NewApp", ReturnsPointer: true, Params: []types.InitParam{
+				{Name: "s", Type: "store.Store", Imports: map[string]string{"store": "example.com/app/store"}, Source: "SQLStore", Interface: true},
+				{Name: "stats", Type: "*app.Stats", Imports: map[string]string{"app": "example.com/app/app"}, Source: "Stats"},
+				{Name: "opts", Type: "app.Option", Variadic: true},
+			}},
+		},
+	}
+
+	src, err := NewWireGenerator().Generate(order, "main")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for _, expected := range []string{
+		"package main",
+		"\"time\"\n\n\t\"example.com/app/app\"",
+		"func InitializeApp() (*app.App, error) {",
+		"var timeout time.Duration // NewSQLStore",
+		"config2 := &config.Config{}",
+		"sqlStore, err := store.NewSQLStore(config2, timeout)",
+		"return nil, err",
+		"app2 := app.NewApp(sqlStore, &stats)",
+		"return app2, nil",
+	} {
+		if !strings.Contains(src, expected) {
+			t.Errorf("generated code should contain %q\n%s", expected, src)
+		}
+	}
+
+	order.Steps = order.Steps[:3]
+	order.Blocked = []string{"App"}
+	if _, err := NewWireGenerator().Generate(order, "main"); err == nil {
+		t.Error("expected error when root cannot be constructed")
+	}
+}
+
+func TestMarkdownReporter_InterfaceSuggestions(t *testing.T) {
+	result := createTestAnalysisResult()
+	result.Structs[0].InterfaceSuggestions = []types.InterfaceSuggestion{{
+		Field:     "repo",
+		FieldType: "repository.UserRepository",
+		Name:      "Repo",
+		Methods:   []types.InterfaceMethod{{Name: "FindByID", Signature: "(id int64) (*model.User, error)"}},
+		Callers:   []string{"GetUser"},
+		Imports:   []string{"example.com/app/model"},
+		Matching:  []string{"repository.Finder"},
+	}}
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"## 最小接口建议",
+		"#### repo `repository.UserRepository` → Repo",
+		"- **调用的方法**: FindByID（GetUser 中调用）",
+		"- **已有相同的接口**: repository.Finder，可直接使用",
+		"- **需要导入**: example.com/app/model",
+		"type Repo interface {\n\tFindByID(id int64) (*model.User, error)\n}",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+}
+
+func TestMarkdownReporter_UnusedMembers(t *testing.T) {
+	result := createTestAnalysisResult()
+	content := NewMarkdownReporter().Generate(result, nil)
+	if strings.Contains(content, "## 未使用的结构体成员") {
+		t.Error("member section should be omitted when nothing is reported")
+	}
+
+	result.Members = types.MemberUsageReport{
+		UnexportCandidates: []types.UnusedMember{{Struct: "UserService", Package: "service", Name: "Reset", Kind: types.MemberKindMethod, FilePath: filepath.Join(result.ProjectPath, "service", "user.go")}},
+		Unused:             []types.UnusedMember{{Struct: "UserService", Package: "service", Name: "dirty", Kind: types.MemberKindField}},
+	}
+	content = NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"## 未使用的结构体成员",
+		"### 可改为未导出的成员（没有被其他包使用） (1)",
+		"| UserService | Reset | 方法 | service | " + filepath.Join("service", "user.go") + " |",
+		"### 未被使用的未导出成员 (1)",
+		"| UserService | dirty | 字段 | service |",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	jsonContent, err := NewJSONReporter().Generate(result)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(jsonContent, `"UnexportCandidates"`) {
+		t.Error("JSON output should include member usage")
+	}
+}
+
+func TestMarkdownReporter_Layout(t *testing.T) {
+	result := createTestAnalysisResult()
+	content := NewMarkdownReporter().Generate(result, nil)
+	if strings.Contains(content, "#### 内存布局") {
+		t.Error("layout should be omitted when it was not computed")
+	}
+
+	result.Structs[0].Layout = types.StructLayout{
+		Arch: "amd64", Size: 24, Align: 8, Padding: 14, OptimalSize: 16,
+		Fields: []types.FieldLayout{
+			{Name: "A", Type: "bool", Offset: 0, Size: 1, Align: 1, Padding: 7},
+			{Name: "B", Type: "int64", Offset: 8, Size: 8, Align: 8},
+			{Name: "C", Type: "bool", Offset: 16, Size: 1, Align: 1, Padding: 7},
+		},
+		SuggestedOrder: []string{"B", "A", "C"},
+	}
+	result.Structs[1].Layout = types.StructLayout{Arch: "amd64", Unknown: []string{"lib.Client"}}
+	content = NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"#### 内存布局 (amd64)",
+		"大小 24 字节，对齐 8 字节，填充 14 字节",
+		"| A | bool | 0 | 1 | 1 | 7 |",
+		"**建议字段顺序**（大小 24 → 16 字节）: B, A, C",
+		"无法确定大小的类型: `lib.Client`，未计算布局",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	jsonContent, err := NewJSONReporter().Generate(result)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(jsonContent, `"SuggestedOrder"`) {
+		t.Error("JSON output should include struct layout")
+	}
+}
+
+func TestAPIDiffReporters(t *testing.T) {
+	result := &types.APIDiffResult{
+		Old: "v1.0.0", New: "HEAD", Breaking: 1, Compatible: 1,
+		Changes: []types.APIChange{
+			{Package: "example.com/lib/store", Name: "Item.Size", Kind: types.APIKindMethod, Change: types.APIChangeChanged, Breaking: true,
+				Old: "(Item) Size() int", New: "(*Item) Size() int", Reason: "接收者由值改为指针，值类型的方法集不再包含该方法", FilePath: "store/store.go", Line: 12},
+			{Package: "example.com/lib/store", Name: "Item.TTL", Kind: types.APIKindField, Change: types.APIChangeAdded,
+				New: "int", Reason: "新增字段"},
+		},
+	}
+
+	content := NewMarkdownReporter().GenerateAPIDiff(result)
+	for _, expected := range []string{
+		"**对比**: `v1.0.0` → `HEAD`",
+		"**不兼容变化**: 1 | **兼容变化**: 1",
+		"### 不兼容的变化 (1)",
+		"| `Item.Size` | example.com/lib/store | 方法 | 修改 | `(Item) Size() int` | `(*Item) Size() int` | 接收者由值改为指针，值类型的方法集不再包含该方法 | store/store.go:12 |",
+		"### 兼容的变化 (1)",
+		"| `Item.TTL` | example.com/lib/store | 字段 | 新增 |  | `int` | 新增字段 |  |",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	sarif, err := NewSARIFReporter().GenerateAPIDiff(result)
+	if err != nil {
+		t.Fatalf("GenerateAPIDiff failed: %v", err)
+	}
+	for _, expected := range []string{
+		`"version": "2.1.0"`,
+		`"ruleId": "api-breaking-change"`,
+		`"level": "error"`,
+		`"uri": "store/store.go"`,
+		`"startLine": 12`,
+		`"ruleId": "api-compatible-change"`,
+	} {
+		if !strings.Contains(sarif, expected) {
+			t.Errorf("SARIF should contain %q", expected)
+		}
+	}
+
+	empty := NewMarkdownReporter().GenerateAPIDiff(&types.APIDiffResult{Old: "a", New: "b"})
+	if !strings.Contains(empty, "导出 API 没有变化。") {
+		t.Error("markdown should say that nothing changed")
+	}
+}
+
+func TestResultDiffReporters(t *testing.T) {
+	diff := &types.ResultDiff{
+		Old: "old.json", New: "new.json", OldStructs: 2, NewStructs: 2, OldDeps: 1, NewDeps: 1,
+		AddedStructs:   []types.StructRef{{Name: "Logger", Package: "app", Depth: 1}},
+		RemovedStructs: []types.StructRef{{Name: "Cache", Package: "app", Depth: 1}},
+		Members: []types.MemberChange{
+			{Struct: "Service", Name: "log", Kind: types.APIKindField, Change: types.APIChangeAdded, New: "*Logger"},
+		},
+		AddedEdges:     []types.Dependency{{From: "Service", To: "Logger", Type: types.DepTypeField, Context: "log"}},
+		RemovedEdges:   []types.Dependency{{From: "Service", To: "Cache", Type: types.DepTypeField, Context: "cache"}},
+		DepthChanges:   []types.DepthChange{{Struct: "Repo", Old: 1, New: 2}},
+		ResolvedCycles: [][]string{{"Repo", "Service"}},
+		MetricDeltas: []types.MetricDelta{
+			{Scope: types.MetricScopePackage, Subject: "example.com/app", Metric: "instability", Old: 0.5, New: 0.25},
+		},
+	}
+
+	content := NewMarkdownReporter().GenerateDiff(diff)
+	for _, expected := range []string{
+		"## 分析结果对比",
+		"**旧结果**: `old.json` | **新结果**: `new.json`",
+		"| Logger | app | 新增 | 1 |",
+		"| Cache | app | 删除 | 1 |",
+		"| Service | log | 字段 | 新增 |  | `*Logger` |",
+		"| Service | Logger | 字段依赖 | log | 新增 |",
+		"| Repo | 1 | 2 |",
+		"- Repo -> Service -> Repo",
+		"| 包 | example.com/app | instability | 0.50 | 0.25 | -0.25 |",
+		"```mermaid",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	graph := NewMermaidGenerator().GenerateDiff(diff)
+	for _, expected := range []string{
+		"Service -->|",
+		"Service -.->|",
+		"linkStyle 0 stroke:#2da44e",
+		"linkStyle 1 stroke:#cf222e",
+		"style Logger fill:#ccffcc",
+		"style Cache fill:#ffcccc",
+	} {
+		if !strings.Contains(graph, expected) {
+			t.Errorf("mermaid should contain %q, got:\n%s", expected, graph)
+		}
+	}
+
+	empty := NewMarkdownReporter().GenerateDiff(&types.ResultDiff{Old: "a", New: "b"})
+	if !strings.Contains(empty, "两次分析结果没有差异。") {
+		t.Error("markdown should say that nothing changed")
+	}
+}
+
+func TestClusterReporters(t *testing.T) {
+	result := &types.ClusterResult{
+		Modularity: 0.42, PackageModularity: 0.18,
+		Clusters: []types.Cluster{
+			{ID: 1, Package: "example.com/shop/order", Structs: []string{"Order", "OrderItem", "Refund"}, Internal: 4, External: 1,
+				Packages: []types.ClusterPackage{
+					{ImportPath: "example.com/shop/order", Structs: []string{"Order", "OrderItem"}},
+					{ImportPath: "example.com/shop/billing", Structs: []string{"Refund"}},
+				}},
+			{ID: 2, Package: "example.com/shop/billing", Structs: []string{"Invoice", "Payment"}, Internal: 2, External: 1,
+				Packages: []types.ClusterPackage{{ImportPath: "example.com/shop/billing", Structs: []string{"Invoice", "Payment"}}}},
+		},
+		Misplaced: []types.MisplacedStruct{
+			{Struct: "Refund", Package: "example.com/shop/billing", Cluster: 1, Suggested: "example.com/shop/order", PackageLinks: 0, ClusterLinks: 3},
+		},
+		Isolated: []string{"Unused"},
+		Edges: []types.ClusterEdge{
+			{From: "Order", To: "OrderItem", Weight: 2},
+			{From: "Order", To: "Refund", Weight: 1},
+			{From: "Invoice", To: "Payment", Weight: 2},
+			{From: "Payment", To: "Refund", Weight: 1},
+		},
+	}
+
+	content := NewMarkdownReporter().GenerateClusters(result)
+	for _, expected := range []string{
+		"## 模块边界建议",
+		"**社区**: 2 | **位置不当的结构体**: 1 | **建议拆分的包**: 0 | **孤立结构体**: 1",
+		"**模块度**: 检测到的划分 0.4200，实际包布局 0.1800",
+		"| 1 | `example.com/shop/order` | 3 | 4 | 1 | `example.com/shop/order`: Order, OrderItem<br/>`example.com/shop/billing`: Refund |",
+		"| Refund | `example.com/shop/billing` | 1 | `example.com/shop/order` | 0 | 3 |",
+		"```mermaid",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	graph := NewMermaidGenerator().GenerateClusters(result)
+	for _, expected := range []string{
+		"subgraph cluster_1[\"社区 1 · order\"]",
+		"Refund[\"Refund<br/>billing\"]",
+		"Order ---|2| OrderItem",
+		"class Order,OrderItem,Refund c1",
+		"style Refund stroke:#cf222e",
+	} {
+		if !strings.Contains(graph, expected) {
+			t.Errorf("mermaid should contain %q, got:\n%s", expected, graph)
+		}
+	}
+
+	viz := NewVisualizerReporter().GenerateClusters(result)
+	colors := make(map[string]string)
+	for _, s := range viz.Structs {
+		colors[s.Metadata.Name] = s.Metadata.Color
+		if s.Metadata.Name == "Refund" && !strings.Contains(s.Metadata.Description, "建议移入 example.com/shop/order") {
+			t.Errorf("Refund description should suggest the target package, got %q", s.Metadata.Description)
+		}
+	}
+	if colors["Refund"] != colors["Order"] || colors["Refund"] == colors["Invoice"] {
+		t.Errorf("nodes should be coloured by cluster, got %v", colors)
+	}
+	if len(viz.Connections) != 4 {
+		t.Errorf("expected 4 connections, got %d", len(viz.Connections))
+	}
+
+	empty := NewMarkdownReporter().GenerateClusters(&types.ClusterResult{})
+	if !strings.Contains(empty, "结构体之间没有依赖，无法划分社区。") {
+		t.Error("markdown should say that there is nothing to cluster")
+	}
+}
+
+func TestMarkdownReporter_SerializedNames(t *testing.T) {
+	result := &types.AnalysisResult{
+		ProjectPath: "/test",
+		StartStruct: "User",
+		Structs: []types.StructAnalysis{
+			{
+				Name:    "User",
+				Package: "model",
+				Fields: []types.FieldAnalysis{
+					{Name: "ID", Type: "uint", IsExported: true, SerializedNames: map[string]string{"gorm": "uid", "json": "id", "db": "user_id"}},
+					{Name: "mu", Type: "sync.Mutex"},
+				},
+			},
+		},
+	}
+
+	content := NewMarkdownReporter().Generate(result, nil)
+	for _, expected := range []string{
+		"| 字段名 | 类型 | 导出 | 序列化名 | 描述 |",
+		"| ID | uint | ✓ | `json:id` `db:user_id` `gorm:uid` |",
+		"| mu | sync.Mutex | ✗ | - |",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	viz := NewVisualizerReporter().Generate(result)
+	if names := viz.Structs[0].Metadata.Fields[0].SerializedNames; names["json"] != "id" {
+		t.Errorf("visualizer field should carry serialized names, got %v", names)
+	}
+}
+
+func TestERReporters(t *testing.T) {
+	model := &types.ERModel{
+		Root: "User",
+		Entities: []types.EREntity{
+			{Name: "User", ImportPath: "example.com/shop/model", Attributes: []types.ERAttribute{
+				{Field: "ID", Column: "id", Type: "uint", Keys: []string{"PK"}, JSON: "id"},
+				{Field: "Tags", Column: "tags", Type: "[]string"},
+				{Field: "DeletedAt", Column: "deleted_at", Type: "*gorm.DeletedAt"},
+			}},
+			{Name: "Order", Attributes: []types.ERAttribute{
+				{Field: "OwnerID", Column: "owner_id", Type: "uint", Keys: []string{"FK"}},
+			}},
+			{Name: "Language"},
+		},
+		Relations: []types.ERRelation{
+			{From: "User", To: "Order", Kind: types.RelationHasMany, Field: "Orders", ForeignKey: "OwnerID"},
+			{From: "User", To: "Language", Kind: types.RelationManyToMany, Field: "Languages", JoinTable: "user_languages"},
+			{From: "Order", To: "User", Kind: types.RelationBelongsTo, Field: "Owner", ForeignKey: "OwnerID"},
+		},
+	}
+
+	graph := NewMermaidGenerator().GenerateER(model)
+	for _, expected := range []string{
+		"erDiagram\n",
+		"        uint id PK \"json: id\"\n",
+		"        string[] tags\n",
+		"        gorm_DeletedAt deleted_at\n",
+		"        uint owner_id FK\n",
+		"    Language {\n    }\n",
+		"    User ||--o{ Order : \"Orders\"\n",
+		"    User }o--o{ Language : \"user_languages\"\n",
+		"    Order }o--|| User : \"Owner\"\n",
+	} {
+		if !strings.Contains(graph, expected) {
+			t.Errorf("mermaid should contain %q, got:\n%s", expected, graph)
+		}
+	}
+
+	content := NewMarkdownReporter().GenerateER(model)
+	for _, expected := range []string{
+		"## 持久化模型",
+		"**起点**: User | **实体**: 3 | **关联**: 3",
+		"| User | many2many | Language | Languages | user_languages |",
+		"| id | ID | uint | PK | id |",
+		"```mermaid\nerDiagram",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("markdown should contain %q", expected)
+		}
+	}
+
+	data, err := NewJSONReporter().GenerateER(model)
+	if err != nil {
+		t.Fatalf("GenerateER failed: %v", err)
+	}
+	if !strings.Contains(data, `"JoinTable": "user_languages"`) {
+		t.Errorf("json should contain the join table, got:\n%s", data)
+	}
+}
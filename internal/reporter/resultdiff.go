@@ -0,0 +1,229 @@
+package reporter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// GenerateDiff 生成两次分析结果对比的 Markdown（适合作为 PR 评论），依赖边有变化时附带 Mermaid 图
+func (r *MarkdownReporter) GenerateDiff(diff *types.ResultDiff) string {
+	r.builder.Reset()
+
+	r.builder.WriteString("## 分析结果对比\n\n")
+	r.builder.WriteString(fmt.Sprintf("**旧结果**: `%s` | **新结果**: `%s`\n\n", diff.Old, diff.New))
+	for _, w := range diff.Warnings {
+		r.builder.WriteString(fmt.Sprintf("> 注意: %s\n", w))
+	}
+	if len(diff.Warnings) > 0 {
+		r.builder.WriteString("\n")
+	}
+
+	r.builder.WriteString("| 项目 | 旧 | 新 | 变化 |\n")
+	r.builder.WriteString("|------|----|----|------|\n")
+	r.builder.WriteString(fmt.Sprintf("| 结构体 | %d | %d | %s |\n", diff.OldStructs, diff.NewStructs, formatDelta(float64(diff.NewStructs-diff.OldStructs))))
+	r.builder.WriteString(fmt.Sprintf("| 依赖关系 | %d | %d | %s |\n\n", diff.OldDeps, diff.NewDeps, formatDelta(float64(diff.NewDeps-diff.OldDeps))))
+
+	r.builder.WriteString(fmt.Sprintf("**新增结构体**: %d | **删除结构体**: %d | **成员变化**: %d | **新增依赖**: %d | **删除依赖**: %d | **新增循环**: %d | **消除循环**: %d\n\n",
+		len(diff.AddedStructs), len(diff.RemovedStructs), len(diff.Members), len(diff.AddedEdges), len(diff.RemovedEdges),
+		len(diff.NewCycles), len(diff.ResolvedCycles)))
+
+	if len(diff.AddedStructs) == 0 && len(diff.RemovedStructs) == 0 && len(diff.Members) == 0 &&
+		len(diff.AddedEdges) == 0 && len(diff.RemovedEdges) == 0 && len(diff.DepthChanges) == 0 &&
+		len(diff.NewCycles) == 0 && len(diff.ResolvedCycles) == 0 && len(diff.MetricDeltas) == 0 {
+		r.builder.WriteString("两次分析结果没有差异。\n")
+		return r.builder.String()
+	}
+
+	// 1. 结构体
+	if len(diff.AddedStructs) > 0 || len(diff.RemovedStructs) > 0 {
+		r.builder.WriteString("### 结构体\n\n")
+		r.builder.WriteString("| 结构体 | 包 | 变化 | 深度 |\n")
+		r.builder.WriteString("|--------|----|------|------|\n")
+		for _, s := range diff.AddedStructs {
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | 新增 | %d |\n", s.Name, s.Package, s.Depth))
+		}
+		for _, s := range diff.RemovedStructs {
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | 删除 | %d |\n", s.Name, s.Package, s.Depth))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	// 2. 字段与方法
+	if len(diff.Members) > 0 {
+		r.builder.WriteString("### 字段与方法\n\n")
+		r.builder.WriteString("| 结构体 | 成员 | 种类 | 变化 | 变化前 | 变化后 |\n")
+		r.builder.WriteString("|--------|------|------|------|--------|--------|\n")
+		for _, m := range diff.Members {
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+				m.Struct, m.Name, apiKindLabel(m.Kind), apiChangeLabel(m.Change), codeCell(m.Old), codeCell(m.New)))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	// 3. 依赖边
+	if len(diff.AddedEdges) > 0 || len(diff.RemovedEdges) > 0 {
+		r.builder.WriteString("### 依赖边\n\n")
+		r.builder.WriteString("| 起点 | 终点 | 依赖类型 | 上下文 | 变化 |\n")
+		r.builder.WriteString("|------|------|----------|--------|------|\n")
+		for _, dep := range diff.AddedEdges {
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s | 新增 |\n", dep.From, dep.To, getDepTypeLabel(dep.Type), dep.Context))
+		}
+		for _, dep := range diff.RemovedEdges {
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s | 删除 |\n", dep.From, dep.To, getDepTypeLabel(dep.Type), dep.Context))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	// 4. 深度
+	if len(diff.DepthChanges) > 0 {
+		r.builder.WriteString("### 深度变化\n\n")
+		r.builder.WriteString("| 结构体 | 旧深度 | 新深度 |\n")
+		r.builder.WriteString("|--------|--------|--------|\n")
+		for _, c := range diff.DepthChanges {
+			r.builder.WriteString(fmt.Sprintf("| %s | %d | %d |\n", c.Struct, c.Old, c.New))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	// 5. 循环依赖
+	if len(diff.NewCycles) > 0 || len(diff.ResolvedCycles) > 0 {
+		r.builder.WriteString("### 循环依赖\n\n")
+		for _, group := range []struct {
+			title  string
+			cycles [][]string
+		}{
+			{"新出现的循环", diff.NewCycles},
+			{"已消除的循环", diff.ResolvedCycles},
+		} {
+			if len(group.cycles) == 0 {
+				continue
+			}
+			r.builder.WriteString(fmt.Sprintf("**%s**:\n\n", group.title))
+			for _, cycle := range group.cycles {
+				r.builder.WriteString(fmt.Sprintf("- %s -> %s\n", strings.Join(cycle, " -> "), cycle[0]))
+			}
+			r.builder.WriteString("\n")
+		}
+	}
+
+	// 6. 度量
+	if len(diff.MetricDeltas) > 0 {
+		r.builder.WriteString("### 度量变化\n\n")
+		r.builder.WriteString("| 范围 | 对象 | 度量 | 旧 | 新 | 变化 |\n")
+		r.builder.WriteString("|------|------|------|----|----|------|\n")
+		for _, d := range diff.MetricDeltas {
+			scope := "结构体"
+			if d.Scope == types.MetricScopePackage {
+				scope = "包"
+			}
+			r.builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+				scope, d.Subject, d.Metric, formatMetric(d.Old), formatMetric(d.New), formatDelta(d.New-d.Old)))
+		}
+		r.builder.WriteString("\n")
+	}
+
+	// 7. 依赖变化图
+	if len(diff.AddedEdges) > 0 || len(diff.RemovedEdges) > 0 {
+		r.builder.WriteString("### 依赖变化图\n\n")
+		r.builder.WriteString("新增的依赖为绿色，删除的依赖为红色虚线。\n\n")
+		r.builder.WriteString("```mermaid\n")
+		r.builder.WriteString(NewMermaidGenerator().GenerateDiff(diff))
+		r.builder.WriteString("```\n")
+	}
+
+	return r.builder.String()
+}
+
+// formatMetric 格式化度量值：整数不带小数
+func formatMetric(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+// formatDelta 格式化带符号的变化量
+func formatDelta(v float64) string {
+	if v > 0 {
+		return "+" + formatMetric(v)
+	}
+	return formatMetric(v)
+}
+
+// GenerateDiff 生成两次分析结果之间依赖变化的 Mermaid 图：只包含有变化的边及其端点，
+// 新增的边和结构体为绿色，删除的边为红色虚线、删除的结构体为红色
+func (m *MermaidGenerator) GenerateDiff(diff *types.ResultDiff) string {
+	m.builder.Reset()
+	m.builder.WriteString("graph TD\n")
+
+	type edge struct {
+		from, to string
+		labels   []string
+		added    bool
+	}
+	var edges []*edge
+	index := make(map[string]*edge)
+	var nodes []string
+	seen := make(map[string]bool)
+	addNode := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			nodes = append(nodes, name)
+		}
+	}
+	collect := func(deps []types.Dependency, added bool) {
+		for _, dep := range deps {
+			key := fmt.Sprintf("%s->%s|%v", dep.From, dep.To, added)
+			e, ok := index[key]
+			if !ok {
+				e = &edge{from: dep.From, to: dep.To, added: added}
+				index[key] = e
+				edges = append(edges, e)
+				addNode(dep.From)
+				addNode(dep.To)
+			}
+			e.labels = append(e.labels, m.getEdgeLabel(dep.Type))
+		}
+	}
+	collect(diff.AddedEdges, true)
+	collect(diff.RemovedEdges, false)
+	for _, s := range diff.AddedStructs {
+		addNode(s.Name)
+	}
+	for _, s := range diff.RemovedStructs {
+		addNode(s.Name)
+	}
+
+	for _, n := range nodes {
+		m.builder.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", sanitizeID(n), n))
+	}
+	m.builder.WriteString("\n")
+
+	for _, e := range edges {
+		arrow := "-->"
+		if !e.added {
+			arrow = "-.->"
+		}
+		m.builder.WriteString(fmt.Sprintf("    %s %s|%s| %s\n", sanitizeID(e.from), arrow, strings.Join(e.labels, "/"), sanitizeID(e.to)))
+	}
+	m.builder.WriteString("\n")
+
+	for i, e := range edges {
+		color := "#2da44e"
+		if !e.added {
+			color = "#cf222e"
+		}
+		m.builder.WriteString(fmt.Sprintf("    linkStyle %d stroke:%s,stroke-width:2px\n", i, color))
+	}
+	for _, s := range diff.AddedStructs {
+		m.builder.WriteString(fmt.Sprintf("    style %s fill:#ccffcc,stroke:#2da44e\n", sanitizeID(s.Name)))
+	}
+	for _, s := range diff.RemovedStructs {
+		m.builder.WriteString(fmt.Sprintf("    style %s fill:#ffcccc,stroke:#cf222e,stroke-dasharray: 5 5\n", sanitizeID(s.Name)))
+	}
+
+	return m.builder.String()
+}
@@ -0,0 +1,69 @@
+package reporter
+
+import (
+	"sort"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// roleFills 内置角色在 Mermaid 图中的填充色
+var roleFills = map[string]string{
+	types.RoleHandler:    "#ff9999",
+	types.RoleService:    "#99ccff",
+	types.RoleRepository: "#99ff99",
+	types.RoleModel:      "#ffcc99",
+	types.RoleDTO:        "#cc99ff",
+}
+
+// customRoleFills 自定义角色在 Mermaid 图中的填充色（按角色名排序后循环使用）
+var customRoleFills = []string{"#ffff99", "#99ffff", "#ff99ff", "#cccccc"}
+
+// roleColors 内置角色在可视化工具中的颜色，自定义角色和未判定角色为 black
+var roleColors = map[string]string{
+	types.RoleHandler:    "red",
+	types.RoleService:    "blue",
+	types.RoleRepository: "green",
+	types.RoleModel:      "orange",
+	types.RoleDTO:        "gray",
+}
+
+// structRoles 返回结构体中出现的角色：内置角色按 handler -> dto 的顺序在前，自定义角色按名称排序在后
+func structRoles(structs []types.StructAnalysis) []string {
+	present := make(map[string]bool)
+	for _, s := range structs {
+		if s.Role != "" {
+			present[s.Role] = true
+		}
+	}
+
+	var roles, custom []string
+	for _, role := range []string{types.RoleHandler, types.RoleService, types.RoleRepository, types.RoleModel, types.RoleDTO} {
+		if present[role] {
+			roles = append(roles, role)
+			delete(present, role)
+		}
+	}
+	for role := range present {
+		custom = append(custom, role)
+	}
+	sort.Strings(custom)
+	return append(roles, custom...)
+}
+
+// roleFill 返回角色在 Mermaid 图中的填充色；roles 为 structRoles 的结果，用于给自定义角色分配颜色
+func roleFill(role string, roles []string) string {
+	if fill, ok := roleFills[role]; ok {
+		return fill
+	}
+	i := 0
+	for _, r := range roles {
+		if _, builtin := roleFills[r]; builtin {
+			continue
+		}
+		if r == role {
+			break
+		}
+		i++
+	}
+	return customRoleFills[i%len(customRoleFills)]
+}
@@ -0,0 +1,129 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// SARIF 规则 ID
+const (
+	SARIFRuleAPIBreaking   = "api-breaking-change"
+	SARIFRuleAPICompatible = "api-compatible-change"
+)
+
+// SARIFReporter 生成 SARIF 2.1.0 格式的报告（可上传到代码扫描平台）
+type SARIFReporter struct{}
+
+// NewSARIFReporter 创建 SARIF 报告生成器
+func NewSARIFReporter() *SARIFReporter {
+	return &SARIFReporter{}
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// GenerateAPIDiff 生成导出 API 差异的 SARIF：不兼容的变化为 error，兼容的变化为 note
+func (r *SARIFReporter) GenerateAPIDiff(result *types.APIDiffResult) (string, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name: "go-struct-analyzer",
+			Rules: []sarifRule{
+				{ID: SARIFRuleAPIBreaking, ShortDescription: sarifMessage{Text: "不兼容的导出 API 变化"}, DefaultConfiguration: sarifConfiguration{Level: "error"}},
+				{ID: SARIFRuleAPICompatible, ShortDescription: sarifMessage{Text: "兼容的导出 API 变化"}, DefaultConfiguration: sarifConfiguration{Level: "note"}},
+			},
+		}},
+		Results: []sarifResult{},
+	}
+
+	for _, c := range result.Changes {
+		res := sarifResult{
+			RuleID:  SARIFRuleAPICompatible,
+			Level:   "note",
+			Message: sarifMessage{Text: fmt.Sprintf("%s.%s: %s", c.Package, c.Name, c.Reason)},
+		}
+		if c.Breaking {
+			res.RuleID, res.Level = SARIFRuleAPIBreaking, "error"
+		}
+		if c.Old != "" || c.New != "" {
+			res.Message.Text += fmt.Sprintf("（%s → %s）", c.Old, c.New)
+		}
+		if c.FilePath != "" {
+			loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(c.FilePath)},
+			}}
+			if c.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: c.Line}
+			}
+			res.Locations = append(res.Locations, loc)
+		}
+		run.Results = append(run.Results, res)
+	}
+
+	data, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
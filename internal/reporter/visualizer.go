@@ -2,6 +2,7 @@ package reporter
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
 
@@ -19,10 +20,13 @@ type VisualizerOutput struct {
 
 // VisualizerStruct 表示单个结构体的可视化数据
 type VisualizerStruct struct {
-	ID       string              `json:"id"`
-	X        float64             `json:"x"`
-	Y        float64             `json:"y"`
-	Metadata StructBoxMetadata   `json:"metadata"`
+	ID       string            `json:"id"`
+	X        float64           `json:"x"`
+	Y        float64           `json:"y"`
+	Width    float64           `json:"width,omitempty"`  // 按 SizeBy 度量缩放后的宽度
+	Height   float64           `json:"height,omitempty"` // 按 SizeBy 度量缩放后的高度
+	Weight   float64           `json:"weight,omitempty"` // SizeBy 度量的原始值
+	Metadata StructBoxMetadata `json:"metadata"`
 }
 
 // StructBoxMetadata 对应前端 StructBoxMetadata 类型
@@ -40,10 +44,11 @@ type StructBoxMetadata struct {
 
 // FieldInfo 对应前端 FieldInfo 类型
 type FieldInfo struct {
-	Name        string `json:"name"`
-	Type        string `json:"type"`
-	Description string `json:"description,omitempty"`
-	Expanded    bool   `json:"expanded"`
+	Name            string            `json:"name"`
+	Type            string            `json:"type"`
+	Description     string            `json:"description,omitempty"`
+	Expanded        bool              `json:"expanded"`
+	SerializedNames map[string]string `json:"serializedNames,omitempty"`
 }
 
 // MethodInfo 对应前端 MethodInfo 类型
@@ -71,6 +76,9 @@ type VisualizerReporter struct {
 	VerticalGap   float64
 	StartX        float64
 	StartY        float64
+
+	// SizeBy 节点大小依据的度量键（见 RankKeys），为空时所有节点大小相同
+	SizeBy string
 }
 
 // NewVisualizerReporter 创建可视化报告生成器
@@ -104,11 +112,30 @@ func (r *VisualizerReporter) Generate(result *types.AnalysisResult) *VisualizerO
 	// 深度对应的颜色
 	depthColors := []string{"red", "blue", "green", "orange", "gray", "black"}
 
+	// 判定出角色时按角色着色（未判定角色为 black），否则按深度着色
+	byRole := len(structRoles(result.Structs)) > 0
+
+	// 节点大小依据的度量
+	weights, maxWeight := r.structWeights(result)
+
 	// 转换结构体
 	for _, s := range result.Structs {
 		id := "struct-" + s.Name
 		pos := positions[s.Name]
 		color := depthColors[s.Depth%len(depthColors)]
+		if byRole {
+			color = "black"
+			if c, ok := roleColors[s.Role]; ok {
+				color = c
+			}
+		}
+		title := s.Package
+		if s.Layer != "" {
+			title = s.Package + " · " + s.Layer
+		}
+		if s.Role != "" {
+			title += " · " + s.Role
+		}
 
 		vs := VisualizerStruct{
 			ID: id,
@@ -118,7 +145,7 @@ func (r *VisualizerReporter) Generate(result *types.AnalysisResult) *VisualizerO
 				Type:             "struct-box",
 				Name:             s.Name,
 				Description:      s.Description,
-				DescriptionTitle: s.Package,
+				DescriptionTitle: title,
 				Fields:           r.convertFields(s.Fields),
 				Methods:          r.convertMethods(s.Methods),
 				CurrentView:      "fields",
@@ -126,9 +153,15 @@ func (r *VisualizerReporter) Generate(result *types.AnalysisResult) *VisualizerO
 				Color:            color,
 			},
 		}
+		if maxWeight > 0 {
+			r.applyWeight(&vs, weights[s.Name], maxWeight)
+		}
 		output.Structs = append(output.Structs, vs)
 	}
 
+	// 第三方模块类型放在最底层
+	output.Structs = append(output.Structs, r.externalStructs(result, depthGroups)...)
+
 	// 生成连接关系（去重）
 	connSet := make(map[string]bool)
 	for _, s := range result.Structs {
@@ -151,6 +184,160 @@ func (r *VisualizerReporter) Generate(result *types.AnalysisResult) *VisualizerO
 	return output
 }
 
+// GeneratePackages 生成包级视图：每个包一个方框，字段列表为包内涉及的类型，连线标注依赖数量
+func (r *VisualizerReporter) GeneratePackages(result *types.AnalysisResult) *VisualizerOutput {
+	graph := result.PackageGraph
+	output := &VisualizerOutput{
+		ProjectPath: result.ProjectPath,
+		StartStruct: result.StartStruct,
+		GeneratedAt: result.GeneratedAt,
+		Structs:     make([]VisualizerStruct, 0, len(graph.Packages)),
+		Connections: make([]VisualizerConnect, 0, len(graph.Edges)),
+	}
+
+	// 按包内结构体的最小深度分层，第三方包放在最底层
+	maxDepth := 0
+	for _, pkg := range graph.Packages {
+		if !pkg.External && pkg.Depth > maxDepth {
+			maxDepth = pkg.Depth
+		}
+	}
+	depthGroups := make(map[int][]string)
+	for _, pkg := range graph.Packages {
+		depth := pkg.Depth
+		if pkg.External {
+			depth = maxDepth + 1
+		}
+		depthGroups[depth] = append(depthGroups[depth], pkg.ImportPath)
+	}
+	positions := r.calculateLayout(depthGroups)
+
+	inCycle := make(map[string]bool)
+	for _, cycle := range graph.Cycles {
+		for _, p := range cycle {
+			inCycle[p] = true
+		}
+	}
+
+	for _, pkg := range graph.Packages {
+		fields := make([]FieldInfo, 0, len(pkg.Structs))
+		for _, name := range pkg.Structs {
+			fields = append(fields, FieldInfo{Name: name, Type: pkg.Name})
+		}
+
+		color := "blue"
+		switch {
+		case pkg.External:
+			color = "gray"
+		case inCycle[pkg.ImportPath]:
+			color = "red"
+		}
+
+		pos := positions[pkg.ImportPath]
+		output.Structs = append(output.Structs, VisualizerStruct{
+			ID: "package-" + pkg.ImportPath,
+			X:  pos.X,
+			Y:  pos.Y,
+			Metadata: StructBoxMetadata{
+				Type:             "struct-box",
+				Name:             pkg.Name,
+				Description:      fmt.Sprintf("%d 个类型", len(pkg.Structs)),
+				DescriptionTitle: pkg.ImportPath,
+				Fields:           fields,
+				Methods:          []MethodInfo{},
+				CurrentView:      "fields",
+				FontSize:         "m",
+				Color:            color,
+			},
+		})
+	}
+
+	for _, edge := range graph.Edges {
+		output.Connections = append(output.Connections, VisualizerConnect{
+			FromID: "package-" + edge.From,
+			ToID:   "package-" + edge.To,
+			Label:  fmt.Sprintf("%d", edge.Weight),
+		})
+	}
+
+	return output
+}
+
+// externalStructs 生成第三方模块类型的可视化节点（排在最深一层之下）
+func (r *VisualizerReporter) externalStructs(result *types.AnalysisResult, depthGroups map[int][]string) []VisualizerStruct {
+	if len(result.ExternalTypes) == 0 {
+		return nil
+	}
+
+	maxDepth := 0
+	for depth := range depthGroups {
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+	}
+	y := r.StartY + float64(maxDepth+1)*(r.BoxHeight+r.VerticalGap)
+
+	var structs []VisualizerStruct
+	for i, ext := range result.ExternalTypes {
+		fields := make([]FieldInfo, 0, len(ext.Fields))
+		for _, f := range ext.Fields {
+			fields = append(fields, FieldInfo{Name: f.Name, Type: f.Type})
+		}
+		structs = append(structs, VisualizerStruct{
+			ID: "struct-" + ext.Name,
+			X:  r.StartX + float64(i)*(r.BoxWidth+r.HorizontalGap),
+			Y:  y,
+			Metadata: StructBoxMetadata{
+				Type:             "struct-box",
+				Name:             ext.Name,
+				Description:      "外部依赖: " + ext.Module + " " + ext.Version,
+				DescriptionTitle: ext.ImportPath,
+				Fields:           fields,
+				Methods:          []MethodInfo{},
+				CurrentView:      "fields",
+				FontSize:         "s",
+				Color:            "gray",
+			},
+		})
+	}
+	return structs
+}
+
+// structWeights 返回每个结构体在 SizeBy 度量上的值及最大值，未设置 SizeBy 时最大值为 0
+func (r *VisualizerReporter) structWeights(result *types.AnalysisResult) (map[string]float64, float64) {
+	if !IsRankKey(r.SizeBy) {
+		return nil, 0
+	}
+	weights := make(map[string]float64, len(result.Metrics.Structs))
+	maxWeight := 0.0
+	for _, m := range result.Metrics.Structs {
+		v := rankValue(m, r.SizeBy)
+		weights[m.Name] = v
+		if v > maxWeight {
+			maxWeight = v
+		}
+	}
+	return weights, maxWeight
+}
+
+// applyWeight 按度量值把节点缩放到默认大小的 0.75～1.25 倍（左上角不变，不会与相邻节点重叠），
+// 并按所在区间设置字号
+func (r *VisualizerReporter) applyWeight(vs *VisualizerStruct, weight, maxWeight float64) {
+	ratio := weight / maxWeight
+	scale := 0.75 + 0.5*ratio
+	vs.Width = r.BoxWidth * scale
+	vs.Height = r.BoxHeight * scale
+	vs.Weight = weight
+	switch {
+	case ratio >= 2.0/3:
+		vs.Metadata.FontSize = "l"
+	case ratio >= 1.0/3:
+		vs.Metadata.FontSize = "m"
+	default:
+		vs.Metadata.FontSize = "s"
+	}
+}
+
 // Position 表示位置
 type Position struct {
 	X float64
@@ -205,10 +392,11 @@ func (r *VisualizerReporter) convertFields(fields []types.FieldAnalysis) []Field
 	result := make([]FieldInfo, 0, len(fields))
 	for _, f := range fields {
 		result = append(result, FieldInfo{
-			Name:        f.Name,
-			Type:        f.Type,
-			Description: f.Description,
-			Expanded:    false,
+			Name:            f.Name,
+			Type:            f.Type,
+			Description:     f.Description,
+			Expanded:        false,
+			SerializedNames: f.SerializedNames,
 		})
 	}
 	return result
@@ -283,6 +471,10 @@ func (r *VisualizerReporter) depTypeToLabel(depType string) string {
 		return "嵌入"
 	case types.DepTypeConstructor:
 		return "构造"
+	case types.DepTypeConstructorParam:
+		return "注入"
+	case types.DepTypeRelation:
+		return "关联"
 	default:
 		return depType
 	}
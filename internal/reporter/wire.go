@@ -0,0 +1,249 @@
+package reporter
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/user/go-struct-analyzer/internal/types"
+)
+
+// WireGenerator 根据构造顺序生成类似 wire.go 的初始化函数
+type WireGenerator struct {
+	builder strings.Builder
+}
+
+// NewWireGenerator 创建初始化函数生成器
+func NewWireGenerator() *WireGenerator {
+	return &WireGenerator{}
+}
+
+// Generate 生成包 pkgName 中的 Initialize<Root> 函数，按构造顺序依次调用构造函数。
+// 无法由已构造结构体提供的参数生成占位变量（带 TODO），没有构造函数的结构体按零值构造
+func (w *WireGenerator) Generate(order *types.InitOrder, pkgName string) (string, error) {
+	var rootStep *types.InitStep
+	for i := range order.Steps {
+		if order.Steps[i].Struct == order.Root {
+			rootStep = &order.Steps[i]
+		}
+	}
+	if rootStep == nil {
+		return "", fmt.Errorf("root %s cannot be constructed (blocked by dependency cycle)", order.Root)
+	}
+
+	// 1. 导入：限定名 -> 导入路径，同名不同路径无法生成
+	imports := make(map[string]string)
+	addImport := func(name, path string) error {
+		if name == "" || path == "" || name == pkgName {
+			return nil
+		}
+		if existing, ok := imports[name]; ok && existing != path {
+			return fmt.Errorf("package name %s refers to both %s and %s", name, existing, path)
+		}
+		imports[name] = path
+		return nil
+	}
+	needsImport := func(step types.InitStep) bool { return step.Package != pkgName }
+	qualify := func(step types.InitStep, name string) string {
+		if !needsImport(step) {
+			return name
+		}
+		return step.Package + "." + name
+	}
+	for _, step := range order.Steps {
+		if needsImport(step) {
+			if err := addImport(step.Package, step.ImportPath); err != nil {
+				return "", err
+			}
+		}
+		for _, param := range step.Params {
+			for name, path := range param.Imports {
+				if err := addImport(name, path); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+
+	// 2. 变量名
+	used := make(map[string]bool)
+	for name := range imports {
+		used[name] = true
+	}
+	used["err"] = true
+	newVar := func(base string) string {
+		name := lowerFirst(base)
+		if name == "" || name == "_" {
+			name = "v"
+		}
+		if token.IsKeyword(name) || used[name] {
+			for i := 2; ; i++ {
+				candidate := fmt.Sprintf("%s%d", name, i)
+				if !used[candidate] {
+					name = candidate
+					break
+				}
+			}
+		}
+		used[name] = true
+		return name
+	}
+	vars := make(map[string]string)
+	pointer := make(map[string]bool)
+	for _, step := range order.Steps {
+		vars[step.Struct] = newVar(step.Struct)
+		pointer[step.Struct] = step.ReturnsPointer
+	}
+
+	// 3. 未解析参数的占位变量
+	type placeholder struct {
+		name, typ, owner string
+	}
+	var placeholders []placeholder
+	args := make(map[string][]string)
+	for _, step := range order.Steps {
+		for _, param := range step.Params {
+			if param.Source != "" {
+				args[step.Struct] = append(args[step.Struct], sourceArg(param, vars[param.Source], pointer[param.Source]))
+				continue
+			}
+			if param.Variadic {
+				continue // 可变参数可以为空
+			}
+			name := newVar(param.Name)
+			placeholders = append(placeholders, placeholder{name: name, typ: localType(param.Type, pkgName), owner: step.Constructor})
+			args[step.Struct] = append(args[step.Struct], name)
+		}
+	}
+
+	// 4. 输出
+	w.builder.Reset()
+	w.builder.WriteString("// 由 go-struct-analyzer init-order 生成的初始化脚手架，请按需修改。\n\n")
+	w.builder.WriteString(fmt.Sprintf("package %s\n\n", pkgName))
+
+	if len(imports) > 0 {
+		names := make([]string, 0, len(imports))
+		for name := range imports {
+			names = append(names, name)
+		}
+		// 标准库在前，项目和第三方包在后
+		projectRoots := make(map[string]bool)
+		for _, step := range order.Steps {
+			projectRoots[strings.Split(step.ImportPath, "/")[0]] = true
+		}
+		isStd := func(path string) bool {
+			first := strings.Split(path, "/")[0]
+			return !strings.Contains(first, ".") && !projectRoots[first]
+		}
+		sort.Slice(names, func(i, j int) bool {
+			a, b := imports[names[i]], imports[names[j]]
+			if isStd(a) != isStd(b) {
+				return isStd(a)
+			}
+			return a < b
+		})
+		w.builder.WriteString("import (\n")
+		for i, name := range names {
+			path := imports[name]
+			if i > 0 && isStd(imports[names[i-1]]) && !isStd(path) {
+				w.builder.WriteString("\n")
+			}
+			if path[strings.LastIndex(path, "/")+1:] == name {
+				w.builder.WriteString(fmt.Sprintf("\t%q\n", path))
+			} else {
+				w.builder.WriteString(fmt.Sprintf("\t%s %q\n", name, path))
+			}
+		}
+		w.builder.WriteString(")\n\n")
+	}
+
+	rootType := qualify(*rootStep, rootStep.Struct)
+	w.builder.WriteString(fmt.Sprintf("// Initialize%s 按依赖顺序构造 %s\n", order.Root, order.Root))
+	w.builder.WriteString(fmt.Sprintf("func Initialize%s() (*%s, error) {\n", order.Root, rootType))
+
+	if len(placeholders) > 0 {
+		w.builder.WriteString("\t// TODO: 以下参数无法由已构造的结构体提供，请手动赋值\n")
+		for _, ph := range placeholders {
+			w.builder.WriteString(fmt.Sprintf("\tvar %s %s // %s\n", ph.name, ph.typ, ph.owner))
+		}
+		w.builder.WriteString("\n")
+	}
+
+	// 被后续步骤使用的变量
+	referenced := map[string]bool{order.Root: true}
+	for _, step := range order.Steps {
+		for _, param := range step.Params {
+			if param.Source != "" {
+				referenced[param.Source] = true
+			}
+		}
+	}
+
+	for _, step := range order.Steps {
+		v := vars[step.Struct]
+		switch {
+		case step.Constructor == "":
+			w.builder.WriteString(fmt.Sprintf("\t// TODO: %s 没有构造函数，按零值构造\n", step.Struct))
+			w.builder.WriteString(fmt.Sprintf("\t%s := &%s{}\n", v, qualify(step, step.Struct)))
+		case step.ReturnsError:
+			w.builder.WriteString(fmt.Sprintf("\t%s, err := %s(%s)\n", v, qualify(step, step.Constructor), strings.Join(args[step.Struct], ", ")))
+			w.builder.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		default:
+			w.builder.WriteString(fmt.Sprintf("\t%s := %s(%s)\n", v, qualify(step, step.Constructor), strings.Join(args[step.Struct], ", ")))
+		}
+		if !referenced[step.Struct] {
+			w.builder.WriteString(fmt.Sprintf("\t_ = %s\n", v))
+		}
+	}
+
+	if rootStep.ReturnsPointer {
+		w.builder.WriteString(fmt.Sprintf("\treturn %s, nil\n}\n", vars[order.Root]))
+	} else {
+		w.builder.WriteString(fmt.Sprintf("\treturn &%s, nil\n}\n", vars[order.Root]))
+	}
+
+	src, err := format.Source([]byte(w.builder.String()))
+	if err != nil {
+		return "", fmt.Errorf("format generated code: %w", err)
+	}
+	return string(src), nil
+}
+
+// sourceArg 根据参数是否为指针调整已构造变量的取址或解引用
+func sourceArg(param types.InitParam, v string, isPointer bool) string {
+	if param.Interface {
+		return v
+	}
+	wantPointer := strings.HasPrefix(param.Type, "*")
+	switch {
+	case wantPointer && !isPointer:
+		return "&" + v
+	case !wantPointer && isPointer:
+		return "*" + v
+	}
+	return v
+}
+
+// localType 去掉生成包自身的限定名
+func localType(typ, pkgName string) string {
+	return regexp.MustCompile(`\b`+regexp.QuoteMeta(pkgName)+`\.`).ReplaceAllString(typ, "")
+}
+
+// lowerFirst 将标识符首字母小写（连续的大写前缀一并小写，如 HTTPClient -> httpClient）
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
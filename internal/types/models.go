@@ -4,10 +4,13 @@ package types
 type StructInfo struct {
 	Name       string       // 结构体名称
 	Package    string       // 所属包名
+	ImportPath string       // 所属包导入路径
 	FilePath   string       // 所在文件路径
 	SourceCode string       // 结构体源代码
 	Fields     []FieldInfo  // 字段列表
 	Methods    []MethodInfo // 方法列表
+
+	Annotations Annotations // 源码注释指令
 }
 
 // FieldInfo 表示字段信息
@@ -17,6 +20,18 @@ type FieldInfo struct {
 	Tag        string // 字段标签
 	IsExported bool   // 是否导出（首字母大写）
 	IsEmbedded bool   // 是否为嵌入字段
+
+	Tags        FieldTags   // 解析后的字段标签
+	Annotations Annotations // 源码注释指令
+}
+
+// FieldTags 表示按 reflect.StructTag 规则解析的字段标签
+type FieldTags struct {
+	Values   map[string]string   // 全部标签：键 -> 值
+	Names    map[string]string   // 序列化名：json、yaml、db、bson -> 名称（标签未写名称时为默认名，"-" 表示不参与序列化），gorm -> column 设置的列名
+	Options  map[string][]string // 序列化选项：json、yaml、db、bson -> 如 omitempty、inline
+	Gorm     map[string]string   // gorm 设置（键为小写，如 column、foreignkey、many2many；没有值的设置如 primarykey 值为空）
+	Validate []string            // validate 规则，如 required、max=50
 }
 
 // MethodInfo 表示方法信息
@@ -30,11 +45,23 @@ type MethodInfo struct {
 
 // InterfaceInfo 表示接口信息
 type InterfaceInfo struct {
-	Name       string              // 接口名称
-	Package    string              // 所属包名
-	FilePath   string              // 所在文件路径
-	Methods    []InterfaceMethod   // 方法列表
-	SourceCode string              // 接口源代码
+	Name       string            // 接口名称
+	Package    string            // 所属包名
+	ImportPath string            // 所属包导入路径
+	FilePath   string            // 所在文件路径
+	Methods    []InterfaceMethod // 方法列表
+	SourceCode string            // 接口源代码
+
+	Annotations Annotations // 源码注释指令
+}
+
+// Annotations 表示类型声明或字段上的 //structanalyzer: 注释指令
+type Annotations struct {
+	Ignore bool   // //structanalyzer:ignore 不参与分析
+	Stop   bool   // //structanalyzer:stop 保留节点但不继续遍历其依赖（仅类型）
+	Layer  string // //structanalyzer:layer=domain 所属架构层（仅类型）
+	Role   string // //structanalyzer:role=service 架构角色，优先于启发式规则（仅类型）
+	Desc   string // //structanalyzer:desc "..." 人工描述，优先于 LLM 生成的描述
 }
 
 // InterfaceMethod 表示接口方法签名
@@ -45,22 +72,95 @@ type InterfaceMethod struct {
 
 // FunctionInfo 表示函数信息（用于构造函数检测）
 type FunctionInfo struct {
-	Name       string // 函数名
-	Package    string // 所属包名
-	FilePath   string // 所在文件路径
-	ReturnType string // 返回类型
-	Signature  string // 完整签名
+	Name         string      // 函数名
+	Package      string      // 所属包名
+	FilePath     string      // 所在文件路径
+	ReturnType   string      // 返回类型（第一个非 error 返回值）
+	Signature    string      // 完整签名
+	TypeParams   string      // 泛型类型参数（如 "[T any]"）
+	Params       []ParamInfo // 参数列表
+	ReturnsError bool        // 是否返回 error
+	Results      int         // 返回值个数
+}
+
+// ParamInfo 表示函数参数信息
+type ParamInfo struct {
+	Name string // 参数名（匿名参数为空）
+	Type string // 参数类型
 }
 
 // StructAnalysis 表示分析后的结构体信息（包含LLM描述）
 type StructAnalysis struct {
 	Name         string           // 结构体名称
 	Package      string           // 所属包名
+	ImportPath   string           // 所属包导入路径
 	Description  string           // 功能简述（Claude 生成）
 	Fields       []FieldAnalysis  // 字段列表
 	Methods      []MethodAnalysis // 方法列表
 	Dependencies []Dependency     // 依赖关系
 	Depth        int              // 在依赖树中的深度
+	Stopped      bool             // 命中 stop 规则，依赖未继续遍历
+	Layer        string           // 架构层（来自源码注释指令）
+	Role         string           // 架构角色（handler、service、repository、model、dto 或自定义角色），无法判定时为空
+	RoleReason   string           // 角色的判定依据
+
+	FieldAccess          FieldAccessMatrix     // 方法 × 字段访问矩阵
+	InterfaceSuggestions []InterfaceSuggestion // 为具体类型字段建议的最小接口
+	Layout               StructLayout          // 内存布局
+}
+
+// StructLayout 表示结构体在指定 GOARCH 下的内存布局
+type StructLayout struct {
+	Arch           string        // GOARCH
+	Size           int64         // 大小（字节）
+	Align          int64         // 对齐
+	Padding        int64         // 填充浪费的字节数
+	Fields         []FieldLayout // 每个字段的偏移和大小（声明顺序）
+	OptimalSize    int64         // 按建议顺序排列字段后的大小
+	SuggestedOrder []string      // 使大小最小的字段顺序，当前顺序已最优时为空
+	Unknown        []string      // 无法确定大小的类型（非空时布局未计算）
+}
+
+// FieldLayout 表示一个字段在结构体中的位置
+type FieldLayout struct {
+	Name    string // 字段名（嵌入字段为类型名）
+	Type    string // 字段类型
+	Offset  int64  // 偏移
+	Size    int64  // 大小
+	Align   int64  // 对齐
+	Padding int64  // 字段之后的填充字节数
+}
+
+// InterfaceSuggestion 表示为类型为具体结构体的字段建议抽取的最小接口，只包含实际调用的方法
+type InterfaceSuggestion struct {
+	Field     string            // 字段名
+	FieldType string            // 字段的结构体类型（带包名，如 repository.UserRepository）
+	Name      string            // 建议的接口名
+	Methods   []InterfaceMethod // 方法中实际调用的方法（签名已改写为使用方包中的写法）
+	Callers   []string          // 调用这些方法的方法
+	Imports   []string          // 接口声明需要的导入路径（已排序）
+	Matching  []string          // 方法集合与建议完全相同的已有接口
+	Covering  []string          // 包含全部调用方法、且被字段类型实现的更大的已有接口
+}
+
+// FieldAccessMatrix 表示结构体的方法对字段的访问情况
+type FieldAccessMatrix struct {
+	Methods  []string      // 方法（按名称排序）
+	Fields   []string      // 字段（声明顺序）
+	Accesses []FieldAccess // 非空的访问记录（按方法、字段顺序）
+
+	UnreadFields      []string // 没有任何方法读取或调用的字段
+	MultiWriterFields []string // 被多个方法写入的字段
+	FieldlessMethods  []string // 不访问任何字段的方法（可考虑改为普通函数）
+}
+
+// FieldAccess 表示一个方法对一个字段的访问
+type FieldAccess struct {
+	Method string // 方法名
+	Field  string // 字段名
+	Read   bool   // 读取字段值
+	Write  bool   // 写入字段（包括经由指针、索引的间接写入）
+	Call   bool   // 在字段值上调用方法
 }
 
 // FieldAnalysis 表示分析后的字段信息
@@ -70,6 +170,8 @@ type FieldAnalysis struct {
 	Description string // 功能简述（Claude 生成）
 	IsExported  bool   // 是否导出
 	IsEmbedded  bool   // 是否为嵌入字段
+
+	SerializedNames map[string]string // 序列化名（json、yaml、db、bson，gorm 为列名），没有相关标签时为空
 }
 
 // MethodAnalysis 表示分析后的方法信息
@@ -83,11 +185,23 @@ type MethodAnalysis struct {
 
 // Dependency 表示依赖关系
 type Dependency struct {
-	From    string // 源结构体
-	To      string // 目标结构体
-	Type    string // 依赖类型："field", "init", "method_call", "interface", "embed"
-	Context string // 上下文（字段名/方法名）
-	Depth   int    // 依赖深度
+	From     string // 源结构体
+	To       string // 目标结构体
+	Type     string // 依赖类型："field", "init", "method_call", "interface", "embed", "relation"
+	Context  string // 上下文（字段名/方法名）
+	Depth    int    // 依赖深度
+	External bool   // 目标是否为第三方模块类型（叶子节点，不再展开）
+}
+
+// ExternalType 表示第三方模块中的类型（作为终端节点出现在报告中）
+type ExternalType struct {
+	Name       string      // 类型名（包名.类型名，如 gorm.DB）
+	ImportPath string      // 导入路径
+	Module     string      // 所属模块路径（来自 go.mod require）
+	Version    string      // 模块版本
+	Fields     []FieldInfo // 字段列表（从 vendor 或模块缓存读取，可能为空）
+	Source     string      // 定义来源："vendor"、"modcache"，未读取时为空
+	UsedBy     []string    // 依赖该类型的项目结构体
 }
 
 // AnalysisResult 表示完整的分析结果
@@ -98,11 +212,126 @@ type AnalysisResult struct {
 	Structs      []StructAnalysis // 分析的结构体列表
 	TotalStructs int              // 总结构体数
 	TotalDeps    int              // 总依赖关系数
-	Cycles       [][]string       // 循环依赖
+	Cycles       [][]string       // 循环依赖（基本环，从字典序最小的节点开始）
 	Blacklist    []string         // 黑名单类型
 	GeneratedAt  string           // 生成时间
+
+	ExternalTypes []ExternalType // 第三方模块类型（仅在启用外部类型模式时填充）
+	Decisions     []TypeDecision // 候选类型的过滤判定（仅在 explain 模式下填充）
+
+	CycleDetails    []CycleDetail // 循环依赖详情，与 Cycles 一一对应
+	Components      [][]string    // 包含环的强连通分量
+	CyclesTruncated bool          // 基本环数量超过上限，Cycles 不完整
+
+	PackageGraph PackageGraph // 包级依赖图
+	Metrics      Metrics      // 耦合与内聚度量
+
+	DeadCode DeadCodeReport    // 从根出发不可达的声明
+	Members  MemberUsageReport // 未被使用的结构体成员
+}
+
+// Metrics 表示结构体和包的耦合、内聚度量
+type Metrics struct {
+	Structs  []StructMetrics  // 每个结构体的度量（按名称排序）
+	Packages []PackageMetrics // 每个项目包的度量（按导入路径排序，不含第三方包）
+}
+
+// StructMetrics 表示单个结构体的度量
+type StructMetrics struct {
+	Name        string  // 结构体名称
+	Package     string  // 所属包名
+	FanIn       int     // 依赖该结构体的已分析结构体数
+	FanOut      int     // 该结构体依赖的不同类型数
+	Fields      int     // 字段数
+	Methods     int     // 方法数（有方法体的）
+	LCOM        float64 // Henderson-Sellers 内聚缺乏度，0 表示完全内聚，越大越松散
+	Reach       int     // 直接或间接依赖该结构体的已分析结构体数（影响范围）
+	PageRank    float64 // 依赖图上的 PageRank，全部结构体之和为 1
+	Betweenness float64 // 归一化的介数中心性，经过该结构体的最短依赖路径比例
+}
+
+// PackageMetrics 表示单个包的度量（Robert C. Martin 包度量）
+type PackageMetrics struct {
+	ImportPath   string  // 导入路径
+	Name         string  // 包名
+	FanIn        int     // 指向该包的跨包结构体依赖数
+	FanOut       int     // 从该包发出的跨包结构体依赖数
+	Ca           int     // 传入耦合：依赖该包的其他包数
+	Ce           int     // 传出耦合：该包依赖的其他包数
+	Instability  float64 // 不稳定性 I = Ce / (Ca + Ce)
+	Abstractness float64 // 抽象度 A = 接口数 / 类型总数
+	Distance     float64 // 与主序列的距离 D = |A + I - 1|
+	Interfaces   int     // 包内接口数
+	Types        int     // 包内类型总数（结构体 + 接口）
+}
+
+// PackageGraph 表示由结构体依赖聚合得到的包级依赖图
+type PackageGraph struct {
+	Packages []PackageNode // 包节点（按导入路径排序）
+	Edges    []PackageEdge // 包之间的依赖（按源、目标排序，不含包内依赖）
+	Cycles   [][]string    // 包级循环依赖（导入路径）
+}
+
+// PackageNode 表示包级依赖图中的一个包
+type PackageNode struct {
+	ImportPath string   // 导入路径
+	Name       string   // 包名
+	Structs    []string // 涉及的结构体和接口（已排序）
+	Depth      int      // 包内结构体的最小深度
+	External   bool     // 是否为第三方模块的包
+}
+
+// PackageEdge 表示两个包之间的依赖
+type PackageEdge struct {
+	From   string       // 源包导入路径
+	To     string       // 目标包导入路径
+	Weight int          // 贡献的结构体依赖数量
+	Deps   []Dependency // 贡献的结构体依赖
+}
+
+// CycleDetail 表示一个基本环及构成它的依赖
+type CycleDetail struct {
+	Nodes     []string    // 环上的结构体，最后一个节点指回第一个节点
+	Edges     []CycleEdge // 环上的每一步
+	EdgeTypes []string    // 环上出现的全部依赖类型（已排序）
+}
+
+// CycleEdge 表示环上的一步及其依据
+type CycleEdge struct {
+	From     string   // 源结构体
+	To       string   // 目标结构体
+	Types    []string // 该步的依赖类型（已排序）
+	Evidence []string // 依据（字段名、方法名等上下文）
 }
 
+// TypeDecision 表示对一个候选依赖类型的过滤判定
+type TypeDecision struct {
+	Type     string // 候选类型（源码中的写法）
+	Accepted bool   // 是否纳入分析
+	Rule     string // 命中的判定规则
+	Detail   string // 补充说明
+	From     string // 引用该类型的结构体
+	DepType  string // 候选依赖类型
+	Context  string // 出现位置（字段、方法等）
+	FilePath string // 所在文件
+}
+
+// 判定规则
+const (
+	DecisionAccepted   = "accepted"           // 项目内部类型，纳入分析
+	DecisionEmpty      = "empty"              // 类型名为空
+	DecisionMap        = "map"                // map 类型
+	DecisionBuiltin    = "builtin"            // 内置类型
+	DecisionStdlib     = "stdlib"             // 标准库类型
+	DecisionBlacklist  = "blacklist"          // 命中黑名单
+	DecisionLowercase  = "lowercase"          // 首字母小写（通常是被误识别的变量名）
+	DecisionNonProject = "non-project-import" // 包别名解析到项目外的导入路径
+	DecisionNotFound   = "not-found"          // 项目中未找到该类型
+	DecisionIgnored    = "ignore-annotation"  // 标注了 //structanalyzer:ignore
+	DecisionExternal   = "external"           // 作为第三方模块叶子节点保留
+	DecisionEdgeRule   = "edge-rule"          // 被按依赖类型生效的黑名单规则忽略
+)
+
 // AnalysisTask 表示分析任务（用于BFS遍历）
 type AnalysisTask struct {
 	StructName string // 结构体名称
@@ -110,11 +339,37 @@ type AnalysisTask struct {
 }
 
 // BlacklistConfig 表示黑名单配置
+// 模式默认为 glob（* 和 ? 不跨越 "/"，以 "/..." 结尾表示包含所有子包），以 "re:" 开头时为正则表达式
 type BlacklistConfig struct {
-	Types    []string `yaml:"types"`    // 忽略的类型列表
-	Packages []string `yaml:"packages"` // 忽略的包列表
+	Types    []string        `yaml:"types"`    // 忽略的类型列表
+	Packages []string        `yaml:"packages"` // 忽略的包列表
+	Patterns []string        `yaml:"patterns"` // 忽略的类型名模式（匹配短名或 包名.类型名）
+	Paths    []string        `yaml:"paths"`    // 忽略的导入路径模式（匹配完整导入路径）
+	Allow    BlacklistAllow  `yaml:"allow"`    // 白名单，命中时覆盖上述阻止规则
+	Rules    []BlacklistRule `yaml:"rules"`    // 按依赖类型生效的规则
 }
 
+// BlacklistAllow 表示白名单配置
+type BlacklistAllow struct {
+	Types    []string `yaml:"types"`    // 允许的类型列表
+	Patterns []string `yaml:"patterns"` // 允许的类型名模式
+	Paths    []string `yaml:"paths"`    // 允许的导入路径模式
+}
+
+// BlacklistRule 表示一条作用于依赖边的黑名单规则
+type BlacklistRule struct {
+	Type     string   `yaml:"type"`      // 目标类型名模式（为空表示不限）
+	Path     string   `yaml:"path"`      // 目标导入路径模式（为空表示不限）
+	DepTypes []string `yaml:"dep_types"` // 生效的依赖类型（为空表示全部），仅对 block 生效
+	Action   string   `yaml:"action"`    // 动作："block"（默认，忽略该边）或 "stop"（保留节点但不继续遍历）
+}
+
+// BlacklistAction 黑名单规则动作
+const (
+	BlacklistActionBlock = "block" // 忽略匹配的依赖边
+	BlacklistActionStop  = "stop"  // 保留节点，但不再遍历其依赖
+)
+
 // LLMAnalysisResult 表示 LLM 分析结果
 type LLMAnalysisResult struct {
 	StructDescription string `json:"struct_description"`
@@ -130,10 +385,477 @@ type LLMAnalysisResult struct {
 
 // DependencyType 定义依赖类型常量
 const (
-	DepTypeField       = "field"       // 字段依赖
-	DepTypeInit        = "init"        // 方法内初始化
-	DepTypeMethodCall  = "method_call" // 方法调用
-	DepTypeInterface   = "interface"   // 接口实现
-	DepTypeEmbed       = "embed"       // 结构体嵌入
-	DepTypeConstructor = "constructor" // 构造函数调用
+	DepTypeField            = "field"             // 字段依赖
+	DepTypeInit             = "init"              // 方法内初始化
+	DepTypeMethodCall       = "method_call"       // 方法调用
+	DepTypeInterface        = "interface"         // 接口实现
+	DepTypeEmbed            = "embed"             // 结构体嵌入
+	DepTypeConstructor      = "constructor"       // 构造函数调用
+	DepTypeConstructorParam = "constructor_param" // 构造函数参数（依赖注入）
+	DepTypeRelation         = "relation"          // ORM 关联（gorm foreignKey、many2many 标签）
+)
+
+// ArchitectureConfig 表示架构分层规则
+// 层按 Order（或 Layers 的声明顺序）从上到下排列，上层可以依赖下层，反之为违规
+type ArchitectureConfig struct {
+	Layers   []LayerConfig `yaml:"layers"`   // 分层定义
+	Order    string        `yaml:"order"`    // 分层顺序，如 "handler -> service -> repository -> model"（为空时按 Layers 声明顺序）
+	Strict   bool          `yaml:"strict"`   // 严格分层：只允许依赖紧邻的下一层
+	Allow    []LayerRule   `yaml:"allow"`    // 额外允许的依赖（覆盖分层顺序）
+	Forbid   []LayerRule   `yaml:"forbid"`   // 禁止的依赖（优先于分层顺序和 Allow）
+	Baseline string        `yaml:"baseline"` // 基线文件路径，其中记录的已知违规不再报告
+}
+
+// LayerConfig 表示一个架构层
+// 类型所属层的判定顺序：源码注释 //structanalyzer:layer=... > Structs 模式 > Roles > Packages 模式
+type LayerConfig struct {
+	Name     string   `yaml:"name"`     // 层名称
+	Packages []string `yaml:"packages"` // 导入路径模式（glob 或 re: 正则，与黑名单语法相同）
+	Structs  []string `yaml:"structs"`  // 类型名模式（匹配短名或 包名.类型名）
+	Roles    []string `yaml:"roles"`    // 结构体架构角色（见 RoleConfig）
+}
+
+// LayerRule 表示层之间的允许或禁止规则
+type LayerRule struct {
+	From     string   `yaml:"from"`      // 源层，"*" 表示任意层
+	To       string   `yaml:"to"`        // 目标层，"*" 表示任意层
+	DepTypes []string `yaml:"dep_types"` // 只对这些依赖类型生效（为空表示全部）
+	Reason   string   `yaml:"reason"`    // 说明（出现在违规报告中）
+}
+
+// LayerViolation 表示一条违反分层规则的依赖
+type LayerViolation struct {
+	From      string // 源结构体
+	To        string // 目标类型
+	FromLayer string // 源结构体所属层
+	ToLayer   string // 目标类型所属层
+	DepType   string // 依赖类型
+	Context   string // 依据（字段名、方法名等）
+	Rule      string // 违反的规则：upward、skip-layer、forbid
+	Reason    string // 说明
+	FilePath  string // 源结构体所在文件（相对项目根目录，基线按它比对）
+}
+
+// 分层违规规则
+const (
+	LayerRuleUpward    = "upward"     // 依赖上层
+	LayerRuleSkipLayer = "skip-layer" // 严格分层下跨层依赖
+	LayerRuleForbid    = "forbid"     // 命中禁止规则
+)
+
+// DependencyPath 表示两个结构体之间的一条依赖路径
+type DependencyPath struct {
+	Nodes []string  // 路径上的结构体（含起点和终点）
+	Hops  []PathHop // 每一跳，len(Hops) == len(Nodes)-1
+}
+
+// PathHop 表示依赖路径上的一跳及其依据
+type PathHop struct {
+	From     string   // 源结构体
+	To       string   // 目标结构体
+	Types    []string // 该跳的依赖类型
+	Evidence []string // 依据（字段名、方法名等上下文）
+}
+
+// PathResult 表示两个结构体之间的路径查询结果
+type PathResult struct {
+	From      string           // 起点
+	To        string           // 终点
+	DepTypes  []string         // 参与的依赖类型（为空表示全部）
+	Paths     []DependencyPath // 路径（第一条为最短路径），不可达时为空
+	Truncated bool             // 路径数量达到上限，Paths 不完整
+}
+
+// FileChange 表示一个改动的文件及其改动的行
+type FileChange struct {
+	Path  string      // 文件路径
+	Lines []LineRange // 改动的行（改动后文件中的行号），为空表示整个文件都视为改动
+}
+
+// LineRange 表示闭区间行号范围
+type LineRange struct {
+	Start int // 起始行
+	End   int // 结束行
+}
+
+// ChangedDecl 表示与改动行重叠的声明
+type ChangedDecl struct {
+	Kind     string // 声明种类：struct、interface、method、constructor
+	Name     string // 声明名（方法为 类型名.方法名）
+	Type     string // 受影响的类型（结构体、接口本身，方法的接收者，构造函数的返回类型）
+	FilePath string // 所在文件
+	Line     int    // 声明起始行
+}
+
+// 改动声明的种类
+const (
+	ChangedKindStruct      = "struct"
+	ChangedKindInterface   = "interface"
+	ChangedKindMethod      = "method"
+	ChangedKindConstructor = "constructor"
 )
+
+// ImpactedType 表示受改动影响的类型
+type ImpactedType struct {
+	Name     string   // 类型名
+	Package  string   // 所属包名
+	FilePath string   // 定义所在文件
+	Distance int      // 与改动类型之间的反向依赖距离，0 表示自身被改动
+	Via      string   // 经由的依赖（更靠近改动的一跳），Distance 为 0 时为空
+	Types    []string // 与 Via 之间的依赖类型
+	Evidence []string // 与 Via 之间的依据（字段名、方法名等上下文）
+}
+
+// ImpactResult 表示改动影响分析的结果
+type ImpactResult struct {
+	Base     string         // 对比的 git 基准（按文件列表分析时为空）
+	Files    []string       // 改动的 Go 文件（相对项目根目录）
+	Changed  []ChangedDecl  // 与改动行重叠的声明
+	Impacted []ImpactedType // 受影响的类型（按距离、名称排序）
+	MaxDepth int            // 反向依赖的最大深度（<= 0 表示不限制）
+}
+
+// 内置的结构体架构角色
+const (
+	RoleHandler    = "handler"    // 处理请求的入口（HTTP/RPC handler、controller）
+	RoleService    = "service"    // 业务逻辑
+	RoleRepository = "repository" // 数据访问
+	RoleModel      = "model"      // 领域模型、持久化实体
+	RoleDTO        = "dto"        // 请求、响应等数据传输对象
+)
+
+// RoleConfig 表示结构体架构角色的分类规则
+// 角色的判定顺序：源码注释 //structanalyzer:role=... > 启发式规则（得分最高者）> LLM（开启 LLM 时）
+type RoleConfig struct {
+	Rules      []RoleRule `yaml:"rules"`       // 自定义规则，与内置规则一起打分，同分时优先于内置规则
+	NoDefaults bool       `yaml:"no_defaults"` // 不使用内置规则
+	MinScore   int        `yaml:"min_score"`   // 判定角色所需的最低得分（<= 0 时为 2）
+	LLM        bool       `yaml:"llm"`         // 启发式规则无法判定的结构体交给 LLM 判定（需配置 API Key）
+}
+
+// RoleRule 表示一个角色的判定线索
+// 每类线索至少命中一个模式即加分：名称、实现的接口、方法、字段类型 3 分，包路径、字段标签 2 分
+type RoleRule struct {
+	Role       string   `yaml:"role"`        // 角色名称（内置角色或自定义角色）
+	Names      []string `yaml:"names"`       // 类型名模式，如 "*Handler"
+	Packages   []string `yaml:"packages"`    // 导入路径模式，如 "re:(^|/)handlers?$"
+	Implements []string `yaml:"implements"`  // 实现的项目接口名模式，如 "*Repository"
+	Methods    []string `yaml:"methods"`     // 方法名模式，如 "ServeHTTP"
+	FieldTypes []string `yaml:"field_types"` // 字段类型模式（去掉指针和切片前缀后匹配），如 "sql.DB"
+	Tags       []string `yaml:"tags"`        // 字段标签键，如 gorm、json
+}
+
+// ReachabilityConfig 表示死代码检测配置
+type ReachabilityConfig struct {
+	Roots  []string `yaml:"roots"`  // 根：main（main 包）、exported（库包的导出 API），或类型名模式；为空时为 main 和 exported
+	Check  bool     `yaml:"check"`  // check 子命令是否把不可达的结构体、接口和未使用的构造函数视为违规
+	Report bool     `yaml:"report"` // 分析报告是否包含死代码检测（设置了 roots 时总是包含）
+}
+
+// 内置的根
+const (
+	RootMain     = "main"     // main 包中的全部函数和类型
+	RootExported = "exported" // 库包（非 main、不在 internal 下）的导出类型、函数和方法
+)
+
+// DeadCodeReport 表示从根出发不可达的声明
+type DeadCodeReport struct {
+	Roots                   []string        // 使用的根
+	UnreachableStructs      []DeadDecl      // 不可达的结构体
+	UnreachableInterfaces   []DeadDecl      // 不可达的接口
+	UnusedConstructors      []DeadDecl      // 不可达代码之外没有被调用的构造函数
+	UnimplementedInterfaces []DeadDecl      // 没有实现的接口
+	SingleImplInterfaces    []InterfaceImpl // 只有一个实现的接口
+}
+
+// DeadDecl 表示一个不可达或无用的声明
+type DeadDecl struct {
+	Name     string // 类型名或函数名
+	Package  string // 所属包名
+	FilePath string // 所在文件
+}
+
+// InterfaceImpl 表示接口与其唯一实现
+type InterfaceImpl struct {
+	Interface   string // 接口名
+	Package     string // 接口所属包名
+	Implementer string // 唯一实现的结构体
+	FilePath    string // 接口所在文件
+}
+
+// 死代码规则（check 子命令中与分层违规一起报告）
+const (
+	DeadCodeRuleStruct      = "unreachable-struct"
+	DeadCodeRuleInterface   = "unreachable-interface"
+	DeadCodeRuleConstructor = "unused-constructor"
+)
+
+// InitOrder 表示从根结构体出发的构造顺序
+type InitOrder struct {
+	Root       string             // 根结构体
+	Steps      []InitStep         // 构造步骤（叶子在前，依赖总是先于使用者构造）
+	Interfaces []InterfaceBinding // 依赖中出现的接口及选用的实现
+	Cycles     []CycleDetail      // 使构造无法进行的依赖环
+	Blocked    []string           // 位于依赖环上或依赖环上结构体的结构体（无法构造）
+}
+
+// InitStep 表示构造顺序中的一步
+type InitStep struct {
+	Struct         string      // 结构体名
+	Package        string      // 所属包名
+	ImportPath     string      // 所属包导入路径
+	Constructor    string      // 使用的构造函数名（为空表示没有构造函数）
+	ReturnsPointer bool        // 构造函数返回指针（没有构造函数时按 &T{} 构造，视为指针）
+	ReturnsError   bool        // 构造函数返回 error
+	Params         []InitParam // 构造函数参数
+	Deps           []string    // 需要先构造的结构体
+}
+
+// InitParam 表示构造函数的一个参数
+type InitParam struct {
+	Name      string            // 参数名（匿名参数为空）
+	Type      string            // 参数类型，包外可用的写法（如 *repository.UserRepo）
+	Imports   map[string]string // 参数类型引用的包：限定名 -> 导入路径
+	Source    string            // 提供该参数的已构造结构体，为空表示未解析（需要占位）
+	Interface bool              // 参数类型是否为项目中的接口（由其实现提供）
+	Variadic  bool              // 是否为可变参数
+}
+
+// InterfaceBinding 表示接口与选用的实现
+type InterfaceBinding struct {
+	Interface   string   // 接口名
+	Implementer string   // 唯一的实现，为空表示没有实现或有多个实现（需要手动选择）
+	Candidates  []string // 全部实现
+}
+
+// MemberConfig 表示结构体成员使用检查配置
+type MemberConfig struct {
+	Check  bool `yaml:"check"`  // check 子命令是否把未被其他包使用的导出成员和未被使用的未导出成员视为违规
+	Report bool `yaml:"report"` // 分析报告是否包含被分析结构体的未使用成员
+}
+
+// MemberUsageReport 表示结构体字段和方法的使用情况
+type MemberUsageReport struct {
+	UnexportCandidates []UnusedMember // 没有被其他包使用的导出成员（可改为未导出）
+	Unused             []UnusedMember // 完全没有被使用的未导出成员
+}
+
+// UnusedMember 表示一个结构体成员
+type UnusedMember struct {
+	Struct   string // 结构体名
+	Package  string // 所属包名
+	Name     string // 成员名
+	Kind     string // 成员类别：field、method
+	FilePath string // 结构体所在文件
+}
+
+// 成员类别
+const (
+	MemberKindField  = "field"
+	MemberKindMethod = "method"
+)
+
+// 成员检查规则（check 子命令）
+const (
+	MemberRuleUnexport = "unexport-member"
+	MemberRuleUnused   = "unused-member"
+)
+
+// API 声明种类（apidiff 子命令）
+const (
+	APIKindStruct    = "struct"
+	APIKindInterface = "interface"
+	APIKindType      = "type"     // 其他具名类型和类型别名
+	APIKindFunction  = "function" // 包级函数
+	APIKindField     = "field"
+	APIKindEmbedded  = "embedded" // 结构体或接口中的嵌入类型
+	APIKindMethod    = "method"
+)
+
+// API 变化种类
+const (
+	APIChangeAdded   = "added"
+	APIChangeRemoved = "removed"
+	APIChangeChanged = "changed"
+)
+
+// APIChange 表示导出 API 的一处变化
+type APIChange struct {
+	Package  string // 所属包导入路径
+	Name     string // 类型名、函数名或 类型名.成员名
+	Kind     string // 声明种类：struct、interface、type、function、field、embedded、method
+	Change   string // 变化：added、removed、changed
+	Breaking bool   // 是否为不兼容变化
+	Old      string // 变化前的类型或签名
+	New      string // 变化后的类型或签名
+	Reason   string // 分类依据
+	FilePath string // 所在文件（相对源码树根目录；删除时为旧版本中的位置，从 JSON 加载时为空）
+	Line     int    // 所在行
+}
+
+// APIDiffResult 表示两个版本之间导出 API 的差异
+type APIDiffResult struct {
+	Old        string      // 旧版本（目录、git 修订或 JSON 文件）
+	New        string      // 新版本
+	Changes    []APIChange // 全部变化（按包、名称排序）
+	Breaking   int         // 不兼容变化数
+	Compatible int         // 兼容变化数
+}
+
+// ResultDiff 表示两次分析结果之间的差异（用于跟踪架构漂移）
+type ResultDiff struct {
+	Old        string   // 旧结果（文件路径或生成时间）
+	New        string   // 新结果
+	Warnings   []string // 两次分析的起点或深度不同等提示
+	OldStructs int      // 旧结果的结构体数
+	NewStructs int      // 新结果的结构体数
+	OldDeps    int      // 旧结果的依赖关系数
+	NewDeps    int      // 新结果的依赖关系数
+
+	AddedStructs   []StructRef    // 新增的结构体
+	RemovedStructs []StructRef    // 删除的结构体
+	Members        []MemberChange // 两次都存在的结构体中新增、删除或修改的字段和方法
+	AddedEdges     []Dependency   // 新增的依赖边（同一起点、终点和依赖类型只保留一条）
+	RemovedEdges   []Dependency   // 删除的依赖边
+	DepthChanges   []DepthChange  // 深度变化
+	NewCycles      [][]string     // 新出现的循环依赖
+	ResolvedCycles [][]string     // 已消除的循环依赖
+	MetricDeltas   []MetricDelta  // 两次都存在的结构体和包的度量变化
+}
+
+// StructRef 表示一个结构体
+type StructRef struct {
+	Name    string // 结构体名称
+	Package string // 所属包名
+	Depth   int    // 在依赖树中的深度
+}
+
+// MemberChange 表示结构体字段或方法的变化
+type MemberChange struct {
+	Struct string // 结构体名称
+	Name   string // 成员名
+	Kind   string // 成员种类：field、method（同 APIKindField、APIKindMethod）
+	Change string // 变化：added、removed、changed（同 APIChange*）
+	Old    string // 变化前的类型或签名
+	New    string // 变化后的类型或签名
+}
+
+// DepthChange 表示结构体深度的变化
+type DepthChange struct {
+	Struct string // 结构体名称
+	Old    int    // 旧深度
+	New    int    // 新深度
+}
+
+// MetricDelta 表示一个度量的变化
+type MetricDelta struct {
+	Scope   string  // 范围：struct、package
+	Subject string  // 结构体名称或包导入路径
+	Metric  string  // 度量名：fan-in、fan-out、fields、methods、lcom、reach、pagerank、betweenness、ca、ce、instability、abstractness、distance
+	Old     float64 // 旧值
+	New     float64 // 新值
+}
+
+// 度量变化的范围
+const (
+	MetricScopeStruct  = "struct"
+	MetricScopePackage = "package"
+)
+
+// ClusterResult 表示结构体依赖图的社区划分，以及与实际包布局的对比（模块边界建议）
+type ClusterResult struct {
+	DepTypes          []string          // 参与的依赖类型（为空表示全部）
+	Modularity        float64           // 检测到的划分的模块度
+	PackageModularity float64           // 按实际包划分的模块度
+	Clusters          []Cluster         // 社区，按大小降序
+	Misplaced         []MisplacedStruct // 位于一个包、却与另一个包聚在一起的结构体
+	SplitPackages     []PackageSplit    // 分散在多个社区中的包（建议拆分）
+	Isolated          []string          // 与其他项目结构体没有依赖的结构体（不参与聚类）
+	Edges             []ClusterEdge     // 结构体之间的无向加权边
+}
+
+// Cluster 表示一个社区
+type Cluster struct {
+	ID       int              // 社区编号（从 1 开始）
+	Package  string           // 成员最多的包（导入路径）
+	Structs  []string         // 成员结构体（已排序）
+	Packages []ClusterPackage // 成员按包分组，按成员数降序
+	Internal int              // 社区内部的依赖数
+	External int              // 与其他社区之间的依赖数
+}
+
+// ClusterPackage 表示社区中属于同一个包的成员
+type ClusterPackage struct {
+	ImportPath string   // 包导入路径
+	Structs    []string // 成员结构体
+}
+
+// MisplacedStruct 表示与其他包的结构体聚在一起的结构体
+type MisplacedStruct struct {
+	Struct       string // 结构体名称
+	Package      string // 当前所在的包（导入路径）
+	Cluster      int    // 所在社区
+	Suggested    string // 建议移入的包（社区中成员最多的包）
+	PackageLinks int    // 与当前包中其他结构体之间的依赖数
+	ClusterLinks int    // 与社区中其他结构体之间的依赖数
+}
+
+// PackageSplit 表示分散在多个社区中的包
+type PackageSplit struct {
+	Package string        // 包导入路径
+	Parts   []PackagePart // 每个社区中的成员，按成员数降序
+}
+
+// PackagePart 表示包在一个社区中的成员
+type PackagePart struct {
+	Cluster int      // 社区编号
+	Structs []string // 成员结构体
+}
+
+// ClusterEdge 表示两个结构体之间的无向加权边
+type ClusterEdge struct {
+	From   string // 结构体（名称较小的一端）
+	To     string // 结构体
+	Weight int    // 两个方向上的依赖总数
+}
+
+// ORM 关联的类型（gorm 约定）
+const (
+	RelationBelongsTo  = "belongs-to" // 外键在本实体上
+	RelationHasOne     = "has-one"    // 外键在关联实体上，单个
+	RelationHasMany    = "has-many"   // 外键在关联实体上，切片
+	RelationManyToMany = "many2many"  // 通过连接表
+)
+
+// ERModel 表示从一个实体出发、沿关联字段可达的持久化模型（按 gorm 约定推断）
+type ERModel struct {
+	Root      string       // 起点实体
+	Entities  []EREntity   // 实体（按从起点出发的广度优先顺序）
+	Relations []ERRelation // 实体之间的关联
+}
+
+// EREntity 表示一个实体（表）
+type EREntity struct {
+	Name       string        // 结构体名
+	ImportPath string        // 所属包导入路径
+	Attributes []ERAttribute // 列（嵌入结构体的字段已展开）
+}
+
+// ERAttribute 表示实体的一列
+type ERAttribute struct {
+	Field  string   // 字段名（嵌入结构体的字段为其自身的字段名）
+	Column string   // 列名（gorm column 设置，否则为字段名的 snake_case）
+	Type   string   // 字段类型
+	Keys   []string // PK、FK、UK
+	JSON   string   // json 序列化名（没有 json 标签时为空）
+}
+
+// ERRelation 表示两个实体之间的关联
+type ERRelation struct {
+	From       string // 声明关联字段的实体
+	To         string // 关联的实体
+	Kind       string // 关联类型（见 Relation* 常量）
+	Field      string // 关联字段
+	ForeignKey string // 外键字段（many2many 时为空）
+	JoinTable  string // 连接表（仅 many2many）
+}
@@ -32,6 +32,7 @@ import (
 	"path/filepath"
 
 	internalAnalyzer "github.com/user/go-struct-analyzer/internal/analyzer"
+	"github.com/user/go-struct-analyzer/internal/config"
 	"github.com/user/go-struct-analyzer/internal/llm"
 	"github.com/user/go-struct-analyzer/internal/parser"
 	"github.com/user/go-struct-analyzer/internal/reporter"
@@ -70,8 +71,103 @@ type Options struct {
 	// EnableCache 是否启用 LLM 缓存，默认 true
 	EnableCache bool
 
+	// IncludeExternal 是否将第三方模块类型保留为叶子节点（可选）
+	IncludeExternal bool
+
+	// ExternalVendor 是否从 vendor/ 目录读取第三方结构体定义（可选）
+	ExternalVendor bool
+
+	// GoModCache 本地 GOMODCACHE 目录，用于读取第三方结构体定义（可选）
+	GoModCache string
+
 	// Verbose 详细输出模式
 	Verbose bool
+
+	// Explain 是否在结果中记录每个候选类型的过滤判定（可选）
+	Explain bool
+
+	// CycleDepTypes 只检测完全由这些依赖类型构成的循环（可选，默认全部类型）
+	CycleDepTypes []string
+
+	// CycleLimit 最多枚举的循环数量（可选，默认 1000）
+	CycleLimit int
+
+	// MetricsSort Markdown 报告中度量表格的排序键（可选，默认 "fan-in"）
+	MetricsSort string
+
+	// RankBy 关键结构体排行和可视化节点大小依据的度量："reach"、"pagerank"、"betweenness" 或 "fan-in"（可选，默认 "reach"）
+	RankBy string
+
+	// ProjectCentrality 在全项目依赖图上计算中心性度量（可选，默认 false，只使用遍历到的子图）
+	ProjectCentrality bool
+
+	// DeadCode 在结果中附带死代码检测（可选，默认 false；设置了 Roots 时总是附带）
+	DeadCode bool
+
+	// Roots 死代码检测的根："main"、"exported" 或类型名模式（可选，默认 main 和 exported）
+	Roots []string
+
+	// Members 在结果中附带被分析结构体的未使用成员（可选，默认 false）
+	Members bool
+
+	// Layout 计算结构体内存布局（可选，默认 false）
+	Layout bool
+
+	// Arch 计算结构体内存布局使用的 GOARCH（可选，默认 "amd64"）
+	Arch string
+
+	// filters 配置文件中的内联过滤规则（由 LoadOptions 设置）
+	filters types.BlacklistConfig
+
+	// roles 配置文件中的结构体角色规则（由 LoadOptions 设置）
+	roles types.RoleConfig
+}
+
+// LoadOptions 从项目配置文件 .struct-analyzer.yaml 加载选项
+// 配置文件从 projectPath 开始逐级向上查找，profile 为空表示不使用命名 profile；
+// 未找到配置文件时返回默认选项。返回的选项可以继续修改后传给 New
+func LoadOptions(projectPath, profile string) (Options, error) {
+	cfg := config.Default()
+	if path := config.Find(projectPath); path != "" {
+		var err error
+		if cfg, err = config.Load(path, profile); err != nil {
+			return Options{}, err
+		}
+	} else if profile != "" {
+		return Options{}, fmt.Errorf("config file %s not found, cannot use profile %q", config.FileName, profile)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Options{}, err
+	}
+
+	return Options{
+		ProjectPath:       projectPath,
+		StartStruct:       cfg.Start,
+		MaxDepth:          cfg.Depth,
+		BlacklistFile:     cfg.Blacklist,
+		LLMProvider:       cfg.LLM.Provider,
+		LLMModel:          cfg.LLM.Model,
+		APIKey:            cfg.APIKey(),
+		EnableCache:       cfg.LLM.Cache,
+		IncludeExternal:   cfg.External.Enabled,
+		ExternalVendor:    cfg.External.Vendor,
+		GoModCache:        cfg.External.GoModCache,
+		Verbose:           cfg.Verbose,
+		Explain:           cfg.Explain,
+		CycleDepTypes:     cfg.Cycles.DepTypes,
+		CycleLimit:        cfg.Cycles.Limit,
+		MetricsSort:       cfg.MetricsSort,
+		RankBy:            cfg.RankBy,
+		ProjectCentrality: cfg.ProjectCentrality,
+		DeadCode:          cfg.Reachability.Report,
+		Roots:             cfg.Reachability.Roots,
+		Members:           cfg.Members.Report,
+		Layout:            cfg.Layout,
+		Arch:              cfg.Arch,
+		filters:           cfg.Filters,
+		roles:             cfg.Roles,
+	}, nil
 }
 
 // Analyzer 结构体依赖分析器
@@ -81,6 +177,7 @@ type Analyzer struct {
 	traverser  *internalAnalyzer.Traverser
 	blacklist  *internalAnalyzer.Blacklist
 	cache      *internalAnalyzer.AnalysisCache
+	filter     *internalAnalyzer.ScopeFilter
 	llmClient  llm.LLMClient
 	lastResult *Result
 }
@@ -91,6 +188,9 @@ func New(opts Options) (*Analyzer, error) {
 	if opts.MaxDepth <= 0 {
 		opts.MaxDepth = 2
 	}
+	if opts.RankBy == "" {
+		opts.RankBy = reporter.SortByReach
+	}
 
 	// 验证必需参数
 	if opts.ProjectPath == "" {
@@ -144,6 +244,9 @@ func (a *Analyzer) Analyze() (*Result, error) {
 	for _, p := range a.opts.BlacklistPackages {
 		a.blacklist.AddPackage(p)
 	}
+	if err := a.blacklist.ApplyConfig(a.opts.filters); err != nil {
+		return nil, fmt.Errorf("invalid filters: %w", err)
+	}
 
 	// 3. 创建 LLM 客户端（可选）
 	if a.opts.APIKey != "" && a.opts.LLMProvider != "" {
@@ -152,7 +255,26 @@ func (a *Analyzer) Analyze() (*Result, error) {
 
 	// 4. 创建过滤器和遍历器
 	filter := internalAnalyzer.NewScopeFilter(a.parser, a.blacklist)
+	filter.SetExternalOptions(internalAnalyzer.ExternalOptions{
+		Enabled:   a.opts.IncludeExternal,
+		UseVendor: a.opts.ExternalVendor,
+		ModCache:  a.opts.GoModCache,
+	})
+	a.filter = filter
 	a.traverser = internalAnalyzer.NewTraverser(a.parser, filter, a.llmClient, a.opts.Verbose)
+	a.traverser.SetExplain(a.opts.Explain)
+	a.traverser.SetCycleOptions(a.opts.CycleDepTypes, a.opts.CycleLimit)
+	a.traverser.SetDeadCode(a.opts.DeadCode)
+	a.traverser.SetRoots(a.opts.Roots)
+	a.traverser.SetMembers(a.opts.Members)
+	a.traverser.SetLayout(a.opts.Layout)
+	a.traverser.SetProjectCentrality(a.opts.ProjectCentrality)
+	a.traverser.SetArch(a.opts.Arch)
+	roles, err := internalAnalyzer.NewRoleClassifier(a.parser, a.opts.roles)
+	if err != nil {
+		return nil, fmt.Errorf("invalid roles: %w", err)
+	}
+	a.traverser.SetRoles(roles, a.opts.roles.LLM)
 
 	// 5. 创建缓存（如果启用）
 	if a.opts.EnableCache && a.llmClient != nil && a.llmClient.IsConfigured() {
@@ -186,7 +308,9 @@ func (a *Analyzer) GenerateMarkdown() (string, error) {
 	}
 
 	mdReporter := reporter.NewMarkdownReporter()
-	content := mdReporter.Generate(a.lastResult.raw, a.blacklist.GetBlockedTypes())
+	mdReporter.SetMetricsSort(a.opts.MetricsSort)
+	mdReporter.SetRankBy(a.opts.RankBy)
+	content := mdReporter.Generate(a.lastResult.raw, append(a.blacklist.GetBlockedTypes(), a.blacklist.GetBlockedPatterns()...))
 	return content, nil
 }
 
@@ -217,10 +341,67 @@ func (a *Analyzer) GenerateVisualizerJSON() (string, error) {
 	}
 
 	vizReporter := reporter.NewVisualizerReporter()
+	vizReporter.SizeBy = a.opts.RankBy
 	vizOutput := vizReporter.Generate(a.lastResult.raw)
 	return vizReporter.ToJSON(vizOutput)
 }
 
+// GeneratePackageMermaid 生成包级依赖 Mermaid 图
+func (a *Analyzer) GeneratePackageMermaid() (string, error) {
+	if a.lastResult == nil {
+		return "", fmt.Errorf("no analysis result, call Analyze() first")
+	}
+
+	mermaidGen := reporter.NewMermaidGenerator()
+	return mermaidGen.GeneratePackages(a.lastResult.raw), nil
+}
+
+// GeneratePackageVisualizerJSON 生成包级依赖可视化工具 JSON 字符串
+func (a *Analyzer) GeneratePackageVisualizerJSON() (string, error) {
+	if a.lastResult == nil {
+		return "", fmt.Errorf("no analysis result, call Analyze() first")
+	}
+
+	vizReporter := reporter.NewVisualizerReporter()
+	vizOutput := vizReporter.GeneratePackages(a.lastResult.raw)
+	return vizReporter.ToJSON(vizOutput)
+}
+
+// FindPaths 查找两个类型之间的依赖路径（需先调用 Analyze），遵循黑名单和依赖类型过滤
+// 查找范围是项目中全部结构体，不受起点和最大深度限制
+func (a *Analyzer) FindPaths(from, to string, opts PathOptions) (*PathResult, error) {
+	if a.lastResult == nil {
+		return nil, fmt.Errorf("no analysis result, call Analyze() first")
+	}
+
+	raw, err := internalAnalyzer.FindPaths(a.parser, a.filter, internalAnalyzer.PathQuery{
+		From:     from,
+		To:       to,
+		DepTypes: opts.DepTypes,
+		All:      opts.All,
+		MaxHops:  opts.MaxHops,
+		Limit:    opts.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PathResult{From: raw.From, To: raw.To, Truncated: raw.Truncated, raw: raw}
+	for _, path := range raw.Paths {
+		dp := DependencyPath{Nodes: path.Nodes}
+		for _, hop := range path.Hops {
+			dp.Hops = append(dp.Hops, PathHop{From: hop.From, To: hop.To, Types: hop.Types, Evidence: hop.Evidence})
+		}
+		result.Paths = append(result.Paths, dp)
+	}
+	return result, nil
+}
+
+// Mermaid 生成高亮最短路径的 Mermaid 图
+func (r *PathResult) Mermaid() string {
+	return reporter.NewMermaidGenerator().GeneratePath(r.raw)
+}
+
 // SaveMarkdown 保存 Markdown 报告到文件
 func (a *Analyzer) SaveMarkdown(path string) error {
 	content, err := a.GenerateMarkdown()
@@ -259,6 +440,7 @@ func (a *Analyzer) SaveVisualizerJSON(path string) error {
 	}
 
 	vizReporter := reporter.NewVisualizerReporter()
+	vizReporter.SizeBy = a.opts.RankBy
 	vizOutput := vizReporter.Generate(a.lastResult.raw)
 	return vizReporter.SaveToFile(vizOutput, path)
 }
@@ -289,6 +471,18 @@ func convertResult(r *types.AnalysisResult) *Result {
 		Cycles:       r.Cycles,
 		Blacklist:    r.Blacklist,
 		raw:          r,
+
+		Components:      r.Components,
+		CyclesTruncated: r.CyclesTruncated,
+	}
+
+	// 转换循环依赖详情
+	for _, c := range r.CycleDetails {
+		cd := CycleDetail{Nodes: c.Nodes, EdgeTypes: c.EdgeTypes}
+		for _, e := range c.Edges {
+			cd.Edges = append(cd.Edges, CycleEdge{From: e.From, To: e.To, Types: e.Types, Evidence: e.Evidence})
+		}
+		result.CycleDetails = append(result.CycleDetails, cd)
 	}
 
 	// 转换结构体分析
@@ -298,16 +492,21 @@ func convertResult(r *types.AnalysisResult) *Result {
 			Package:     s.Package,
 			Description: s.Description,
 			Depth:       s.Depth,
+			Stopped:     s.Stopped,
+			Layer:       s.Layer,
+			Role:        s.Role,
+			RoleReason:  s.RoleReason,
 		}
 
 		// 转换字段
 		for _, f := range s.Fields {
 			sa.Fields = append(sa.Fields, FieldAnalysis{
-				Name:        f.Name,
-				Type:        f.Type,
-				Description: f.Description,
-				IsExported:  f.IsExported,
-				IsEmbedded:  f.IsEmbedded,
+				Name:            f.Name,
+				Type:            f.Type,
+				Description:     f.Description,
+				IsExported:      f.IsExported,
+				IsEmbedded:      f.IsEmbedded,
+				SerializedNames: f.SerializedNames,
 			})
 		}
 
@@ -323,18 +522,255 @@ func convertResult(r *types.AnalysisResult) *Result {
 
 		// 转换依赖
 		for _, d := range s.Dependencies {
-			sa.Dependencies = append(sa.Dependencies, Dependency{
-				From:    d.From,
-				To:      d.To,
-				Type:    DependencyType(d.Type),
-				Context: d.Context,
-				Depth:   d.Depth,
+			sa.Dependencies = append(sa.Dependencies, convertDependency(d))
+		}
+
+		// 转换字段访问矩阵
+		sa.FieldAccess = FieldAccessMatrix{
+			Methods:           s.FieldAccess.Methods,
+			Fields:            s.FieldAccess.Fields,
+			UnreadFields:      s.FieldAccess.UnreadFields,
+			MultiWriterFields: s.FieldAccess.MultiWriterFields,
+			FieldlessMethods:  s.FieldAccess.FieldlessMethods,
+		}
+		for _, acc := range s.FieldAccess.Accesses {
+			sa.FieldAccess.Accesses = append(sa.FieldAccess.Accesses, FieldAccess{
+				Method: acc.Method,
+				Field:  acc.Field,
+				Read:   acc.Read,
+				Write:  acc.Write,
+				Call:   acc.Call,
 			})
 		}
 
+		// 转换最小接口建议
+		for _, sug := range s.InterfaceSuggestions {
+			is := InterfaceSuggestion{
+				Field:     sug.Field,
+				FieldType: sug.FieldType,
+				Name:      sug.Name,
+				Callers:   sug.Callers,
+				Imports:   sug.Imports,
+				Matching:  sug.Matching,
+				Covering:  sug.Covering,
+			}
+			for _, m := range sug.Methods {
+				is.Methods = append(is.Methods, InterfaceMethod{Name: m.Name, Signature: m.Signature})
+			}
+			sa.InterfaceSuggestions = append(sa.InterfaceSuggestions, is)
+		}
+
+		// 转换内存布局
+		sa.Layout = StructLayout{
+			Arch:           s.Layout.Arch,
+			Size:           s.Layout.Size,
+			Align:          s.Layout.Align,
+			Padding:        s.Layout.Padding,
+			OptimalSize:    s.Layout.OptimalSize,
+			SuggestedOrder: s.Layout.SuggestedOrder,
+			Unknown:        s.Layout.Unknown,
+		}
+		for _, f := range s.Layout.Fields {
+			sa.Layout.Fields = append(sa.Layout.Fields, FieldLayout(f))
+		}
+
 		result.Structs = append(result.Structs, sa)
 	}
 
+	// 转换第三方模块类型
+	for _, ext := range r.ExternalTypes {
+		et := ExternalType{
+			Name:       ext.Name,
+			ImportPath: ext.ImportPath,
+			Module:     ext.Module,
+			Version:    ext.Version,
+			Source:     ext.Source,
+			UsedBy:     ext.UsedBy,
+		}
+		for _, f := range ext.Fields {
+			et.Fields = append(et.Fields, FieldAnalysis{
+				Name:       f.Name,
+				Type:       f.Type,
+				IsExported: f.IsExported,
+				IsEmbedded: f.IsEmbedded,
+			})
+		}
+		result.ExternalTypes = append(result.ExternalTypes, et)
+	}
+
+	// 转换包级依赖图
+	for _, p := range r.PackageGraph.Packages {
+		result.PackageGraph.Packages = append(result.PackageGraph.Packages, PackageNode{
+			ImportPath: p.ImportPath,
+			Name:       p.Name,
+			Structs:    p.Structs,
+			Depth:      p.Depth,
+			External:   p.External,
+		})
+	}
+	for _, e := range r.PackageGraph.Edges {
+		pe := PackageEdge{From: e.From, To: e.To, Weight: e.Weight}
+		for _, d := range e.Deps {
+			pe.Deps = append(pe.Deps, convertDependency(d))
+		}
+		result.PackageGraph.Edges = append(result.PackageGraph.Edges, pe)
+	}
+	result.PackageGraph.Cycles = r.PackageGraph.Cycles
+
+	// 转换度量
+	for _, m := range r.Metrics.Structs {
+		result.Metrics.Structs = append(result.Metrics.Structs, StructMetrics{
+			Name:        m.Name,
+			Package:     m.Package,
+			FanIn:       m.FanIn,
+			FanOut:      m.FanOut,
+			Fields:      m.Fields,
+			Methods:     m.Methods,
+			LCOM:        m.LCOM,
+			Reach:       m.Reach,
+			PageRank:    m.PageRank,
+			Betweenness: m.Betweenness,
+		})
+	}
+	for _, m := range r.Metrics.Packages {
+		result.Metrics.Packages = append(result.Metrics.Packages, PackageMetrics{
+			ImportPath:   m.ImportPath,
+			Name:         m.Name,
+			FanIn:        m.FanIn,
+			FanOut:       m.FanOut,
+			Ca:           m.Ca,
+			Ce:           m.Ce,
+			Instability:  m.Instability,
+			Abstractness: m.Abstractness,
+			Distance:     m.Distance,
+		})
+	}
+
+	// 转换死代码检测结果
+	result.DeadCode.Roots = r.DeadCode.Roots
+	result.DeadCode.UnreachableStructs = convertDeadDecls(r.DeadCode.UnreachableStructs)
+	result.DeadCode.UnreachableInterfaces = convertDeadDecls(r.DeadCode.UnreachableInterfaces)
+	result.DeadCode.UnusedConstructors = convertDeadDecls(r.DeadCode.UnusedConstructors)
+	result.DeadCode.UnimplementedInterfaces = convertDeadDecls(r.DeadCode.UnimplementedInterfaces)
+	for _, impl := range r.DeadCode.SingleImplInterfaces {
+		result.DeadCode.SingleImplInterfaces = append(result.DeadCode.SingleImplInterfaces, InterfaceImpl{
+			Interface:   impl.Interface,
+			Package:     impl.Package,
+			Implementer: impl.Implementer,
+			FilePath:    impl.FilePath,
+		})
+	}
+
+	// 转换成员使用情况
+	result.Members.UnexportCandidates = convertUnusedMembers(r.Members.UnexportCandidates)
+	result.Members.Unused = convertUnusedMembers(r.Members.Unused)
+
+	// 转换过滤判定
+	for _, d := range r.Decisions {
+		result.Decisions = append(result.Decisions, TypeDecision{
+			Type:     d.Type,
+			Accepted: d.Accepted,
+			Rule:     d.Rule,
+			Detail:   d.Detail,
+			From:     d.From,
+			DepType:  d.DepType,
+			Context:  d.Context,
+			FilePath: d.FilePath,
+		})
+	}
+
 	return result
 }
 
+// convertDependency 转换单条依赖关系
+func convertDependency(d types.Dependency) Dependency {
+	return Dependency{
+		From:     d.From,
+		To:       d.To,
+		Type:     DependencyType(d.Type),
+		Context:  d.Context,
+		Depth:    d.Depth,
+		External: d.External,
+	}
+}
+
+// convertDeadDecls 转换不可达声明列表
+func convertDeadDecls(decls []types.DeadDecl) []DeadDecl {
+	var result []DeadDecl
+	for _, d := range decls {
+		result = append(result, DeadDecl{Name: d.Name, Package: d.Package, FilePath: d.FilePath})
+	}
+	return result
+}
+
+// convertUnusedMembers 转换成员列表
+func convertUnusedMembers(members []types.UnusedMember) []UnusedMember {
+	var result []UnusedMember
+	for _, m := range members {
+		result = append(result, UnusedMember{Struct: m.Struct, Package: m.Package, Name: m.Name, Kind: m.Kind, FilePath: m.FilePath})
+	}
+	return result
+}
+
+// LoadResult 读取 SaveJSON（或命令行 --format json）保存的分析结果
+func LoadResult(path string) (*Result, error) {
+	raw, err := reporter.NewJSONReporter().LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return convertResult(raw), nil
+}
+
+// DiffResults 比较两次分析结果：新增和删除的结构体、字段、方法和依赖边，深度变化，
+// 新出现和已消除的循环依赖，以及度量变化。结果需要来自 Analyze 或 LoadResult
+func DiffResults(oldResult, newResult *Result) (*ResultDiff, error) {
+	if oldResult == nil || newResult == nil || oldResult.raw == nil || newResult.raw == nil {
+		return nil, fmt.Errorf("results must come from Analyze() or LoadResult()")
+	}
+
+	raw := internalAnalyzer.DiffResults(oldResult.raw, newResult.raw)
+	diff := &ResultDiff{
+		Old:            raw.Old,
+		New:            raw.New,
+		Warnings:       raw.Warnings,
+		OldStructs:     raw.OldStructs,
+		NewStructs:     raw.NewStructs,
+		OldDeps:        raw.OldDeps,
+		NewDeps:        raw.NewDeps,
+		NewCycles:      raw.NewCycles,
+		ResolvedCycles: raw.ResolvedCycles,
+		raw:            raw,
+	}
+	for _, s := range raw.AddedStructs {
+		diff.AddedStructs = append(diff.AddedStructs, StructRef{Name: s.Name, Package: s.Package, Depth: s.Depth})
+	}
+	for _, s := range raw.RemovedStructs {
+		diff.RemovedStructs = append(diff.RemovedStructs, StructRef{Name: s.Name, Package: s.Package, Depth: s.Depth})
+	}
+	for _, m := range raw.Members {
+		diff.Members = append(diff.Members, MemberChange{Struct: m.Struct, Name: m.Name, Kind: m.Kind, Change: m.Change, Old: m.Old, New: m.New})
+	}
+	for _, dep := range raw.AddedEdges {
+		diff.AddedEdges = append(diff.AddedEdges, convertDependency(dep))
+	}
+	for _, dep := range raw.RemovedEdges {
+		diff.RemovedEdges = append(diff.RemovedEdges, convertDependency(dep))
+	}
+	for _, c := range raw.DepthChanges {
+		diff.DepthChanges = append(diff.DepthChanges, DepthChange{Struct: c.Struct, Old: c.Old, New: c.New})
+	}
+	for _, d := range raw.MetricDeltas {
+		diff.MetricDeltas = append(diff.MetricDeltas, MetricDelta{Scope: d.Scope, Subject: d.Subject, Metric: d.Metric, Old: d.Old, New: d.New})
+	}
+	return diff, nil
+}
+
+// Markdown 生成对比结果的 Markdown（适合作为 PR 评论）
+func (d *ResultDiff) Markdown() string {
+	return reporter.NewMarkdownReporter().GenerateDiff(&d.raw)
+}
+
+// Mermaid 生成依赖变化的 Mermaid 图：新增的边为绿色，删除的边为红色
+func (d *ResultDiff) Mermaid() string {
+	return reporter.NewMermaidGenerator().GenerateDiff(&d.raw)
+}
@@ -273,6 +273,8 @@ func TestResult_GetStructByName(t *testing.T) {
 	s := result.GetStructByName("UserService")
 	if s == nil {
 		t.Error("GetStructByName(UserService) returned nil")
+	} else if s.Role != "service" {
+		t.Errorf("UserService role = %q, want service", s.Role)
 	}
 
 	// 测试获取不存在的结构体
@@ -311,3 +313,72 @@ func TestResult_GetStructsByDepth(t *testing.T) {
 		t.Errorf("Depth 0 struct name = %q, want %q", depth0[0].Name, "UserService")
 	}
 }
+
+func TestAnalyzer_FindPaths(t *testing.T) {
+	projectPath := getTestProjectPath()
+	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
+		t.Skip("testdata/sample_project not found")
+	}
+
+	a, _ := New(Options{ProjectPath: projectPath, StartStruct: "UserService"})
+	if _, err := a.FindPaths("UserService", "Cache", PathOptions{}); err == nil {
+		t.Error("FindPaths() before Analyze() should fail")
+	}
+	if _, err := a.Analyze(); err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+
+	result, err := a.FindPaths("UserService", "Cache", PathOptions{})
+	if err != nil {
+		t.Fatalf("FindPaths() failed: %v", err)
+	}
+	if len(result.Paths) != 1 || len(result.Paths[0].Hops) != 1 {
+		t.Fatalf("expected a single one-hop path, got %+v", result.Paths)
+	}
+	if !strings.Contains(result.Mermaid(), "linkStyle 0") {
+		t.Error("path mermaid should highlight the shortest path")
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	projectPath := getTestProjectPath()
+	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
+		t.Skip("testdata/sample_project not found")
+	}
+
+	a, err := New(Options{
+		ProjectPath: projectPath,
+		StartStruct: "UserService",
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	result, err := a.Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() failed: %v", err)
+	}
+
+	jsonPath := filepath.Join(t.TempDir(), "report.json")
+	if err := a.SaveJSON(jsonPath); err != nil {
+		t.Fatalf("SaveJSON() failed: %v", err)
+	}
+	loaded, err := LoadResult(jsonPath)
+	if err != nil {
+		t.Fatalf("LoadResult() failed: %v", err)
+	}
+
+	diff, err := DiffResults(loaded, result)
+	if err != nil {
+		t.Fatalf("DiffResults() failed: %v", err)
+	}
+	if len(diff.AddedStructs)+len(diff.RemovedStructs)+len(diff.Members)+len(diff.AddedEdges)+len(diff.RemovedEdges) != 0 {
+		t.Errorf("saved and fresh results should not differ, got %+v", diff)
+	}
+	if !strings.Contains(diff.Markdown(), "两次分析结果没有差异。") {
+		t.Error("Markdown() should say that nothing changed")
+	}
+
+	if _, err := DiffResults(&Result{}, result); err == nil {
+		t.Error("DiffResults() should fail for a result without raw data")
+	}
+}
@@ -25,12 +25,39 @@ type Result struct {
 	// Structs 结构体分析列表
 	Structs []StructAnalysis
 
-	// Cycles 检测到的循环依赖
+	// Cycles 检测到的循环依赖（基本环，从字典序最小的节点开始）
 	Cycles [][]string
 
+	// CycleDetails 循环依赖详情，与 Cycles 一一对应
+	CycleDetails []CycleDetail
+
+	// Components 包含环的强连通分量
+	Components [][]string
+
+	// CyclesTruncated 循环数量超过 CycleLimit，Cycles 不完整
+	CyclesTruncated bool
+
 	// Blacklist 使用的黑名单
 	Blacklist []string
 
+	// ExternalTypes 第三方模块类型（启用 IncludeExternal 时填充）
+	ExternalTypes []ExternalType
+
+	// Decisions 候选类型的过滤判定（启用 Explain 时填充）
+	Decisions []TypeDecision
+
+	// PackageGraph 包级依赖图
+	PackageGraph PackageGraph
+
+	// Metrics 耦合与内聚度量
+	Metrics Metrics
+
+	// DeadCode 从根出发不可达的声明
+	DeadCode DeadCodeReport
+
+	// Members 未被使用的结构体成员
+	Members MemberUsageReport
+
 	// raw 内部原始结果（用于生成报告）
 	raw *types.AnalysisResult
 }
@@ -49,6 +76,18 @@ type StructAnalysis struct {
 	// Depth 在依赖树中的深度
 	Depth int
 
+	// Stopped 命中 stop 规则，依赖未继续遍历
+	Stopped bool
+
+	// Layer 架构层（来自源码注释 //structanalyzer:layer=...）
+	Layer string
+
+	// Role 架构角色（handler、service、repository、model、dto 或自定义角色），无法判定时为空
+	Role string
+
+	// RoleReason 角色的判定依据
+	RoleReason string
+
 	// Fields 字段列表
 	Fields []FieldAnalysis
 
@@ -57,6 +96,138 @@ type StructAnalysis struct {
 
 	// Dependencies 依赖列表
 	Dependencies []Dependency
+
+	// FieldAccess 方法 × 字段访问矩阵
+	FieldAccess FieldAccessMatrix
+
+	// InterfaceSuggestions 为具体类型字段建议的最小接口
+	InterfaceSuggestions []InterfaceSuggestion
+
+	// Layout 内存布局
+	Layout StructLayout
+}
+
+// StructLayout 结构体在指定 GOARCH 下的内存布局
+type StructLayout struct {
+	// Arch 计算使用的 GOARCH
+	Arch string
+
+	// Size 大小（字节）
+	Size int64
+
+	// Align 对齐（字节）
+	Align int64
+
+	// Padding 填充字节总数
+	Padding int64
+
+	// Fields 按声明顺序的字段布局
+	Fields []FieldLayout
+
+	// OptimalSize 按建议顺序排列后的大小
+	OptimalSize int64
+
+	// SuggestedOrder 使大小最小的字段顺序（当前顺序已最优时为空）
+	SuggestedOrder []string
+
+	// Unknown 无法确定大小的类型（不为空时不计算布局）
+	Unknown []string
+}
+
+// FieldLayout 字段的内存布局
+type FieldLayout struct {
+	// Name 字段名
+	Name string
+
+	// Type 字段类型
+	Type string
+
+	// Offset 偏移
+	Offset int64
+
+	// Size 大小
+	Size int64
+
+	// Align 对齐
+	Align int64
+
+	// Padding 字段之后的填充字节数
+	Padding int64
+}
+
+// InterfaceSuggestion 为类型为具体结构体的字段建议抽取的最小接口
+type InterfaceSuggestion struct {
+	// Field 字段名
+	Field string
+
+	// FieldType 字段的结构体类型（带包名）
+	FieldType string
+
+	// Name 建议的接口名
+	Name string
+
+	// Methods 实际调用的方法（签名为使用方包中的写法）
+	Methods []InterfaceMethod
+
+	// Callers 调用这些方法的方法
+	Callers []string
+
+	// Imports 接口声明需要的导入路径
+	Imports []string
+
+	// Matching 方法集合与建议完全相同的已有接口
+	Matching []string
+
+	// Covering 包含全部调用方法、且被字段类型实现的更大的已有接口
+	Covering []string
+}
+
+// InterfaceMethod 接口方法
+type InterfaceMethod struct {
+	// Name 方法名
+	Name string
+
+	// Signature 签名（参数和返回值）
+	Signature string
+}
+
+// FieldAccessMatrix 结构体的方法对字段的访问情况
+type FieldAccessMatrix struct {
+	// Methods 方法（按名称排序）
+	Methods []string
+
+	// Fields 字段（声明顺序）
+	Fields []string
+
+	// Accesses 非空的访问记录
+	Accesses []FieldAccess
+
+	// UnreadFields 没有任何方法读取或调用的字段
+	UnreadFields []string
+
+	// MultiWriterFields 被多个方法写入的字段
+	MultiWriterFields []string
+
+	// FieldlessMethods 不访问任何字段的方法
+	FieldlessMethods []string
+}
+
+// FieldAccess 一个方法对一个字段的访问
+type FieldAccess struct {
+	// Method 方法名
+	Method string
+
+	// Field 字段名
+	Field string
+
+	// Read 读取字段值
+	Read bool
+
+	// Write 写入字段（包括经由指针、索引的间接写入）
+	Write bool
+
+	// Call 在字段值上调用方法
+	Call bool
 }
 
 // FieldAnalysis 字段分析结果
@@ -75,6 +246,9 @@ type FieldAnalysis struct {
 
 	// IsEmbedded 是否为嵌入字段
 	IsEmbedded bool
+
+	// SerializedNames 序列化名：标签键（json、yaml、db、bson，gorm 为列名）-> 名称
+	SerializedNames map[string]string
 }
 
 // MethodAnalysis 方法分析结果
@@ -113,6 +287,12 @@ const (
 
 	// DepTypeConstructor 构造函数调用
 	DepTypeConstructor DependencyType = "constructor"
+
+	// DepTypeConstructorParam 构造函数参数（依赖注入）
+	DepTypeConstructorParam DependencyType = "constructor_param"
+
+	// DepTypeRelation ORM 关联（gorm foreignKey、many2many 标签）
+	DepTypeRelation DependencyType = "relation"
 )
 
 // Dependency 依赖关系
@@ -131,6 +311,350 @@ type Dependency struct {
 
 	// Depth 深度
 	Depth int
+
+	// External 目标是否为第三方模块类型
+	External bool
+}
+
+// CycleDetail 一个基本环及构成它的依赖
+type CycleDetail struct {
+	// Nodes 环上的结构体，最后一个节点指回第一个节点
+	Nodes []string
+
+	// Edges 环上的每一步
+	Edges []CycleEdge
+
+	// EdgeTypes 环上出现的全部依赖类型
+	EdgeTypes []string
+}
+
+// CycleEdge 环上的一步及其依据
+type CycleEdge struct {
+	// From 源结构体
+	From string
+
+	// To 目标结构体
+	To string
+
+	// Types 该步的依赖类型
+	Types []string
+
+	// Evidence 依据（字段名、方法名等上下文）
+	Evidence []string
+}
+
+// PackageGraph 由结构体依赖聚合得到的包级依赖图
+type PackageGraph struct {
+	// Packages 涉及的包（按导入路径排序）
+	Packages []PackageNode
+
+	// Edges 包之间的依赖（不含包内依赖）
+	Edges []PackageEdge
+
+	// Cycles 包级循环依赖（导入路径，从字典序最小的包开始）
+	Cycles [][]string
+}
+
+// PackageNode 包级依赖图中的一个包
+type PackageNode struct {
+	// ImportPath 导入路径
+	ImportPath string
+
+	// Name 包名
+	Name string
+
+	// Structs 包内涉及的类型
+	Structs []string
+
+	// Depth 包内结构体的最小深度
+	Depth int
+
+	// External 是否为第三方模块的包
+	External bool
+}
+
+// PackageEdge 两个包之间的依赖
+type PackageEdge struct {
+	// From 源包导入路径
+	From string
+
+	// To 目标包导入路径
+	To string
+
+	// Weight 构成该边的结构体依赖数量
+	Weight int
+
+	// Deps 构成该边的结构体依赖
+	Deps []Dependency
+}
+
+// Metrics 结构体和包的耦合、内聚度量
+type Metrics struct {
+	// Structs 每个结构体的度量（按名称排序）
+	Structs []StructMetrics
+
+	// Packages 每个项目包的度量（按导入路径排序）
+	Packages []PackageMetrics
+}
+
+// StructMetrics 单个结构体的度量
+type StructMetrics struct {
+	// Name 结构体名称
+	Name string
+
+	// Package 所属包名
+	Package string
+
+	// FanIn 依赖该结构体的已分析结构体数
+	FanIn int
+
+	// FanOut 该结构体依赖的不同类型数
+	FanOut int
+
+	// Fields 字段数
+	Fields int
+
+	// Methods 方法数
+	Methods int
+
+	// LCOM Henderson-Sellers 内聚缺乏度，0 表示完全内聚
+	LCOM float64
+
+	// Reach 直接或间接依赖该结构体的已分析结构体数（影响范围）
+	Reach int
+
+	// PageRank 依赖图上的 PageRank，全部结构体之和为 1
+	PageRank float64
+
+	// Betweenness 归一化的介数中心性，经过该结构体的最短依赖路径比例
+	Betweenness float64
+}
+
+// PackageMetrics 单个包的度量
+type PackageMetrics struct {
+	// ImportPath 导入路径
+	ImportPath string
+
+	// Name 包名
+	Name string
+
+	// FanIn 指向该包的跨包结构体依赖数
+	FanIn int
+
+	// FanOut 从该包发出的跨包结构体依赖数
+	FanOut int
+
+	// Ca 传入耦合：依赖该包的其他包数
+	Ca int
+
+	// Ce 传出耦合：该包依赖的其他包数
+	Ce int
+
+	// Instability 不稳定性 I = Ce / (Ca + Ce)
+	Instability float64
+
+	// Abstractness 抽象度 A = 接口数 / 类型总数
+	Abstractness float64
+
+	// Distance 与主序列的距离 D = |A + I - 1|
+	Distance float64
+}
+
+// CyclesOfType 返回包含指定依赖类型的循环
+func (r *Result) CyclesOfType(depType string) []CycleDetail {
+	var result []CycleDetail
+	for _, c := range r.CycleDetails {
+		for _, t := range c.EdgeTypes {
+			if t == depType {
+				result = append(result, c)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// DeadCodeReport 从根出发不可达的声明，以及实现数过少的接口
+type DeadCodeReport struct {
+	// Roots 使用的根
+	Roots []string
+
+	// UnreachableStructs 不可达的结构体
+	UnreachableStructs []DeadDecl
+
+	// UnreachableInterfaces 不可达的接口
+	UnreachableInterfaces []DeadDecl
+
+	// UnusedConstructors 没有被可达代码调用的构造函数
+	UnusedConstructors []DeadDecl
+
+	// UnimplementedInterfaces 没有实现的接口
+	UnimplementedInterfaces []DeadDecl
+
+	// SingleImplInterfaces 只有一个实现的接口
+	SingleImplInterfaces []InterfaceImpl
+}
+
+// DeadDecl 不可达或无用的声明
+type DeadDecl struct {
+	// Name 类型名或函数名
+	Name string
+
+	// Package 所属包名
+	Package string
+
+	// FilePath 所在文件
+	FilePath string
+}
+
+// InterfaceImpl 接口与其唯一实现
+type InterfaceImpl struct {
+	// Interface 接口名
+	Interface string
+
+	// Package 接口所属包名
+	Package string
+
+	// Implementer 唯一实现的结构体
+	Implementer string
+
+	// FilePath 接口所在文件
+	FilePath string
+}
+
+// MemberUsageReport 结构体字段和方法的使用情况
+type MemberUsageReport struct {
+	// UnexportCandidates 没有被其他包使用的导出成员（可改为未导出）
+	UnexportCandidates []UnusedMember
+
+	// Unused 完全没有被使用的未导出成员
+	Unused []UnusedMember
+}
+
+// UnusedMember 结构体成员
+type UnusedMember struct {
+	// Struct 结构体名
+	Struct string
+
+	// Package 所属包名
+	Package string
+
+	// Name 成员名
+	Name string
+
+	// Kind 成员类别："field" 或 "method"
+	Kind string
+
+	// FilePath 结构体所在文件
+	FilePath string
+}
+
+// TypeDecision 候选依赖类型的过滤判定
+type TypeDecision struct {
+	// Type 候选类型（源码中的写法）
+	Type string
+
+	// Accepted 是否纳入分析
+	Accepted bool
+
+	// Rule 命中的判定规则，如 "accepted"、"builtin"、"stdlib"、"blacklist"、"lowercase"、"not-found"
+	Rule string
+
+	// Detail 补充说明
+	Detail string
+
+	// From 引用该类型的结构体
+	From string
+
+	// DepType 候选依赖类型
+	DepType string
+
+	// Context 出现位置（字段、方法等）
+	Context string
+
+	// FilePath 所在文件
+	FilePath string
+}
+
+// ExternalType 第三方模块类型（作为叶子节点）
+type ExternalType struct {
+	// Name 类型名（包名.类型名）
+	Name string
+
+	// ImportPath 导入路径
+	ImportPath string
+
+	// Module 所属模块路径
+	Module string
+
+	// Version 模块版本
+	Version string
+
+	// Fields 字段列表（从 vendor 或模块缓存读取时填充）
+	Fields []FieldAnalysis
+
+	// Source 定义来源："vendor"、"modcache" 或空
+	Source string
+
+	// UsedBy 依赖该类型的项目结构体
+	UsedBy []string
+}
+
+// PathOptions 路径查询选项
+type PathOptions struct {
+	// DepTypes 只沿这些依赖类型的边查找（为空表示全部）
+	DepTypes []string
+
+	// All 是否列出全部简单路径（否则只返回最短路径）
+	All bool
+
+	// MaxHops 列出全部路径时的最大跳数（<= 0 表示不限制）
+	MaxHops int
+
+	// Limit 列出全部路径时的最大数量（<= 0 时默认 100）
+	Limit int
+}
+
+// PathResult 两个类型之间的依赖路径查询结果
+type PathResult struct {
+	// From 起点结构体
+	From string
+
+	// To 终点类型
+	To string
+
+	// Paths 找到的路径，第一条为最短路径；没有路径时为空
+	Paths []DependencyPath
+
+	// Truncated 路径数量是否超过上限而被截断
+	Truncated bool
+
+	// raw 内部结果（用于生成 Mermaid 图）
+	raw types.PathResult
+}
+
+// DependencyPath 一条依赖路径
+type DependencyPath struct {
+	// Nodes 路径上的节点，从起点到终点
+	Nodes []string
+
+	// Hops 路径上的每一跳
+	Hops []PathHop
+}
+
+// PathHop 路径上的一跳及其依据
+type PathHop struct {
+	// From 源结构体
+	From string
+
+	// To 目标类型
+	To string
+
+	// Types 该跳的依赖类型
+	Types []string
+
+	// Evidence 依据（字段名、方法名等上下文）
+	Evidence []string
 }
 
 // GetStructByName 根据名称获取结构体分析
@@ -193,3 +717,120 @@ func (r *Result) GetDependentsOf(structName string) []string {
 	}
 	return dependents
 }
+
+// ResultDiff 两次分析结果之间的差异（用于跟踪架构漂移）
+type ResultDiff struct {
+	// Old 旧结果的生成时间
+	Old string
+
+	// New 新结果的生成时间
+	New string
+
+	// Warnings 两次分析的起点或深度不同等提示
+	Warnings []string
+
+	// OldStructs 旧结果的结构体数
+	OldStructs int
+
+	// NewStructs 新结果的结构体数
+	NewStructs int
+
+	// OldDeps 旧结果的依赖关系数
+	OldDeps int
+
+	// NewDeps 新结果的依赖关系数
+	NewDeps int
+
+	// AddedStructs 新增的结构体
+	AddedStructs []StructRef
+
+	// RemovedStructs 删除的结构体
+	RemovedStructs []StructRef
+
+	// Members 两次都存在的结构体中新增、删除或修改的字段和方法
+	Members []MemberChange
+
+	// AddedEdges 新增的依赖边（同一起点、终点和依赖类型只保留一条）
+	AddedEdges []Dependency
+
+	// RemovedEdges 删除的依赖边
+	RemovedEdges []Dependency
+
+	// DepthChanges 深度变化
+	DepthChanges []DepthChange
+
+	// NewCycles 新出现的循环依赖
+	NewCycles [][]string
+
+	// ResolvedCycles 已消除的循环依赖
+	ResolvedCycles [][]string
+
+	// MetricDeltas 两次都存在的结构体和包的度量变化
+	MetricDeltas []MetricDelta
+
+	// raw 内部结果（用于生成报告）
+	raw types.ResultDiff
+}
+
+// StructRef 结构体
+type StructRef struct {
+	// Name 结构体名称
+	Name string
+
+	// Package 所属包名
+	Package string
+
+	// Depth 在依赖树中的深度
+	Depth int
+}
+
+// MemberChange 结构体字段或方法的变化
+type MemberChange struct {
+	// Struct 结构体名称
+	Struct string
+
+	// Name 成员名
+	Name string
+
+	// Kind 成员种类：field、method
+	Kind string
+
+	// Change 变化：added、removed、changed
+	Change string
+
+	// Old 变化前的类型或签名
+	Old string
+
+	// New 变化后的类型或签名
+	New string
+}
+
+// DepthChange 结构体深度的变化
+type DepthChange struct {
+	// Struct 结构体名称
+	Struct string
+
+	// Old 旧深度
+	Old int
+
+	// New 新深度
+	New int
+}
+
+// MetricDelta 一个度量的变化
+type MetricDelta struct {
+	// Scope 范围：struct、package
+	Scope string
+
+	// Subject 结构体名称或包导入路径
+	Subject string
+
+	// Metric 度量名：fan-in、fan-out、fields、methods、lcom、reach、pagerank、betweenness、ca、ce、instability、abstractness、distance
+	Metric string
+
+	// Old 旧值
+	Old float64
+
+	// New 新值
+	New float64
+}